@@ -87,6 +87,63 @@ func main() {
 	}
 	logger.Info(data.LogGRPCClientSetupSuccess, map[string]any{"service": data.GRPCClientService, "duration": utils.Ms(time.Since(startTime))})
 
+	// Keep compensating wallet updates left behind by an interrupted process
+	sagaRepo := repository.NewSagaLogRepository(dbInstance.GetDB())
+	sagaOrchestrator := service.NewSagaOrchestrator(sagaRepo, client.NewWalletClient(grpcManager.GetWalletClient()))
+	go sagaOrchestrator.Start(ctx)
+
+	// Start recurring transaction scheduler, catching up occurrences missed while down
+	startTime = time.Now()
+	transactionService := service.NewTransactionService(
+		repository.NewTxManager(dbInstance.GetDB()),
+		repository.NewTransactionRepository(dbInstance.GetDB()),
+		client.NewWalletClient(grpcManager.GetWalletClient()),
+		repository.NewCategoryRepository(dbInstance.GetDB()),
+		repository.NewAttachmentsRepository(dbInstance.GetDB()),
+		outboxRepo,
+		sagaRepo,
+		repository.NewIdempotencyRepository(dbInstance.GetDB()),
+		repository.NewBudgetsRepository(dbInstance.GetDB()),
+		repository.NewCurrenciesRepository(dbInstance.GetDB()),
+		repository.NewTagsRepository(dbInstance.GetDB()),
+		repository.NewTransactionHistoryRepository(dbInstance.GetDB()),
+		repository.NewReconciliationsRepository(dbInstance.GetDB()),
+		minioInstance,
+	)
+	recurringService := service.NewRecurringTransactionsService(
+		repository.NewTxManager(dbInstance.GetDB()),
+		repository.NewRecurringTransactionsRepository(dbInstance.GetDB()),
+		repository.NewCategoryRepository(dbInstance.GetDB()),
+		transactionService,
+	)
+	go service.NewRecurringScheduler(recurringService).Start(ctx)
+	logger.Info(data.LogRecurringSchedulerStarted, map[string]any{"service": data.RecurringService, "duration": utils.Ms(time.Since(startTime))})
+
+	// Start trash purger, removing transactions deleted past the retention period
+	startTime = time.Now()
+	go service.NewTrashPurger(transactionService).Start(ctx)
+	logger.Info(data.LogTrashPurgerStarted, map[string]any{"service": data.TransactionService, "duration": utils.Ms(time.Since(startTime))})
+
+	// Start scheduled transaction poster, posting future-dated transactions that came due while down
+	startTime = time.Now()
+	go service.NewScheduledTransactionPoster(transactionService).Start(ctx)
+	logger.Info(data.LogScheduledPosterStarted, map[string]any{"service": data.TransactionService, "duration": utils.Ms(time.Since(startTime))})
+
+	// Start wallet drift checker, comparing ledger sums with wallet-service balances
+	startTime = time.Now()
+	driftService := service.NewWalletDriftsService(
+		repository.NewTxManager(dbInstance.GetDB()),
+		repository.NewWalletDriftsRepository(dbInstance.GetDB()),
+		repository.NewTransactionRepository(dbInstance.GetDB()),
+		client.NewWalletClient(grpcManager.GetWalletClient()),
+		repository.NewCategoryRepository(dbInstance.GetDB()),
+		outboxRepo,
+		repository.NewCurrenciesRepository(dbInstance.GetDB()),
+		repository.NewTransactionHistoryRepository(dbInstance.GetDB()),
+	)
+	go service.NewWalletDriftChecker(driftService).Start(ctx)
+	logger.Info(data.LogWalletDriftCheckerStarted, map[string]any{"service": data.WalletDriftService, "duration": utils.Ms(time.Since(startTime))})
+
 	// Setup Queue Consumers
 	startTime = time.Now()
 	setup.SetupQueueConsumers(ctx, dbInstance, minioInstance, queueInstance)
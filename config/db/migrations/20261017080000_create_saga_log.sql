@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS saga_logs (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    type VARCHAR(100) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'started',
+    error text
+);
+
+CREATE TABLE IF NOT EXISTS saga_log_steps (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    saga_id uuid NOT NULL REFERENCES saga_logs(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    sequence INTEGER NOT NULL,
+    wallet_id uuid NOT NULL,
+    delta numeric(18,2) NOT NULL,
+    previous_balance numeric(18,2) NOT NULL,
+    new_balance numeric(18,2) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending'
+);
+
+-- Index for the startup resume job
+CREATE INDEX idx_saga_logs_incomplete ON saga_logs(status, created_at) WHERE status = 'started' AND deleted_at IS NULL;
+CREATE INDEX idx_saga_log_steps_saga_id ON saga_log_steps(saga_id, sequence) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE saga_logs IS 'Saga log for remote wallet balance updates performed by a local transaction';
+COMMENT ON COLUMN saga_logs.status IS 'started, completed (local commit succeeded), compensated or failed (needs manual review)';
+COMMENT ON TABLE saga_log_steps IS 'One row per UpdateWallet call issued by a saga, used to build compensations';
+COMMENT ON COLUMN saga_log_steps.delta IS 'Signed balance change applied to the wallet by this step';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_saga_log_steps_saga_id;
+DROP INDEX IF EXISTS idx_saga_logs_incomplete;
+
+DROP TABLE IF EXISTS saga_log_steps;
+DROP TABLE IF EXISTS saga_logs;
+-- +goose StatementEnd
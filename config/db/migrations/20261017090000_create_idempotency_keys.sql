@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    key VARCHAR(255) NOT NULL,
+    operation VARCHAR(100) NOT NULL,
+    response jsonb NOT NULL
+);
+
+-- A key is unique per operation, so a concurrent duplicate fails on insert
+CREATE UNIQUE INDEX idx_idempotency_keys_operation_key ON idempotency_keys(operation, key);
+
+COMMENT ON TABLE idempotency_keys IS 'Responses of create requests keyed by client/message idempotency key, replayed on retries';
+COMMENT ON COLUMN idempotency_keys.key IS 'Idempotency-Key header, x-idempotency-key gRPC metadata or RabbitMQ message/event ID';
+COMMENT ON COLUMN idempotency_keys.operation IS 'transaction.create or transaction.fund_transfer';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_idempotency_keys_operation_key;
+
+DROP TABLE IF EXISTS idempotency_keys;
+-- +goose StatementEnd
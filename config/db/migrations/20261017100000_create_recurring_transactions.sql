@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS recurring_transactions (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    wallet_id uuid NOT NULL,
+    category_id uuid NOT NULL REFERENCES categories(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    amount numeric(18,2) NOT NULL,
+    description text,
+    frequency VARCHAR(20) NOT NULL,
+    interval INTEGER NOT NULL DEFAULT 1,
+    start_date timestamp NOT NULL,
+    end_date timestamp,
+    count INTEGER NOT NULL DEFAULT 0,
+    occurrences INTEGER NOT NULL DEFAULT 0,
+    next_run_at timestamp NOT NULL,
+    last_run_at timestamp,
+    status VARCHAR(20) NOT NULL DEFAULT 'active'
+);
+
+-- Index for the scheduler due query
+CREATE INDEX idx_recurring_transactions_due ON recurring_transactions(next_run_at) WHERE status = 'active' AND deleted_at IS NULL;
+CREATE INDEX idx_recurring_transactions_wallet_id ON recurring_transactions(wallet_id) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE recurring_transactions IS 'Schedules that the recurring scheduler materialises into transactions';
+COMMENT ON COLUMN recurring_transactions.frequency IS 'daily, weekly, monthly or yearly';
+COMMENT ON COLUMN recurring_transactions.interval IS 'Repeat every N frequency units';
+COMMENT ON COLUMN recurring_transactions.count IS 'Maximum number of occurrences, 0 means unlimited';
+COMMENT ON COLUMN recurring_transactions.occurrences IS 'Occurrences consumed so far, either materialised or skipped';
+COMMENT ON COLUMN recurring_transactions.status IS 'active, paused or completed';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_recurring_transactions_wallet_id;
+DROP INDEX IF EXISTS idx_recurring_transactions_due;
+
+DROP TABLE IF EXISTS recurring_transactions;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS budgets (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    user_id VARCHAR(255) NOT NULL,
+    category_id uuid NOT NULL REFERENCES categories(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    period VARCHAR(20) NOT NULL DEFAULT 'monthly',
+    amount numeric(18,2) NOT NULL,
+    rollover BOOLEAN NOT NULL DEFAULT false
+);
+
+-- One budget per category and period for each user
+CREATE UNIQUE INDEX idx_budgets_user_category_period ON budgets(user_id, category_id, period) WHERE deleted_at IS NULL;
+CREATE INDEX idx_budgets_category_id ON budgets(category_id) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE budgets IS 'Spending limits per expense category or category group, checked on every transaction write';
+COMMENT ON COLUMN budgets.category_id IS 'Expense category, or a parent category to budget its whole group';
+COMMENT ON COLUMN budgets.period IS 'weekly, monthly or yearly, aligned to calendar boundaries';
+COMMENT ON COLUMN budgets.rollover IS 'Carry the unspent amount of the previous period into the current one';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_budgets_category_id;
+DROP INDEX IF EXISTS idx_budgets_user_category_period;
+
+DROP TABLE IF EXISTS budgets;
+-- +goose StatementEnd
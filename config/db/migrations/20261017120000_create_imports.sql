@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS imports (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    user_id VARCHAR(255) NOT NULL,
+    wallet_id uuid NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    file_name VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'previewed',
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    valid_rows INTEGER NOT NULL DEFAULT 0,
+    balance_delta numeric(18,2) NOT NULL DEFAULT 0,
+    rows jsonb NOT NULL DEFAULT '[]',
+    committed_at timestamptz,
+    undone_at timestamptz
+);
+
+CREATE INDEX idx_imports_user_id ON imports(user_id) WHERE deleted_at IS NULL;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS import_id uuid REFERENCES imports(id) ON DELETE SET NULL;
+CREATE INDEX idx_transactions_import_id ON transactions(import_id) WHERE import_id IS NOT NULL;
+
+COMMENT ON TABLE imports IS 'Audit trail of bank statement imports, used to preview, commit and undo them';
+COMMENT ON COLUMN imports.status IS 'previewed, committed or undone';
+COMMENT ON COLUMN imports.rows IS 'Parsed rows with their category mapping and validation errors';
+COMMENT ON COLUMN imports.balance_delta IS 'Net wallet balance change applied on commit and reverted on undo';
+COMMENT ON COLUMN transactions.import_id IS 'Import that created the transaction, if any';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_import_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS import_id;
+
+DROP INDEX IF EXISTS idx_imports_user_id;
+
+DROP TABLE IF EXISTS imports;
+-- +goose StatementEnd
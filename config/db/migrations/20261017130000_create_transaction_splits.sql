@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS transaction_splits (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    transaction_id uuid NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    category_id uuid NOT NULL REFERENCES categories(id),
+    amount numeric(18,2) NOT NULL CHECK (amount > 0),
+    note TEXT,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_transaction_splits_transaction_id ON transaction_splits(transaction_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transaction_splits_category_id ON transaction_splits(category_id) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE transaction_splits IS 'Category lines of a transaction split across several categories; they sum to the parent amount';
+COMMENT ON COLUMN transaction_splits.position IS 'Order of the line as entered by the user';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transaction_splits_category_id;
+DROP INDEX IF EXISTS idx_transaction_splits_transaction_id;
+
+DROP TABLE IF EXISTS transaction_splits;
+-- +goose StatementEnd
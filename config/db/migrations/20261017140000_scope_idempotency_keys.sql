@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys
+    ADD COLUMN IF NOT EXISTS user_id VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS request_hash VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Keys are chosen by clients, so the same key from two users must not collide
+DROP INDEX IF EXISTS idx_idempotency_keys_operation_key;
+CREATE UNIQUE INDEX idx_idempotency_keys_user_operation_key ON idempotency_keys(user_id, operation, key);
+
+COMMENT ON COLUMN idempotency_keys.user_id IS 'Caller the key belongs to; empty for internal callers such as the investment consumer and the recurring scheduler';
+COMMENT ON COLUMN idempotency_keys.request_hash IS 'SHA-256 of the original request body; a reused key with a different body is rejected';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_idempotency_keys_user_operation_key;
+CREATE UNIQUE INDEX idx_idempotency_keys_operation_key ON idempotency_keys(operation, key);
+
+ALTER TABLE idempotency_keys
+    DROP COLUMN IF EXISTS request_hash,
+    DROP COLUMN IF EXISTS user_id;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE recurring_transactions
+    ADD COLUMN IF NOT EXISTS failure_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_error text,
+    ADD COLUMN IF NOT EXISTS retry_at timestamp;
+
+COMMENT ON COLUMN recurring_transactions.failure_count IS 'Consecutive failed attempts at the next occurrence; the schedule is paused once it reaches the limit';
+COMMENT ON COLUMN recurring_transactions.last_error IS 'Error of the last failed attempt, cleared once an occurrence succeeds';
+COMMENT ON COLUMN recurring_transactions.retry_at IS 'Earliest time the scheduler retries a failed occurrence';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE recurring_transactions
+    DROP COLUMN IF EXISTS retry_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS failure_count;
+-- +goose StatementEnd
@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS fx_rates (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    base_currency VARCHAR(3) NOT NULL,
+    quote_currency VARCHAR(3) NOT NULL,
+    rate numeric(24,10) NOT NULL CHECK (rate > 0),
+    effective_date DATE NOT NULL,
+    source VARCHAR(50) NOT NULL DEFAULT 'manual',
+    CHECK (base_currency <> quote_currency)
+);
+
+CREATE UNIQUE INDEX idx_fx_rates_pair_date ON fx_rates(base_currency, quote_currency, effective_date) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS wallet_currencies (
+    wallet_id uuid PRIMARY KEY,
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    currency VARCHAR(3) NOT NULL
+);
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    ADD COLUMN IF NOT EXISTS original_amount numeric(18,2),
+    ADD COLUMN IF NOT EXISTS original_currency VARCHAR(3),
+    ADD COLUMN IF NOT EXISTS fx_rate numeric(24,10);
+
+COMMENT ON TABLE fx_rates IS 'Exchange rates by day: one base_currency is worth rate quote_currency';
+COMMENT ON COLUMN fx_rates.effective_date IS 'First day the rate applies; it holds until a later rate of the same pair';
+COMMENT ON COLUMN fx_rates.source IS 'manual for rates entered through the API, import for uploaded files';
+COMMENT ON TABLE wallet_currencies IS 'Currency each wallet is kept in; wallets without a row use the default currency';
+COMMENT ON COLUMN transactions.currency IS 'Currency of amount, always the currency of the wallet';
+COMMENT ON COLUMN transactions.original_amount IS 'Amount as entered when it was in another currency than the wallet';
+COMMENT ON COLUMN transactions.original_currency IS 'Currency of original_amount';
+COMMENT ON COLUMN transactions.fx_rate IS 'Rate used to convert original_amount into amount';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS fx_rate,
+    DROP COLUMN IF EXISTS original_currency,
+    DROP COLUMN IF EXISTS original_amount,
+    DROP COLUMN IF EXISTS currency;
+
+DROP TABLE IF EXISTS wallet_currencies;
+
+DROP INDEX IF EXISTS idx_fx_rates_pair_date;
+
+DROP TABLE IF EXISTS fx_rates;
+-- +goose StatementEnd
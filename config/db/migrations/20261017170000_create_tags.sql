@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tags (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    user_id VARCHAR(255) NOT NULL,
+    name VARCHAR(50) NOT NULL
+);
+
+-- Tag names are unique per user regardless of case
+CREATE UNIQUE INDEX idx_tags_user_name ON tags(user_id, LOWER(name)) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS transaction_tags (
+    transaction_id uuid NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    tag_id uuid NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (transaction_id, tag_id)
+);
+
+CREATE INDEX idx_transaction_tags_tag_id ON transaction_tags(tag_id);
+
+COMMENT ON TABLE tags IS 'Free-form labels a user puts on transactions across categories and wallets';
+COMMENT ON TABLE transaction_tags IS 'Tags of each transaction; rows are removed with the tag';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transaction_tags_tag_id;
+DROP TABLE IF EXISTS transaction_tags;
+
+DROP INDEX IF EXISTS idx_tags_user_name;
+DROP TABLE IF EXISTS tags;
+-- +goose StatementEnd
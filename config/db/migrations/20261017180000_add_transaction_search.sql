@@ -0,0 +1,102 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+-- Words are indexed as typed and stemmed in both languages, so that "makan"
+-- finds "makanan" by prefix and "running" finds "runs" by stem
+CREATE OR REPLACE FUNCTION search_document(doc text, weight "char") RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('simple', COALESCE(doc, '')), weight)
+        || setweight(to_tsvector('indonesian', COALESCE(doc, '')), weight)
+        || setweight(to_tsvector('english', COALESCE(doc, '')), weight)
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION transaction_search_vector(p_description text, p_category_id uuid, p_transaction_id uuid) RETURNS tsvector AS $$
+    SELECT search_document(p_description, 'A')
+        || search_document((SELECT name FROM categories WHERE id = p_category_id), 'B')
+        || search_document((
+            SELECT string_agg(tags.name, ' ')
+            FROM transaction_tags JOIN tags ON tags.id = transaction_tags.tag_id AND tags.deleted_at IS NULL
+            WHERE transaction_tags.transaction_id = p_transaction_id
+        ), 'B')
+$$ LANGUAGE sql STABLE;
+
+-- A generated column cannot read categories or tags, so triggers keep the
+-- vector current when any of its sources change
+CREATE OR REPLACE FUNCTION transactions_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := transaction_search_vector(NEW.description, NEW.category_id, NEW.id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_transactions_search_vector
+    BEFORE INSERT OR UPDATE OF description, category_id ON transactions
+    FOR EACH ROW EXECUTE FUNCTION transactions_search_vector_refresh();
+
+CREATE OR REPLACE FUNCTION transaction_tags_search_vector_refresh() RETURNS trigger AS $$
+DECLARE
+    changed uuid;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD.transaction_id;
+    ELSE
+        changed := NEW.transaction_id;
+    END IF;
+    UPDATE transactions SET search_vector = transaction_search_vector(description, category_id, id) WHERE id = changed;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_transaction_tags_search_vector
+    AFTER INSERT OR DELETE ON transaction_tags
+    FOR EACH ROW EXECUTE FUNCTION transaction_tags_search_vector_refresh();
+
+CREATE OR REPLACE FUNCTION tags_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    UPDATE transactions SET search_vector = transaction_search_vector(description, category_id, id)
+    WHERE id IN (SELECT transaction_id FROM transaction_tags WHERE tag_id = NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_tags_search_vector
+    AFTER UPDATE OF name ON tags
+    FOR EACH ROW EXECUTE FUNCTION tags_search_vector_refresh();
+
+CREATE OR REPLACE FUNCTION categories_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    UPDATE transactions SET search_vector = transaction_search_vector(description, category_id, id)
+    WHERE category_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_categories_search_vector
+    AFTER UPDATE OF name ON categories
+    FOR EACH ROW EXECUTE FUNCTION categories_search_vector_refresh();
+
+UPDATE transactions SET search_vector = transaction_search_vector(description, category_id, id);
+
+CREATE INDEX idx_transactions_search_vector ON transactions USING GIN (search_vector);
+
+COMMENT ON COLUMN transactions.search_vector IS 'Full-text document of the description (weight A), category name and tag names (weight B), kept by triggers';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_search_vector;
+
+DROP TRIGGER IF EXISTS trg_categories_search_vector ON categories;
+DROP TRIGGER IF EXISTS trg_tags_search_vector ON tags;
+DROP TRIGGER IF EXISTS trg_transaction_tags_search_vector ON transaction_tags;
+DROP TRIGGER IF EXISTS trg_transactions_search_vector ON transactions;
+
+DROP FUNCTION IF EXISTS categories_search_vector_refresh();
+DROP FUNCTION IF EXISTS tags_search_vector_refresh();
+DROP FUNCTION IF EXISTS transaction_tags_search_vector_refresh();
+DROP FUNCTION IF EXISTS transactions_search_vector_refresh();
+DROP FUNCTION IF EXISTS transaction_search_vector(text, uuid, uuid);
+DROP FUNCTION IF EXISTS search_document(text, "char");
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- No foreign key to transactions: the trail outlives a purged transaction
+CREATE TABLE IF NOT EXISTS transaction_history (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    transaction_id uuid NOT NULL,
+    wallet_id uuid NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255),
+    source VARCHAR(20) NOT NULL,
+    request_id VARCHAR(255),
+    before jsonb,
+    after jsonb
+);
+
+CREATE INDEX idx_transaction_history_transaction_id ON transaction_history(transaction_id, created_at);
+
+CREATE OR REPLACE FUNCTION transaction_history_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'transaction_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transaction_history_no_update_delete
+    BEFORE UPDATE OR DELETE ON transaction_history
+    FOR EACH ROW EXECUTE FUNCTION transaction_history_append_only();
+
+CREATE TRIGGER transaction_history_no_truncate
+    BEFORE TRUNCATE ON transaction_history
+    FOR EACH STATEMENT EXECUTE FUNCTION transaction_history_append_only();
+
+COMMENT ON TABLE transaction_history IS 'Append-only audit trail of every change to a transaction, written in the same database transaction as the change';
+COMMENT ON COLUMN transaction_history.before IS 'Transaction as it was before the change; NULL when it was created';
+COMMENT ON COLUMN transaction_history.after IS 'Transaction as the change left it';
+COMMENT ON COLUMN transaction_history.source IS 'Entry point of the change: http, grpc, consumer or scheduler';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS transaction_history_no_truncate ON transaction_history;
+DROP TRIGGER IF EXISTS transaction_history_no_update_delete ON transaction_history;
+DROP FUNCTION IF EXISTS transaction_history_append_only();
+
+DROP INDEX IF EXISTS idx_transaction_history_transaction_id;
+DROP TABLE IF EXISTS transaction_history;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reconciliations (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    user_id VARCHAR(255) NOT NULL,
+    wallet_id uuid NOT NULL,
+    statement_date DATE NOT NULL,
+    statement_balance numeric(18,2) NOT NULL,
+    opening_balance numeric(18,2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'completed', 'cancelled')),
+    transaction_count integer NOT NULL DEFAULT 0,
+    completed_at timestamptz
+);
+
+CREATE INDEX idx_reconciliations_user ON reconciliations(user_id, created_at DESC) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_reconciliations_open_wallet ON reconciliations(wallet_id) WHERE status = 'open' AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS wallet_settings (
+    wallet_id uuid PRIMARY KEY,
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    balance_stage VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (balance_stage IN ('pending', 'cleared'))
+);
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'cleared' CHECK (status IN ('pending', 'cleared', 'reconciled', 'void')),
+    ADD COLUMN IF NOT EXISTS reconciliation_id uuid REFERENCES reconciliations(id);
+
+CREATE INDEX idx_transactions_wallet_status ON transactions(wallet_id, status) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE reconciliations IS 'Sessions matching the cleared transactions of a wallet against a statement ending balance';
+COMMENT ON COLUMN reconciliations.opening_balance IS 'Sum of the reconciled transactions of the wallet when the session started or completed';
+COMMENT ON TABLE wallet_settings IS 'Per-wallet options; wallets without a row use the column defaults';
+COMMENT ON COLUMN wallet_settings.balance_stage IS 'pending books transactions when entered, cleared leaves pending ones out of the balance';
+COMMENT ON COLUMN transactions.status IS 'pending, cleared, reconciled or void; void transactions never count in balances or reports';
+COMMENT ON COLUMN transactions.reconciliation_id IS 'Reconciliation that marked the transaction reconciled';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_wallet_status;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS reconciliation_id,
+    DROP COLUMN IF EXISTS status;
+
+DROP TABLE IF EXISTS wallet_settings;
+
+DROP INDEX IF EXISTS idx_reconciliations_open_wallet;
+DROP INDEX IF EXISTS idx_reconciliations_user;
+
+DROP TABLE IF EXISTS reconciliations;
+-- +goose StatementEnd
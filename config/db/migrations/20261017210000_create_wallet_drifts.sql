@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS wallet_drifts (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    wallet_id uuid NOT NULL,
+    ledger_balance numeric(18,2) NOT NULL,
+    wallet_balance numeric(18,2) NOT NULL,
+    difference numeric(18,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'corrected', 'resolved')),
+    adjustment_transaction_id uuid REFERENCES transactions(id) ON DELETE SET NULL,
+    checked_at timestamptz NOT NULL DEFAULT now(),
+    resolved_at timestamptz
+);
+
+CREATE INDEX idx_wallet_drifts_wallet ON wallet_drifts(wallet_id, created_at DESC) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_wallet_drifts_open_wallet ON wallet_drifts(wallet_id) WHERE status = 'open' AND deleted_at IS NULL;
+
+-- Categories of the ledger entries that correct a drift, one per direction
+INSERT INTO categories (id, parent_id, name, type) VALUES
+('00000000-0000-0000-0000-000000000020', NULL, 'Penyesuaian Saldo', 'income'),
+('00000000-0000-0000-0000-000000000021', NULL, 'Penyesuaian Saldo', 'expense')
+ON CONFLICT (id) DO NOTHING;
+
+COMMENT ON TABLE wallet_drifts IS 'Differences found between the ledger sum of a wallet and its balance in wallet-service';
+COMMENT ON COLUMN wallet_drifts.difference IS 'wallet_balance minus ledger_balance';
+COMMENT ON COLUMN wallet_drifts.status IS 'open until the balances agree again (resolved) or an adjustment transaction is booked (corrected)';
+COMMENT ON COLUMN wallet_drifts.checked_at IS 'Last check that found this difference';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_wallet_drifts_open_wallet;
+DROP INDEX IF EXISTS idx_wallet_drifts_wallet;
+
+DROP TABLE IF EXISTS wallet_drifts;
+
+-- Adjustment categories stay while transactions reference them
+DELETE FROM categories
+WHERE id IN ('00000000-0000-0000-0000-000000000020', '00000000-0000-0000-0000-000000000021')
+  AND NOT EXISTS (SELECT 1 FROM transactions WHERE category_id = categories.id);
+-- +goose StatementEnd
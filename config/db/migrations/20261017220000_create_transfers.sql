@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS transfers (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    from_wallet_id uuid NOT NULL,
+    to_wallet_id uuid NOT NULL,
+    cash_out_transaction_id uuid NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    cash_in_transaction_id uuid NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    amount numeric(18,2) NOT NULL,
+    admin_fee numeric(18,2) NOT NULL DEFAULT 0,
+    transfer_date timestamp NOT NULL,
+    description text
+);
+
+CREATE INDEX idx_transfers_from_wallet ON transfers(from_wallet_id, transfer_date DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transfers_to_wallet ON transfers(to_wallet_id, transfer_date DESC) WHERE deleted_at IS NULL;
+
+-- Deferred: the legs are inserted before the transfer that links them
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS transfer_id uuid REFERENCES transfers(id) DEFERRABLE INITIALLY DEFERRED;
+
+CREATE INDEX idx_transactions_transfer_id ON transactions(transfer_id) WHERE transfer_id IS NOT NULL;
+
+COMMENT ON TABLE transfers IS 'Fund transfers between two wallets, linking the cash-out and cash-in transactions';
+COMMENT ON COLUMN transfers.admin_fee IS 'Fee paid by the source wallet on top of amount';
+COMMENT ON COLUMN transactions.transfer_id IS 'Transfer this transaction is a leg of; legs are changed through the transfer. Transfers made before this column existed are not linked';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_transfer_id;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS transfer_id;
+
+DROP INDEX IF EXISTS idx_transfers_to_wallet;
+DROP INDEX IF EXISTS idx_transfers_from_wallet;
+
+DROP TABLE IF EXISTS transfers;
+-- +goose StatementEnd
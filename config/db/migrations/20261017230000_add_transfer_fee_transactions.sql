@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO categories (id, parent_id, name, type) VALUES
+('00000000-0000-0000-0000-000000000022', NULL, 'Biaya Admin', 'expense')
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE transfers
+    ADD COLUMN IF NOT EXISTS fee_transaction_id uuid REFERENCES transactions(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN transfers.fee_transaction_id IS 'Expense transaction booking admin_fee on the source wallet; transfers made before it existed folded the fee into the cash-out leg';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transfers DROP COLUMN IF EXISTS fee_transaction_id;
+
+-- The fee category stays while transactions reference it
+DELETE FROM categories
+WHERE id = '00000000-0000-0000-0000-000000000022'
+  AND NOT EXISTS (SELECT 1 FROM transactions WHERE category_id = categories.id);
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transfers
+    ADD COLUMN IF NOT EXISTS to_amount numeric(18,2),
+    ADD COLUMN IF NOT EXISTS exchange_rate numeric(24,10) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+
+-- Transfers so far were between wallets of one currency
+UPDATE transfers SET to_amount = amount WHERE to_amount IS NULL;
+
+ALTER TABLE transfers ALTER COLUMN to_amount SET NOT NULL;
+
+COMMENT ON COLUMN transfers.amount IS 'Amount leaving the source wallet, in its currency';
+COMMENT ON COLUMN transfers.to_amount IS 'Amount credited to the destination wallet, in its currency; equal to amount between wallets of one currency';
+COMMENT ON COLUMN transfers.exchange_rate IS 'What one unit of the source currency was exchanged for in the destination currency; 1 between wallets of one currency';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+COMMENT ON COLUMN transfers.amount IS NULL;
+
+ALTER TABLE transfers
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS to_amount;
+-- +goose StatementEnd
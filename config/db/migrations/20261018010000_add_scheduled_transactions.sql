@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS scheduled boolean NOT NULL DEFAULT false;
+
+-- The posting worker looks for due items only
+CREATE INDEX idx_transactions_scheduled_due ON transactions(transaction_date) WHERE scheduled AND deleted_at IS NULL;
+
+COMMENT ON COLUMN transactions.scheduled IS 'Future-dated transaction waiting for its date; it counts in no balance, budget or report until it is posted';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_scheduled_due;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS scheduled;
+-- +goose StatementEnd
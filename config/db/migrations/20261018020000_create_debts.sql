@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS debts (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    deleted_at timestamptz,
+    wallet_id uuid NOT NULL,
+    counterparty VARCHAR(100) NOT NULL,
+    direction VARCHAR(20) NOT NULL CHECK (direction IN ('payable', 'receivable')),
+    principal numeric(18,2) NOT NULL CHECK (principal > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    debt_date timestamp NOT NULL,
+    due_date timestamp,
+    description text,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'settled')),
+    settled_at timestamptz,
+    principal_transaction_id uuid REFERENCES transactions(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_debts_wallet ON debts(wallet_id, debt_date DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_debts_overdue ON debts(due_date) WHERE status = 'open' AND deleted_at IS NULL;
+
+-- Deferred: the principal is inserted before the debt that links it
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS debt_id uuid REFERENCES debts(id) DEFERRABLE INITIALLY DEFERRED;
+
+CREATE INDEX idx_transactions_debt_id ON transactions(debt_id) WHERE debt_id IS NOT NULL;
+
+-- Categories of the money a debt moves, by direction and by who pays
+INSERT INTO categories (id, parent_id, name, type) VALUES
+('00000000-0000-0000-0000-000000000030', NULL, 'Pinjaman Diterima', 'income'),
+('00000000-0000-0000-0000-000000000031', NULL, 'Pembayaran Hutang', 'expense'),
+('00000000-0000-0000-0000-000000000032', NULL, 'Pinjaman Diberikan', 'expense'),
+('00000000-0000-0000-0000-000000000033', NULL, 'Pelunasan Piutang', 'income')
+ON CONFLICT (id) DO NOTHING;
+
+COMMENT ON TABLE debts IS 'Money owed to (payable, hutang) or by (receivable, piutang) a counterparty, repaid in one or more transactions';
+COMMENT ON COLUMN debts.currency IS 'Currency of the wallet the debt was recorded on; repayments are made in it';
+COMMENT ON COLUMN debts.status IS 'open until the repayments add up to principal (settled)';
+COMMENT ON COLUMN debts.principal_transaction_id IS 'Transaction that moved the principal, null for a debt recorded without moving money';
+COMMENT ON COLUMN transactions.debt_id IS 'Debt this transaction lent, borrowed or repaid; such transactions are changed through the debt';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_debt_id;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS debt_id;
+
+DROP INDEX IF EXISTS idx_debts_overdue;
+DROP INDEX IF EXISTS idx_debts_wallet;
+
+DROP TABLE IF EXISTS debts;
+
+-- Debt categories stay while transactions reference them
+DELETE FROM categories
+WHERE id IN ('00000000-0000-0000-0000-000000000030', '00000000-0000-0000-0000-000000000031',
+             '00000000-0000-0000-0000-000000000032', '00000000-0000-0000-0000-000000000033')
+  AND NOT EXISTS (SELECT 1 FROM transactions WHERE category_id = categories.id);
+-- +goose StatementEnd
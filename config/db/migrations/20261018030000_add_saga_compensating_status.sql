@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Sagas are claimed before they are compensated, and a claim left behind by
+-- a stopped compensator is resumed like a started saga
+DROP INDEX IF EXISTS idx_saga_logs_incomplete;
+CREATE INDEX idx_saga_logs_incomplete ON saga_logs(status, created_at) WHERE status IN ('started', 'compensating') AND deleted_at IS NULL;
+
+COMMENT ON COLUMN saga_logs.status IS 'started, completed (local commit succeeded), compensating (claimed for compensation), compensated or failed (needs manual review)';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE saga_logs SET status = 'started' WHERE status = 'compensating';
+
+DROP INDEX IF EXISTS idx_saga_logs_incomplete;
+CREATE INDEX idx_saga_logs_incomplete ON saga_logs(status, created_at) WHERE status = 'started' AND deleted_at IS NULL;
+
+COMMENT ON COLUMN saga_logs.status IS 'started, completed (local commit succeeded), compensated or failed (needs manual review)';
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS post_failure_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS post_error text,
+    ADD COLUMN IF NOT EXISTS post_retry_at timestamp;
+
+COMMENT ON COLUMN transactions.post_failure_count IS 'Consecutive failed attempts at posting a due scheduled transaction, cleared once it posts';
+COMMENT ON COLUMN transactions.post_error IS 'Error of the last failed attempt at posting the scheduled transaction';
+COMMENT ON COLUMN transactions.post_retry_at IS 'Earliest time the poster retries a scheduled transaction that failed to post';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS post_retry_at,
+    DROP COLUMN IF EXISTS post_error,
+    DROP COLUMN IF EXISTS post_failure_count;
+-- +goose StatementEnd
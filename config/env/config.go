@@ -44,12 +44,21 @@ type (
 		RMQVirtualHost string `env:"RABBITMQ_VIRTUAL_HOST"`
 	}
 
+	Auth struct {
+		JWTAlgorithm string `env:"JWT_ALGORITHM"`
+		JWTSecret    string `env:"JWT_SECRET"`
+		JWKSFile     string `env:"JWT_JWKS_FILE"`
+		JWTIssuer    string `env:"JWT_ISSUER"`
+		JWTAudience  string `env:"JWT_AUDIENCE"`
+	}
+
 	Config struct {
 		Server     Server
 		Database   Database
 		Minio      Minio
 		GRPCConfig GRPCConfig
 		RabbitMQ   RabbitMQ
+		Auth       Auth
 	}
 )
 
@@ -147,6 +156,17 @@ func LoadNative() ([]string, error) {
 	}
 	// ! ______________________________________________________
 
+	// ! Load Auth configuration ______________________________
+	if Cfg.Auth.JWTAlgorithm, ok = os.LookupEnv("JWT_ALGORITHM"); !ok {
+		missing = append(missing, "JWT_ALGORITHM env is not set")
+	}
+	Cfg.Auth.JWTSecret = os.Getenv("JWT_SECRET")
+	Cfg.Auth.JWKSFile = os.Getenv("JWT_JWKS_FILE")
+	Cfg.Auth.JWTIssuer = os.Getenv("JWT_ISSUER")
+	Cfg.Auth.JWTAudience = os.Getenv("JWT_AUDIENCE")
+	missing = append(missing, validateAuth(Cfg.Auth, "JWT_SECRET", "JWT_JWKS_FILE")...)
+	// ! ______________________________________________________
+
 	return missing, nil
 }
 
@@ -236,5 +256,36 @@ func LoadByViper() ([]string, error) {
 	}
 	// ! ______________________________________________________
 
+	// ! Load Auth configuration ______________________________
+	if Cfg.Auth.JWTAlgorithm = config.GetString("AUTH.JWT.ALGORITHM"); Cfg.Auth.JWTAlgorithm == "" {
+		missing = append(missing, "AUTH.JWT.ALGORITHM env is not set")
+	}
+	Cfg.Auth.JWTSecret = config.GetString("AUTH.JWT.SECRET")
+	Cfg.Auth.JWKSFile = config.GetString("AUTH.JWT.JWKS_FILE")
+	Cfg.Auth.JWTIssuer = config.GetString("AUTH.JWT.ISSUER")
+	Cfg.Auth.JWTAudience = config.GetString("AUTH.JWT.AUDIENCE")
+	missing = append(missing, validateAuth(Cfg.Auth, "AUTH.JWT.SECRET", "AUTH.JWT.JWKS_FILE")...)
+	// ! ______________________________________________________
+
 	return missing, nil
 }
+
+// validateAuth checks that the key material required by the configured JWT
+// algorithm is present. Issuer and audience are optional.
+func validateAuth(auth Auth, secretKey, jwksKey string) []string {
+	switch auth.JWTAlgorithm {
+	case "":
+		return nil
+	case "HS256":
+		if auth.JWTSecret == "" {
+			return []string{secretKey + " env is not set"}
+		}
+	case "RS256":
+		if auth.JWKSFile == "" {
+			return []string{jwksKey + " env is not set"}
+		}
+	default:
+		return []string{fmt.Sprintf("JWT algorithm must be HS256 or RS256, got %s", auth.JWTAlgorithm)}
+	}
+	return nil
+}
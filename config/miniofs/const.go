@@ -3,4 +3,5 @@ package miniofs
 const (
 	TRANSACTION_ATTACHMENT_BUCKET = "refina-transaction-attachments"
 	TRANSACTION_ATTACHMENT_PREFIX = "transaction_attachments"
+	TRANSACTION_STATEMENT_PREFIX  = "transaction_statements"
 )
\ No newline at end of file
@@ -2,14 +2,102 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
 	wpb "github.com/MuhammadMiftaa/Refina-Protobuf/wallet"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Wallet-service contract for AdjustBalance. UpdateWalletRequest has no
+// version or delta field, so both guarantees travel as gRPC metadata and are
+// only as strong as wallet-service's handling of them:
+//
+//   - if-match: wallet-service must compare it with the stored updated_at and
+//     reject the write with Aborted or FailedPrecondition when they differ.
+//     Without that check two replicas can still overwrite each other.
+//   - x-idempotency-key: wallet-service must apply a key at most once and
+//     answer a repeated key with the original result. Saga compensation
+//     relies on this to replay pending steps.
+//
+// walletLocks only serialises adjustments inside this process.
+const (
+	// MDKeyIfMatch carries the wallet version (its updated_at) the new balance
+	// was computed from, so wallet-service can reject stale writes.
+	MDKeyIfMatch = "if-match"
+	// MDKeyIdempotencyKey lets wallet-service deduplicate a retried adjustment.
+	MDKeyIdempotencyKey = "x-idempotency-key"
 )
 
+// ErrBalanceConflict is returned when a balance adjustment keeps losing the
+// version check after all retries.
+var ErrBalanceConflict = errors.New("wallet balance conflict")
+
 type WalletClient interface {
 	GetWalletByID(ctx context.Context, walletID string) (*wpb.Wallet, error)
+	GetUserWallets(ctx context.Context, userID string) ([]*wpb.Wallet, error)
 	UpdateWallet(ctx context.Context, wallet *wpb.Wallet) (*wpb.Wallet, error)
+	AdjustBalance(ctx context.Context, walletID string, delta money.Amount, idempotencyKey string) (*wpb.Wallet, error)
+}
+
+// WalletBalance reads the float64 balance of a wallet message as an exact
+// amount. wallet-service keeps two decimals, so rounding to the cent recovers
+// the stored balance.
+func WalletBalance(wallet *wpb.Wallet) money.Amount {
+	return money.FromFloat(wallet.GetBalance())
+}
+
+// walletLock is a per-wallet semaphore shared by every client instance in
+// this process, e.g. the investment consumer and the HTTP handlers. It is
+// dropped from walletLocks once nobody holds or waits for it.
+type walletLock struct {
+	sem  chan struct{}
+	refs int
+}
+
+var (
+	walletLocksMu sync.Mutex
+	walletLocks   = map[string]*walletLock{}
+)
+
+// lockWallet waits for the wallet's lock until ctx is done and returns the
+// function that releases it.
+func lockWallet(ctx context.Context, walletID string) (func(), error) {
+	walletLocksMu.Lock()
+	lock, ok := walletLocks[walletID]
+	if !ok {
+		lock = &walletLock{sem: make(chan struct{}, 1)}
+		walletLocks[walletID] = lock
+	}
+	lock.refs++
+	walletLocksMu.Unlock()
+
+	release := func() {
+		walletLocksMu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(walletLocks, walletID)
+		}
+		walletLocksMu.Unlock()
+	}
+
+	select {
+	case lock.sem <- struct{}{}:
+		return func() {
+			<-lock.sem
+			release()
+		}, nil
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
 }
 
 type walletClientImpl struct {
@@ -33,6 +121,18 @@ func (w *walletClientImpl) GetWalletByID(ctx context.Context, walletID string) (
 	return w.client.GetWalletByID(ctx, req)
 }
 
+func (w *walletClientImpl) GetUserWallets(ctx context.Context, userID string) ([]*wpb.Wallet, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := w.client.GetUserWallets(ctx, &wpb.UserID{Id: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.GetWallets(), nil
+}
+
 // UpdateWallet converts the full Wallet to an UpdateWalletRequest (which now
 // includes balance) and sends it to the wallet-service.
 func (w *walletClientImpl) UpdateWallet(ctx context.Context, wallet *wpb.Wallet) (*wpb.Wallet, error) {
@@ -49,3 +149,60 @@ func (w *walletClientImpl) UpdateWallet(ctx context.Context, wallet *wpb.Wallet)
 
 	return w.client.UpdateWallet(ctx, req)
 }
+
+// AdjustBalance applies delta to the wallet balance. wallet-service only
+// exposes an absolute UpdateWallet, so the balance is read, shifted by delta
+// and written back guarded by the version it was read at (see the contract
+// above). A rejected version is retried against a fresh read up to
+// WALLET_ADJUST_MAX_RETRIES times.
+func (w *walletClientImpl) AdjustBalance(ctx context.Context, walletID string, delta money.Amount, idempotencyKey string) (*wpb.Wallet, error) {
+	unlock, err := lockWallet(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("lock wallet [wallet_id=%s]: %w", walletID, err)
+	}
+	defer unlock()
+
+	var lastErr error
+	for attempt := 0; attempt < data.WALLET_ADJUST_MAX_RETRIES; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt) * data.WALLET_ADJUST_RETRY_BACKOFF):
+			}
+		}
+
+		wallet, err := w.GetWalletByID(ctx, walletID)
+		if err != nil {
+			return nil, err
+		}
+
+		version := wallet.GetUpdatedAt()
+		// Shift in cents so repeated adjustments do not drift
+		wallet.Balance = WalletBalance(wallet).Add(delta).Float64()
+
+		updateCtx := metadata.AppendToOutgoingContext(ctx,
+			MDKeyIfMatch, version,
+			MDKeyIdempotencyKey, idempotencyKey,
+		)
+		updated, err := w.UpdateWallet(updateCtx, wallet)
+		if err == nil {
+			return updated, nil
+		}
+		if !isVersionConflict(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("%w [wallet_id=%s]: %v", ErrBalanceConflict, walletID, lastErr)
+}
+
+func isVersionConflict(err error) bool {
+	switch status.Code(err) {
+	case codes.Aborted, codes.FailedPrecondition:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	wpb "github.com/MuhammadMiftaa/Refina-Protobuf/wallet"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeWalletService serves GetWalletByID from balance and answers each
+// UpdateWallet with the next entry of updateErrs (nil once they run out).
+type fakeWalletService struct {
+	wpb.WalletServiceClient
+
+	mu         sync.Mutex
+	balance    float64
+	version    int
+	updateErrs []error
+	updates    []*wpb.UpdateWalletRequest
+	ifMatch    []string
+	keys       []string
+}
+
+func (f *fakeWalletService) GetWalletByID(ctx context.Context, in *wpb.WalletID, opts ...grpc.CallOption) (*wpb.Wallet, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &wpb.Wallet{Id: in.GetId(), Balance: f.balance, UpdatedAt: time.Unix(int64(f.version), 0).UTC().Format(time.RFC3339)}, nil
+}
+
+func (f *fakeWalletService) UpdateWallet(ctx context.Context, in *wpb.UpdateWalletRequest, opts ...grpc.CallOption) (*wpb.Wallet, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	f.updates = append(f.updates, in)
+	f.ifMatch = append(f.ifMatch, md.Get(MDKeyIfMatch)...)
+	f.keys = append(f.keys, md.Get(MDKeyIdempotencyKey)...)
+
+	if len(f.updateErrs) > 0 {
+		err := f.updateErrs[0]
+		f.updateErrs = f.updateErrs[1:]
+		if err != nil {
+			// a conflicting writer moved the wallet on in the meantime
+			f.version++
+			return nil, err
+		}
+	}
+
+	f.balance = in.GetBalance()
+	f.version++
+	return &wpb.Wallet{Id: in.GetId(), Balance: f.balance}, nil
+}
+
+const testWalletID = "22222222-2222-2222-2222-222222222222"
+
+func TestAdjustBalance_AppliesDeltaWithVersionAndKey(t *testing.T) {
+	svc := &fakeWalletService{balance: 200000}
+	c := NewWalletClient(svc)
+
+	wallet, err := c.AdjustBalance(context.Background(), testWalletID, money.New(-50000), "step-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(150000), wallet.GetBalance())
+	assert.Len(t, svc.updates, 1)
+	assert.Equal(t, []string{time.Unix(0, 0).UTC().Format(time.RFC3339)}, svc.ifMatch)
+	assert.Equal(t, []string{"step-1"}, svc.keys)
+}
+
+func TestAdjustBalance_RetriesOnVersionConflict(t *testing.T) {
+	svc := &fakeWalletService{
+		balance:    200000,
+		updateErrs: []error{status.Error(codes.Aborted, "version mismatch")},
+	}
+	c := NewWalletClient(svc)
+
+	wallet, err := c.AdjustBalance(context.Background(), testWalletID, money.New(10000), "step-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(210000), wallet.GetBalance())
+	assert.Len(t, svc.updates, 2)
+	// the retry is guarded by the version read after the conflict
+	assert.NotEqual(t, svc.ifMatch[0], svc.ifMatch[1])
+	assert.Equal(t, []string{"step-1", "step-1"}, svc.keys)
+}
+
+func TestAdjustBalance_GivesUpAfterMaxRetries(t *testing.T) {
+	conflicts := make([]error, data.WALLET_ADJUST_MAX_RETRIES)
+	for i := range conflicts {
+		conflicts[i] = status.Error(codes.FailedPrecondition, "version mismatch")
+	}
+	svc := &fakeWalletService{balance: 200000, updateErrs: conflicts}
+	c := NewWalletClient(svc)
+
+	wallet, err := c.AdjustBalance(context.Background(), testWalletID, money.New(10000), "step-1")
+
+	assert.Nil(t, wallet)
+	assert.ErrorIs(t, err, ErrBalanceConflict)
+	assert.Len(t, svc.updates, data.WALLET_ADJUST_MAX_RETRIES)
+	assert.Equal(t, float64(200000), svc.balance)
+}
+
+func TestAdjustBalance_OtherErrorIsNotRetried(t *testing.T) {
+	svc := &fakeWalletService{
+		balance:    200000,
+		updateErrs: []error{status.Error(codes.Unavailable, "connection refused")},
+	}
+	c := NewWalletClient(svc)
+
+	wallet, err := c.AdjustBalance(context.Background(), testWalletID, money.New(10000), "step-1")
+
+	assert.Nil(t, wallet)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.NotErrorIs(t, err, ErrBalanceConflict)
+	assert.Len(t, svc.updates, 1)
+}
+
+func TestAdjustBalance_LockWaitHonoursContext(t *testing.T) {
+	unlock, err := lockWallet(context.Background(), testWalletID)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	svc := &fakeWalletService{balance: 200000}
+	wallet, err := NewWalletClient(svc).AdjustBalance(ctx, testWalletID, money.New(10000), "step-1")
+
+	assert.Nil(t, wallet)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Empty(t, svc.updates)
+
+	unlock()
+	walletLocksMu.Lock()
+	defer walletLocksMu.Unlock()
+	// nobody holds or waits for the lock any more, so it is dropped
+	assert.NotContains(t, walletLocks, testWalletID)
+}
@@ -3,6 +3,9 @@ package interceptor
 import (
 	"context"
 
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
@@ -13,6 +16,8 @@ const (
 	MDKeyUserEmail      = "x-user-email"
 	MDKeyUserProvider   = "x-user-provider"
 	MDKeyProviderUserID = "x-provider-user-id"
+	MDKeyUserRole       = "x-user-role"
+	MDKeyIdempotencyKey = "x-idempotency-key"
 )
 
 // ── context keys ──
@@ -22,10 +27,42 @@ type (
 	userEmailKey      struct{}
 	userProviderKey   struct{}
 	providerUserIDKey struct{}
+	userRoleKey       struct{}
 )
 
+// UserMetadata is the caller identity shared by the gRPC interceptor and the
+// HTTP auth middleware.
+type UserMetadata struct {
+	UserID         string
+	Email          string
+	Provider       string
+	ProviderUserID string
+	Role           string
+}
+
 // ── context helpers ──
 
+// WithUserMetadata stores the non-empty identity fields in the context so they
+// can be read back with the *FromContext helpers.
+func WithUserMetadata(ctx context.Context, user UserMetadata) context.Context {
+	if user.UserID != "" {
+		ctx = context.WithValue(ctx, userIDKey{}, user.UserID)
+	}
+	if user.Email != "" {
+		ctx = context.WithValue(ctx, userEmailKey{}, user.Email)
+	}
+	if user.Provider != "" {
+		ctx = context.WithValue(ctx, userProviderKey{}, user.Provider)
+	}
+	if user.ProviderUserID != "" {
+		ctx = context.WithValue(ctx, providerUserIDKey{}, user.ProviderUserID)
+	}
+	if user.Role != "" {
+		ctx = context.WithValue(ctx, userRoleKey{}, user.Role)
+	}
+	return ctx
+}
+
 // UserIDFromContext returns the user ID injected by the server interceptor.
 func UserIDFromContext(ctx context.Context) string {
 	v, _ := ctx.Value(userIDKey{}).(string)
@@ -50,6 +87,12 @@ func ProviderUserIDFromContext(ctx context.Context) string {
 	return v
 }
 
+// UserRoleFromContext returns the user role injected by the server interceptor.
+func UserRoleFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(userRoleKey{}).(string)
+	return v
+}
+
 // ── interceptors ──
 
 // UnaryServerInterceptor extracts user metadata from incoming gRPC metadata
@@ -81,31 +124,26 @@ func StreamServerInterceptor() grpc.StreamServerInterceptor {
 	}
 }
 
-// extractUserMetadata reads the x-user-* keys from incoming gRPC metadata
-// and stores them in the context.
+// extractUserMetadata reads the x-user-* keys and the request ID from
+// incoming gRPC metadata and stores them in the context.
 func extractUserMetadata(ctx context.Context) context.Context {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return ctx
+		return helper.WithRequestSource(ctx, data.REQUEST_SOURCE_GRPC, "")
 	}
 
-	userID := firstValue(md, MDKeyUserID)
-	email := firstValue(md, MDKeyUserEmail)
-	provider := firstValue(md, MDKeyUserProvider)
-	providerUID := firstValue(md, MDKeyProviderUserID)
+	ctx = helper.WithRequestSource(ctx, data.REQUEST_SOURCE_GRPC, firstValue(md, data.REQUEST_ID_METADATA_KEY))
 
-	if userID != "" {
-		ctx = context.WithValue(ctx, userIDKey{}, userID)
-	}
-	if email != "" {
-		ctx = context.WithValue(ctx, userEmailKey{}, email)
-	}
-	if provider != "" {
-		ctx = context.WithValue(ctx, userProviderKey{}, provider)
-	}
-	if providerUID != "" {
-		ctx = context.WithValue(ctx, providerUserIDKey{}, providerUID)
-	}
+	ctx = WithUserMetadata(ctx, UserMetadata{
+		UserID:         firstValue(md, MDKeyUserID),
+		Email:          firstValue(md, MDKeyUserEmail),
+		Provider:       firstValue(md, MDKeyUserProvider),
+		ProviderUserID: firstValue(md, MDKeyProviderUserID),
+		Role:           firstValue(md, MDKeyUserRole),
+	})
+
+	// Retried create RPCs carry the same key so the service can replay them
+	ctx = helper.WithIdempotencyKey(ctx, firstValue(md, MDKeyIdempotencyKey))
 
 	return ctx
 }
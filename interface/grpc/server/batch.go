@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const batchServiceName = "transaction.BatchService"
+
+// batchServiceServer is the server API of transaction.BatchService. The RPC
+// takes and returns a google.protobuf.Struct shaped like the
+// /transactions/batch HTTP bodies.
+type batchServiceServer interface {
+	BatchTransactions(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+}
+
+var batchServiceDesc = grpc.ServiceDesc{
+	ServiceName: batchServiceName,
+	HandlerType: (*batchServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		structMethod(batchServiceName, "BatchTransactions", batchServiceServer.BatchTransactions),
+	},
+	Metadata: "batch.go",
+}
+
+type batchServer struct {
+	transactionService   service.TransactionsService
+	authorizationService service.AuthorizationService
+}
+
+// BatchTransactions answers a batch with a failed item with its per-item
+// results and "applied": false rather than an error status, so that the
+// caller can tell which items to fix.
+func (s *batchServer) BatchTransactions(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in dto.BatchTransactionsRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizationService.AuthorizeBatch(ctx, userID, in); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	batch, err := s.transactionService.BatchTransactions(ctx, in)
+	if err != nil {
+		log.Error(data.LogBatchTransactionsFailed, map[string]any{
+			"service": data.GRPCServerService,
+			"user_id": userID,
+			"items":   len(in.Items),
+			"error":   err.Error(),
+		})
+		switch {
+		case errors.Is(err, service.ErrInvalidBatch) && len(batch.Results) > 0:
+			return encodeStruct(batch)
+		case errors.Is(err, service.ErrInvalidBatch):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, service.ErrIdempotencyKeyReused):
+			return nil, status.Error(codes.AlreadyExists, "idempotency key already used for a different request")
+		}
+		return nil, fmt.Errorf("batch transactions: %w", err)
+	}
+
+	return encodeStruct(batch)
+}
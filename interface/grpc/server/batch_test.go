@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeBatchService struct {
+	service.TransactionsService
+
+	request dto.BatchTransactionsRequest
+	err     error
+}
+
+func (f *fakeBatchService) BatchTransactions(ctx context.Context, request dto.BatchTransactionsRequest) (dto.BatchTransactionsResponse, error) {
+	f.request = request
+	if f.err != nil {
+		return dto.BatchTransactionsResponse{Results: []dto.BatchTransactionResult{
+			{Index: 0, Action: "create", Status: "failed", Error: "category not found"},
+		}}, f.err
+	}
+	return dto.BatchTransactionsResponse{Applied: true, Results: []dto.BatchTransactionResult{
+		{Index: 0, Action: "create", ID: "txn-9", Status: "applied"},
+	}}, nil
+}
+
+// AuthorizeBatch lets user-1 write to wallet-1 only.
+func (fakeAuthorization) AuthorizeBatch(ctx context.Context, userID string, request dto.BatchTransactionsRequest) error {
+	if userID == "" {
+		return service.ErrUnauthenticated
+	}
+	for _, item := range request.Items {
+		if item.Transaction.WalletID != "" && item.Transaction.WalletID != "wallet-1" {
+			return service.ErrPermissionDenied
+		}
+	}
+	return nil
+}
+
+func dialBatchServer(t *testing.T, transactions service.TransactionsService) *grpc.ClientConn {
+	t.Helper()
+	return dialServer(t, func(s *grpc.Server) {
+		s.RegisterService(&batchServiceDesc, &batchServer{
+			transactionService:   transactions,
+			authorizationService: fakeAuthorization{},
+		})
+	})
+}
+
+func batchBody(walletID string) map[string]any {
+	return map[string]any{"items": []any{
+		map[string]any{"action": "create", "transaction": map[string]any{"wallet_id": walletID, "amount": "1000"}},
+	}}
+}
+
+func TestBatchService_Applies(t *testing.T) {
+	batch := &fakeBatchService{}
+	conn := dialBatchServer(t, batch)
+
+	out, err := invokeStruct(asUser("user-1"), conn, batchServiceName, "BatchTransactions", batchBody("wallet-1"))
+
+	assert.NoError(t, err)
+	if assert.Len(t, batch.request.Items, 1) {
+		assert.Equal(t, "wallet-1", batch.request.Items[0].Transaction.WalletID)
+	}
+	assert.True(t, out.GetFields()["applied"].GetBoolValue())
+	results := out.GetFields()["results"].GetListValue().GetValues()
+	assert.Equal(t, "txn-9", results[0].GetStructValue().GetFields()["id"].GetStringValue())
+}
+
+func TestBatchService_ReturnsItemResultsWhenRejected(t *testing.T) {
+	batch := &fakeBatchService{err: fmt.Errorf("%w: 1 of 1 items failed", service.ErrInvalidBatch)}
+	conn := dialBatchServer(t, batch)
+
+	out, err := invokeStruct(asUser("user-1"), conn, batchServiceName, "BatchTransactions", batchBody("wallet-1"))
+
+	assert.NoError(t, err)
+	assert.False(t, out.GetFields()["applied"].GetBoolValue())
+	results := out.GetFields()["results"].GetListValue().GetValues()
+	assert.Equal(t, "category not found", results[0].GetStructValue().GetFields()["error"].GetStringValue())
+}
+
+func TestBatchService_RejectsForeignWallet(t *testing.T) {
+	batch := &fakeBatchService{}
+	conn := dialBatchServer(t, batch)
+
+	_, err := invokeStruct(asUser("user-1"), conn, batchServiceName, "BatchTransactions", batchBody("wallet-2"))
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Empty(t, batch.request.Items)
+}
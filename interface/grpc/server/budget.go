@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const budgetServiceName = "transaction.BudgetService"
+
+// budgetServiceServer is the server API of transaction.BudgetService. Every
+// RPC takes and returns a google.protobuf.Struct shaped like the /budgets
+// HTTP bodies.
+type budgetServiceServer interface {
+	ListBudgets(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	GetBudget(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	CreateBudget(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	UpdateBudget(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	DeleteBudget(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+}
+
+var budgetServiceDesc = grpc.ServiceDesc{
+	ServiceName: budgetServiceName,
+	HandlerType: (*budgetServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		structMethod(budgetServiceName, "ListBudgets", budgetServiceServer.ListBudgets),
+		structMethod(budgetServiceName, "GetBudget", budgetServiceServer.GetBudget),
+		structMethod(budgetServiceName, "CreateBudget", budgetServiceServer.CreateBudget),
+		structMethod(budgetServiceName, "UpdateBudget", budgetServiceServer.UpdateBudget),
+		structMethod(budgetServiceName, "DeleteBudget", budgetServiceServer.DeleteBudget),
+	},
+	Metadata: "budget.go",
+}
+
+// budgetServer relies on the budget service for authorization: every method
+// takes the caller and only touches budgets the caller owns.
+type budgetServer struct {
+	budgetService service.BudgetsService
+}
+
+type budgetIDRequest struct {
+	ID string `json:"id"`
+}
+
+type updateBudgetRequest struct {
+	ID string `json:"id"`
+	dto.BudgetsRequest
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Budget RPCs
+// ──────────────────────────────────────────────────────────────────────────────
+
+func (s *budgetServer) ListBudgets(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	budgets, err := s.budgetService.GetBudgets(ctx, userID)
+	if err != nil {
+		return nil, budgetError(userID, "", data.LogGetBudgetsFailed, "get budgets", err)
+	}
+
+	return encodeStruct(budgets)
+}
+
+func (s *budgetServer) GetBudget(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in budgetIDRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	budget, err := s.budgetService.GetBudgetByID(ctx, userID, in.ID)
+	if err != nil {
+		return nil, budgetError(userID, in.ID, data.LogGetBudgetByIDFailed, "get budget", err)
+	}
+
+	return encodeStruct(budget)
+}
+
+func (s *budgetServer) CreateBudget(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in dto.BudgetsRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	budget, err := s.budgetService.CreateBudget(ctx, userID, in)
+	if err != nil {
+		return nil, budgetError(userID, "", data.LogCreateBudgetFailed, "create budget", err)
+	}
+
+	return encodeStruct(budget)
+}
+
+func (s *budgetServer) UpdateBudget(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in updateBudgetRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	budget, err := s.budgetService.UpdateBudget(ctx, userID, in.ID, in.BudgetsRequest)
+	if err != nil {
+		return nil, budgetError(userID, in.ID, data.LogUpdateBudgetFailed, "update budget", err)
+	}
+
+	return encodeStruct(budget)
+}
+
+func (s *budgetServer) DeleteBudget(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in budgetIDRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	budget, err := s.budgetService.DeleteBudget(ctx, userID, in.ID)
+	if err != nil {
+		return nil, budgetError(userID, in.ID, data.LogDeleteBudgetFailed, "delete budget", err)
+	}
+
+	return encodeStruct(budget)
+}
+
+func budgetError(userID, budgetID, logMsg, action string, err error) error {
+	if isAuthorizationError(err) {
+		return authorizationError(userID, err)
+	}
+
+	log.Error(logMsg, map[string]any{
+		"service":   data.GRPCServerService,
+		"user_id":   userID,
+		"budget_id": budgetID,
+		"error":     err.Error(),
+	})
+	return fmt.Errorf("%s [id=%s]: %w", action, budgetID, err)
+}
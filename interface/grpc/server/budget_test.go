@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/money"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeBudgetService owns budget-1 for user-1, like the real service checks.
+type fakeBudgetService struct {
+	service.BudgetsService
+
+	updated dto.BudgetsRequest
+}
+
+func (f *fakeBudgetService) GetBudgets(ctx context.Context, userID string) ([]dto.BudgetsResponse, error) {
+	if userID == "" {
+		return nil, service.ErrUnauthenticated
+	}
+	return []dto.BudgetsResponse{{ID: "budget-1", Amount: money.New(1000000)}}, nil
+}
+
+func (f *fakeBudgetService) UpdateBudget(ctx context.Context, userID, id string, budget dto.BudgetsRequest) (dto.BudgetsResponse, error) {
+	if id != "budget-1" {
+		return dto.BudgetsResponse{}, fmt.Errorf("%w: budget does not belong to user", service.ErrPermissionDenied)
+	}
+	f.updated = budget
+	return dto.BudgetsResponse{ID: id, Amount: budget.Amount, Period: budget.Period}, nil
+}
+
+func TestBudgetService_List(t *testing.T) {
+	conn := dialServer(t, func(s *grpc.Server) {
+		s.RegisterService(&budgetServiceDesc, &budgetServer{budgetService: &fakeBudgetService{}})
+	})
+
+	out, err := invokeStruct(asUser("user-1"), conn, budgetServiceName, "ListBudgets", map[string]any{})
+
+	assert.NoError(t, err)
+	list := out.GetFields()["data"].GetListValue().GetValues()
+	assert.Len(t, list, 1)
+	assert.Equal(t, "budget-1", list[0].GetStructValue().GetFields()["id"].GetStringValue())
+}
+
+func TestBudgetService_ListUnauthenticated(t *testing.T) {
+	conn := dialServer(t, func(s *grpc.Server) {
+		s.RegisterService(&budgetServiceDesc, &budgetServer{budgetService: &fakeBudgetService{}})
+	})
+
+	_, err := invokeStruct(context.Background(), conn, budgetServiceName, "ListBudgets", map[string]any{})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestBudgetService_Update(t *testing.T) {
+	budgets := &fakeBudgetService{}
+	conn := dialServer(t, func(s *grpc.Server) {
+		s.RegisterService(&budgetServiceDesc, &budgetServer{budgetService: budgets})
+	})
+
+	out, err := invokeStruct(asUser("user-1"), conn, budgetServiceName, "UpdateBudget", map[string]any{
+		"id":       "budget-1",
+		"period":   "weekly",
+		"amount":   250000,
+		"rollover": true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(250000), out.GetFields()["amount"].GetNumberValue())
+	assert.Equal(t, dto.BudgetsRequest{Period: "weekly", Amount: money.New(250000), Rollover: true}, budgets.updated)
+}
+
+func TestBudgetService_UpdateForeignBudgetDenied(t *testing.T) {
+	conn := dialServer(t, func(s *grpc.Server) {
+		s.RegisterService(&budgetServiceDesc, &budgetServer{budgetService: &fakeBudgetService{}})
+	})
+
+	_, err := invokeStruct(asUser("user-1"), conn, budgetServiceName, "UpdateBudget", map[string]any{"id": "budget-2", "amount": 1})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
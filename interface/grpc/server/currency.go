@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const currencyServiceName = "transaction.CurrencyService"
+
+// currencyServiceServer is the server API of transaction.CurrencyService.
+// Every RPC takes and returns a google.protobuf.Struct shaped like the
+// /currencies HTTP bodies.
+type currencyServiceServer interface {
+	ListFXRates(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	UpsertFXRates(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	ImportFXRates(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	GetWalletCurrency(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	SetWalletCurrency(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+}
+
+var currencyServiceDesc = grpc.ServiceDesc{
+	ServiceName: currencyServiceName,
+	HandlerType: (*currencyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		structMethod(currencyServiceName, "ListFXRates", currencyServiceServer.ListFXRates),
+		structMethod(currencyServiceName, "UpsertFXRates", currencyServiceServer.UpsertFXRates),
+		structMethod(currencyServiceName, "ImportFXRates", currencyServiceServer.ImportFXRates),
+		structMethod(currencyServiceName, "GetWalletCurrency", currencyServiceServer.GetWalletCurrency),
+		structMethod(currencyServiceName, "SetWalletCurrency", currencyServiceServer.SetWalletCurrency),
+	},
+	Metadata: "currency.go",
+}
+
+type currencyServer struct {
+	currencyService      service.CurrenciesService
+	authorizationService service.AuthorizationService
+}
+
+type listFXRatesRequest struct {
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+}
+
+type upsertFXRatesRequest struct {
+	Rates []dto.FXRatesRequest `json:"rates"`
+}
+
+type walletCurrencyRequest struct {
+	WalletID string `json:"wallet_id"`
+	dto.WalletCurrencyRequest
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Currency RPCs
+// ──────────────────────────────────────────────────────────────────────────────
+
+func (s *currencyServer) ListFXRates(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, authorizationError(userID, service.ErrUnauthenticated)
+	}
+
+	var in listFXRatesRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	rates, err := s.currencyService.GetFXRates(ctx, in.BaseCurrency, in.QuoteCurrency)
+	if err != nil {
+		return nil, currencyError(userID, "", data.LogGetFXRatesFailed, "get fx rates", err)
+	}
+
+	return encodeStruct(rates)
+}
+
+func (s *currencyServer) UpsertFXRates(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in upsertFXRatesRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	rates, err := s.currencyService.UpsertFXRates(ctx, in.Rates)
+	if err != nil {
+		return nil, currencyError(userID, "", data.LogUpsertFXRatesFailed, "store fx rates", err)
+	}
+
+	return encodeStruct(rates)
+}
+
+func (s *currencyServer) ImportFXRates(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in dto.FXRatesImportRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	rates, err := s.currencyService.ImportFXRates(ctx, in.Content)
+	if err != nil {
+		return nil, currencyError(userID, "", data.LogImportFXRatesFailed, "import fx rates", err)
+	}
+
+	return encodeStruct(rates)
+}
+
+func (s *currencyServer) GetWalletCurrency(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in walletCurrencyRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizationService.AuthorizeWallets(ctx, userID, in.WalletID); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	currency, err := s.currencyService.GetWalletCurrency(ctx, in.WalletID)
+	if err != nil {
+		return nil, currencyError(userID, in.WalletID, data.LogGetWalletCurrencyFailed, "get wallet currency", err)
+	}
+
+	return encodeStruct(currency)
+}
+
+func (s *currencyServer) SetWalletCurrency(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in walletCurrencyRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizationService.AuthorizeWallets(ctx, userID, in.WalletID); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	currency, err := s.currencyService.SetWalletCurrency(ctx, in.WalletID, in.WalletCurrencyRequest)
+	if err != nil {
+		return nil, currencyError(userID, in.WalletID, data.LogSetWalletCurrencyFailed, "set wallet currency", err)
+	}
+
+	return encodeStruct(currency)
+}
+
+func currencyError(userID, walletID, logMsg, action string, err error) error {
+	if isAuthorizationError(err) {
+		return authorizationError(userID, err)
+	}
+
+	log.Error(logMsg, map[string]any{
+		"service":   data.GRPCServerService,
+		"user_id":   userID,
+		"wallet_id": walletID,
+		"error":     err.Error(),
+	})
+	return fmt.Errorf("%s: %w", action, err)
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeCurrencyService struct {
+	service.CurrenciesService
+
+	set dto.WalletCurrencyRequest
+}
+
+func (f *fakeCurrencyService) UpsertFXRates(ctx context.Context, rates []dto.FXRatesRequest) ([]dto.FXRatesResponse, error) {
+	return nil, service.ErrPermissionDenied
+}
+
+func (f *fakeCurrencyService) SetWalletCurrency(ctx context.Context, walletID string, request dto.WalletCurrencyRequest) (dto.WalletCurrencyResponse, error) {
+	f.set = request
+	return dto.WalletCurrencyResponse{WalletID: walletID, Currency: request.Currency}, nil
+}
+
+func dialCurrencyServer(t *testing.T, currencies service.CurrenciesService) *grpc.ClientConn {
+	t.Helper()
+	return dialServer(t, func(s *grpc.Server) {
+		s.RegisterService(&currencyServiceDesc, &currencyServer{
+			currencyService:      currencies,
+			authorizationService: fakeAuthorization{},
+		})
+	})
+}
+
+func TestCurrencyService_SetWalletCurrency(t *testing.T) {
+	currencies := &fakeCurrencyService{}
+	conn := dialCurrencyServer(t, currencies)
+
+	out, err := invokeStruct(asUser("user-1"), conn, currencyServiceName, "SetWalletCurrency", map[string]any{
+		"wallet_id": "wallet-1",
+		"currency":  "USD",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", currencies.set.Currency)
+	assert.Equal(t, "wallet-1", out.GetFields()["wallet_id"].GetStringValue())
+}
+
+func TestCurrencyService_SetWalletCurrencyForeignWallet(t *testing.T) {
+	currencies := &fakeCurrencyService{}
+	conn := dialCurrencyServer(t, currencies)
+
+	_, err := invokeStruct(asUser("user-1"), conn, currencyServiceName, "SetWalletCurrency", map[string]any{
+		"wallet_id": "wallet-2",
+		"currency":  "USD",
+	})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Empty(t, currencies.set.Currency)
+}
+
+func TestCurrencyService_UpsertFXRatesNonAdmin(t *testing.T) {
+	conn := dialCurrencyServer(t, &fakeCurrencyService{})
+
+	_, err := invokeStruct(asUser("user-1"), conn, currencyServiceName, "UpsertFXRates", map[string]any{
+		"rates": []any{map[string]any{"base_currency": "USD", "quote_currency": "IDR", "rate": 16000, "effective_date": "2025-06-01T00:00:00Z"}},
+	})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
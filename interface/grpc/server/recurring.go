@@ -0,0 +1,243 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const recurringServiceName = "transaction.RecurringTransactionService"
+
+// recurringTransactionServiceServer is the server API of
+// transaction.RecurringTransactionService. Every RPC takes and returns a
+// google.protobuf.Struct shaped like the /recurring-transactions HTTP bodies.
+type recurringTransactionServiceServer interface {
+	ListRecurringTransactions(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	GetRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	CreateRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	UpdateRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	DeleteRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	PauseRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	ResumeRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	SkipNextOccurrence(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+}
+
+var recurringServiceDesc = grpc.ServiceDesc{
+	ServiceName: recurringServiceName,
+	HandlerType: (*recurringTransactionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		structMethod(recurringServiceName, "ListRecurringTransactions", recurringTransactionServiceServer.ListRecurringTransactions),
+		structMethod(recurringServiceName, "GetRecurringTransaction", recurringTransactionServiceServer.GetRecurringTransaction),
+		structMethod(recurringServiceName, "CreateRecurringTransaction", recurringTransactionServiceServer.CreateRecurringTransaction),
+		structMethod(recurringServiceName, "UpdateRecurringTransaction", recurringTransactionServiceServer.UpdateRecurringTransaction),
+		structMethod(recurringServiceName, "DeleteRecurringTransaction", recurringTransactionServiceServer.DeleteRecurringTransaction),
+		structMethod(recurringServiceName, "PauseRecurringTransaction", recurringTransactionServiceServer.PauseRecurringTransaction),
+		structMethod(recurringServiceName, "ResumeRecurringTransaction", recurringTransactionServiceServer.ResumeRecurringTransaction),
+		structMethod(recurringServiceName, "SkipNextOccurrence", recurringTransactionServiceServer.SkipNextOccurrence),
+	},
+	Metadata: "recurring.go",
+}
+
+type recurringServer struct {
+	recurringService     service.RecurringTransactionsService
+	authorizationService service.AuthorizationService
+}
+
+type recurringIDRequest struct {
+	ID string `json:"id"`
+}
+
+type listRecurringRequest struct {
+	WalletIDs []string `json:"wallet_ids"`
+}
+
+type updateRecurringRequest struct {
+	ID string `json:"id"`
+	dto.RecurringTransactionsRequest
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Recurring transaction RPCs
+// ──────────────────────────────────────────────────────────────────────────────
+
+func (s *recurringServer) ListRecurringTransactions(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in listRecurringRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	// Optional wallet_ids filter, defaulting to every wallet of the caller
+	walletIDs, err := s.authorizationService.ScopeWallets(ctx, userID, in.WalletIDs...)
+	if err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	recurrings, err := s.recurringService.GetRecurringTransactionsByWalletIDs(ctx, walletIDs)
+	if err != nil {
+		log.Error(data.LogGetRecurringTransactionsFailed, map[string]any{
+			"service": data.GRPCServerService,
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("get recurring transactions: %w", err)
+	}
+
+	return encodeStruct(recurrings)
+}
+
+func (s *recurringServer) GetRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in recurringIDRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizationService.AuthorizeRecurringTransaction(ctx, userID, in.ID); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	recurring, err := s.recurringService.GetRecurringTransactionByID(ctx, in.ID)
+	if err != nil {
+		log.Error(data.LogGetRecurringTransactionByIDFailed, map[string]any{
+			"service":      data.GRPCServerService,
+			"user_id":      userID,
+			"recurring_id": in.ID,
+			"error":        err.Error(),
+		})
+		return nil, fmt.Errorf("get recurring transaction [id=%s]: %w", in.ID, err)
+	}
+
+	return encodeStruct(recurring)
+}
+
+func (s *recurringServer) CreateRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in dto.RecurringTransactionsRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizationService.AuthorizeWallets(ctx, userID, in.WalletID); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	recurring, err := s.recurringService.CreateRecurringTransaction(ctx, in)
+	if err != nil {
+		log.Error(data.LogCreateRecurringTransactionFailed, map[string]any{
+			"service":   data.GRPCServerService,
+			"user_id":   userID,
+			"wallet_id": in.WalletID,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("create recurring transaction: %w", err)
+	}
+
+	return encodeStruct(recurring)
+}
+
+func (s *recurringServer) UpdateRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in updateRecurringRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	// The schedule may also move to another wallet, which must be the caller's too
+	if err := s.authorizationService.AuthorizeRecurringTransaction(ctx, userID, in.ID); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+	if err := s.authorizationService.AuthorizeWallets(ctx, userID, in.WalletID); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	recurring, err := s.recurringService.UpdateRecurringTransaction(ctx, in.ID, in.RecurringTransactionsRequest)
+	if err != nil {
+		log.Error(data.LogUpdateRecurringTransactionFailed, map[string]any{
+			"service":      data.GRPCServerService,
+			"user_id":      userID,
+			"recurring_id": in.ID,
+			"error":        err.Error(),
+		})
+		return nil, fmt.Errorf("update recurring transaction [id=%s]: %w", in.ID, err)
+	}
+
+	return encodeStruct(recurring)
+}
+
+func (s *recurringServer) DeleteRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in recurringIDRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizationService.AuthorizeRecurringTransaction(ctx, userID, in.ID); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	recurring, err := s.recurringService.DeleteRecurringTransaction(ctx, in.ID)
+	if err != nil {
+		log.Error(data.LogDeleteRecurringTransactionFailed, map[string]any{
+			"service":      data.GRPCServerService,
+			"user_id":      userID,
+			"recurring_id": in.ID,
+			"error":        err.Error(),
+		})
+		return nil, fmt.Errorf("delete recurring transaction [id=%s]: %w", in.ID, err)
+	}
+
+	return encodeStruct(recurring)
+}
+
+func (s *recurringServer) PauseRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	return s.changeSchedule(ctx, req, "pause", s.recurringService.PauseRecurringTransaction)
+}
+
+func (s *recurringServer) ResumeRecurringTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	return s.changeSchedule(ctx, req, "resume", s.recurringService.ResumeRecurringTransaction)
+}
+
+func (s *recurringServer) SkipNextOccurrence(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	return s.changeSchedule(ctx, req, "skip", s.recurringService.SkipNextOccurrence)
+}
+
+func (s *recurringServer) changeSchedule(ctx context.Context, req *structpb.Struct, action string, change func(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error)) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in recurringIDRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizationService.AuthorizeRecurringTransaction(ctx, userID, in.ID); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	recurring, err := change(ctx, in.ID)
+	if err != nil {
+		log.Error(data.LogChangeRecurringScheduleFailed, map[string]any{
+			"service":      data.GRPCServerService,
+			"user_id":      userID,
+			"recurring_id": in.ID,
+			"action":       action,
+			"error":        err.Error(),
+		})
+		return nil, fmt.Errorf("%s recurring transaction [id=%s]: %w", action, in.ID, err)
+	}
+
+	return encodeStruct(recurring)
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/money"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRecurringService struct {
+	service.RecurringTransactionsService
+
+	created dto.RecurringTransactionsRequest
+	paused  string
+}
+
+func (f *fakeRecurringService) CreateRecurringTransaction(ctx context.Context, recurring dto.RecurringTransactionsRequest) (dto.RecurringTransactionsResponse, error) {
+	f.created = recurring
+	return dto.RecurringTransactionsResponse{ID: "rec-1", WalletID: recurring.WalletID, Amount: recurring.Amount, Status: "active"}, nil
+}
+
+func (f *fakeRecurringService) GetRecurringTransactionsByWalletIDs(ctx context.Context, ids []string) ([]dto.RecurringTransactionsResponse, error) {
+	responses := make([]dto.RecurringTransactionsResponse, 0, len(ids))
+	for _, id := range ids {
+		responses = append(responses, dto.RecurringTransactionsResponse{ID: "rec-" + id, WalletID: id})
+	}
+	return responses, nil
+}
+
+func (f *fakeRecurringService) PauseRecurringTransaction(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error) {
+	f.paused = id
+	return dto.RecurringTransactionsResponse{ID: id, Status: "paused"}, nil
+}
+
+// fakeAuthorization lets user-1 act on wallet-1 and rec-1 only.
+type fakeAuthorization struct {
+	service.AuthorizationService
+}
+
+func (fakeAuthorization) ScopeWallets(ctx context.Context, userID string, walletIDs ...string) ([]string, error) {
+	if userID == "" {
+		return nil, service.ErrUnauthenticated
+	}
+	for _, id := range walletIDs {
+		if id != "wallet-1" {
+			return nil, service.ErrPermissionDenied
+		}
+	}
+	return []string{"wallet-1"}, nil
+}
+
+func (a fakeAuthorization) AuthorizeWallets(ctx context.Context, userID string, walletIDs ...string) error {
+	_, err := a.ScopeWallets(ctx, userID, walletIDs...)
+	return err
+}
+
+func (fakeAuthorization) AuthorizeRecurringTransaction(ctx context.Context, userID, recurringID string) error {
+	if userID == "" {
+		return service.ErrUnauthenticated
+	}
+	if recurringID != "rec-1" {
+		return service.ErrPermissionDenied
+	}
+	return nil
+}
+
+func dialRecurringServer(t *testing.T, recurring service.RecurringTransactionsService) *grpc.ClientConn {
+	t.Helper()
+	return dialServer(t, func(s *grpc.Server) {
+		s.RegisterService(&recurringServiceDesc, &recurringServer{
+			recurringService:     recurring,
+			authorizationService: fakeAuthorization{},
+		})
+	})
+}
+
+func TestRecurringService_CreateDecodesHTTPShapedBody(t *testing.T) {
+	recurring := &fakeRecurringService{}
+	conn := dialRecurringServer(t, recurring)
+
+	out, err := invokeStruct(asUser("user-1"), conn, recurringServiceName, "CreateRecurringTransaction", map[string]any{
+		"wallet_id":   "wallet-1",
+		"category_id": "cat-1",
+		"amount":      1500000,
+		"frequency":   "monthly",
+		"start_date":  "2025-01-31T09:00:00Z",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "rec-1", out.GetFields()["id"].GetStringValue())
+	assert.Equal(t, "active", out.GetFields()["status"].GetStringValue())
+	assert.Equal(t, money.New(1500000), recurring.created.Amount)
+	assert.Equal(t, time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), recurring.created.StartDate)
+}
+
+func TestRecurringService_CreateRejectsForeignWallet(t *testing.T) {
+	recurring := &fakeRecurringService{}
+	conn := dialRecurringServer(t, recurring)
+
+	_, err := invokeStruct(asUser("user-1"), conn, recurringServiceName, "CreateRecurringTransaction", map[string]any{
+		"wallet_id": "wallet-2",
+	})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Empty(t, recurring.created.WalletID)
+}
+
+func TestRecurringService_RequiresAuthentication(t *testing.T) {
+	conn := dialRecurringServer(t, &fakeRecurringService{})
+
+	_, err := invokeStruct(context.Background(), conn, recurringServiceName, "PauseRecurringTransaction", map[string]any{"id": "rec-1"})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRecurringService_ListWrapsSliceInData(t *testing.T) {
+	conn := dialRecurringServer(t, &fakeRecurringService{})
+
+	out, err := invokeStruct(asUser("user-1"), conn, recurringServiceName, "ListRecurringTransactions", map[string]any{})
+
+	assert.NoError(t, err)
+	list := out.GetFields()["data"].GetListValue().GetValues()
+	assert.Len(t, list, 1)
+	assert.Equal(t, "wallet-1", list[0].GetStructValue().GetFields()["wallet_id"].GetStringValue())
+}
+
+func TestRecurringService_Pause(t *testing.T) {
+	recurring := &fakeRecurringService{}
+	conn := dialRecurringServer(t, recurring)
+
+	out, err := invokeStruct(asUser("user-1"), conn, recurringServiceName, "PauseRecurringTransaction", map[string]any{"id": "rec-1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "rec-1", recurring.paused)
+	assert.Equal(t, "paused", out.GetFields()["status"].GetStringValue())
+}
+
+func TestRecurringService_InvalidBody(t *testing.T) {
+	conn := dialRecurringServer(t, &fakeRecurringService{})
+
+	_, err := invokeStruct(asUser("user-1"), conn, recurringServiceName, "GetRecurringTransaction", map[string]any{"id": 42})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
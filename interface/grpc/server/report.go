@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/utils/data"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const reportServiceName = "transaction.ReportService"
+
+// reportServiceServer is the server API of transaction.ReportService. Its RPC
+// takes the /reports/transactions query parameters as a
+// google.protobuf.Struct and returns the same summary body.
+type reportServiceServer interface {
+	GetTransactionSummary(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+}
+
+var reportServiceDesc = grpc.ServiceDesc{
+	ServiceName: reportServiceName,
+	HandlerType: (*reportServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		structMethod(reportServiceName, "GetTransactionSummary", reportServiceServer.GetTransactionSummary),
+	},
+	Metadata: "report.go",
+}
+
+type reportServer struct {
+	reportService        service.ReportsService
+	authorizationService service.AuthorizationService
+}
+
+type transactionSummaryRequest struct {
+	WalletIDs    []string `json:"wallet_ids"`
+	CategoryID   string   `json:"category_id"`
+	CategoryType string   `json:"category_type"`
+	DateFrom     string   `json:"date_from"`
+	DateTo       string   `json:"date_to"`
+	Search       string   `json:"search"`
+	TagIDs       []string `json:"tag_ids"`
+	TagMatch     string   `json:"tag_match"`
+	GroupBy      string   `json:"group_by"`
+	BaseCurrency string   `json:"base_currency"`
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Report RPCs
+// ──────────────────────────────────────────────────────────────────────────────
+
+func (s *reportServer) GetTransactionSummary(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in transactionSummaryRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	// wallet_ids defaults to every wallet of the caller
+	walletIDs, err := s.authorizationService.ScopeWallets(ctx, userID, in.WalletIDs...)
+	if err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	summary, err := s.reportService.GetTransactionSummary(ctx, repository.CursorQuery{
+		WalletIDs:    walletIDs,
+		CategoryID:   in.CategoryID,
+		CategoryType: in.CategoryType,
+		DateFrom:     in.DateFrom,
+		DateTo:       in.DateTo,
+		Search:       in.Search,
+		TagIDs:       in.TagIDs,
+		TagMatch:     repository.TagMatch(in.TagMatch),
+		BaseCurrency: in.BaseCurrency,
+	}, in.GroupBy)
+	if err != nil {
+		log.Error(data.LogGetTransactionSummaryFailed, map[string]any{
+			"service":  data.GRPCServerService,
+			"user_id":  userID,
+			"group_by": in.GroupBy,
+			"error":    err.Error(),
+		})
+		return nil, fmt.Errorf("get transaction summary: %w", err)
+	}
+
+	return encodeStruct(summary)
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/money"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeReportService struct {
+	query   repository.CursorQuery
+	groupBy string
+}
+
+func (f *fakeReportService) GetTransactionSummary(ctx context.Context, q repository.CursorQuery, groupBy string) (dto.TransactionSummaryResponse, error) {
+	f.query, f.groupBy = q, groupBy
+	return dto.TransactionSummaryResponse{GroupBy: groupBy, Net: money.New(250000)}, nil
+}
+
+func dialReportServer(t *testing.T, reports service.ReportsService) *grpc.ClientConn {
+	t.Helper()
+	return dialServer(t, func(s *grpc.Server) {
+		s.RegisterService(&reportServiceDesc, &reportServer{
+			reportService:        reports,
+			authorizationService: fakeAuthorization{},
+		})
+	})
+}
+
+func TestReportService_SummaryScopedToCallerWallets(t *testing.T) {
+	reports := &fakeReportService{}
+	conn := dialReportServer(t, reports)
+
+	out, err := invokeStruct(asUser("user-1"), conn, reportServiceName, "GetTransactionSummary", map[string]any{
+		"category_type": "expense",
+		"date_from":     "2025-01-01",
+		"group_by":      "week",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(250000), out.GetFields()["net"].GetNumberValue())
+	assert.Equal(t, []string{"wallet-1"}, reports.query.WalletIDs)
+	assert.Equal(t, "expense", reports.query.CategoryType)
+	assert.Equal(t, "2025-01-01", reports.query.DateFrom)
+	assert.Equal(t, "week", reports.groupBy)
+}
+
+func TestReportService_ForeignWalletDenied(t *testing.T) {
+	reports := &fakeReportService{}
+	conn := dialReportServer(t, reports)
+
+	_, err := invokeStruct(asUser("user-1"), conn, reportServiceName, "GetTransactionSummary", map[string]any{
+		"wallet_ids": []any{"wallet-2"},
+	})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Empty(t, reports.groupBy)
+}
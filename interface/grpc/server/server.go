@@ -33,6 +33,15 @@ func SetupGRPCServer(dbInstance db.DatabaseClient, minioInstance *miniofs.MinIOM
 	categoryRepo := repository.NewCategoryRepository(dbInstance.GetDB())
 	attachmentRepo := repository.NewAttachmentsRepository(dbInstance.GetDB())
 	outboxRepo := repository.NewOutboxRepository(dbInstance.GetDB())
+	sagaRepo := repository.NewSagaLogRepository(dbInstance.GetDB())
+	idempotencyRepo := repository.NewIdempotencyRepository(dbInstance.GetDB())
+	recurringRepo := repository.NewRecurringTransactionsRepository(dbInstance.GetDB())
+	budgetRepo := repository.NewBudgetsRepository(dbInstance.GetDB())
+	currencyRepo := repository.NewCurrenciesRepository(dbInstance.GetDB())
+	tagRepo := repository.NewTagsRepository(dbInstance.GetDB())
+	historyRepo := repository.NewTransactionHistoryRepository(dbInstance.GetDB())
+	reconciliationRepo := repository.NewReconciliationsRepository(dbInstance.GetDB())
+	debtRepo := repository.NewDebtsRepository(dbInstance.GetDB())
 
 	// ── gRPC Client (wallet) ──
 	walletClient := grpcclient.NewWalletClient(grpcclient.GetManager().GetWalletClient())
@@ -45,17 +54,55 @@ func SetupGRPCServer(dbInstance db.DatabaseClient, minioInstance *miniofs.MinIOM
 		categoryRepo,
 		attachmentRepo,
 		outboxRepo,
+		sagaRepo,
+		idempotencyRepo,
+		budgetRepo,
+		currencyRepo,
+		tagRepo,
+		historyRepo,
+		reconciliationRepo,
 		minioInstance,
 	)
 	categoryService := service.NewCategoriesService(txManager, categoryRepo)
 	attachmentService := service.NewAttachmentsService(txManager, attachmentRepo)
+	authorizationService := service.NewAuthorizationService(walletClient, transactionsRepo, attachmentRepo, recurringRepo, debtRepo)
+	recurringService := service.NewRecurringTransactionsService(txManager, recurringRepo, categoryRepo, transactionService)
+	budgetService := service.NewBudgetsService(budgetRepo, categoryRepo, walletClient)
+	reportService := service.NewReportsService(transactionsRepo)
+	currencyService := service.NewCurrenciesService(currencyRepo)
+	tagService := service.NewTagsService(txManager, tagRepo)
 
 	txnServer := &transactionServer{
-		transactionService: transactionService,
-		categoryService:    categoryService,
-		attachmentService:  attachmentService,
+		transactionService:   transactionService,
+		categoryService:      categoryService,
+		attachmentService:    attachmentService,
+		authorizationService: authorizationService,
 	}
 	tpb.RegisterTransactionServiceServer(s, txnServer)
 
+	// Services missing from the shared proto module, see structService.go
+	s.RegisterService(&recurringServiceDesc, &recurringServer{
+		recurringService:     recurringService,
+		authorizationService: authorizationService,
+	})
+	s.RegisterService(&budgetServiceDesc, &budgetServer{budgetService: budgetService})
+	s.RegisterService(&tagServiceDesc, &tagServer{tagService: tagService})
+	s.RegisterService(&trashServiceDesc, &trashServer{
+		transactionService:   transactionService,
+		authorizationService: authorizationService,
+	})
+	s.RegisterService(&batchServiceDesc, &batchServer{
+		transactionService:   transactionService,
+		authorizationService: authorizationService,
+	})
+	s.RegisterService(&reportServiceDesc, &reportServer{
+		reportService:        reportService,
+		authorizationService: authorizationService,
+	})
+	s.RegisterService(&currencyServiceDesc, &currencyServer{
+		currencyService:      currencyService,
+		authorizationService: authorizationService,
+	})
+
 	return s, &lis, nil
 }
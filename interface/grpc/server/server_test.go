@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"testing"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestMain(m *testing.M) {
+	// Handlers log through log.Log, so it must be set before they run
+	log.Log = logrus.New()
+	log.Log.SetOutput(io.Discard)
+	log.Log.SetLevel(logrus.PanicLevel)
+
+	os.Exit(m.Run())
+}
+
+// dialServer serves the services registered by register over an in-memory
+// listener, behind the same user metadata interceptor as production.
+func dialServer(t *testing.T, register func(s *grpc.Server)) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.UnaryInterceptor(interceptor.UnaryServerInterceptor()))
+	register(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// invokeStruct calls a google.protobuf.Struct RPC of service.
+func invokeStruct(ctx context.Context, conn *grpc.ClientConn, service, method string, req map[string]any) (*structpb.Struct, error) {
+	in, err := structpb.NewStruct(req)
+	if err != nil {
+		return nil, err
+	}
+	out := new(structpb.Struct)
+	err = conn.Invoke(ctx, "/"+service+"/"+method, in, out)
+	return out, err
+}
+
+func asUser(userID string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), interceptor.MDKeyUserID, userID)
+}
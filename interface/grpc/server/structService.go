@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// The shared Refina-Protobuf module only describes the transaction RPCs.
+// Services added after its last release are registered with hand-written
+// descriptors whose request and response messages are google.protobuf.Struct,
+// carrying the same JSON bodies as the HTTP API, until typed messages land in
+// the proto module.
+
+// structMethod describes a unary RPC of service that takes and returns a
+// google.protobuf.Struct. call receives the registered implementation.
+func structMethod[S any](service, name string, call func(srv S, ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			in := new(structpb.Struct)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return call(srv.(S), ctx, in)
+			}
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: "/" + service + "/" + name,
+			}
+			return interceptor(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+				return call(srv.(S), ctx, req.(*structpb.Struct))
+			})
+		},
+	}
+}
+
+// decodeStruct unmarshals the Struct into out through its JSON form, so out
+// uses the json tags of the HTTP DTOs.
+func decodeStruct(in *structpb.Struct, out any) error {
+	raw, err := json.Marshal(in.AsMap())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	return nil
+}
+
+// encodeStruct marshals v into a Struct through its JSON form. Slices and
+// other non-object values are wrapped as {"data": v}.
+func encodeStruct(v any) (*structpb.Struct, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal response: %w", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, fmt.Errorf("unmarshal response: %w", err)
+		}
+		fields = map[string]any{"data": value}
+	}
+
+	out, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, fmt.Errorf("build response struct: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const tagServiceName = "transaction.TagService"
+
+// tagServiceServer is the server API of transaction.TagService. Every
+// RPC takes and returns a google.protobuf.Struct shaped like the /tags
+// HTTP bodies.
+type tagServiceServer interface {
+	ListTags(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	GetTag(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	CreateTag(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	UpdateTag(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	DeleteTag(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+}
+
+var tagServiceDesc = grpc.ServiceDesc{
+	ServiceName: tagServiceName,
+	HandlerType: (*tagServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		structMethod(tagServiceName, "ListTags", tagServiceServer.ListTags),
+		structMethod(tagServiceName, "GetTag", tagServiceServer.GetTag),
+		structMethod(tagServiceName, "CreateTag", tagServiceServer.CreateTag),
+		structMethod(tagServiceName, "UpdateTag", tagServiceServer.UpdateTag),
+		structMethod(tagServiceName, "DeleteTag", tagServiceServer.DeleteTag),
+	},
+	Metadata: "tag.go",
+}
+
+// tagServer relies on the tag service for authorization: every method
+// takes the caller and only touches tags the caller owns.
+type tagServer struct {
+	tagService service.TagsService
+}
+
+type tagIDRequest struct {
+	ID string `json:"id"`
+}
+
+type updateTagRequest struct {
+	ID string `json:"id"`
+	dto.TagsRequest
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Tag RPCs
+// ──────────────────────────────────────────────────────────────────────────────
+
+func (s *tagServer) ListTags(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	tags, err := s.tagService.GetTags(ctx, userID)
+	if err != nil {
+		return nil, tagError(userID, "", data.LogGetTagsFailed, "get tags", err)
+	}
+
+	return encodeStruct(tags)
+}
+
+func (s *tagServer) GetTag(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in tagIDRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	tag, err := s.tagService.GetTagByID(ctx, userID, in.ID)
+	if err != nil {
+		return nil, tagError(userID, in.ID, data.LogGetTagByIDFailed, "get tag", err)
+	}
+
+	return encodeStruct(tag)
+}
+
+func (s *tagServer) CreateTag(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in dto.TagsRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	tag, err := s.tagService.CreateTag(ctx, userID, in)
+	if err != nil {
+		return nil, tagError(userID, "", data.LogCreateTagFailed, "create tag", err)
+	}
+
+	return encodeStruct(tag)
+}
+
+func (s *tagServer) UpdateTag(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in updateTagRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	tag, err := s.tagService.UpdateTag(ctx, userID, in.ID, in.TagsRequest)
+	if err != nil {
+		return nil, tagError(userID, in.ID, data.LogUpdateTagFailed, "update tag", err)
+	}
+
+	return encodeStruct(tag)
+}
+
+func (s *tagServer) DeleteTag(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in tagIDRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	tag, err := s.tagService.DeleteTag(ctx, userID, in.ID)
+	if err != nil {
+		return nil, tagError(userID, in.ID, data.LogDeleteTagFailed, "delete tag", err)
+	}
+
+	return encodeStruct(tag)
+}
+
+func tagError(userID, tagID, logMsg, action string, err error) error {
+	if isAuthorizationError(err) {
+		return authorizationError(userID, err)
+	}
+
+	log.Error(logMsg, map[string]any{
+		"service": data.GRPCServerService,
+		"user_id": userID,
+		"tag_id":  tagID,
+		"error":   err.Error(),
+	})
+	return fmt.Errorf("%s [id=%s]: %w", action, tagID, err)
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeTagService owns tag-1 for user-1, like the real service checks.
+type fakeTagService struct {
+	service.TagsService
+}
+
+func (f *fakeTagService) CreateTag(ctx context.Context, userID string, tag dto.TagsRequest) (dto.TagsResponse, error) {
+	if userID == "" {
+		return dto.TagsResponse{}, service.ErrUnauthenticated
+	}
+	return dto.TagsResponse{ID: "tag-2", Name: tag.Name}, nil
+}
+
+func (f *fakeTagService) DeleteTag(ctx context.Context, userID, id string) (dto.TagsResponse, error) {
+	if id != "tag-1" {
+		return dto.TagsResponse{}, fmt.Errorf("%w: tag does not belong to user", service.ErrPermissionDenied)
+	}
+	return dto.TagsResponse{ID: id, Name: "Liburan"}, nil
+}
+
+func TestTagService_Create(t *testing.T) {
+	conn := dialServer(t, func(s *grpc.Server) {
+		s.RegisterService(&tagServiceDesc, &tagServer{tagService: &fakeTagService{}})
+	})
+
+	out, err := invokeStruct(asUser("user-1"), conn, tagServiceName, "CreateTag", map[string]any{"name": "Kantor"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Kantor", out.GetFields()["name"].GetStringValue())
+}
+
+func TestTagService_DeleteForeignTag(t *testing.T) {
+	conn := dialServer(t, func(s *grpc.Server) {
+		s.RegisterService(&tagServiceDesc, &tagServer{tagService: &fakeTagService{}})
+	})
+
+	_, err := invokeStruct(asUser("user-1"), conn, tagServiceName, "DeleteTag", map[string]any{"id": "tag-9"})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -2,7 +2,9 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"refina-transaction/config/log"
@@ -10,16 +12,20 @@ import (
 	"refina-transaction/internal/repository"
 	"refina-transaction/internal/service"
 	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/money"
 	"refina-transaction/internal/utils/data"
 
 	tpb "github.com/MuhammadMiftaa/Refina-Protobuf/transaction"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type transactionServer struct {
 	tpb.UnimplementedTransactionServiceServer
-	transactionService service.TransactionsService
-	categoryService    service.CategoriesService
-	attachmentService  service.AttachmentsService
+	transactionService   service.TransactionsService
+	categoryService      service.CategoriesService
+	attachmentService    service.AttachmentsService
+	authorizationService service.AuthorizationService
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -28,8 +34,15 @@ type transactionServer struct {
 
 func (s *transactionServer) GetTransactions(req *tpb.GetTransactionOptions, stream tpb.TransactionService_GetTransactionsServer) error {
 	ctx := stream.Context()
+	userID := interceptor.UserIDFromContext(ctx)
+
+	// Only stream transactions of the caller's own wallets
+	walletIDs, err := s.authorizationService.GetUserWalletIDs(ctx, userID)
+	if err != nil {
+		return authorizationError(userID, err)
+	}
 
-	transactions, err := s.transactionService.GetAllTransactions(ctx)
+	transactions, err := s.transactionService.GetTransactionsByWalletIDs(ctx, walletIDs)
 	if err != nil {
 		log.Error(data.LogGetTransactionsFailed, map[string]any{
 			"service": data.GRPCServerService,
@@ -68,10 +81,17 @@ func (s *transactionServer) GetUserTransactions(ctx context.Context, req *tpb.Ge
 		SortOrder:    req.GetSortOrder(),
 		PageSize:     int(pageSize),
 		Cursor:       req.GetCursor(),
-		CursorAmount: req.GetCursorAmount(),
+		CursorAmount: money.FromFloat(req.GetCursorAmount()),
 		CursorDate:   req.GetCursorDate(),
 	}
 
+	// Never trust the requested wallet IDs; default to every wallet of the caller
+	walletIDs, err := s.authorizationService.ScopeWallets(ctx, userID, q.WalletIDs...)
+	if err != nil {
+		return nil, authorizationError(userID, err)
+	}
+	q.WalletIDs = walletIDs
+
 	results, total, err := s.transactionService.GetTransactionsByCursor(ctx, q)
 	if err != nil {
 		log.Error(data.LogGetUserTransactionsFailed, map[string]any{
@@ -106,7 +126,7 @@ func (s *transactionServer) GetUserTransactions(ctx context.Context, req *tpb.Ge
 	if len(results) > 0 {
 		last := results[len(results)-1]
 		resp.NextCursor = last.ID
-		resp.NextCursorAmount = last.Amount
+		resp.NextCursorAmount = last.Amount.Float64()
 		resp.NextCursorDate = last.TransactionDate.Format(time.RFC3339)
 	}
 
@@ -124,6 +144,10 @@ func (s *transactionServer) GetUserTransactions(ctx context.Context, req *tpb.Ge
 func (s *transactionServer) GetTransactionByID(ctx context.Context, req *tpb.TransactionID) (*tpb.TransactionDetail, error) {
 	userID := interceptor.UserIDFromContext(ctx)
 
+	if err := s.authorizationService.AuthorizeTransaction(ctx, userID, req.GetId()); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
 	txn, err := s.transactionService.GetTransactionByID(ctx, req.GetId())
 	if err != nil {
 		log.Error(data.LogGetTransactionByIDGRPCFailed, map[string]any{
@@ -147,6 +171,13 @@ func (s *transactionServer) GetTransactionByID(ctx context.Context, req *tpb.Tra
 func (s *transactionServer) CreateTransaction(ctx context.Context, req *tpb.CreateTransactionRequest) (*tpb.TransactionDetail, error) {
 	userID := interceptor.UserIDFromContext(ctx)
 
+	// A wallet that is still being created is not yet visible in wallet-service
+	if !req.GetIsWalletNotCreated() {
+		if err := s.authorizationService.AuthorizeWallets(ctx, userID, req.GetWalletId()); err != nil {
+			return nil, authorizationError(userID, err)
+		}
+	}
+
 	transactionDate, err := time.Parse(time.RFC3339, req.GetTransactionDate())
 	if err != nil {
 		return nil, fmt.Errorf("create transaction: invalid date format: %w", err)
@@ -155,7 +186,7 @@ func (s *transactionServer) CreateTransaction(ctx context.Context, req *tpb.Crea
 	svcReq := dto.TransactionsRequest{
 		WalletID:    req.GetWalletId(),
 		CategoryID:  req.GetCategoryId(),
-		Amount:      req.GetAmount(),
+		Amount:      money.FromFloat(req.GetAmount()),
 		Date:        transactionDate,
 		Description: req.GetDescription(),
 		Attachments: []dto.UpdateAttachmentsRequest{
@@ -175,6 +206,9 @@ func (s *transactionServer) CreateTransaction(ctx context.Context, req *tpb.Crea
 			"wallet_id": req.GetWalletId(),
 			"error":     err.Error(),
 		})
+		if errors.Is(err, service.ErrIdempotencyKeyReused) {
+			return nil, status.Error(codes.AlreadyExists, "idempotency key already used for a different request")
+		}
 		return nil, fmt.Errorf("create transaction: %w", err)
 	}
 
@@ -191,6 +225,10 @@ func (s *transactionServer) CreateTransaction(ctx context.Context, req *tpb.Crea
 func (s *transactionServer) CreateFundTransfer(ctx context.Context, req *tpb.CreateFundTransferRequest) (*tpb.FundTransferResponse, error) {
 	userID := interceptor.UserIDFromContext(ctx)
 
+	if err := s.authorizationService.AuthorizeWallets(ctx, userID, req.GetFromWalletId(), req.GetToWalletId()); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
 	transactionDate, err := time.Parse(time.RFC3339, req.GetTransactionDate())
 	if err != nil {
 		return nil, fmt.Errorf("create fund transfer: invalid date format: %w", err)
@@ -201,8 +239,8 @@ func (s *transactionServer) CreateFundTransfer(ctx context.Context, req *tpb.Cre
 		CashOutCategoryID: req.GetCashOutCategoryId(),
 		FromWalletID:      req.GetFromWalletId(),
 		ToWalletID:        req.GetToWalletId(),
-		Amount:            req.GetAmount(),
-		AdminFee:          req.GetAdminFee(),
+		Amount:            money.FromFloat(req.GetAmount()),
+		AdminFee:          money.FromFloat(req.GetAdminFee()),
 		Date:              transactionDate,
 		Description:       req.GetDescription(),
 	}
@@ -216,6 +254,9 @@ func (s *transactionServer) CreateFundTransfer(ctx context.Context, req *tpb.Cre
 			"to_wallet_id":   req.GetToWalletId(),
 			"error":          err.Error(),
 		})
+		if errors.Is(err, service.ErrIdempotencyKeyReused) {
+			return nil, status.Error(codes.AlreadyExists, "idempotency key already used for a different request")
+		}
 		return nil, fmt.Errorf("create fund transfer: %w", err)
 	}
 
@@ -224,7 +265,7 @@ func (s *transactionServer) CreateFundTransfer(ctx context.Context, req *tpb.Cre
 		"user_id":        userID,
 		"from_wallet_id": result.FromWalletID,
 		"to_wallet_id":   result.ToWalletID,
-		"amount":         result.Amount,
+		"amount":         result.Amount.Float64(),
 	})
 
 	return &tpb.FundTransferResponse{
@@ -232,7 +273,7 @@ func (s *transactionServer) CreateFundTransfer(ctx context.Context, req *tpb.Cre
 		CashInTransactionId:  result.CashInTransactionID,
 		FromWalletId:         result.FromWalletID,
 		ToWalletId:           result.ToWalletID,
-		Amount:               result.Amount,
+		Amount:               result.Amount.Float64(),
 		Date:                 result.Date.Format(time.RFC3339),
 		Description:          result.Description,
 	}, nil
@@ -241,6 +282,14 @@ func (s *transactionServer) CreateFundTransfer(ctx context.Context, req *tpb.Cre
 func (s *transactionServer) UpdateTransaction(ctx context.Context, req *tpb.UpdateTransactionRequest) (*tpb.TransactionDetail, error) {
 	userID := interceptor.UserIDFromContext(ctx)
 
+	if err := s.authorizationService.AuthorizeTransaction(ctx, userID, req.GetId()); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+	// The transaction may also be moved to another wallet of the caller
+	if err := s.authorizationService.AuthorizeWallets(ctx, userID, req.GetWalletId()); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
 	transactionDate, err := time.Parse(time.RFC3339, req.GetTransactionDate())
 	if err != nil {
 		return nil, fmt.Errorf("update transaction: invalid date format: %w", err)
@@ -258,10 +307,12 @@ func (s *transactionServer) UpdateTransaction(ctx context.Context, req *tpb.Upda
 	svcReq := dto.TransactionsRequest{
 		WalletID:    req.GetWalletId(),
 		CategoryID:  req.GetCategoryId(),
-		Amount:      req.GetAmount(),
+		Amount:      money.FromFloat(req.GetAmount()),
 		Date:        transactionDate,
 		Description: req.GetDescription(),
 		Attachments: attachmentActions,
+		// The proto has no split lines, so keep existing ones in line with the new amount
+		RescaleSplits: true,
 	}
 
 	txn, err := s.transactionService.UpdateTransaction(ctx, req.GetId(), svcReq)
@@ -287,6 +338,10 @@ func (s *transactionServer) UpdateTransaction(ctx context.Context, req *tpb.Upda
 func (s *transactionServer) DeleteTransaction(ctx context.Context, req *tpb.TransactionID) (*tpb.TransactionDetail, error) {
 	userID := interceptor.UserIDFromContext(ctx)
 
+	if err := s.authorizationService.AuthorizeTransaction(ctx, userID, req.GetId()); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
 	txn, err := s.transactionService.DeleteTransaction(ctx, req.GetId())
 	if err != nil {
 		log.Error(data.LogDeleteTransactionFailed, map[string]any{
@@ -315,6 +370,11 @@ func (s *transactionServer) GetCategories(ctx context.Context, req *tpb.GetCateg
 	userID := interceptor.UserIDFromContext(ctx)
 	filterType := req.GetType()
 
+	// Categories are shared, so only an authenticated caller is required
+	if userID == "" {
+		return nil, authorizationError(userID, service.ErrUnauthenticated)
+	}
+
 	var categoryGroups []*tpb.CategoryGroup
 
 	if filterType != "" {
@@ -389,6 +449,10 @@ func (s *transactionServer) GetCategories(ctx context.Context, req *tpb.GetCateg
 func (s *transactionServer) GetAttachmentsByTransactionID(ctx context.Context, req *tpb.TransactionID) (*tpb.GetAttachmentsResponse, error) {
 	userID := interceptor.UserIDFromContext(ctx)
 
+	if err := s.authorizationService.AuthorizeTransaction(ctx, userID, req.GetId()); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
 	attachments, err := s.attachmentService.GetAttachmentsByTransactionID(ctx, req.GetId())
 	if err != nil {
 		log.Error(data.LogGetAttachmentsByTxnIDFailed, map[string]any{
@@ -420,6 +484,10 @@ func (s *transactionServer) GetAttachmentsByTransactionID(ctx context.Context, r
 func (s *transactionServer) CreateAttachment(ctx context.Context, req *tpb.CreateAttachmentRequest) (*tpb.Attachment, error) {
 	userID := interceptor.UserIDFromContext(ctx)
 
+	if err := s.authorizationService.AuthorizeTransaction(ctx, userID, req.GetTransactionId()); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
 	svcReq := dto.AttachmentsRequest{
 		TransactionID: req.GetTransactionId(),
 		Image:         req.GetImage(),
@@ -449,6 +517,10 @@ func (s *transactionServer) CreateAttachment(ctx context.Context, req *tpb.Creat
 func (s *transactionServer) DeleteAttachment(ctx context.Context, req *tpb.AttachmentID) (*tpb.Attachment, error) {
 	userID := interceptor.UserIDFromContext(ctx)
 
+	if err := s.authorizationService.AuthorizeAttachment(ctx, userID, req.GetId()); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
 	attachment, err := s.attachmentService.DeleteAttachment(ctx, req.GetId())
 	if err != nil {
 		log.Error(data.LogDeleteAttachmentGRPCFailed, map[string]any{
@@ -469,6 +541,37 @@ func (s *transactionServer) DeleteAttachment(ctx context.Context, req *tpb.Attac
 	return toProtoAttachment(attachment), nil
 }
 
+// ──────────────────────────────────────────────────────────────────────────────
+// Authorization
+// ──────────────────────────────────────────────────────────────────────────────
+
+// isAuthorizationError reports whether a service failed the caller's
+// authentication or ownership check itself.
+func isAuthorizationError(err error) bool {
+	return errors.Is(err, service.ErrUnauthenticated) || errors.Is(err, service.ErrPermissionDenied)
+}
+
+// authorizationError logs a rejected call and converts it to a gRPC status so
+// the BFF can tell a forbidden resource from a missing one.
+func authorizationError(userID string, err error) error {
+	log.Warn(data.LogAuthorizationDenied, map[string]any{
+		"service": data.GRPCServerService,
+		"user_id": userID,
+		"error":   err.Error(),
+	})
+
+	switch {
+	case errors.Is(err, service.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, "unauthenticated")
+	case errors.Is(err, service.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "permission denied")
+	case strings.Contains(err.Error(), "not found"):
+		return status.Error(codes.NotFound, "resource not found")
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+
 // ──────────────────────────────────────────────────────────────────────────────
 // Proto Converters
 // ──────────────────────────────────────────────────────────────────────────────
@@ -477,7 +580,7 @@ func toProtoTransaction(txn dto.TransactionsResponse) *tpb.Transaction {
 	return &tpb.Transaction{
 		Id:              txn.ID,
 		WalletId:        txn.WalletID,
-		Amount:          txn.Amount,
+		Amount:          txn.Amount.Float64(),
 		CategoryId:      txn.CategoryID,
 		CategoryName:    txn.CategoryName,
 		CategoryType:    txn.CategoryType,
@@ -486,6 +589,9 @@ func toProtoTransaction(txn dto.TransactionsResponse) *tpb.Transaction {
 	}
 }
 
+// toProtoTransactionDetail carries the parent category only; TransactionDetail
+// has no field for split lines yet, so gRPC clients see split transactions
+// under the category they were filed with. Updates over gRPC keep the lines.
 func toProtoTransactionDetail(txn dto.TransactionsResponse) *tpb.TransactionDetail {
 	protoAttachments := make([]*tpb.Attachment, 0, len(txn.Attachments))
 	for _, a := range txn.Attachments {
@@ -498,7 +604,7 @@ func toProtoTransactionDetail(txn dto.TransactionsResponse) *tpb.TransactionDeta
 		CategoryId:      txn.CategoryID,
 		CategoryName:    txn.CategoryName,
 		CategoryType:    txn.CategoryType,
-		Amount:          txn.Amount,
+		Amount:          txn.Amount.Float64(),
 		TransactionDate: txn.TransactionDate.Format(time.RFC3339),
 		Description:     txn.Description,
 		Attachments:     protoAttachments,
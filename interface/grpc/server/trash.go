@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/utils/data"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const trashServiceName = "transaction.TrashService"
+
+// trashServiceServer is the server API of transaction.TrashService. Every
+// RPC takes and returns a google.protobuf.Struct shaped like the
+// /transactions/trash HTTP bodies.
+type trashServiceServer interface {
+	ListTrash(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	RestoreTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+}
+
+var trashServiceDesc = grpc.ServiceDesc{
+	ServiceName: trashServiceName,
+	HandlerType: (*trashServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		structMethod(trashServiceName, "ListTrash", trashServiceServer.ListTrash),
+		structMethod(trashServiceName, "RestoreTransaction", trashServiceServer.RestoreTransaction),
+	},
+	Metadata: "trash.go",
+}
+
+type trashServer struct {
+	transactionService   service.TransactionsService
+	authorizationService service.AuthorizationService
+}
+
+type listTrashRequest struct {
+	WalletIDs []string `json:"wallet_ids"`
+}
+
+type trashIDRequest struct {
+	ID string `json:"id"`
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Trash RPCs
+// ──────────────────────────────────────────────────────────────────────────────
+
+func (s *trashServer) ListTrash(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in listTrashRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	// Optional wallet_ids filter, defaulting to every wallet of the caller
+	walletIDs, err := s.authorizationService.ScopeWallets(ctx, userID, in.WalletIDs...)
+	if err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	transactions, err := s.transactionService.GetDeletedTransactions(ctx, walletIDs)
+	if err != nil {
+		log.Error(data.LogGetDeletedTransactionsFailed, map[string]any{
+			"service": data.GRPCServerService,
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("get deleted transactions: %w", err)
+	}
+
+	return encodeStruct(transactions)
+}
+
+func (s *trashServer) RestoreTransaction(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var in trashIDRequest
+	if err := decodeStruct(req, &in); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizationService.AuthorizeDeletedTransaction(ctx, userID, in.ID); err != nil {
+		return nil, authorizationError(userID, err)
+	}
+
+	transaction, err := s.transactionService.RestoreTransaction(ctx, in.ID)
+	if err != nil {
+		log.Error(data.LogRestoreTransactionFailed, map[string]any{
+			"service":        data.GRPCServerService,
+			"user_id":        userID,
+			"transaction_id": in.ID,
+			"error":          err.Error(),
+		})
+		return nil, fmt.Errorf("restore transaction [id=%s]: %w", in.ID, err)
+	}
+
+	return encodeStruct(transaction)
+}
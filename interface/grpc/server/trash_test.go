@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeTrashService struct {
+	service.TransactionsService
+
+	listed   []string
+	restored string
+}
+
+func (f *fakeTrashService) GetDeletedTransactions(ctx context.Context, walletIDs []string) ([]dto.TransactionsResponse, error) {
+	f.listed = walletIDs
+	return []dto.TransactionsResponse{{ID: "txn-1", WalletID: "wallet-1"}}, nil
+}
+
+func (f *fakeTrashService) RestoreTransaction(ctx context.Context, id string) (dto.TransactionsResponse, error) {
+	f.restored = id
+	return dto.TransactionsResponse{ID: id, WalletID: "wallet-1"}, nil
+}
+
+// AuthorizeDeletedTransaction lets user-1 act on txn-1 only.
+func (fakeAuthorization) AuthorizeDeletedTransaction(ctx context.Context, userID, transactionID string) error {
+	if userID == "" {
+		return service.ErrUnauthenticated
+	}
+	if transactionID != "txn-1" {
+		return service.ErrPermissionDenied
+	}
+	return nil
+}
+
+func dialTrashServer(t *testing.T, transactions service.TransactionsService) *grpc.ClientConn {
+	t.Helper()
+	return dialServer(t, func(s *grpc.Server) {
+		s.RegisterService(&trashServiceDesc, &trashServer{
+			transactionService:   transactions,
+			authorizationService: fakeAuthorization{},
+		})
+	})
+}
+
+func TestTrashService_ListScopesToCallerWallets(t *testing.T) {
+	trash := &fakeTrashService{}
+	conn := dialTrashServer(t, trash)
+
+	out, err := invokeStruct(asUser("user-1"), conn, trashServiceName, "ListTrash", map[string]any{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"wallet-1"}, trash.listed)
+	list := out.GetFields()["data"].GetListValue().GetValues()
+	assert.Len(t, list, 1)
+	assert.Equal(t, "txn-1", list[0].GetStructValue().GetFields()["id"].GetStringValue())
+}
+
+func TestTrashService_ListRejectsForeignWallet(t *testing.T) {
+	trash := &fakeTrashService{}
+	conn := dialTrashServer(t, trash)
+
+	_, err := invokeStruct(asUser("user-1"), conn, trashServiceName, "ListTrash", map[string]any{
+		"wallet_ids": []any{"wallet-2"},
+	})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Nil(t, trash.listed)
+}
+
+func TestTrashService_Restore(t *testing.T) {
+	trash := &fakeTrashService{}
+	conn := dialTrashServer(t, trash)
+
+	out, err := invokeStruct(asUser("user-1"), conn, trashServiceName, "RestoreTransaction", map[string]any{"id": "txn-1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "txn-1", trash.restored)
+	assert.Equal(t, "txn-1", out.GetFields()["id"].GetStringValue())
+}
+
+func TestTrashService_RestoreRejectsForeignTransaction(t *testing.T) {
+	trash := &fakeTrashService{}
+	conn := dialTrashServer(t, trash)
+
+	_, err := invokeStruct(asUser("user-1"), conn, trashServiceName, "RestoreTransaction", map[string]any{"id": "txn-2"})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Empty(t, trash.restored)
+}
+
+func TestTrashService_RequiresAuthentication(t *testing.T) {
+	conn := dialTrashServer(t, &fakeTrashService{})
+
+	_, err := invokeStruct(context.Background(), conn, trashServiceName, "RestoreTransaction", map[string]any{"id": "txn-1"})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
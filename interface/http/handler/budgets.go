@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"net/http"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BudgetHandler struct {
+	budgetServ service.BudgetsService
+}
+
+func NewBudgetHandler(budgetServ service.BudgetsService) *BudgetHandler {
+	return &BudgetHandler{budgetServ}
+}
+
+func (budgetHandler *BudgetHandler) GetBudgets(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	budgets, err := budgetHandler.budgetServ.GetBudgets(ctx, userID)
+	if err != nil {
+		log.Error(data.LogGetBudgetsFailed, map[string]any{
+			"service":    data.BudgetService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get budgets data",
+		"data":       budgets,
+	})
+}
+
+func (budgetHandler *BudgetHandler) GetBudgetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	id := c.Param("id")
+
+	budget, err := budgetHandler.budgetServ.GetBudgetByID(ctx, userID, id)
+	if err != nil {
+		log.Error(data.LogGetBudgetByIDFailed, map[string]any{
+			"service":    data.BudgetService,
+			"request_id": requestID,
+			"budget_id":  id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get budget data by ID",
+		"data":       budget,
+	})
+}
+
+func (budgetHandler *BudgetHandler) CreateBudget(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var budget dto.BudgetsRequest
+	if err := c.ShouldBindJSON(&budget); err != nil {
+		log.Warn(data.LogCreateBudgetBadRequest, map[string]any{
+			"service":    data.BudgetService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	budgetCreated, err := budgetHandler.budgetServ.CreateBudget(ctx, userID, budget)
+	if err != nil {
+		log.Error(data.LogCreateBudgetFailed, map[string]any{
+			"service":    data.BudgetService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"statusCode": 201,
+		"status":     true,
+		"message":    "Create budget data",
+		"data":       budgetCreated,
+	})
+}
+
+func (budgetHandler *BudgetHandler) UpdateBudget(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	id := c.Param("id")
+
+	var budget dto.BudgetsRequest
+	if err := c.ShouldBindJSON(&budget); err != nil {
+		log.Warn(data.LogUpdateBudgetBadRequest, map[string]any{
+			"service":    data.BudgetService,
+			"request_id": requestID,
+			"budget_id":  id,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	budgetUpdated, err := budgetHandler.budgetServ.UpdateBudget(ctx, userID, id, budget)
+	if err != nil {
+		log.Error(data.LogUpdateBudgetFailed, map[string]any{
+			"service":    data.BudgetService,
+			"request_id": requestID,
+			"budget_id":  id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Update budget data",
+		"data":       budgetUpdated,
+	})
+}
+
+func (budgetHandler *BudgetHandler) DeleteBudget(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	id := c.Param("id")
+
+	budgetDeleted, err := budgetHandler.budgetServ.DeleteBudget(ctx, userID, id)
+	if err != nil {
+		log.Error(data.LogDeleteBudgetFailed, map[string]any{
+			"service":    data.BudgetService,
+			"request_id": requestID,
+			"budget_id":  id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Delete budget data",
+		"data":       budgetDeleted,
+	})
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"net/http"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CurrencyHandler struct {
+	currencyServ      service.CurrenciesService
+	authorizationServ service.AuthorizationService
+}
+
+func NewCurrencyHandler(currencyServ service.CurrenciesService, authorizationServ service.AuthorizationService) *CurrencyHandler {
+	return &CurrencyHandler{currencyServ, authorizationServ}
+}
+
+func (currencyHandler *CurrencyHandler) GetFXRates(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	rates, err := currencyHandler.currencyServ.GetFXRates(ctx, c.Query("base_currency"), c.Query("quote_currency"))
+	if err != nil {
+		log.Error(data.LogGetFXRatesFailed, map[string]any{
+			"service":    data.CurrencyService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get fx rates data",
+		"data":       rates,
+	})
+}
+
+func (currencyHandler *CurrencyHandler) UpsertFXRates(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	var rates []dto.FXRatesRequest
+	if err := c.ShouldBindJSON(&rates); err != nil {
+		log.Warn(data.LogUpsertFXRatesBadRequest, map[string]any{
+			"service":    data.CurrencyService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	stored, err := currencyHandler.currencyServ.UpsertFXRates(ctx, rates)
+	if err != nil {
+		log.Error(data.LogUpsertFXRatesFailed, map[string]any{
+			"service":    data.CurrencyService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Store fx rates",
+		"data":       stored,
+	})
+}
+
+func (currencyHandler *CurrencyHandler) ImportFXRates(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	var file dto.FXRatesImportRequest
+	if err := c.ShouldBindJSON(&file); err != nil {
+		log.Warn(data.LogImportFXRatesBadRequest, map[string]any{
+			"service":    data.CurrencyService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	stored, err := currencyHandler.currencyServ.ImportFXRates(ctx, file.Content)
+	if err != nil {
+		log.Error(data.LogImportFXRatesFailed, map[string]any{
+			"service":    data.CurrencyService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Import fx rates",
+		"data":       stored,
+	})
+}
+
+func (currencyHandler *CurrencyHandler) GetWalletCurrency(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	walletID := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := currencyHandler.authorizationServ.AuthorizeWallets(ctx, userID, walletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	currency, err := currencyHandler.currencyServ.GetWalletCurrency(ctx, walletID)
+	if err != nil {
+		log.Error(data.LogGetWalletCurrencyFailed, map[string]any{
+			"service":    data.CurrencyService,
+			"request_id": requestID,
+			"wallet_id":  walletID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get wallet currency",
+		"data":       currency,
+	})
+}
+
+func (currencyHandler *CurrencyHandler) SetWalletCurrency(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	walletID := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := currencyHandler.authorizationServ.AuthorizeWallets(ctx, userID, walletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	var request dto.WalletCurrencyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogSetWalletCurrencyBadRequest, map[string]any{
+			"service":    data.CurrencyService,
+			"request_id": requestID,
+			"wallet_id":  walletID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	currency, err := currencyHandler.currencyServ.SetWalletCurrency(ctx, walletID, request)
+	if err != nil {
+		log.Error(data.LogSetWalletCurrencyFailed, map[string]any{
+			"service":    data.CurrencyService,
+			"request_id": requestID,
+			"wallet_id":  walletID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Set wallet currency",
+		"data":       currency,
+	})
+}
@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"net/http"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DebtHandler struct {
+	debtServ          service.DebtsService
+	authorizationServ service.AuthorizationService
+}
+
+func NewDebtHandler(debtServ service.DebtsService, authorizationServ service.AuthorizationService) *DebtHandler {
+	return &DebtHandler{debtServ, authorizationServ}
+}
+
+// GetDebts lists the debts of the caller, only the open or the settled ones
+// with ?status=.
+func (debtHandler *DebtHandler) GetDebts(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	// Optional wallet_id filter, defaulting to every wallet of the caller
+	userID := interceptor.UserIDFromContext(ctx)
+	walletIDs, err := debtHandler.authorizationServ.ScopeWallets(ctx, userID, c.QueryArray("wallet_id")...)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	debts, err := debtHandler.debtServ.GetDebts(ctx, walletIDs, c.Query("status"))
+	if err != nil {
+		log.Error(data.LogGetDebtsFailed, map[string]any{
+			"service":    data.DebtService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get debts data",
+		"data":       debts,
+	})
+}
+
+func (debtHandler *DebtHandler) GetOverdueDebts(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	// Optional wallet_id filter, defaulting to every wallet of the caller
+	userID := interceptor.UserIDFromContext(ctx)
+	walletIDs, err := debtHandler.authorizationServ.ScopeWallets(ctx, userID, c.QueryArray("wallet_id")...)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	debts, err := debtHandler.debtServ.GetOverdueDebts(ctx, walletIDs)
+	if err != nil {
+		log.Error(data.LogGetDebtsFailed, map[string]any{
+			"service":    data.DebtService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get overdue debts data",
+		"data":       debts,
+	})
+}
+
+func (debtHandler *DebtHandler) GetDebtByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := debtHandler.authorizationServ.AuthorizeDebt(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	debt, err := debtHandler.debtServ.GetDebtByID(ctx, id)
+	if err != nil {
+		log.Error(data.LogGetDebtByIDFailed, map[string]any{
+			"service":    data.DebtService,
+			"request_id": requestID,
+			"debt_id":    id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get debt data",
+		"data":       debt,
+	})
+}
+
+func (debtHandler *DebtHandler) CreateDebt(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	var request dto.DebtsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogCreateDebtBadRequest, map[string]any{
+			"service":    data.DebtService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := debtHandler.authorizationServ.AuthorizeWallets(ctx, userID, request.WalletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	debt, err := debtHandler.debtServ.CreateDebt(ctx, request)
+	if err != nil {
+		log.Error(data.LogCreateDebtFailed, map[string]any{
+			"service":    data.DebtService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"statusCode": 201,
+		"status":     true,
+		"message":    "Create debt data",
+		"data":       debt,
+	})
+}
+
+func (debtHandler *DebtHandler) UpdateDebt(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	var request dto.UpdateDebtRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogUpdateDebtBadRequest, map[string]any{
+			"service":    data.DebtService,
+			"request_id": requestID,
+			"debt_id":    id,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := debtHandler.authorizationServ.AuthorizeDebt(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	debt, err := debtHandler.debtServ.UpdateDebt(ctx, id, request)
+	if err != nil {
+		log.Error(data.LogUpdateDebtFailed, map[string]any{
+			"service":    data.DebtService,
+			"request_id": requestID,
+			"debt_id":    id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Update debt data",
+		"data":       debt,
+	})
+}
+
+func (debtHandler *DebtHandler) RepayDebt(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	var request dto.DebtRepaymentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogRepayDebtBadRequest, map[string]any{
+			"service":    data.DebtService,
+			"request_id": requestID,
+			"debt_id":    id,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	// The caller must own the debt and the wallet the repayment moves through
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := debtHandler.authorizationServ.AuthorizeDebt(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+	if request.WalletID != "" {
+		if err := debtHandler.authorizationServ.AuthorizeWallets(ctx, userID, request.WalletID); err != nil {
+			abortUnauthorized(c, requestID, userID, err)
+			return
+		}
+	}
+
+	debt, err := debtHandler.debtServ.RepayDebt(ctx, id, request)
+	if err != nil {
+		log.Error(data.LogRepayDebtFailed, map[string]any{
+			"service":    data.DebtService,
+			"request_id": requestID,
+			"debt_id":    id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"statusCode": 201,
+		"status":     true,
+		"message":    "Repay debt data",
+		"data":       debt,
+	})
+}
+
+func (debtHandler *DebtHandler) DeleteDebt(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := debtHandler.authorizationServ.AuthorizeDebt(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	debt, err := debtHandler.debtServ.DeleteDebt(ctx, id)
+	if err != nil {
+		log.Error(data.LogDeleteDebtFailed, map[string]any{
+			"service":    data.DebtService,
+			"request_id": requestID,
+			"debt_id":    id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Delete debt data",
+		"data":       debt,
+	})
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+var exportContentTypes = map[string]string{
+	service.ExportFormatCSV:   "text/csv; charset=utf-8",
+	service.ExportFormatJSONL: "application/x-ndjson",
+}
+
+type ExportHandler struct {
+	exportServ        service.ExportsService
+	authorizationServ service.AuthorizationService
+}
+
+func NewExportHandler(exportServ service.ExportsService, authorizationServ service.AuthorizationService) *ExportHandler {
+	return &ExportHandler{exportServ, authorizationServ}
+}
+
+func (exportHandler *ExportHandler) ExportTransactions(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	format := c.DefaultQuery("format", service.ExportFormatCSV)
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid export format",
+		})
+		return
+	}
+
+	// Same filters as the paged transaction list; ?wallet_id= defaults to every wallet of the caller
+	walletIDs, err := exportHandler.authorizationServ.ScopeWallets(ctx, userID, c.QueryArray("wallet_id")...)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	q := repository.CursorQuery{
+		WalletIDs:    walletIDs,
+		CategoryID:   c.Query("category_id"),
+		CategoryType: c.Query("category_type"),
+		DateFrom:     c.Query("date_from"),
+		DateTo:       c.Query("date_to"),
+		Search:       c.Query("search"),
+		TagIDs:       c.QueryArray("tag_id"),
+		TagMatch:     repository.TagMatch(c.Query("tag_match")),
+		Statuses:     c.QueryArray("status"),
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="transactions_%s.%s"`, time.Now().Format("20060102"), format))
+
+	if err := exportHandler.exportServ.ExportTransactions(ctx, q, format, c.Writer); err != nil {
+		log.Error(data.LogExportTransactionsFailed, map[string]any{
+			"service":    data.ExportService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		// Once rows are on the wire the status is sent; cut the stream short instead
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+	}
+}
+
+func (exportHandler *ExportHandler) GetMonthlyStatement(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	walletID := c.Param("wallet_id")
+	if err := exportHandler.authorizationServ.AuthorizeWallets(ctx, userID, walletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	statement, pdf, err := exportHandler.exportServ.GetMonthlyStatement(ctx, walletID, c.Query("month"))
+	if err != nil {
+		log.Error(data.LogGetMonthlyStatementFailed, map[string]any{
+			"service":    data.ExportService,
+			"request_id": requestID,
+			"wallet_id":  walletID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, statement.FileName))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+func (exportHandler *ExportHandler) StoreMonthlyStatement(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	walletID := c.Param("wallet_id")
+	if err := exportHandler.authorizationServ.AuthorizeWallets(ctx, userID, walletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	statement, err := exportHandler.exportServ.StoreMonthlyStatement(ctx, walletID, c.Query("month"))
+	if err != nil {
+		log.Error(data.LogStoreMonthlyStatementFailed, map[string]any{
+			"service":    data.ExportService,
+			"request_id": requestID,
+			"wallet_id":  walletID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"statusCode": 201,
+		"status":     true,
+		"message":    "Store monthly statement",
+		"data":       statement,
+	})
+}
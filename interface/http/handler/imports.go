@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHandler struct {
+	importServ        service.ImportsService
+	authorizationServ service.AuthorizationService
+}
+
+func NewImportHandler(importServ service.ImportsService, authorizationServ service.AuthorizationService) *ImportHandler {
+	return &ImportHandler{importServ, authorizationServ}
+}
+
+func (importHandler *ImportHandler) GetImports(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	imports, err := importHandler.importServ.GetImports(ctx, userID)
+	if err != nil {
+		log.Error(data.LogGetImportsFailed, map[string]any{
+			"service":    data.ImportService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get imports data",
+		"data":       imports,
+	})
+}
+
+func (importHandler *ImportHandler) GetImportByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	id := c.Param("id")
+
+	imp, err := importHandler.importServ.GetImportByID(ctx, userID, id)
+	if err != nil {
+		log.Error(data.LogGetImportByIDFailed, map[string]any{
+			"service":    data.ImportService,
+			"request_id": requestID,
+			"import_id":  id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get import data by ID",
+		"data":       imp,
+	})
+}
+
+func (importHandler *ImportHandler) PreviewImport(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	var request dto.ImportsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogPreviewImportBadRequest, map[string]any{
+			"service":    data.ImportService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := importHandler.authorizationServ.AuthorizeWallets(ctx, userID, request.WalletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	preview, err := importHandler.importServ.PreviewImport(ctx, userID, request)
+	if err != nil {
+		log.Error(data.LogPreviewImportFailed, map[string]any{
+			"service":    data.ImportService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"statusCode": 201,
+		"status":     true,
+		"message":    "Preview import data",
+		"data":       preview,
+	})
+}
+
+func (importHandler *ImportHandler) CommitImport(c *gin.Context) {
+	importHandler.changeImport(c, "commit", data.LogCommitImportFailed, importHandler.importServ.CommitImport)
+}
+
+func (importHandler *ImportHandler) UndoImport(c *gin.Context) {
+	importHandler.changeImport(c, "undo", data.LogUndoImportFailed, importHandler.importServ.UndoImport)
+}
+
+// changeImport menjalankan aksi commit/undo pada import milik user
+func (importHandler *ImportHandler) changeImport(c *gin.Context, action, logMessage string, change func(ctx context.Context, userID, id string) (dto.ImportsResponse, error)) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	id := c.Param("id")
+
+	imp, err := change(ctx, userID, id)
+	if err != nil {
+		log.Error(logMessage, map[string]any{
+			"service":    data.ImportService,
+			"request_id": requestID,
+			"import_id":  id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Import " + action + " success",
+		"data":       imp,
+	})
+}
@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReconciliationHandler struct {
+	reconciliationServ service.ReconciliationsService
+	authorizationServ  service.AuthorizationService
+}
+
+func NewReconciliationHandler(reconciliationServ service.ReconciliationsService, authorizationServ service.AuthorizationService) *ReconciliationHandler {
+	return &ReconciliationHandler{reconciliationServ, authorizationServ}
+}
+
+func (reconciliationHandler *ReconciliationHandler) GetReconciliations(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	reconciliations, err := reconciliationHandler.reconciliationServ.GetReconciliations(ctx, userID)
+	if err != nil {
+		log.Error(data.LogGetReconciliationsFailed, map[string]any{
+			"service":    data.ReconciliationService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get reconciliations data",
+		"data":       reconciliations,
+	})
+}
+
+func (reconciliationHandler *ReconciliationHandler) GetReconciliationByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	id := c.Param("id")
+
+	reconciliation, err := reconciliationHandler.reconciliationServ.GetReconciliationByID(ctx, userID, id)
+	if err != nil {
+		log.Error(data.LogGetReconciliationByIDFailed, map[string]any{
+			"service":           data.ReconciliationService,
+			"request_id":        requestID,
+			"reconciliation_id": id,
+			"error":             err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get reconciliation data by ID",
+		"data":       reconciliation,
+	})
+}
+
+func (reconciliationHandler *ReconciliationHandler) StartReconciliation(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	var request dto.ReconciliationsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogStartReconciliationBadRequest, map[string]any{
+			"service":    data.ReconciliationService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := reconciliationHandler.authorizationServ.AuthorizeWallets(ctx, userID, request.WalletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	reconciliation, err := reconciliationHandler.reconciliationServ.StartReconciliation(ctx, userID, request)
+	if err != nil {
+		log.Error(data.LogStartReconciliationFailed, map[string]any{
+			"service":    data.ReconciliationService,
+			"request_id": requestID,
+			"wallet_id":  request.WalletID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"statusCode": 201,
+		"status":     true,
+		"message":    "Start reconciliation",
+		"data":       reconciliation,
+	})
+}
+
+// CompleteReconciliation answers a selection that misses the statement
+// balance with the session and its difference, so the caller can adjust it.
+func (reconciliationHandler *ReconciliationHandler) CompleteReconciliation(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	id := c.Param("id")
+
+	var request dto.CompleteReconciliationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogCompleteReconciliationBadRequest, map[string]any{
+			"service":           data.ReconciliationService,
+			"request_id":        requestID,
+			"reconciliation_id": id,
+			"error":             err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	reconciliation, err := reconciliationHandler.reconciliationServ.CompleteReconciliation(ctx, userID, id, request)
+	if err != nil {
+		log.Error(data.LogCompleteReconciliationFailed, map[string]any{
+			"service":           data.ReconciliationService,
+			"request_id":        requestID,
+			"reconciliation_id": id,
+			"error":             err.Error(),
+		})
+		if errors.Is(err, service.ErrReconciliationMismatch) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"statusCode": 400,
+				"status":     false,
+				"message":    "cleared balance does not match the statement balance",
+				"data":       reconciliation,
+			})
+			return
+		}
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Complete reconciliation",
+		"data":       reconciliation,
+	})
+}
+
+func (reconciliationHandler *ReconciliationHandler) CancelReconciliation(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	id := c.Param("id")
+
+	reconciliation, err := reconciliationHandler.reconciliationServ.CancelReconciliation(ctx, userID, id)
+	if err != nil {
+		log.Error(data.LogCancelReconciliationFailed, map[string]any{
+			"service":           data.ReconciliationService,
+			"request_id":        requestID,
+			"reconciliation_id": id,
+			"error":             err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Cancel reconciliation",
+		"data":       reconciliation,
+	})
+}
+
+func (reconciliationHandler *ReconciliationHandler) GetBalanceStage(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	walletID := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := reconciliationHandler.authorizationServ.AuthorizeWallets(ctx, userID, walletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	stage, err := reconciliationHandler.reconciliationServ.GetBalanceStage(ctx, walletID)
+	if err != nil {
+		log.Error(data.LogGetBalanceStageFailed, map[string]any{
+			"service":    data.ReconciliationService,
+			"request_id": requestID,
+			"wallet_id":  walletID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get wallet balance stage",
+		"data":       stage,
+	})
+}
+
+func (reconciliationHandler *ReconciliationHandler) SetBalanceStage(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	walletID := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := reconciliationHandler.authorizationServ.AuthorizeWallets(ctx, userID, walletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	var request dto.BalanceStageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogSetBalanceStageBadRequest, map[string]any{
+			"service":    data.ReconciliationService,
+			"request_id": requestID,
+			"wallet_id":  walletID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	stage, err := reconciliationHandler.reconciliationServ.SetBalanceStage(ctx, walletID, request)
+	if err != nil {
+		log.Error(data.LogSetBalanceStageFailed, map[string]any{
+			"service":    data.ReconciliationService,
+			"request_id": requestID,
+			"wallet_id":  walletID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Set wallet balance stage",
+		"data":       stage,
+	})
+}
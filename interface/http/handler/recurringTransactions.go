@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecurringTransactionHandler struct {
+	recurringServ     service.RecurringTransactionsService
+	authorizationServ service.AuthorizationService
+}
+
+func NewRecurringTransactionHandler(recurringServ service.RecurringTransactionsService, authorizationServ service.AuthorizationService) *RecurringTransactionHandler {
+	return &RecurringTransactionHandler{recurringServ, authorizationServ}
+}
+
+func (recurringHandler *RecurringTransactionHandler) GetRecurringTransactions(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	// Optional ?wallet_id= filters, defaulting to every wallet of the caller
+	walletIDs, err := recurringHandler.authorizationServ.ScopeWallets(ctx, userID, c.QueryArray("wallet_id")...)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	recurrings, err := recurringHandler.recurringServ.GetRecurringTransactionsByWalletIDs(ctx, walletIDs)
+	if err != nil {
+		log.Error(data.LogGetRecurringTransactionsFailed, map[string]any{
+			"service":    data.RecurringService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get recurring transactions data",
+		"data":       recurrings,
+	})
+}
+
+func (recurringHandler *RecurringTransactionHandler) GetRecurringTransactionByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := recurringHandler.authorizationServ.AuthorizeRecurringTransaction(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	recurring, err := recurringHandler.recurringServ.GetRecurringTransactionByID(ctx, id)
+	if err != nil {
+		log.Error(data.LogGetRecurringTransactionByIDFailed, map[string]any{
+			"service":      data.RecurringService,
+			"request_id":   requestID,
+			"recurring_id": id,
+			"error":        err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get recurring transaction data by ID",
+		"data":       recurring,
+	})
+}
+
+func (recurringHandler *RecurringTransactionHandler) CreateRecurringTransaction(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	var recurring dto.RecurringTransactionsRequest
+	if err := c.ShouldBindJSON(&recurring); err != nil {
+		log.Warn(data.LogCreateRecurringTransactionBadRequest, map[string]any{
+			"service":    data.RecurringService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := recurringHandler.authorizationServ.AuthorizeWallets(ctx, userID, recurring.WalletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	recurringCreated, err := recurringHandler.recurringServ.CreateRecurringTransaction(ctx, recurring)
+	if err != nil {
+		log.Error(data.LogCreateRecurringTransactionFailed, map[string]any{
+			"service":    data.RecurringService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"statusCode": 201,
+		"status":     true,
+		"message":    "Create recurring transaction data",
+		"data":       recurringCreated,
+	})
+}
+
+func (recurringHandler *RecurringTransactionHandler) UpdateRecurringTransaction(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	var recurring dto.RecurringTransactionsRequest
+	if err := c.ShouldBindJSON(&recurring); err != nil {
+		log.Warn(data.LogUpdateRecurringTransactionBadRequest, map[string]any{
+			"service":      data.RecurringService,
+			"request_id":   requestID,
+			"recurring_id": id,
+			"error":        err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := recurringHandler.authorizationServ.AuthorizeRecurringTransaction(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+	if err := recurringHandler.authorizationServ.AuthorizeWallets(ctx, userID, recurring.WalletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	recurringUpdated, err := recurringHandler.recurringServ.UpdateRecurringTransaction(ctx, id, recurring)
+	if err != nil {
+		log.Error(data.LogUpdateRecurringTransactionFailed, map[string]any{
+			"service":      data.RecurringService,
+			"request_id":   requestID,
+			"recurring_id": id,
+			"error":        err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Update recurring transaction data",
+		"data":       recurringUpdated,
+	})
+}
+
+func (recurringHandler *RecurringTransactionHandler) DeleteRecurringTransaction(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := recurringHandler.authorizationServ.AuthorizeRecurringTransaction(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	recurringDeleted, err := recurringHandler.recurringServ.DeleteRecurringTransaction(ctx, id)
+	if err != nil {
+		log.Error(data.LogDeleteRecurringTransactionFailed, map[string]any{
+			"service":      data.RecurringService,
+			"request_id":   requestID,
+			"recurring_id": id,
+			"error":        err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Delete recurring transaction data",
+		"data":       recurringDeleted,
+	})
+}
+
+func (recurringHandler *RecurringTransactionHandler) PauseRecurringTransaction(c *gin.Context) {
+	recurringHandler.changeSchedule(c, "pause", recurringHandler.recurringServ.PauseRecurringTransaction)
+}
+
+func (recurringHandler *RecurringTransactionHandler) ResumeRecurringTransaction(c *gin.Context) {
+	recurringHandler.changeSchedule(c, "resume", recurringHandler.recurringServ.ResumeRecurringTransaction)
+}
+
+func (recurringHandler *RecurringTransactionHandler) SkipNextOccurrence(c *gin.Context) {
+	recurringHandler.changeSchedule(c, "skip", recurringHandler.recurringServ.SkipNextOccurrence)
+}
+
+// changeSchedule menjalankan aksi pause/resume/skip setelah memastikan kepemilikan jadwal
+func (recurringHandler *RecurringTransactionHandler) changeSchedule(c *gin.Context, action string, change func(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error)) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := recurringHandler.authorizationServ.AuthorizeRecurringTransaction(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	recurring, err := change(ctx, id)
+	if err != nil {
+		log.Error(data.LogChangeRecurringScheduleFailed, map[string]any{
+			"service":      data.RecurringService,
+			"request_id":   requestID,
+			"recurring_id": id,
+			"action":       action,
+			"error":        err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Recurring transaction " + action + " success",
+		"data":       recurring,
+	})
+}
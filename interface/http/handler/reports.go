@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportServ        service.ReportsService
+	authorizationServ service.AuthorizationService
+}
+
+func NewReportHandler(reportServ service.ReportsService, authorizationServ service.AuthorizationService) *ReportHandler {
+	return &ReportHandler{reportServ, authorizationServ}
+}
+
+func (reportHandler *ReportHandler) GetTransactionSummary(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	// Same filters as the paged transaction list; ?wallet_id= defaults to every wallet of the caller
+	walletIDs, err := reportHandler.authorizationServ.ScopeWallets(ctx, userID, c.QueryArray("wallet_id")...)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	q := repository.CursorQuery{
+		WalletIDs:    walletIDs,
+		CategoryID:   c.Query("category_id"),
+		CategoryType: c.Query("category_type"),
+		DateFrom:     c.Query("date_from"),
+		DateTo:       c.Query("date_to"),
+		Search:       c.Query("search"),
+		TagIDs:       c.QueryArray("tag_id"),
+		TagMatch:     repository.TagMatch(c.Query("tag_match")),
+		Statuses:     c.QueryArray("status"),
+		BaseCurrency: c.Query("base_currency"),
+	}
+
+	summary, err := reportHandler.reportServ.GetTransactionSummary(ctx, q, c.Query("group_by"))
+	if err != nil {
+		log.Error(data.LogGetTransactionSummaryFailed, map[string]any{
+			"service":    data.ReportService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get transaction summary data",
+		"data":       summary,
+	})
+}
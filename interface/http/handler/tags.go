@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"net/http"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	tagServ service.TagsService
+}
+
+func NewTagHandler(tagServ service.TagsService) *TagHandler {
+	return &TagHandler{tagServ}
+}
+
+func (tagHandler *TagHandler) GetTags(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	tags, err := tagHandler.tagServ.GetTags(ctx, userID)
+	if err != nil {
+		log.Error(data.LogGetTagsFailed, map[string]any{
+			"service":    data.TagService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get tags data",
+		"data":       tags,
+	})
+}
+
+func (tagHandler *TagHandler) GetTagByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	id := c.Param("id")
+
+	tag, err := tagHandler.tagServ.GetTagByID(ctx, userID, id)
+	if err != nil {
+		log.Error(data.LogGetTagByIDFailed, map[string]any{
+			"service":    data.TagService,
+			"request_id": requestID,
+			"tag_id":     id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get tag data by ID",
+		"data":       tag,
+	})
+}
+
+func (tagHandler *TagHandler) CreateTag(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	var tag dto.TagsRequest
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		log.Warn(data.LogCreateTagBadRequest, map[string]any{
+			"service":    data.TagService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	tagCreated, err := tagHandler.tagServ.CreateTag(ctx, userID, tag)
+	if err != nil {
+		log.Error(data.LogCreateTagFailed, map[string]any{
+			"service":    data.TagService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"statusCode": 201,
+		"status":     true,
+		"message":    "Create tag data",
+		"data":       tagCreated,
+	})
+}
+
+func (tagHandler *TagHandler) UpdateTag(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	id := c.Param("id")
+
+	var tag dto.TagsRequest
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		log.Warn(data.LogUpdateTagBadRequest, map[string]any{
+			"service":    data.TagService,
+			"request_id": requestID,
+			"tag_id":     id,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	tagUpdated, err := tagHandler.tagServ.UpdateTag(ctx, userID, id, tag)
+	if err != nil {
+		log.Error(data.LogUpdateTagFailed, map[string]any{
+			"service":    data.TagService,
+			"request_id": requestID,
+			"tag_id":     id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Update tag data",
+		"data":       tagUpdated,
+	})
+}
+
+func (tagHandler *TagHandler) DeleteTag(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
+
+	id := c.Param("id")
+
+	tagDeleted, err := tagHandler.tagServ.DeleteTag(ctx, userID, id)
+	if err != nil {
+		log.Error(data.LogDeleteTagFailed, map[string]any{
+			"service":    data.TagService,
+			"request_id": requestID,
+			"tag_id":     id,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Delete tag data",
+		"data":       tagDeleted,
+	})
+}
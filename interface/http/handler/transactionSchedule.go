@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"net/http"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (transactionHandler *TransactionHandler) GetScheduledTransactions(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	// Optional wallet_id filter, defaulting to every wallet of the caller
+	userID := interceptor.UserIDFromContext(ctx)
+	walletIDs, err := transactionHandler.authorizationServ.ScopeWallets(ctx, userID, c.QueryArray("wallet_id")...)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	transactions, err := transactionHandler.transactionServ.GetScheduledTransactions(ctx, walletIDs)
+	if err != nil {
+		log.Error(data.LogGetScheduledTransactionsFailed, map[string]any{
+			"service":    data.TransactionService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get scheduled transactions data",
+		"data":       transactions,
+	})
+}
+
+func (transactionHandler *TransactionHandler) CancelScheduledTransaction(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := transactionHandler.authorizationServ.AuthorizeTransaction(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	transactionCancelled, err := transactionHandler.transactionServ.CancelScheduledTransaction(ctx, id)
+	if err != nil {
+		log.Error(data.LogCancelScheduledTransactionFailed, map[string]any{
+			"service":        data.TransactionService,
+			"request_id":     requestID,
+			"transaction_id": id,
+			"error":          err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Cancel scheduled transaction data",
+		"data":       transactionCancelled,
+	})
+}
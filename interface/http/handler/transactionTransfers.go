@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"net/http"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (transactionHandler *TransactionHandler) GetTransfers(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	// Optional wallet_id filter, defaulting to every wallet of the caller
+	userID := interceptor.UserIDFromContext(ctx)
+	walletIDs, err := transactionHandler.authorizationServ.ScopeWallets(ctx, userID, c.QueryArray("wallet_id")...)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	transfers, err := transactionHandler.transactionServ.GetTransfers(ctx, walletIDs)
+	if err != nil {
+		log.Error(data.LogGetTransfersFailed, map[string]any{
+			"service":    data.TransactionService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get transfers data",
+		"data":       transfers,
+	})
+}
+
+func (transactionHandler *TransactionHandler) GetTransferByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := transactionHandler.authorizationServ.AuthorizeTransfer(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	transfer, err := transactionHandler.transactionServ.GetTransferByID(ctx, id)
+	if err != nil {
+		log.Error(data.LogGetTransferByIDFailed, map[string]any{
+			"service":     data.TransactionService,
+			"request_id":  requestID,
+			"transfer_id": id,
+			"error":       err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get transfer data",
+		"data":       transfer,
+	})
+}
+
+func (transactionHandler *TransactionHandler) UpdateTransfer(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	var request dto.UpdateTransferRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogUpdateTransferBadRequest, map[string]any{
+			"service":     data.TransactionService,
+			"request_id":  requestID,
+			"transfer_id": id,
+			"error":       err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	// The caller must own the wallets the transfer leaves and the ones it moves to
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := transactionHandler.authorizationServ.AuthorizeTransfer(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+	if err := transactionHandler.authorizationServ.AuthorizeWallets(ctx, userID, request.FromWalletID, request.ToWalletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	transfer, err := transactionHandler.transactionServ.UpdateTransfer(ctx, id, request)
+	if err != nil {
+		log.Error(data.LogUpdateTransferFailed, map[string]any{
+			"service":     data.TransactionService,
+			"request_id":  requestID,
+			"transfer_id": id,
+			"error":       err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Update transfer data",
+		"data":       transfer,
+	})
+}
+
+func (transactionHandler *TransactionHandler) DeleteTransfer(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := transactionHandler.authorizationServ.AuthorizeTransfer(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	transfer, err := transactionHandler.transactionServ.DeleteTransfer(ctx, id)
+	if err != nil {
+		log.Error(data.LogDeleteTransferFailed, map[string]any{
+			"service":     data.TransactionService,
+			"request_id":  requestID,
+			"transfer_id": id,
+			"error":       err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Delete transfer data",
+		"data":       transfer,
+	})
+}
@@ -1,30 +1,41 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
 	"refina-transaction/internal/service"
 	"refina-transaction/internal/types/dto"
+	helper "refina-transaction/internal/utils"
 	"refina-transaction/internal/utils/data"
 
 	"github.com/gin-gonic/gin"
 )
 
 type TransactionHandler struct {
-	transactionServ service.TransactionsService
+	transactionServ   service.TransactionsService
+	authorizationServ service.AuthorizationService
 }
 
-func NewTransactionHandler(transactionServ service.TransactionsService) *TransactionHandler {
-	return &TransactionHandler{transactionServ}
+func NewTransactionHandler(transactionServ service.TransactionsService, authorizationServ service.AuthorizationService) *TransactionHandler {
+	return &TransactionHandler{transactionServ, authorizationServ}
 }
 
 func (transactionHandler *TransactionHandler) GetAllTransactions(c *gin.Context) {
 	ctx := c.Request.Context()
 	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
 
-	transactions, err := transactionHandler.transactionServ.GetAllTransactions(ctx)
+	walletIDs, err := transactionHandler.authorizationServ.GetUserWalletIDs(ctx, userID)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	transactions, err := transactionHandler.transactionServ.GetTransactionsByWalletIDs(ctx, walletIDs)
 	if err != nil {
 		log.Error(data.LogGetAllTransactionsFailed, map[string]any{
 			"service":    data.TransactionService,
@@ -54,6 +65,12 @@ func (transactionHandler *TransactionHandler) GetTransactionByID(c *gin.Context)
 
 	id := c.Param("id")
 
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := transactionHandler.authorizationServ.AuthorizeTransaction(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
 	transaction, err := transactionHandler.transactionServ.GetTransactionByID(ctx, id)
 	if err != nil {
 		log.Error(data.LogGetTransactionByIDFailed, map[string]any{
@@ -98,6 +115,13 @@ func (transactionHandler *TransactionHandler) GetTransactionsByUserID(c *gin.Con
 		return
 	}
 
+	userID := interceptor.UserIDFromContext(ctx)
+	ids, err := transactionHandler.authorizationServ.ScopeWallets(ctx, userID, ids...)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
 	transactions, err := transactionHandler.transactionServ.GetTransactionsByWalletIDs(ctx, ids)
 	if err != nil {
 		log.Error(data.LogGetTransactionsByWalletIDsFailed, map[string]any{
@@ -123,8 +147,9 @@ func (transactionHandler *TransactionHandler) GetTransactionsByUserID(c *gin.Con
 }
 
 func (transactionHandler *TransactionHandler) CreateTransaction(c *gin.Context) {
-	ctx := c.Request.Context()
+	ctx := helper.WithIdempotencyKey(c.Request.Context(), c.GetHeader(data.IDEMPOTENCY_KEY_HEADER))
 	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+	userID := interceptor.UserIDFromContext(ctx)
 
 	types := c.Param("type")
 
@@ -149,6 +174,10 @@ func (transactionHandler *TransactionHandler) CreateTransaction(c *gin.Context)
 			})
 			return
 		}
+		if err := transactionHandler.authorizationServ.AuthorizeWallets(ctx, userID, transaction.WalletID); err != nil {
+			abortUnauthorized(c, requestID, userID, err)
+			return
+		}
 		transactionCreated, err = transactionHandler.transactionServ.CreateTransaction(ctx, transaction)
 	} else {
 		var transaction dto.FundTransferRequest
@@ -165,6 +194,10 @@ func (transactionHandler *TransactionHandler) CreateTransaction(c *gin.Context)
 			})
 			return
 		}
+		if err := transactionHandler.authorizationServ.AuthorizeWallets(ctx, userID, transaction.FromWalletID, transaction.ToWalletID); err != nil {
+			abortUnauthorized(c, requestID, userID, err)
+			return
+		}
 		transactionCreated, err = transactionHandler.transactionServ.FundTransfer(ctx, transaction)
 	}
 
@@ -219,6 +252,12 @@ func (transactionHandler *TransactionHandler) UploadAttachment(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	userID := interceptor.UserIDFromContext(ctx)
+
+	if err := transactionHandler.authorizationServ.AuthorizeTransaction(ctx, userID, ID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
 
 	attachment, err := transactionHandler.transactionServ.UploadAttachment(ctx, nil, ID, payload.Files)
 	if err != nil {
@@ -267,6 +306,16 @@ func (transactionHandler *TransactionHandler) UpdateTransaction(c *gin.Context)
 		return
 	}
 
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := transactionHandler.authorizationServ.AuthorizeTransaction(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+	if err := transactionHandler.authorizationServ.AuthorizeWallets(ctx, userID, transaction.WalletID); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
 	transactionUpdated, err := transactionHandler.transactionServ.UpdateTransaction(ctx, id, transaction)
 	if err != nil {
 		log.Error(data.LogUpdateTransactionFailed, map[string]any{
@@ -292,12 +341,71 @@ func (transactionHandler *TransactionHandler) UpdateTransaction(c *gin.Context)
 	})
 }
 
+func (transactionHandler *TransactionHandler) UpdateTransactionStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	var request dto.UpdateTransactionStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogUpdateTransactionStatusBadRequest, map[string]any{
+			"service":        data.TransactionService,
+			"request_id":     requestID,
+			"transaction_id": id,
+			"error":          err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := transactionHandler.authorizationServ.AuthorizeTransaction(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	transactionUpdated, err := transactionHandler.transactionServ.UpdateTransactionStatus(ctx, id, request.Status)
+	if err != nil {
+		log.Error(data.LogUpdateTransactionStatusFailed, map[string]any{
+			"service":        data.TransactionService,
+			"request_id":     requestID,
+			"transaction_id": id,
+			"error":          err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Update transaction status",
+		"data":       transactionUpdated,
+	})
+}
+
 func (transactionHandler *TransactionHandler) DeleteTransaction(c *gin.Context) {
 	ctx := c.Request.Context()
 	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
 
 	id := c.Param("id")
 
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := transactionHandler.authorizationServ.AuthorizeTransaction(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
 	transactionDeleted, err := transactionHandler.transactionServ.DeleteTransaction(ctx, id)
 	if err != nil {
 		log.Error(data.LogDeleteTransactionHTTPFailed, map[string]any{
@@ -323,10 +431,197 @@ func (transactionHandler *TransactionHandler) DeleteTransaction(c *gin.Context)
 	})
 }
 
+func (transactionHandler *TransactionHandler) GetDeletedTransactions(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	// Optional wallet_id filter, defaulting to every wallet of the caller
+	userID := interceptor.UserIDFromContext(ctx)
+	walletIDs, err := transactionHandler.authorizationServ.ScopeWallets(ctx, userID, c.QueryArray("wallet_id")...)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	transactions, err := transactionHandler.transactionServ.GetDeletedTransactions(ctx, walletIDs)
+	if err != nil {
+		log.Error(data.LogGetDeletedTransactionsFailed, map[string]any{
+			"service":    data.TransactionService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get deleted transactions data",
+		"data":       transactions,
+	})
+}
+
+func (transactionHandler *TransactionHandler) RestoreTransaction(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := transactionHandler.authorizationServ.AuthorizeDeletedTransaction(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	transactionRestored, err := transactionHandler.transactionServ.RestoreTransaction(ctx, id)
+	if err != nil {
+		log.Error(data.LogRestoreTransactionFailed, map[string]any{
+			"service":        data.TransactionService,
+			"request_id":     requestID,
+			"transaction_id": id,
+			"error":          err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Restore transaction data",
+		"data":       transactionRestored,
+	})
+}
+
+func (transactionHandler *TransactionHandler) BatchTransactions(c *gin.Context) {
+	ctx := helper.WithIdempotencyKey(c.Request.Context(), c.GetHeader(data.IDEMPOTENCY_KEY_HEADER))
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	var request dto.BatchTransactionsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogBatchTransactionsBadRequest, map[string]any{
+			"service":    data.TransactionService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := transactionHandler.authorizationServ.AuthorizeBatch(ctx, userID, request); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	batch, err := transactionHandler.transactionServ.BatchTransactions(ctx, request)
+	if err != nil {
+		log.Error(data.LogBatchTransactionsFailed, map[string]any{
+			"service":    data.TransactionService,
+			"request_id": requestID,
+			"items":      len(request.Items),
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		response := gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		}
+		// Tell the caller which items failed
+		if errors.Is(err, service.ErrInvalidBatch) && len(batch.Results) > 0 {
+			response["data"] = batch
+		}
+		c.JSON(statusCode, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Batch transactions data",
+		"data":       batch,
+	})
+}
+
+func (transactionHandler *TransactionHandler) GetTransactionHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	id := c.Param("id")
+
+	userID := interceptor.UserIDFromContext(ctx)
+	if err := transactionHandler.authorizationServ.AuthorizeTransactionHistory(ctx, userID, id); err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	history, err := transactionHandler.transactionServ.GetTransactionHistory(ctx, id)
+	if err != nil {
+		log.Error(data.LogGetTransactionHistoryFailed, map[string]any{
+			"service":        data.TransactionService,
+			"request_id":     requestID,
+			"transaction_id": id,
+			"error":          err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get transaction history data",
+		"data":       history,
+	})
+}
+
+// abortUnauthorized menulis response untuk request yang ditolak oleh pengecekan kepemilikan
+func abortUnauthorized(c *gin.Context, requestID any, userID string, err error) {
+	log.Warn(data.LogAuthorizationDenied, map[string]any{
+		"service":    data.TransactionService,
+		"request_id": requestID,
+		"user_id":    userID,
+		"error":      err.Error(),
+	})
+	statusCode, message := mapServiceError(err)
+	c.AbortWithStatusJSON(statusCode, gin.H{
+		"statusCode": statusCode,
+		"status":     false,
+		"message":    message,
+	})
+}
+
 // mapServiceError menerjemahkan error dari service ke HTTP status + pesan aman untuk client
 func mapServiceError(err error) (int, string) {
 	msg := err.Error()
 	switch {
+	case errors.Is(err, service.ErrUnauthenticated):
+		return http.StatusUnauthorized, "unauthenticated"
+	case errors.Is(err, service.ErrPermissionDenied):
+		return http.StatusForbidden, "permission denied"
+	case errors.Is(err, service.ErrIdempotencyKeyReused):
+		return http.StatusConflict, "idempotency key already used for a different request"
 	case strings.Contains(msg, "not found"):
 		return http.StatusNotFound, "resource not found"
 	case strings.Contains(msg, "invalid"),
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WalletDriftHandler struct {
+	driftServ         service.WalletDriftsService
+	authorizationServ service.AuthorizationService
+}
+
+func NewWalletDriftHandler(driftServ service.WalletDriftsService, authorizationServ service.AuthorizationService) *WalletDriftHandler {
+	return &WalletDriftHandler{driftServ, authorizationServ}
+}
+
+func (driftHandler *WalletDriftHandler) GetWalletDrifts(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	// Optional wallet_id filter, defaulting to every wallet of the caller
+	userID := interceptor.UserIDFromContext(ctx)
+	walletIDs, err := driftHandler.authorizationServ.ScopeWallets(ctx, userID, c.QueryArray("wallet_id")...)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	drifts, err := driftHandler.driftServ.GetWalletDrifts(ctx, walletIDs)
+	if err != nil {
+		log.Error(data.LogGetWalletDriftsFailed, map[string]any{
+			"service":    data.WalletDriftService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get wallet drifts data",
+		"data":       drifts,
+	})
+}
+
+func (driftHandler *WalletDriftHandler) CheckWalletDrifts(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+	var request dto.CheckWalletDriftsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn(data.LogCheckWalletDriftBadRequest, map[string]any{
+			"service":    data.WalletDriftService,
+			"request_id": requestID,
+			"error":      err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    "invalid request body",
+		})
+		return
+	}
+
+	userID := interceptor.UserIDFromContext(ctx)
+	walletIDs, err := driftHandler.authorizationServ.ScopeWallets(ctx, userID, request.WalletIDs...)
+	if err != nil {
+		abortUnauthorized(c, requestID, userID, err)
+		return
+	}
+
+	drifts, err := driftHandler.driftServ.CheckWallets(ctx, walletIDs, request.Correct)
+	if err != nil {
+		log.Error(data.LogCheckWalletDriftFailed, map[string]any{
+			"service":    data.WalletDriftService,
+			"request_id": requestID,
+			"correct":    request.Correct,
+			"error":      err.Error(),
+		})
+		statusCode, message := mapServiceError(err)
+		c.JSON(statusCode, gin.H{
+			"statusCode": statusCode,
+			"status":     false,
+			"message":    message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Check wallet drifts",
+		"data":       drifts,
+	})
+}
@@ -1 +1,298 @@
 package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"refina-transaction/config/env"
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errMissingToken  = errors.New("missing bearer token")
+	errMalformedJWT  = errors.New("malformed token")
+	errInvalidSig    = errors.New("invalid token signature")
+	errTokenExpired  = errors.New("token expired")
+	errTokenNotValid = errors.New("token not valid")
+	errUnknownKey    = errors.New("unknown signing key")
+)
+
+// jwtClaims are the claims issued by the auth service. The user ID is read
+// from "id" and falls back to the standard "sub" claim.
+type jwtClaims struct {
+	Subject        string   `json:"sub"`
+	ID             string   `json:"id"`
+	Email          string   `json:"email"`
+	Provider       string   `json:"provider"`
+	ProviderUserID string   `json:"provider_user_id"`
+	Role           string   `json:"role"`
+	Issuer         string   `json:"iss"`
+	Audience       audience `json:"aud"`
+	ExpiresAt      int64    `json:"exp"`
+	NotBefore      int64    `json:"nbf"`
+}
+
+// audience accepts both the single string and the array form of "aud".
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type jwtVerifier struct {
+	algorithm string
+	secret    []byte
+	keys      map[string]*rsa.PublicKey
+	issuer    string
+	audience  string
+}
+
+// AuthMiddleware validates the bearer JWT of every request and stores the
+// caller identity in the request context, using the same keys as the gRPC
+// interceptor so handlers read it with interceptor.UserIDFromContext.
+func AuthMiddleware() gin.HandlerFunc {
+	verifier, err := newJWTVerifier(env.Cfg.Auth)
+	if err != nil {
+		log.Fatal(data.LogAuthMiddlewareSetupFailed, map[string]any{
+			"service": data.HTTPServerService,
+			"error":   err.Error(),
+		})
+	}
+
+	return func(c *gin.Context) {
+		requestID, _ := c.Get(data.REQUEST_ID_LOCAL_KEY)
+
+		claims, err := verifier.verify(bearerToken(c.GetHeader("Authorization")), time.Now())
+		if err != nil {
+			log.Warn(data.LogAuthTokenRejected, map[string]any{
+				"service":    data.HTTPServerService,
+				"request_id": requestID,
+				"error":      err.Error(),
+			})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"statusCode": 401,
+				"status":     false,
+				"message":    "unauthenticated",
+			})
+			return
+		}
+
+		user := interceptor.UserMetadata{
+			UserID:         claims.ID,
+			Email:          claims.Email,
+			Provider:       claims.Provider,
+			ProviderUserID: claims.ProviderUserID,
+			Role:           claims.Role,
+		}
+		if user.UserID == "" {
+			user.UserID = claims.Subject
+		}
+
+		c.Request = c.Request.WithContext(interceptor.WithUserMetadata(c.Request.Context(), user))
+		c.Set(data.USER_DATA_LOCAL_KEY, dto.UserData{ID: user.UserID, Email: user.Email})
+
+		c.Next()
+	}
+}
+
+func bearerToken(header string) string {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func newJWTVerifier(cfg env.Auth) (*jwtVerifier, error) {
+	verifier := &jwtVerifier{
+		algorithm: cfg.JWTAlgorithm,
+		issuer:    cfg.JWTIssuer,
+		audience:  cfg.JWTAudience,
+	}
+
+	switch cfg.JWTAlgorithm {
+	case "HS256":
+		if cfg.JWTSecret == "" {
+			return nil, errors.New("jwt secret is required for HS256")
+		}
+		verifier.secret = []byte(cfg.JWTSecret)
+	case "RS256":
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		verifier.keys = keys
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", cfg.JWTAlgorithm)
+	}
+
+	return verifier, nil
+}
+
+// loadJWKS reads the RSA signing keys of a JWKS document, indexed by kid.
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwks file [path=%s]: %w", path, err)
+	}
+
+	var set struct {
+		Keys []struct {
+			KeyType string `json:"kty"`
+			KeyID   string `json:"kid"`
+			Use     string `json:"use"`
+			N       string `json:"n"`
+			E       string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("parse jwks file [path=%s]: %w", path, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.KeyType != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode jwks modulus [kid=%s]: %w", key.KeyID, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode jwks exponent [kid=%s]: %w", key.KeyID, err)
+		}
+		keys[key.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no RSA signing keys in jwks file [path=%s]", path)
+	}
+
+	return keys, nil
+}
+
+func (v *jwtVerifier) verify(token string, now time.Time) (*jwtClaims, error) {
+	if token == "" {
+		return nil, errMissingToken
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errMalformedJWT
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, errMalformedJWT
+	}
+	// The algorithm is pinned by config; a token may not pick its own
+	if header.Algorithm != v.algorithm {
+		return nil, fmt.Errorf("%w: unexpected alg %q", errInvalidSig, header.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errMalformedJWT
+	}
+	if err := v.verifySignature(header.KeyID, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, errMalformedJWT
+	}
+	if err := v.validateClaims(&claims, now); err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+func (v *jwtVerifier) verifySignature(keyID, signingInput string, signature []byte) error {
+	if v.algorithm == "HS256" {
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errInvalidSig
+		}
+		return nil
+	}
+
+	key, ok := v.keys[keyID]
+	if !ok && keyID == "" && len(v.keys) == 1 {
+		for _, only := range v.keys {
+			key, ok = only, true
+		}
+	}
+	if !ok {
+		return fmt.Errorf("%w [kid=%s]", errUnknownKey, keyID)
+	}
+	digest := sha256.Sum256([]byte(signingInput))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return errInvalidSig
+	}
+	return nil
+}
+
+func (v *jwtVerifier) validateClaims(claims *jwtClaims, now time.Time) error {
+	if claims.ExpiresAt == 0 || now.Unix() >= claims.ExpiresAt {
+		return errTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Unix() < claims.NotBefore {
+		return errTokenNotValid
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer %q", errTokenNotValid, claims.Issuer)
+	}
+	if v.audience != "" && !slices.Contains(claims.Audience, v.audience) {
+		return fmt.Errorf("%w: unexpected audience", errTokenNotValid)
+	}
+	if claims.ID == "" && claims.Subject == "" {
+		return fmt.Errorf("%w: missing subject", errTokenNotValid)
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
@@ -0,0 +1,316 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	jwtTestNow    = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	jwtTestSecret = []byte("test-secret")
+)
+
+func testRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	return key
+}
+
+func encodeSegment(t *testing.T, v any) string {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal segment: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// signHS256 builds a token whose signature is an HMAC-SHA256 over the
+// header and claims with secret.
+func signHS256(t *testing.T, header map[string]any, claims map[string]any, secret []byte) string {
+	t.Helper()
+	input := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, header map[string]any, claims map[string]any, key *rsa.PrivateKey) string {
+	t.Helper()
+	input := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign rs256: %v", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// validClaims returns claims accepted by the verifiers below; cases override
+// single fields to break them.
+func validClaims(overrides map[string]any) map[string]any {
+	claims := map[string]any{
+		"id":  "user-1",
+		"iss": "refina-auth",
+		"aud": "refina-transaction",
+		"exp": jwtTestNow.Add(time.Hour).Unix(),
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+		claims[k] = v
+	}
+	return claims
+}
+
+func TestJWTVerifier_HS256(t *testing.T) {
+	verifier := &jwtVerifier{
+		algorithm: "HS256",
+		secret:    jwtTestSecret,
+		issuer:    "refina-auth",
+		audience:  "refina-transaction",
+	}
+	hs256 := map[string]any{"alg": "HS256", "typ": "JWT"}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+		wantID  string
+	}{
+		{
+			name:   "valid token",
+			token:  signHS256(t, hs256, validClaims(nil), jwtTestSecret),
+			wantID: "user-1",
+		},
+		{
+			name:   "sub is used when id is missing",
+			token:  signHS256(t, hs256, validClaims(map[string]any{"id": nil, "sub": "user-2"}), jwtTestSecret),
+			wantID: "user-2",
+		},
+		{
+			name:   "audience array containing the service",
+			token:  signHS256(t, hs256, validClaims(map[string]any{"aud": []string{"other", "refina-transaction"}}), jwtTestSecret),
+			wantID: "user-1",
+		},
+		{
+			name:    "missing token",
+			token:   "",
+			wantErr: errMissingToken,
+		},
+		{
+			name:    "malformed token",
+			token:   "not-a-jwt",
+			wantErr: errMalformedJWT,
+		},
+		{
+			name: "alg none is rejected",
+			token: encodeSegment(t, map[string]any{"alg": "none"}) + "." +
+				encodeSegment(t, validClaims(nil)) + ".",
+			wantErr: errInvalidSig,
+		},
+		{
+			name:    "bad signature",
+			token:   signHS256(t, hs256, validClaims(nil), []byte("wrong-secret")),
+			wantErr: errInvalidSig,
+		},
+		{
+			name:    "expired",
+			token:   signHS256(t, hs256, validClaims(map[string]any{"exp": jwtTestNow.Add(-time.Minute).Unix()}), jwtTestSecret),
+			wantErr: errTokenExpired,
+		},
+		{
+			name:    "missing exp",
+			token:   signHS256(t, hs256, validClaims(map[string]any{"exp": nil}), jwtTestSecret),
+			wantErr: errTokenExpired,
+		},
+		{
+			name:    "not valid before nbf",
+			token:   signHS256(t, hs256, validClaims(map[string]any{"nbf": jwtTestNow.Add(time.Minute).Unix()}), jwtTestSecret),
+			wantErr: errTokenNotValid,
+		},
+		{
+			name:    "issuer mismatch",
+			token:   signHS256(t, hs256, validClaims(map[string]any{"iss": "someone-else"}), jwtTestSecret),
+			wantErr: errTokenNotValid,
+		},
+		{
+			name:    "audience mismatch",
+			token:   signHS256(t, hs256, validClaims(map[string]any{"aud": "refina-wallet"}), jwtTestSecret),
+			wantErr: errTokenNotValid,
+		},
+		{
+			name:    "missing subject",
+			token:   signHS256(t, hs256, validClaims(map[string]any{"id": nil}), jwtTestSecret),
+			wantErr: errTokenNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.verify(tt.token, jwtTestNow)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, claims)
+				return
+			}
+			assert.NoError(t, err)
+			id := claims.ID
+			if id == "" {
+				id = claims.Subject
+			}
+			assert.Equal(t, tt.wantID, id)
+		})
+	}
+}
+
+func TestJWTVerifier_RS256(t *testing.T) {
+	key := testRSAKey(t)
+	otherKey := testRSAKey(t)
+	verifier := &jwtVerifier{
+		algorithm: "RS256",
+		keys:      map[string]*rsa.PublicKey{"key-1": &key.PublicKey},
+		issuer:    "refina-auth",
+		audience:  "refina-transaction",
+	}
+	rs256 := map[string]any{"alg": "RS256", "kid": "key-1"}
+
+	// The classic confusion attack signs an HS256 token with the public key
+	// as the HMAC secret
+	publicDER := x509.MarshalPKCS1PublicKey(&key.PublicKey)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{
+			name:  "valid token",
+			token: signRS256(t, rs256, validClaims(nil), key),
+		},
+		{
+			name:  "single key is used when kid is missing",
+			token: signRS256(t, map[string]any{"alg": "RS256"}, validClaims(nil), key),
+		},
+		{
+			name:    "HS256 signed with the public key is rejected",
+			token:   signHS256(t, map[string]any{"alg": "HS256", "kid": "key-1"}, validClaims(nil), publicDER),
+			wantErr: errInvalidSig,
+		},
+		{
+			name:    "alg none is rejected",
+			token:   encodeSegment(t, map[string]any{"alg": "none", "kid": "key-1"}) + "." + encodeSegment(t, validClaims(nil)) + ".",
+			wantErr: errInvalidSig,
+		},
+		{
+			name:    "signed by another key",
+			token:   signRS256(t, rs256, validClaims(nil), otherKey),
+			wantErr: errInvalidSig,
+		},
+		{
+			name:    "unknown kid",
+			token:   signRS256(t, map[string]any{"alg": "RS256", "kid": "key-2"}, validClaims(nil), key),
+			wantErr: errUnknownKey,
+		},
+		{
+			name:    "expired",
+			token:   signRS256(t, rs256, validClaims(map[string]any{"exp": jwtTestNow.Unix()}), key),
+			wantErr: errTokenExpired,
+		},
+		{
+			name:    "missing subject",
+			token:   signRS256(t, rs256, validClaims(map[string]any{"id": nil}), key),
+			wantErr: errTokenNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.verify(tt.token, jwtTestNow)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, claims)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "user-1", claims.ID)
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "Bearer abc.def.ghi", want: "abc.def.ghi"},
+		{header: "bearer abc.def.ghi", want: "abc.def.ghi"},
+		{header: "Basic dXNlcjpwYXNz", want: ""},
+		{header: "abc.def.ghi", want: ""},
+		{header: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, bearerToken(tt.header), tt.header)
+	}
+}
+
+func TestLoadJWKS_KeepsRSASigningKeys(t *testing.T) {
+	key := testRSAKey(t)
+	rsaKey := func(kid, use string) map[string]any {
+		return map[string]any{
+			"kty": "RSA",
+			"kid": kid,
+			"use": use,
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+	}
+	raw, _ := json.Marshal(map[string]any{"keys": []any{
+		rsaKey("sig-key", "sig"),
+		rsaKey("enc-key", "enc"),
+		map[string]any{"kty": "EC", "kid": "ec-key"},
+	}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+
+	keys, err := loadJWKS(path)
+
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.True(t, key.PublicKey.Equal(keys["sig-key"]))
+}
+
+func TestLoadJWKS_NoSigningKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(`{"keys":[{"kty":"EC","kid":"ec-key"}]}`), 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+
+	keys, err := loadJWKS(path)
+
+	assert.Error(t, err)
+	assert.Nil(t, keys)
+}
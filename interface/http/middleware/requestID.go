@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	helper "refina-transaction/internal/utils"
 	"refina-transaction/internal/utils/data"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +16,7 @@ func RequestIDMiddleware() gin.HandlerFunc {
 		}
 
 		ctx.Set(data.REQUEST_ID_LOCAL_KEY, requestID)
+		ctx.Request = ctx.Request.WithContext(helper.WithRequestSource(ctx.Request.Context(), data.REQUEST_SOURCE_HTTP, requestID))
 		ctx.Header(data.REQUEST_ID_HEADER, requestID)
 
 		ctx.Next()
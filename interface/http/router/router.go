@@ -28,8 +28,21 @@ func SetupHTTPServer(dbInstance db.DatabaseClient, minioInstance *miniofs.MinIOM
 		})
 	})
 
+	// Routes registered after this point require a valid bearer token
+	router.Use(middleware.AuthMiddleware())
+
 	routes.TransactionRoutes(router, dbInstance.GetDB(), minioInstance)
 	routes.CategoryRoutes(router, dbInstance.GetDB())
+	routes.RecurringTransactionRoutes(router, dbInstance.GetDB(), minioInstance)
+	routes.BudgetRoutes(router, dbInstance.GetDB())
+	routes.TagRoutes(router, dbInstance.GetDB())
+	routes.ReportRoutes(router, dbInstance.GetDB())
+	routes.ImportRoutes(router, dbInstance.GetDB())
+	routes.ExportRoutes(router, dbInstance.GetDB(), minioInstance)
+	routes.CurrencyRoutes(router, dbInstance.GetDB())
+	routes.ReconciliationRoutes(router, dbInstance.GetDB())
+	routes.WalletDriftRoutes(router, dbInstance.GetDB())
+	routes.DebtRoutes(router, dbInstance.GetDB())
 
 	return &http.Server{
 		Addr:    ":" + env.Cfg.Server.HTTPPort,
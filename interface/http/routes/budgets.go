@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/interface/http/handler"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func BudgetRoutes(version *gin.Engine, db *gorm.DB) {
+	walletRepo := client.NewWalletClient(client.GetManager().GetWalletClient())
+	categoryRepo := repository.NewCategoryRepository(db)
+	budgetRepo := repository.NewBudgetsRepository(db)
+
+	Budget_serv := service.NewBudgetsService(budgetRepo, categoryRepo, walletRepo)
+	Budget_handler := handler.NewBudgetHandler(Budget_serv)
+
+	budget := version.Group("/budgets")
+
+	budget.GET("", Budget_handler.GetBudgets)
+	budget.GET(":id", Budget_handler.GetBudgetByID)
+	budget.POST("", Budget_handler.CreateBudget)
+	budget.PUT(":id", Budget_handler.UpdateBudget)
+	budget.DELETE(":id", Budget_handler.DeleteBudget)
+}
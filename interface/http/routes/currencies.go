@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/interface/http/handler"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func CurrencyRoutes(version *gin.Engine, db *gorm.DB) {
+	transactionRepo := repository.NewTransactionRepository(db)
+	walletRepo := client.NewWalletClient(client.GetManager().GetWalletClient())
+	attachmentRepo := repository.NewAttachmentsRepository(db)
+	recurringRepo := repository.NewRecurringTransactionsRepository(db)
+	debtRepo := repository.NewDebtsRepository(db)
+	currencyRepo := repository.NewCurrenciesRepository(db)
+
+	Currency_serv := service.NewCurrenciesService(currencyRepo)
+	Authorization_serv := service.NewAuthorizationService(walletRepo, transactionRepo, attachmentRepo, recurringRepo, debtRepo)
+	Currency_handler := handler.NewCurrencyHandler(Currency_serv, Authorization_serv)
+
+	currency := version.Group("/currencies")
+
+	currency.GET("rates", Currency_handler.GetFXRates)
+	currency.POST("rates", Currency_handler.UpsertFXRates)
+	currency.POST("rates/import", Currency_handler.ImportFXRates)
+	currency.GET("wallets/:id", Currency_handler.GetWalletCurrency)
+	currency.PUT("wallets/:id", Currency_handler.SetWalletCurrency)
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/interface/http/handler"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func DebtRoutes(version *gin.Engine, db *gorm.DB) {
+	txManager := repository.NewTxManager(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	walletRepo := client.NewWalletClient(client.GetManager().GetWalletClient())
+	categoryRepo := repository.NewCategoryRepository(db)
+	attachmentRepo := repository.NewAttachmentsRepository(db)
+	outboxRepository := repository.NewOutboxRepository(db)
+	sagaRepo := repository.NewSagaLogRepository(db)
+	recurringRepo := repository.NewRecurringTransactionsRepository(db)
+	debtRepo := repository.NewDebtsRepository(db)
+	currencyRepo := repository.NewCurrenciesRepository(db)
+	historyRepo := repository.NewTransactionHistoryRepository(db)
+	reconciliationRepo := repository.NewReconciliationsRepository(db)
+
+	Debt_serv := service.NewDebtsService(txManager, debtRepo, transactionRepo, walletRepo, categoryRepo, outboxRepository, sagaRepo, currencyRepo, historyRepo, reconciliationRepo)
+	Authorization_serv := service.NewAuthorizationService(walletRepo, transactionRepo, attachmentRepo, recurringRepo, debtRepo)
+	Debt_handler := handler.NewDebtHandler(Debt_serv, Authorization_serv)
+
+	debt := version.Group("/debts")
+
+	debt.GET("", Debt_handler.GetDebts)
+	debt.GET("overdue", Debt_handler.GetOverdueDebts)
+	debt.GET(":id", Debt_handler.GetDebtByID)
+	debt.POST("", Debt_handler.CreateDebt)
+	debt.PUT(":id", Debt_handler.UpdateDebt)
+	debt.DELETE(":id", Debt_handler.DeleteDebt)
+	debt.POST(":id/repayments", Debt_handler.RepayDebt)
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"refina-transaction/config/miniofs"
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/interface/http/handler"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func ExportRoutes(version *gin.Engine, db *gorm.DB, minio *miniofs.MinIOManager) {
+	transactionRepo := repository.NewTransactionRepository(db)
+	walletRepo := client.NewWalletClient(client.GetManager().GetWalletClient())
+	attachmentRepo := repository.NewAttachmentsRepository(db)
+	recurringRepo := repository.NewRecurringTransactionsRepository(db)
+	debtRepo := repository.NewDebtsRepository(db)
+
+	Export_serv := service.NewExportsService(transactionRepo, walletRepo, minio)
+	Authorization_serv := service.NewAuthorizationService(walletRepo, transactionRepo, attachmentRepo, recurringRepo, debtRepo)
+	Export_handler := handler.NewExportHandler(Export_serv, Authorization_serv)
+
+	export := version.Group("/exports")
+
+	export.GET("transactions", Export_handler.ExportTransactions)
+	export.GET("statements/:wallet_id", Export_handler.GetMonthlyStatement)
+	export.POST("statements/:wallet_id", Export_handler.StoreMonthlyStatement)
+}
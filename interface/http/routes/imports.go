@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/interface/http/handler"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func ImportRoutes(version *gin.Engine, db *gorm.DB) {
+	txManager := repository.NewTxManager(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	walletRepo := client.NewWalletClient(client.GetManager().GetWalletClient())
+	categoryRepo := repository.NewCategoryRepository(db)
+	attachmentRepo := repository.NewAttachmentsRepository(db)
+	outboxRepository := repository.NewOutboxRepository(db)
+	sagaRepo := repository.NewSagaLogRepository(db)
+	recurringRepo := repository.NewRecurringTransactionsRepository(db)
+	debtRepo := repository.NewDebtsRepository(db)
+	importRepo := repository.NewImportsRepository(db)
+	currencyRepo := repository.NewCurrenciesRepository(db)
+	historyRepo := repository.NewTransactionHistoryRepository(db)
+
+	Import_serv := service.NewImportsService(txManager, transactionRepo, walletRepo, categoryRepo, importRepo, outboxRepository, sagaRepo, currencyRepo, historyRepo)
+	Authorization_serv := service.NewAuthorizationService(walletRepo, transactionRepo, attachmentRepo, recurringRepo, debtRepo)
+	Import_handler := handler.NewImportHandler(Import_serv, Authorization_serv)
+
+	imports := version.Group("/imports")
+
+	imports.GET("", Import_handler.GetImports)
+	imports.GET(":id", Import_handler.GetImportByID)
+	imports.POST("preview", Import_handler.PreviewImport)
+	imports.POST(":id/commit", Import_handler.CommitImport)
+	imports.POST(":id/undo", Import_handler.UndoImport)
+}
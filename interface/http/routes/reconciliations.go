@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/interface/http/handler"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func ReconciliationRoutes(version *gin.Engine, db *gorm.DB) {
+	txManager := repository.NewTxManager(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	walletRepo := client.NewWalletClient(client.GetManager().GetWalletClient())
+	attachmentRepo := repository.NewAttachmentsRepository(db)
+	outboxRepository := repository.NewOutboxRepository(db)
+	recurringRepo := repository.NewRecurringTransactionsRepository(db)
+	debtRepo := repository.NewDebtsRepository(db)
+	historyRepo := repository.NewTransactionHistoryRepository(db)
+	reconciliationRepo := repository.NewReconciliationsRepository(db)
+
+	Reconciliation_serv := service.NewReconciliationsService(txManager, reconciliationRepo, outboxRepository, historyRepo)
+	Authorization_serv := service.NewAuthorizationService(walletRepo, transactionRepo, attachmentRepo, recurringRepo, debtRepo)
+	Reconciliation_handler := handler.NewReconciliationHandler(Reconciliation_serv, Authorization_serv)
+
+	reconciliation := version.Group("/reconciliations")
+
+	reconciliation.GET("", Reconciliation_handler.GetReconciliations)
+	reconciliation.POST("", Reconciliation_handler.StartReconciliation)
+	reconciliation.GET(":id", Reconciliation_handler.GetReconciliationByID)
+	reconciliation.POST(":id/complete", Reconciliation_handler.CompleteReconciliation)
+	reconciliation.DELETE(":id", Reconciliation_handler.CancelReconciliation)
+	reconciliation.GET("wallets/:id/balance-stage", Reconciliation_handler.GetBalanceStage)
+	reconciliation.PUT("wallets/:id/balance-stage", Reconciliation_handler.SetBalanceStage)
+}
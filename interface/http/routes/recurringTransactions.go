@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"refina-transaction/config/miniofs"
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/interface/http/handler"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RecurringTransactionRoutes(version *gin.Engine, db *gorm.DB, minio *miniofs.MinIOManager) {
+	txManager := repository.NewTxManager(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	walletRepo := client.NewWalletClient(client.GetManager().GetWalletClient())
+	categoryRepo := repository.NewCategoryRepository(db)
+	attachmentRepo := repository.NewAttachmentsRepository(db)
+	outboxRepository := repository.NewOutboxRepository(db)
+	sagaRepo := repository.NewSagaLogRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	recurringRepo := repository.NewRecurringTransactionsRepository(db)
+	debtRepo := repository.NewDebtsRepository(db)
+	budgetRepo := repository.NewBudgetsRepository(db)
+	currencyRepo := repository.NewCurrenciesRepository(db)
+	tagRepo := repository.NewTagsRepository(db)
+	historyRepo := repository.NewTransactionHistoryRepository(db)
+	reconciliationRepo := repository.NewReconciliationsRepository(db)
+
+	Transaction_serv := service.NewTransactionService(txManager, transactionRepo, walletRepo, categoryRepo, attachmentRepo, outboxRepository, sagaRepo, idempotencyRepo, budgetRepo, currencyRepo, tagRepo, historyRepo, reconciliationRepo, minio)
+	Recurring_serv := service.NewRecurringTransactionsService(txManager, recurringRepo, categoryRepo, Transaction_serv)
+	Authorization_serv := service.NewAuthorizationService(walletRepo, transactionRepo, attachmentRepo, recurringRepo, debtRepo)
+	Recurring_handler := handler.NewRecurringTransactionHandler(Recurring_serv, Authorization_serv)
+
+	recurring := version.Group("/recurring-transactions")
+
+	recurring.GET("", Recurring_handler.GetRecurringTransactions)
+	recurring.GET(":id", Recurring_handler.GetRecurringTransactionByID)
+	recurring.POST("", Recurring_handler.CreateRecurringTransaction)
+	recurring.PUT(":id", Recurring_handler.UpdateRecurringTransaction)
+	recurring.DELETE(":id", Recurring_handler.DeleteRecurringTransaction)
+	recurring.POST(":id/pause", Recurring_handler.PauseRecurringTransaction)
+	recurring.POST(":id/resume", Recurring_handler.ResumeRecurringTransaction)
+	recurring.POST(":id/skip", Recurring_handler.SkipNextOccurrence)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/interface/http/handler"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func ReportRoutes(version *gin.Engine, db *gorm.DB) {
+	transactionRepo := repository.NewTransactionRepository(db)
+	walletRepo := client.NewWalletClient(client.GetManager().GetWalletClient())
+	attachmentRepo := repository.NewAttachmentsRepository(db)
+	recurringRepo := repository.NewRecurringTransactionsRepository(db)
+	debtRepo := repository.NewDebtsRepository(db)
+
+	Report_serv := service.NewReportsService(transactionRepo)
+	Authorization_serv := service.NewAuthorizationService(walletRepo, transactionRepo, attachmentRepo, recurringRepo, debtRepo)
+	Report_handler := handler.NewReportHandler(Report_serv, Authorization_serv)
+
+	report := version.Group("/reports")
+
+	report.GET("transactions", Report_handler.GetTransactionSummary)
+}
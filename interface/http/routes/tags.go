@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"refina-transaction/interface/http/handler"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func TagRoutes(version *gin.Engine, db *gorm.DB) {
+	txManager := repository.NewTxManager(db)
+	tagRepo := repository.NewTagsRepository(db)
+
+	Tag_serv := service.NewTagsService(txManager, tagRepo)
+	Tag_handler := handler.NewTagHandler(Tag_serv)
+
+	tag := version.Group("/tags")
+
+	tag.GET("", Tag_handler.GetTags)
+	tag.GET(":id", Tag_handler.GetTagByID)
+	tag.POST("", Tag_handler.CreateTag)
+	tag.PUT(":id", Tag_handler.UpdateTag)
+	tag.DELETE(":id", Tag_handler.DeleteTag)
+}
@@ -18,9 +18,19 @@ func TransactionRoutes(version *gin.Engine, db *gorm.DB, minio *miniofs.MinIOMan
 	categoryRepo := repository.NewCategoryRepository(db)
 	attachmentRepo := repository.NewAttachmentsRepository(db)
 	outboxRepository := repository.NewOutboxRepository(db)
+	sagaRepo := repository.NewSagaLogRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	recurringRepo := repository.NewRecurringTransactionsRepository(db)
+	debtRepo := repository.NewDebtsRepository(db)
+	budgetRepo := repository.NewBudgetsRepository(db)
+	currencyRepo := repository.NewCurrenciesRepository(db)
+	tagRepo := repository.NewTagsRepository(db)
+	historyRepo := repository.NewTransactionHistoryRepository(db)
+	reconciliationRepo := repository.NewReconciliationsRepository(db)
 
-	Transaction_serv := service.NewTransactionService(txManager, transactionRepo, walletRepo, categoryRepo, attachmentRepo, outboxRepository, minio)
-	Transaction_handler := handler.NewTransactionHandler(Transaction_serv)
+	Transaction_serv := service.NewTransactionService(txManager, transactionRepo, walletRepo, categoryRepo, attachmentRepo, outboxRepository, sagaRepo, idempotencyRepo, budgetRepo, currencyRepo, tagRepo, historyRepo, reconciliationRepo, minio)
+	Authorization_serv := service.NewAuthorizationService(walletRepo, transactionRepo, attachmentRepo, recurringRepo, debtRepo)
+	Transaction_handler := handler.NewTransactionHandler(Transaction_serv, Authorization_serv)
 
 	transaction := version.Group("/transactions")
 
@@ -28,7 +38,18 @@ func TransactionRoutes(version *gin.Engine, db *gorm.DB, minio *miniofs.MinIOMan
 	transaction.GET(":id", Transaction_handler.GetTransactionByID)
 	transaction.GET("user", Transaction_handler.GetTransactionsByUserID)
 	transaction.POST(":type", Transaction_handler.CreateTransaction)
+	transaction.POST("batch", Transaction_handler.BatchTransactions)
 	transaction.POST("attachment/:id", Transaction_handler.UploadAttachment)
 	transaction.PUT(":id", Transaction_handler.UpdateTransaction)
+	transaction.PATCH(":id/status", Transaction_handler.UpdateTransactionStatus)
 	transaction.DELETE(":id", Transaction_handler.DeleteTransaction)
+	transaction.GET("trash", Transaction_handler.GetDeletedTransactions)
+	transaction.POST("trash/:id/restore", Transaction_handler.RestoreTransaction)
+	transaction.GET("scheduled", Transaction_handler.GetScheduledTransactions)
+	transaction.DELETE("scheduled/:id", Transaction_handler.CancelScheduledTransaction)
+	transaction.GET(":id/history", Transaction_handler.GetTransactionHistory)
+	transaction.GET("transfers", Transaction_handler.GetTransfers)
+	transaction.GET("transfers/:id", Transaction_handler.GetTransferByID)
+	transaction.PUT("transfers/:id", Transaction_handler.UpdateTransfer)
+	transaction.DELETE("transfers/:id", Transaction_handler.DeleteTransfer)
 }
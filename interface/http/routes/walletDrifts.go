@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/interface/http/handler"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func WalletDriftRoutes(version *gin.Engine, db *gorm.DB) {
+	txManager := repository.NewTxManager(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	walletRepo := client.NewWalletClient(client.GetManager().GetWalletClient())
+	categoryRepo := repository.NewCategoryRepository(db)
+	attachmentRepo := repository.NewAttachmentsRepository(db)
+	outboxRepository := repository.NewOutboxRepository(db)
+	recurringRepo := repository.NewRecurringTransactionsRepository(db)
+	debtRepo := repository.NewDebtsRepository(db)
+	currencyRepo := repository.NewCurrenciesRepository(db)
+	historyRepo := repository.NewTransactionHistoryRepository(db)
+	driftRepo := repository.NewWalletDriftsRepository(db)
+
+	Drift_serv := service.NewWalletDriftsService(txManager, driftRepo, transactionRepo, walletRepo, categoryRepo, outboxRepository, currencyRepo, historyRepo)
+	Authorization_serv := service.NewAuthorizationService(walletRepo, transactionRepo, attachmentRepo, recurringRepo, debtRepo)
+	Drift_handler := handler.NewWalletDriftHandler(Drift_serv, Authorization_serv)
+
+	drift := version.Group("/wallet-drifts")
+
+	drift.GET("", Drift_handler.GetWalletDrifts)
+	drift.POST("check", Drift_handler.CheckWalletDrifts)
+}
@@ -4,12 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
 	"time"
 
 	"refina-transaction/config/log"
 	"refina-transaction/interface/queue/client"
 	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
 	"refina-transaction/internal/utils/data"
 
 	"github.com/rabbitmq/amqp091-go"
@@ -125,11 +126,13 @@ func (c *InvestmentEventConsumer) consume(ctx context.Context) error {
 }
 
 func (c *InvestmentEventConsumer) handleMessage(ctx context.Context, msg amqp091.Delivery) error {
+	ctx = helper.WithRequestSource(ctx, data.REQUEST_SOURCE_CONSUMER, msg.MessageId)
+
 	switch msg.RoutingKey {
 	case data.EVENT_INVESTMENT_BUY:
-		return c.handleInvestmentBuy(ctx, msg.Body)
+		return c.handleInvestmentBuy(ctx, msg.MessageId, msg.Body)
 	case data.EVENT_INVESTMENT_SELL:
-		return c.handleInvestmentSell(ctx, msg.Body)
+		return c.handleInvestmentSell(ctx, msg.MessageId, msg.Body)
 	default:
 		log.Warn(data.LogInvestmentEventUnknown, map[string]any{
 			"service":     data.InvestmentConsumerService,
@@ -139,7 +142,7 @@ func (c *InvestmentEventConsumer) handleMessage(ctx context.Context, msg amqp091
 	}
 }
 
-func (c *InvestmentEventConsumer) handleInvestmentBuy(ctx context.Context, body []byte) error {
+func (c *InvestmentEventConsumer) handleInvestmentBuy(ctx context.Context, messageID string, body []byte) error {
 	var event dto.InvestmentBuyEvent
 	if err := json.Unmarshal(body, &event); err != nil {
 		return fmt.Errorf("unmarshal investment buy event: %w", err)
@@ -177,21 +180,23 @@ func (c *InvestmentEventConsumer) handleInvestmentBuy(ctx context.Context, body
 	assetCode := event.Code
 	description := fmt.Sprintf("Pembelian investasi %s sebanyak %s", assetCode, event.Quantity)
 
-	amountFloat64, err := strconv.ParseFloat(event.Amount, 64)
+	amount, err := money.Parse(event.Amount)
 	if err != nil {
 		return fmt.Errorf("parse amount: %w", err)
 	}
 	txnReq := dto.TransactionsRequest{
 		WalletID:           event.WalletID,
 		CategoryID:         data.CATEGORY_ID_INVESTMENT_BUY,
-		Amount:             amountFloat64,
+		Amount:             amount,
 		Date:               investmentDate,
 		Description:        description,
 		Attachments:        []dto.UpdateAttachmentsRequest{},
 		IsWalletNotCreated: false,
 	}
 
-	_, err = c.transactionCreator.CreateTransaction(ctx, txnReq)
+	// Redeliveries carry the same event, so the transaction is created once
+	idempotencyKey := eventIdempotencyKey(data.EVENT_INVESTMENT_BUY, event.ID, messageID, 0)
+	_, err = c.transactionCreator.CreateTransaction(helper.WithIdempotencyKey(ctx, idempotencyKey), txnReq)
 	if err != nil {
 		return fmt.Errorf("create buy transaction: %w", err)
 	}
@@ -206,13 +211,13 @@ func (c *InvestmentEventConsumer) handleInvestmentBuy(ctx context.Context, body
 	return nil
 }
 
-func (c *InvestmentEventConsumer) handleInvestmentSell(ctx context.Context, body []byte) error {
+func (c *InvestmentEventConsumer) handleInvestmentSell(ctx context.Context, messageID string, body []byte) error {
 	var events []dto.InvestmentSellEvent
 	if err := json.Unmarshal(body, &events); err != nil {
 		return fmt.Errorf("unmarshal investment sell events: %w", err)
 	}
 
-	for _, event := range events {
+	for i, event := range events {
 		if event.WalletID == "" {
 			log.Warn(data.LogInvestmentEventSkipped, map[string]any{
 				"service": data.InvestmentConsumerService,
@@ -248,21 +253,22 @@ func (c *InvestmentEventConsumer) handleInvestmentSell(ctx context.Context, body
 
 		description := fmt.Sprintf("Penjualan investasi sebanyak %s dengan harga jual %s/unit", event.Quantity, event.SellPrice)
 
-		amountFloat64, err := strconv.ParseFloat(event.Amount, 64)
+		amount, err := money.Parse(event.Amount)
 		if err != nil {
 			return fmt.Errorf("parse amount: %w", err)
 		}
 		txnReq := dto.TransactionsRequest{
 			WalletID:           event.WalletID,
 			CategoryID:         data.CATEGORY_ID_INVESTMENT_SELL,
-			Amount:             amountFloat64,
+			Amount:             amount,
 			Date:               investmentDate,
 			Description:        description,
 			Attachments:        []dto.UpdateAttachmentsRequest{},
 			IsWalletNotCreated: false,
 		}
 
-		_, err = c.transactionCreator.CreateTransaction(ctx, txnReq)
+		idempotencyKey := eventIdempotencyKey(data.EVENT_INVESTMENT_SELL, event.ID, messageID, i)
+		_, err = c.transactionCreator.CreateTransaction(helper.WithIdempotencyKey(ctx, idempotencyKey), txnReq)
 		if err != nil {
 			log.Error(data.LogInvestmentEventHandleFailed, map[string]any{
 				"service": data.InvestmentConsumerService,
@@ -282,3 +288,17 @@ func (c *InvestmentEventConsumer) handleInvestmentSell(ctx context.Context, body
 
 	return nil
 }
+
+// eventIdempotencyKey prefers the event ID from investment-service and falls
+// back to the AMQP message ID (plus the record index for batched events).
+// It returns an empty key when neither is set, which disables deduplication.
+func eventIdempotencyKey(routingKey, eventID, messageID string, index int) string {
+	switch {
+	case eventID != "":
+		return routingKey + ":" + eventID
+	case messageID != "":
+		return fmt.Sprintf("%s:%s:%d", routingKey, messageID, index)
+	default:
+		return ""
+	}
+}
@@ -19,6 +19,13 @@ func SetupQueueConsumers(ctx context.Context, dbInstance db.DatabaseClient, mini
 	categoryRepo := repository.NewCategoryRepository(dbInstance.GetDB())
 	attachmentRepo := repository.NewAttachmentsRepository(dbInstance.GetDB())
 	outboxRepo := repository.NewOutboxRepository(dbInstance.GetDB())
+	sagaRepo := repository.NewSagaLogRepository(dbInstance.GetDB())
+	idempotencyRepo := repository.NewIdempotencyRepository(dbInstance.GetDB())
+	budgetRepo := repository.NewBudgetsRepository(dbInstance.GetDB())
+	currencyRepo := repository.NewCurrenciesRepository(dbInstance.GetDB())
+	tagRepo := repository.NewTagsRepository(dbInstance.GetDB())
+	historyRepo := repository.NewTransactionHistoryRepository(dbInstance.GetDB())
+	reconciliationRepo := repository.NewReconciliationsRepository(dbInstance.GetDB())
 
 	transactionService := service.NewTransactionService(
 		txManager,
@@ -27,6 +34,13 @@ func SetupQueueConsumers(ctx context.Context, dbInstance db.DatabaseClient, mini
 		categoryRepo,
 		attachmentRepo,
 		outboxRepo,
+		sagaRepo,
+		idempotencyRepo,
+		budgetRepo,
+		currencyRepo,
+		tagRepo,
+		historyRepo,
+		reconciliationRepo,
 		minioInstance,
 	)
 
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+
+	"gorm.io/gorm"
+)
+
+type BudgetsRepository interface {
+	GetBudgetsByUserID(ctx context.Context, tx Transaction, userID string) ([]model.Budgets, error)
+	GetBudgetsByCategoryIDs(ctx context.Context, tx Transaction, categoryIDs []string) ([]model.Budgets, error)
+	GetBudgetByID(ctx context.Context, tx Transaction, id string) (model.Budgets, error)
+	// GetSpent sums the expenses of walletIDs in [from, to) booked on the
+	// category or, for a group budget, on any of its child categories.
+	GetSpent(ctx context.Context, tx Transaction, categoryID string, walletIDs []string, from, to time.Time) (money.Amount, error)
+	CreateBudget(ctx context.Context, tx Transaction, budget model.Budgets) (model.Budgets, error)
+	UpdateBudget(ctx context.Context, tx Transaction, budget model.Budgets) (model.Budgets, error)
+	DeleteBudget(ctx context.Context, tx Transaction, budget model.Budgets) (model.Budgets, error)
+}
+
+type budgetsRepository struct {
+	db *gorm.DB
+}
+
+func NewBudgetsRepository(db *gorm.DB) BudgetsRepository {
+	return &budgetsRepository{db}
+}
+
+func (budget_repo *budgetsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return budget_repo.db.WithContext(ctx), nil
+}
+
+func (budget_repo *budgetsRepository) GetBudgetsByUserID(ctx context.Context, tx Transaction, userID string) ([]model.Budgets, error) {
+	db, err := budget_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var budgets []model.Budgets
+	err = db.Joins("Category").Where("\"budgets\".user_id = ?", userID).Order("\"budgets\".created_at ASC").Find(&budgets).Error
+	if err != nil {
+		return nil, errors.New("budgets not found")
+	}
+	return budgets, nil
+}
+
+func (budget_repo *budgetsRepository) GetBudgetsByCategoryIDs(ctx context.Context, tx Transaction, categoryIDs []string) ([]model.Budgets, error) {
+	db, err := budget_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var budgets []model.Budgets
+	err = db.Joins("Category").Where("\"budgets\".category_id IN ?", categoryIDs).Find(&budgets).Error
+	if err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func (budget_repo *budgetsRepository) GetBudgetByID(ctx context.Context, tx Transaction, id string) (model.Budgets, error) {
+	db, err := budget_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Budgets{}, err
+	}
+
+	var budget model.Budgets
+	err = db.Joins("Category").Where("\"budgets\".id = ?", id).First(&budget).Error
+	if err != nil {
+		return model.Budgets{}, errors.New("budget not found")
+	}
+
+	return budget, nil
+}
+
+func (budget_repo *budgetsRepository) GetSpent(ctx context.Context, tx Transaction, categoryID string, walletIDs []string, from, to time.Time) (money.Amount, error) {
+	db, err := budget_repo.getDB(ctx, tx)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	var spent money.Amount
+	err = db.Model(&model.Transactions{}).
+		Select("COALESCE(SUM(COALESCE(transaction_splits.amount, transactions.amount)), 0)").
+		Joins("LEFT JOIN transaction_splits ON transaction_splits.transaction_id = transactions.id AND transaction_splits.deleted_at IS NULL").
+		Joins("JOIN categories ON categories.id = COALESCE(transaction_splits.category_id, transactions.category_id)").
+		Where("transactions.wallet_id IN ?", walletIDs).
+		Where("(categories.id = ? OR categories.parent_id = ?)", categoryID, categoryID).
+		Where("categories.type = ?", model.Expense).
+		Where("transactions.status <> ? AND NOT transactions.scheduled", model.StatusVoid).
+		Where("transactions.transaction_date >= ? AND transactions.transaction_date < ?", from, to).
+		Row().Scan(&spent)
+
+	return spent, err
+}
+
+func (budget_repo *budgetsRepository) CreateBudget(ctx context.Context, tx Transaction, budget model.Budgets) (model.Budgets, error) {
+	db, err := budget_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Budgets{}, err
+	}
+
+	if err := db.Omit("Category").Create(&budget).Error; err != nil {
+		return model.Budgets{}, err
+	}
+
+	return budget, nil
+}
+
+func (budget_repo *budgetsRepository) UpdateBudget(ctx context.Context, tx Transaction, budget model.Budgets) (model.Budgets, error) {
+	db, err := budget_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Budgets{}, err
+	}
+
+	if err := db.Omit("Category").Save(&budget).Error; err != nil {
+		return model.Budgets{}, err
+	}
+
+	return budget, nil
+}
+
+func (budget_repo *budgetsRepository) DeleteBudget(ctx context.Context, tx Transaction, budget model.Budgets) (model.Budgets, error) {
+	db, err := budget_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Budgets{}, err
+	}
+
+	if err := db.Delete(&budget).Error; err != nil {
+		return model.Budgets{}, err
+	}
+	return budget, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"refina-transaction/internal/types/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CurrenciesRepository interface {
+	// GetFXRates lists stored rates, newest first. Empty currencies match any.
+	GetFXRates(ctx context.Context, tx Transaction, baseCurrency, quoteCurrency string) ([]model.FXRates, error)
+	// GetFXRate returns the latest rate of the pair effective on or before on.
+	// It fails with gorm.ErrRecordNotFound when the pair has none.
+	GetFXRate(ctx context.Context, tx Transaction, baseCurrency, quoteCurrency string, on time.Time) (model.FXRates, error)
+	// UpsertFXRates stores rates, replacing any rate of the same pair and day.
+	UpsertFXRates(ctx context.Context, tx Transaction, rates []model.FXRates) ([]model.FXRates, error)
+	// GetWalletCurrency returns "" when the wallet has no currency set.
+	GetWalletCurrency(ctx context.Context, tx Transaction, walletID string) (string, error)
+	SetWalletCurrency(ctx context.Context, tx Transaction, walletCurrency model.WalletCurrencies) (model.WalletCurrencies, error)
+	// CountWalletTransactionsNotIn counts the wallet's transactions booked in
+	// another currency than currency.
+	CountWalletTransactionsNotIn(ctx context.Context, tx Transaction, walletID, currency string) (int64, error)
+}
+
+type currenciesRepository struct {
+	db *gorm.DB
+}
+
+func NewCurrenciesRepository(db *gorm.DB) CurrenciesRepository {
+	return &currenciesRepository{db}
+}
+
+func (currency_repo *currenciesRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return currency_repo.db.WithContext(ctx), nil
+}
+
+func (currency_repo *currenciesRepository) GetFXRates(ctx context.Context, tx Transaction, baseCurrency, quoteCurrency string) ([]model.FXRates, error) {
+	db, err := currency_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if baseCurrency != "" {
+		db = db.Where("base_currency = ?", baseCurrency)
+	}
+	if quoteCurrency != "" {
+		db = db.Where("quote_currency = ?", quoteCurrency)
+	}
+
+	var rates []model.FXRates
+	if err := db.Order("effective_date DESC, base_currency ASC, quote_currency ASC").Find(&rates).Error; err != nil {
+		return nil, errors.New("failed to get fx rates")
+	}
+	return rates, nil
+}
+
+func (currency_repo *currenciesRepository) GetFXRate(ctx context.Context, tx Transaction, baseCurrency, quoteCurrency string, on time.Time) (model.FXRates, error) {
+	db, err := currency_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.FXRates{}, err
+	}
+
+	var rate model.FXRates
+	err = db.Where("base_currency = ? AND quote_currency = ? AND effective_date <= ?", baseCurrency, quoteCurrency, on).
+		Order("effective_date DESC").
+		First(&rate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.FXRates{}, fmt.Errorf("fx rate not found: %w", err)
+	}
+	if err != nil {
+		return model.FXRates{}, err
+	}
+	return rate, nil
+}
+
+func (currency_repo *currenciesRepository) UpsertFXRates(ctx context.Context, tx Transaction, rates []model.FXRates) ([]model.FXRates, error) {
+	db, err := currency_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "effective_date"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+	}).Create(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (currency_repo *currenciesRepository) GetWalletCurrency(ctx context.Context, tx Transaction, walletID string) (string, error) {
+	db, err := currency_repo.getDB(ctx, tx)
+	if err != nil {
+		return "", err
+	}
+
+	var walletCurrency model.WalletCurrencies
+	err = db.Where("wallet_id = ?", walletID).Limit(1).Find(&walletCurrency).Error
+	if err != nil {
+		return "", errors.New("failed to get wallet currency")
+	}
+	return walletCurrency.Currency, nil
+}
+
+func (currency_repo *currenciesRepository) SetWalletCurrency(ctx context.Context, tx Transaction, walletCurrency model.WalletCurrencies) (model.WalletCurrencies, error) {
+	db, err := currency_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.WalletCurrencies{}, err
+	}
+
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "wallet_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"currency", "updated_at"}),
+	}).Create(&walletCurrency).Error
+	if err != nil {
+		return model.WalletCurrencies{}, err
+	}
+	return walletCurrency, nil
+}
+
+func (currency_repo *currenciesRepository) CountWalletTransactionsNotIn(ctx context.Context, tx Transaction, walletID, currency string) (int64, error) {
+	db, err := currency_repo.getDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = db.Model(&model.Transactions{}).Where("wallet_id = ? AND currency <> ?", walletID, currency).Count(&count).Error
+	if err != nil {
+		return 0, errors.New("failed to count wallet transactions")
+	}
+	return count, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"refina-transaction/internal/types/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DebtsRepository interface {
+	// GetDebtsByWalletIDs lists the debts of the wallets with their
+	// transactions, most recent first. An empty status lists them all.
+	GetDebtsByWalletIDs(ctx context.Context, tx Transaction, walletIDs []string, status model.DebtStatus) ([]model.Debts, error)
+	// GetOverdueDebts lists the open debts of the wallets due before due,
+	// longest overdue first.
+	GetOverdueDebts(ctx context.Context, tx Transaction, walletIDs []string, due time.Time) ([]model.Debts, error)
+	// GetDebtByID loads a debt with its transactions, oldest first, locking
+	// the debt row when tx is set so that concurrent repayments wait.
+	GetDebtByID(ctx context.Context, tx Transaction, id string) (model.Debts, error)
+	CreateDebt(ctx context.Context, tx Transaction, debt model.Debts) (model.Debts, error)
+	UpdateDebt(ctx context.Context, tx Transaction, debt model.Debts) (model.Debts, error)
+	DeleteDebt(ctx context.Context, tx Transaction, debt model.Debts) (model.Debts, error)
+}
+
+type debtsRepository struct {
+	db *gorm.DB
+}
+
+func NewDebtsRepository(db *gorm.DB) DebtsRepository {
+	return &debtsRepository{db}
+}
+
+func (debt_repo *debtsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return debt_repo.db.WithContext(ctx), nil
+}
+
+// preloadDebtTransactions loads the transactions of debts oldest first.
+func preloadDebtTransactions(db *gorm.DB) *gorm.DB {
+	return db.Preload("Transactions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("transaction_date ASC, created_at ASC")
+	}).Preload("Transactions.Category")
+}
+
+func (debt_repo *debtsRepository) GetDebtsByWalletIDs(ctx context.Context, tx Transaction, walletIDs []string, status model.DebtStatus) ([]model.Debts, error) {
+	db, err := debt_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := preloadDebtTransactions(db).Where("wallet_id IN ?", walletIDs)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var debts []model.Debts
+	if err := query.Order("debt_date DESC").Find(&debts).Error; err != nil {
+		return nil, errors.New("debts not found")
+	}
+	return debts, nil
+}
+
+func (debt_repo *debtsRepository) GetOverdueDebts(ctx context.Context, tx Transaction, walletIDs []string, due time.Time) ([]model.Debts, error) {
+	db, err := debt_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var debts []model.Debts
+	err = preloadDebtTransactions(db).
+		Where("wallet_id IN ?", walletIDs).
+		Where("status = ?", model.DebtOpen).
+		Where("due_date < ?", due).
+		Order("due_date ASC").
+		Find(&debts).Error
+	if err != nil {
+		return nil, errors.New("overdue debts not found")
+	}
+	return debts, nil
+}
+
+func (debt_repo *debtsRepository) GetDebtByID(ctx context.Context, tx Transaction, id string) (model.Debts, error) {
+	db, err := debt_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Debts{}, err
+	}
+	if tx != nil {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var debt model.Debts
+	err = preloadDebtTransactions(db).Where("id = ?", id).First(&debt).Error
+	if err != nil {
+		return model.Debts{}, errors.New("debt not found")
+	}
+
+	return debt, nil
+}
+
+func (debt_repo *debtsRepository) CreateDebt(ctx context.Context, tx Transaction, debt model.Debts) (model.Debts, error) {
+	db, err := debt_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Debts{}, err
+	}
+
+	if err := db.Omit("Transactions").Create(&debt).Error; err != nil {
+		return model.Debts{}, err
+	}
+
+	return debt, nil
+}
+
+func (debt_repo *debtsRepository) UpdateDebt(ctx context.Context, tx Transaction, debt model.Debts) (model.Debts, error) {
+	db, err := debt_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Debts{}, err
+	}
+
+	if err := db.Omit("Transactions").Save(&debt).Error; err != nil {
+		return model.Debts{}, err
+	}
+
+	return debt, nil
+}
+
+func (debt_repo *debtsRepository) DeleteDebt(ctx context.Context, tx Transaction, debt model.Debts) (model.Debts, error) {
+	db, err := debt_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Debts{}, err
+	}
+
+	if err := db.Omit("Transactions").Delete(&debt).Error; err != nil {
+		return model.Debts{}, err
+	}
+	return debt, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"refina-transaction/internal/types/model"
+
+	"gorm.io/gorm"
+)
+
+type IdempotencyRepository interface {
+	// GetByKey returns nil without error when the user has not used the key
+	// for operation yet.
+	GetByKey(ctx context.Context, tx Transaction, userID, operation, key string) (*model.IdempotencyKey, error)
+	Create(ctx context.Context, tx Transaction, record *model.IdempotencyKey) error
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+func (r *idempotencyRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return r.db.WithContext(ctx), nil
+}
+
+func (r *idempotencyRepository) GetByKey(ctx context.Context, tx Transaction, userID, operation, key string) (*model.IdempotencyKey, error) {
+	db, err := r.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var record model.IdempotencyKey
+	err = db.Where("user_id = ? AND operation = ? AND key = ?", userID, operation, key).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (r *idempotencyRepository) Create(ctx context.Context, tx Transaction, record *model.IdempotencyKey) error {
+	db, err := r.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return db.Create(record).Error
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"refina-transaction/internal/types/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ImportsRepository interface {
+	GetImportsByUserID(ctx context.Context, tx Transaction, userID string) ([]model.Imports, error)
+	GetImportByID(ctx context.Context, tx Transaction, id string) (model.Imports, error)
+	CreateImport(ctx context.Context, tx Transaction, imp model.Imports) (model.Imports, error)
+	UpdateImport(ctx context.Context, tx Transaction, imp model.Imports) (model.Imports, error)
+}
+
+type importsRepository struct {
+	db *gorm.DB
+}
+
+func NewImportsRepository(db *gorm.DB) ImportsRepository {
+	return &importsRepository{db}
+}
+
+func (import_repo *importsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return import_repo.db.WithContext(ctx), nil
+}
+
+func (import_repo *importsRepository) GetImportsByUserID(ctx context.Context, tx Transaction, userID string) ([]model.Imports, error) {
+	db, err := import_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows can be large; listings only need the summary columns
+	var imports []model.Imports
+	err = db.Omit("rows").Where("user_id = ?", userID).Order("created_at DESC").Find(&imports).Error
+	if err != nil {
+		return nil, errors.New("imports not found")
+	}
+	return imports, nil
+}
+
+// GetImportByID locks the import row for the rest of tx when one is given, so
+// a concurrent commit or undo of the same import waits and then sees the
+// status this one leaves behind.
+func (import_repo *importsRepository) GetImportByID(ctx context.Context, tx Transaction, id string) (model.Imports, error) {
+	db, err := import_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Imports{}, err
+	}
+	if tx != nil {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var imp model.Imports
+	if err := db.Where("id = ?", id).First(&imp).Error; err != nil {
+		return model.Imports{}, errors.New("import not found")
+	}
+
+	return imp, nil
+}
+
+func (import_repo *importsRepository) CreateImport(ctx context.Context, tx Transaction, imp model.Imports) (model.Imports, error) {
+	db, err := import_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Imports{}, err
+	}
+
+	if err := db.Create(&imp).Error; err != nil {
+		return model.Imports{}, err
+	}
+
+	return imp, nil
+}
+
+func (import_repo *importsRepository) UpdateImport(ctx context.Context, tx Transaction, imp model.Imports) (model.Imports, error) {
+	db, err := import_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Imports{}, err
+	}
+
+	if err := db.Save(&imp).Error; err != nil {
+		return model.Imports{}, err
+	}
+
+	return imp, nil
+}
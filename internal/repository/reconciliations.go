@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReconciliationsRepository interface {
+	// GetBalanceStage returns the stage from which transactions of the wallet
+	// count in its balance, model.BalanceStagePending when none is set.
+	GetBalanceStage(ctx context.Context, tx Transaction, walletID string) (model.BalanceStage, error)
+	SetBalanceStage(ctx context.Context, tx Transaction, settings model.WalletSettings) (model.WalletSettings, error)
+	// CountWalletTransactionsByStatus counts the wallet's transactions in status.
+	CountWalletTransactionsByStatus(ctx context.Context, tx Transaction, walletID string, status model.TransactionStatus) (int64, error)
+	// GetReconciliationsByUserID lists the sessions of the user, newest first.
+	GetReconciliationsByUserID(ctx context.Context, tx Transaction, userID string) ([]model.Reconciliations, error)
+	// GetReconciliationByID loads a session, locking it when tx is set so
+	// that a concurrent completion waits.
+	GetReconciliationByID(ctx context.Context, tx Transaction, id string) (model.Reconciliations, error)
+	// GetOpenReconciliation returns nil when the wallet has no open session.
+	GetOpenReconciliation(ctx context.Context, tx Transaction, walletID string) (*model.Reconciliations, error)
+	CreateReconciliation(ctx context.Context, tx Transaction, reconciliation model.Reconciliations) (model.Reconciliations, error)
+	UpdateReconciliation(ctx context.Context, tx Transaction, reconciliation model.Reconciliations) (model.Reconciliations, error)
+	// GetReconciledBalance sums the balance effect of the wallet's reconciled
+	// transactions, with the sign rules of GetWalletNetChangeSince.
+	GetReconciledBalance(ctx context.Context, tx Transaction, walletID string) (money.Amount, error)
+	// GetReconcilableTransactions lists the cleared transactions of the wallet
+	// dated on or before until, oldest first, locking them when tx is set.
+	GetReconcilableTransactions(ctx context.Context, tx Transaction, walletID string, until time.Time) ([]model.Transactions, error)
+	// MarkReconciled moves the transactions to reconciled under the session.
+	MarkReconciled(ctx context.Context, tx Transaction, reconciliationID uuid.UUID, transactionIDs []uuid.UUID) (int64, error)
+}
+
+type reconciliationsRepository struct {
+	db *gorm.DB
+}
+
+func NewReconciliationsRepository(db *gorm.DB) ReconciliationsRepository {
+	return &reconciliationsRepository{db}
+}
+
+func (reconciliation_repo *reconciliationsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return reconciliation_repo.db.WithContext(ctx), nil
+}
+
+func (reconciliation_repo *reconciliationsRepository) GetBalanceStage(ctx context.Context, tx Transaction, walletID string) (model.BalanceStage, error) {
+	db, err := reconciliation_repo.getDB(ctx, tx)
+	if err != nil {
+		return "", err
+	}
+
+	var settings model.WalletSettings
+	err = db.Where("wallet_id = ?", walletID).Limit(1).Find(&settings).Error
+	if err != nil {
+		return "", errors.New("failed to get wallet settings")
+	}
+	if settings.BalanceStage == "" {
+		return model.BalanceStagePending, nil
+	}
+	return settings.BalanceStage, nil
+}
+
+func (reconciliation_repo *reconciliationsRepository) SetBalanceStage(ctx context.Context, tx Transaction, settings model.WalletSettings) (model.WalletSettings, error) {
+	db, err := reconciliation_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.WalletSettings{}, err
+	}
+
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "wallet_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"balance_stage", "updated_at"}),
+	}).Create(&settings).Error
+	if err != nil {
+		return model.WalletSettings{}, err
+	}
+	return settings, nil
+}
+
+func (reconciliation_repo *reconciliationsRepository) CountWalletTransactionsByStatus(ctx context.Context, tx Transaction, walletID string, status model.TransactionStatus) (int64, error) {
+	db, err := reconciliation_repo.getDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = db.Model(&model.Transactions{}).Where("wallet_id = ? AND status = ?", walletID, status).Count(&count).Error
+	if err != nil {
+		return 0, errors.New("failed to count wallet transactions")
+	}
+	return count, nil
+}
+
+func (reconciliation_repo *reconciliationsRepository) GetReconciliationsByUserID(ctx context.Context, tx Transaction, userID string) ([]model.Reconciliations, error) {
+	db, err := reconciliation_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reconciliations []model.Reconciliations
+	err = db.Where("user_id = ?", userID).Order("created_at DESC").Find(&reconciliations).Error
+	if err != nil {
+		return nil, errors.New("reconciliations not found")
+	}
+	return reconciliations, nil
+}
+
+func (reconciliation_repo *reconciliationsRepository) GetReconciliationByID(ctx context.Context, tx Transaction, id string) (model.Reconciliations, error) {
+	db, err := reconciliation_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Reconciliations{}, err
+	}
+
+	if tx != nil {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var reconciliation model.Reconciliations
+	if err := db.Where("id = ?", id).First(&reconciliation).Error; err != nil {
+		return model.Reconciliations{}, errors.New("reconciliation not found")
+	}
+	return reconciliation, nil
+}
+
+func (reconciliation_repo *reconciliationsRepository) GetOpenReconciliation(ctx context.Context, tx Transaction, walletID string) (*model.Reconciliations, error) {
+	db, err := reconciliation_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reconciliations []model.Reconciliations
+	err = db.Where("wallet_id = ? AND status = ?", walletID, model.ReconciliationOpen).Limit(1).Find(&reconciliations).Error
+	if err != nil {
+		return nil, errors.New("failed to get open reconciliation")
+	}
+	if len(reconciliations) == 0 {
+		return nil, nil
+	}
+	return &reconciliations[0], nil
+}
+
+func (reconciliation_repo *reconciliationsRepository) CreateReconciliation(ctx context.Context, tx Transaction, reconciliation model.Reconciliations) (model.Reconciliations, error) {
+	db, err := reconciliation_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Reconciliations{}, err
+	}
+
+	if err := db.Create(&reconciliation).Error; err != nil {
+		return model.Reconciliations{}, err
+	}
+	return reconciliation, nil
+}
+
+func (reconciliation_repo *reconciliationsRepository) UpdateReconciliation(ctx context.Context, tx Transaction, reconciliation model.Reconciliations) (model.Reconciliations, error) {
+	db, err := reconciliation_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Reconciliations{}, err
+	}
+
+	if err := db.Save(&reconciliation).Error; err != nil {
+		return model.Reconciliations{}, err
+	}
+	return reconciliation, nil
+}
+
+func (reconciliation_repo *reconciliationsRepository) GetReconciledBalance(ctx context.Context, tx Transaction, walletID string) (money.Amount, error) {
+	db, err := reconciliation_repo.getDB(ctx, tx)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	var balance money.Amount
+	err = db.Model(&model.Transactions{}).
+		Joins("JOIN categories AS rec_cat ON rec_cat.id = transactions.category_id").
+		Where("transactions.wallet_id = ? AND transactions.status = ?", walletID, model.StatusReconciled).
+		Select(`COALESCE(SUM(CASE
+			WHEN rec_cat.type = ? OR (rec_cat.type = ? AND rec_cat.name = 'Cash In') THEN transactions.amount
+			WHEN rec_cat.type = ? OR (rec_cat.type = ? AND rec_cat.name = 'Cash Out') THEN -transactions.amount
+			ELSE 0 END), 0)`, model.Income, model.FundTransfer, model.Expense, model.FundTransfer).
+		Row().Scan(&balance)
+	if err != nil {
+		return money.Zero, errors.New("failed to sum reconciled transactions")
+	}
+	return balance, nil
+}
+
+func (reconciliation_repo *reconciliationsRepository) GetReconcilableTransactions(ctx context.Context, tx Transaction, walletID string, until time.Time) ([]model.Transactions, error) {
+	db, err := reconciliation_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx != nil {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "transactions"}})
+	}
+
+	var transactions []model.Transactions
+	err = preloadDetails(db.Joins("Category")).
+		Where("\"transactions\".wallet_id = ? AND \"transactions\".status = ? AND \"transactions\".transaction_date < ?", walletID, model.StatusCleared, until.AddDate(0, 0, 1)).
+		Where("NOT \"transactions\".scheduled").
+		Order("\"transactions\".transaction_date ASC, \"transactions\".id ASC").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, errors.New("failed to get reconcilable transactions")
+	}
+	return transactions, nil
+}
+
+func (reconciliation_repo *reconciliationsRepository) MarkReconciled(ctx context.Context, tx Transaction, reconciliationID uuid.UUID, transactionIDs []uuid.UUID) (int64, error) {
+	if len(transactionIDs) == 0 {
+		return 0, nil
+	}
+
+	db, err := reconciliation_repo.getDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.Model(&model.Transactions{}).
+		Where("id IN ? AND status = ? AND NOT scheduled", transactionIDs, model.StatusCleared).
+		Updates(map[string]any{"status": model.StatusReconciled, "reconciliation_id": reconciliationID})
+	if result.Error != nil {
+		return 0, errors.New("failed to mark transactions reconciled")
+	}
+	return result.RowsAffected, nil
+}
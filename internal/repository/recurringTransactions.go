@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"refina-transaction/internal/types/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurringTransactionsRepository interface {
+	GetRecurringTransactionsByWalletIDs(ctx context.Context, tx Transaction, ids []string) ([]model.RecurringTransactions, error)
+	GetRecurringTransactionByID(ctx context.Context, tx Transaction, id string) (model.RecurringTransactions, error)
+	GetDueRecurringTransactions(ctx context.Context, tx Transaction, now time.Time, limit int) ([]model.RecurringTransactions, error)
+	CreateRecurringTransaction(ctx context.Context, tx Transaction, recurring model.RecurringTransactions) (model.RecurringTransactions, error)
+	UpdateRecurringTransaction(ctx context.Context, tx Transaction, recurring model.RecurringTransactions) (model.RecurringTransactions, error)
+	DeleteRecurringTransaction(ctx context.Context, tx Transaction, recurring model.RecurringTransactions) (model.RecurringTransactions, error)
+}
+
+type recurringTransactionsRepository struct {
+	db *gorm.DB
+}
+
+func NewRecurringTransactionsRepository(db *gorm.DB) RecurringTransactionsRepository {
+	return &recurringTransactionsRepository{db}
+}
+
+func (recurring_repo *recurringTransactionsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return recurring_repo.db.WithContext(ctx), nil
+}
+
+func (recurring_repo *recurringTransactionsRepository) GetRecurringTransactionsByWalletIDs(ctx context.Context, tx Transaction, ids []string) ([]model.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var recurrings []model.RecurringTransactions
+	err = db.Joins("Category").Where("\"recurring_transactions\".wallet_id IN ?", ids).Order("next_run_at ASC").Find(&recurrings).Error
+	if err != nil {
+		return nil, errors.New("recurring transactions not found")
+	}
+	return recurrings, nil
+}
+
+func (recurring_repo *recurringTransactionsRepository) GetRecurringTransactionByID(ctx context.Context, tx Transaction, id string) (model.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.RecurringTransactions{}, err
+	}
+
+	var recurring model.RecurringTransactions
+	err = db.Joins("Category").Where("\"recurring_transactions\".id = ?", id).First(&recurring).Error
+	if err != nil {
+		return model.RecurringTransactions{}, errors.New("recurring transaction not found")
+	}
+
+	return recurring, nil
+}
+
+// GetDueRecurringTransactions locks the due schedules for the lifetime of tx.
+// Rows already locked by another scheduler are skipped, so replicas running
+// side by side each claim a disjoint batch.
+func (recurring_repo *recurringTransactionsRepository) GetDueRecurringTransactions(ctx context.Context, tx Transaction, now time.Time, limit int) ([]model.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var recurrings []model.RecurringTransactions
+	err = db.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ?", model.RecurringActive).
+		Where("next_run_at <= ?", now).
+		Where("retry_at IS NULL OR retry_at <= ?", now).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&recurrings).Error
+
+	return recurrings, err
+}
+
+func (recurring_repo *recurringTransactionsRepository) CreateRecurringTransaction(ctx context.Context, tx Transaction, recurring model.RecurringTransactions) (model.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.RecurringTransactions{}, err
+	}
+
+	if err := db.Omit("Category").Create(&recurring).Error; err != nil {
+		return model.RecurringTransactions{}, err
+	}
+
+	return recurring, nil
+}
+
+func (recurring_repo *recurringTransactionsRepository) UpdateRecurringTransaction(ctx context.Context, tx Transaction, recurring model.RecurringTransactions) (model.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.RecurringTransactions{}, err
+	}
+
+	if err := db.Omit("Category").Save(&recurring).Error; err != nil {
+		return model.RecurringTransactions{}, err
+	}
+
+	return recurring, nil
+}
+
+func (recurring_repo *recurringTransactionsRepository) DeleteRecurringTransaction(ctx context.Context, tx Transaction, recurring model.RecurringTransactions) (model.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.RecurringTransactions{}, err
+	}
+
+	if err := db.Delete(&recurring).Error; err != nil {
+		return model.RecurringTransactions{}, err
+	}
+	return recurring, nil
+}
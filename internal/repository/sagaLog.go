@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"refina-transaction/internal/types/model"
+
+	"gorm.io/gorm"
+)
+
+type SagaLogRepository interface {
+	CreateSaga(ctx context.Context, tx Transaction, saga *model.SagaLog) error
+	UpdateSagaStatus(ctx context.Context, tx Transaction, id string, status model.SagaStatus, reason string) error
+	// CompleteSaga marks a started saga completed. It reports false when the
+	// saga is no longer started because it was claimed for compensation.
+	CompleteSaga(ctx context.Context, tx Transaction, id string) (bool, error)
+	// ClaimSaga marks a saga compensating so that a single caller reverts it,
+	// and reports false when the saga is not the caller's to revert. A claim
+	// older than staleBefore is taken over, in case its compensator stopped.
+	ClaimSaga(ctx context.Context, tx Transaction, id string, staleBefore time.Time) (bool, error)
+	CreateStep(ctx context.Context, tx Transaction, step *model.SagaLogStep) error
+	UpdateStepStatus(ctx context.Context, tx Transaction, id string, status model.SagaStepStatus) error
+	GetIncompleteSagas(ctx context.Context, olderThan time.Time, limit int) ([]model.SagaLog, error)
+}
+
+type sagaLogRepository struct {
+	db *gorm.DB
+}
+
+func NewSagaLogRepository(db *gorm.DB) SagaLogRepository {
+	return &sagaLogRepository{db: db}
+}
+
+func (r *sagaLogRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return r.db.WithContext(ctx), nil
+}
+
+func (r *sagaLogRepository) CreateSaga(ctx context.Context, tx Transaction, saga *model.SagaLog) error {
+	db, err := r.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return db.Omit("Steps").Create(saga).Error
+}
+
+func (r *sagaLogRepository) UpdateSagaStatus(ctx context.Context, tx Transaction, id string, status model.SagaStatus, reason string) error {
+	db, err := r.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return db.Model(&model.SagaLog{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"error":      reason,
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error
+}
+
+func (r *sagaLogRepository) CompleteSaga(ctx context.Context, tx Transaction, id string) (bool, error) {
+	db, err := r.getDB(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+
+	result := db.Model(&model.SagaLog{}).
+		Where("id = ?", id).
+		Where("status = ?", model.SagaStarted).
+		Updates(map[string]interface{}{
+			"status":     model.SagaCompleted,
+			"error":      "",
+			"updated_at": gorm.Expr("NOW()"),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *sagaLogRepository) ClaimSaga(ctx context.Context, tx Transaction, id string, staleBefore time.Time) (bool, error) {
+	db, err := r.getDB(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+
+	result := db.Model(&model.SagaLog{}).
+		Where("id = ?", id).
+		Where("(status = ? OR (status = ? AND updated_at < ?))", model.SagaStarted, model.SagaCompensating, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     model.SagaCompensating,
+			"updated_at": gorm.Expr("NOW()"),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *sagaLogRepository) CreateStep(ctx context.Context, tx Transaction, step *model.SagaLogStep) error {
+	db, err := r.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return db.Create(step).Error
+}
+
+func (r *sagaLogRepository) UpdateStepStatus(ctx context.Context, tx Transaction, id string, status model.SagaStepStatus) error {
+	db, err := r.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return db.Model(&model.SagaLogStep{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error
+}
+
+func (r *sagaLogRepository) GetIncompleteSagas(ctx context.Context, olderThan time.Time, limit int) ([]model.SagaLog, error) {
+	var sagas []model.SagaLog
+
+	err := r.db.WithContext(ctx).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence ASC")
+		}).
+		Where("(status = ? AND created_at < ?) OR (status = ? AND updated_at < ?)", model.SagaStarted, olderThan, model.SagaCompensating, olderThan).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&sagas).Error
+
+	return sagas, err
+}
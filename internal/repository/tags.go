@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"refina-transaction/internal/types/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TagsRepository interface {
+	GetTagsByUserID(ctx context.Context, tx Transaction, userID string) ([]model.Tags, error)
+	GetTagsByIDs(ctx context.Context, tx Transaction, ids []string) ([]model.Tags, error)
+	GetTagByID(ctx context.Context, tx Transaction, id string) (model.Tags, error)
+	// GetTagByName looks a tag of userID up by name, ignoring case.
+	GetTagByName(ctx context.Context, tx Transaction, userID, name string) (model.Tags, error)
+	CreateTag(ctx context.Context, tx Transaction, tag model.Tags) (model.Tags, error)
+	UpdateTag(ctx context.Context, tx Transaction, tag model.Tags) (model.Tags, error)
+	// DeleteTag removes the tag from every transaction carrying it.
+	DeleteTag(ctx context.Context, tx Transaction, tag model.Tags) (model.Tags, error)
+	// ReplaceTransactionTags swaps the tags of a transaction for tagIDs; an
+	// empty slice leaves the transaction untagged.
+	ReplaceTransactionTags(ctx context.Context, tx Transaction, transactionID uuid.UUID, tagIDs []uuid.UUID) error
+}
+
+type tagsRepository struct {
+	db *gorm.DB
+}
+
+func NewTagsRepository(db *gorm.DB) TagsRepository {
+	return &tagsRepository{db}
+}
+
+func (tag_repo *tagsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return tag_repo.db.WithContext(ctx), nil
+}
+
+func (tag_repo *tagsRepository) GetTagsByUserID(ctx context.Context, tx Transaction, userID string) ([]model.Tags, error) {
+	db, err := tag_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []model.Tags
+	err = db.Where("user_id = ?", userID).Order("LOWER(name) ASC").Find(&tags).Error
+	if err != nil {
+		return nil, errors.New("tags not found")
+	}
+	return tags, nil
+}
+
+func (tag_repo *tagsRepository) GetTagsByIDs(ctx context.Context, tx Transaction, ids []string) ([]model.Tags, error) {
+	db, err := tag_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []model.Tags
+	err = db.Where("id IN ?", ids).Order("LOWER(name) ASC").Find(&tags).Error
+	if err != nil {
+		return nil, errors.New("tags not found")
+	}
+	return tags, nil
+}
+
+func (tag_repo *tagsRepository) GetTagByID(ctx context.Context, tx Transaction, id string) (model.Tags, error) {
+	db, err := tag_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Tags{}, err
+	}
+
+	var tag model.Tags
+	err = db.Where("id = ?", id).First(&tag).Error
+	if err != nil {
+		return model.Tags{}, errors.New("tag not found")
+	}
+
+	return tag, nil
+}
+
+func (tag_repo *tagsRepository) GetTagByName(ctx context.Context, tx Transaction, userID, name string) (model.Tags, error) {
+	db, err := tag_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Tags{}, err
+	}
+
+	var tag model.Tags
+	err = db.Where("user_id = ? AND LOWER(name) = LOWER(?)", userID, name).First(&tag).Error
+	if err != nil {
+		return model.Tags{}, errors.New("tag not found")
+	}
+
+	return tag, nil
+}
+
+func (tag_repo *tagsRepository) CreateTag(ctx context.Context, tx Transaction, tag model.Tags) (model.Tags, error) {
+	db, err := tag_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Tags{}, err
+	}
+
+	if err := db.Create(&tag).Error; err != nil {
+		return model.Tags{}, err
+	}
+
+	return tag, nil
+}
+
+func (tag_repo *tagsRepository) UpdateTag(ctx context.Context, tx Transaction, tag model.Tags) (model.Tags, error) {
+	db, err := tag_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Tags{}, err
+	}
+
+	if err := db.Save(&tag).Error; err != nil {
+		return model.Tags{}, err
+	}
+
+	return tag, nil
+}
+
+func (tag_repo *tagsRepository) DeleteTag(ctx context.Context, tx Transaction, tag model.Tags) (model.Tags, error) {
+	db, err := tag_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Tags{}, err
+	}
+
+	if err := db.Where("tag_id = ?", tag.ID).Delete(&model.TransactionTags{}).Error; err != nil {
+		return model.Tags{}, err
+	}
+	if err := db.Delete(&tag).Error; err != nil {
+		return model.Tags{}, err
+	}
+	return tag, nil
+}
+
+func (tag_repo *tagsRepository) ReplaceTransactionTags(ctx context.Context, tx Transaction, transactionID uuid.UUID, tagIDs []uuid.UUID) error {
+	db, err := tag_repo.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Where("transaction_id = ?", transactionID).Delete(&model.TransactionTags{}).Error; err != nil {
+		return err
+	}
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	links := make([]model.TransactionTags, len(tagIDs))
+	for i, tagID := range tagIDs {
+		links[i] = model.TransactionTags{TransactionID: transactionID, TagID: tagID}
+	}
+	return db.Create(&links).Error
+}
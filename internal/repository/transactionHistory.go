@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"refina-transaction/internal/types/model"
+
+	"gorm.io/gorm"
+)
+
+// TransactionHistoryRepository is append-only: entries are never updated or
+// deleted, which the table also enforces with triggers.
+type TransactionHistoryRepository interface {
+	// CreateHistory appends entries to the trail in one insert.
+	CreateHistory(ctx context.Context, tx Transaction, entries []model.TransactionHistory) error
+	// GetHistoryByTransactionID lists the trail of a transaction, oldest first.
+	GetHistoryByTransactionID(ctx context.Context, tx Transaction, transactionID string) ([]model.TransactionHistory, error)
+}
+
+type transactionHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewTransactionHistoryRepository(db *gorm.DB) TransactionHistoryRepository {
+	return &transactionHistoryRepository{db}
+}
+
+func (history_repo *transactionHistoryRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return history_repo.db.WithContext(ctx), nil
+}
+
+func (history_repo *transactionHistoryRepository) CreateHistory(ctx context.Context, tx Transaction, entries []model.TransactionHistory) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	db, err := history_repo.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return db.Create(&entries).Error
+}
+
+func (history_repo *transactionHistoryRepository) GetHistoryByTransactionID(ctx context.Context, tx Transaction, transactionID string) ([]model.TransactionHistory, error) {
+	db, err := history_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []model.TransactionHistory
+	err = db.Where("transaction_id = ?", transactionID).Order("created_at ASC, id ASC").Find(&entries).Error
+	if err != nil {
+		return nil, errors.New("transaction history not found")
+	}
+	return entries, nil
+}
@@ -2,14 +2,18 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
 	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CursorQuery holds cursor-based pagination and filter parameters.
@@ -21,12 +25,62 @@ type CursorQuery struct {
 	DateFrom     string
 	DateTo       string
 	Search       string
-	SortBy       string // "transaction_date" or "amount"
-	SortOrder    string // "asc" or "desc"
+	TagIDs       []string
+	TagMatch     TagMatch // how TagIDs combine, any by default
+	Statuses     []string // any of these statuses, all when empty
+	BookedOnly   bool     // only transactions counted in their wallet balance
+	SortBy       string   // "transaction_date", "amount" or "relevance" (with Search)
+	SortOrder    string   // "asc" or "desc"
 	PageSize     int
-	Cursor       string  // last item ID from previous page
-	CursorAmount float64 // cursor amount value (when sorting by amount)
-	CursorDate   string  // cursor date value (when sorting by date)
+	Cursor       string       // last item ID from previous page
+	CursorAmount money.Amount // cursor amount value (when sorting by amount)
+	CursorDate   string       // cursor date value (when sorting by date)
+	BaseCurrency string       // aggregation only: convert amounts into this currency
+}
+
+// TagMatch is how the tags of a CursorQuery filter combine.
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any" // carries at least one of the tags
+	TagMatchAll TagMatch = "all" // carries every one of the tags
+)
+
+// AggregateGroupBy is the dimension transactions are bucketed by in AggregateTransactions.
+type AggregateGroupBy string
+
+const (
+	AggregateByDay           AggregateGroupBy = "day"
+	AggregateByWeek          AggregateGroupBy = "week"
+	AggregateByMonth         AggregateGroupBy = "month"
+	AggregateByYear          AggregateGroupBy = "year"
+	AggregateByCategory      AggregateGroupBy = "category"
+	AggregateByCategoryGroup AggregateGroupBy = "category_group"
+	AggregateByWallet        AggregateGroupBy = "wallet"
+	// AggregateByTag counts a transaction under each of its tags, and under an
+	// empty key when it has none, so tag buckets may add up to more than the total.
+	AggregateByTag AggregateGroupBy = "tag"
+)
+
+// AggregateRow is one bucket of AggregateTransactions for a single category type.
+type AggregateRow struct {
+	Key          string
+	Label        string
+	CategoryType string
+	Total        money.Amount
+	Count        int64
+	Average      money.Amount
+	// Unconverted counts lines left out of Total for lack of an fx rate to q.BaseCurrency
+	Unconverted int64
+}
+
+// TransferFXTotal is what the cross-currency transfers of a query gained or
+// lost in its base currency, see SumTransferFXGainLoss.
+type TransferFXTotal struct {
+	GainLoss money.Amount
+	Count    int64
+	// Unconverted counts transfers left out of GainLoss for lack of an fx rate to q.BaseCurrency
+	Unconverted int64
 }
 
 type TransactionsRepository interface {
@@ -34,9 +88,67 @@ type TransactionsRepository interface {
 	GetTransactionByID(ctx context.Context, tx Transaction, id string) (model.Transactions, error)
 	GetTransactionsByWalletIDs(ctx context.Context, tx Transaction, ids []string) ([]model.Transactions, error)
 	GetTransactionsByCursor(ctx context.Context, tx Transaction, q CursorQuery) ([]model.Transactions, int64, error)
+	// AggregateTransactions sums income and expense per bucket, honouring the
+	// filters of q. Amounts are converted to q.BaseCurrency at the rate of each
+	// transaction date when it is set. Sorting and cursor fields are ignored.
+	AggregateTransactions(ctx context.Context, tx Transaction, q CursorQuery, groupBy AggregateGroupBy) ([]AggregateRow, error)
+	// SumTransferFXGainLoss values both legs of each transfer between wallets
+	// of different currencies in q.BaseCurrency, at the rate of the transfer
+	// date, and sums what the cash-in leg is worth over the cash-out leg. Only
+	// the wallet and date filters of q apply.
+	SumTransferFXGainLoss(ctx context.Context, tx Transaction, q CursorQuery) (TransferFXTotal, error)
+	// StreamTransactions hands fn the rows matching q in chronological order,
+	// batchSize rows at a time. Sorting and cursor fields are ignored.
+	StreamTransactions(ctx context.Context, tx Transaction, q CursorQuery, batchSize int, fn func([]model.Transactions) error) error
+	// GetWalletNetChangeSince returns the signed balance movement recorded
+	// for a wallet at or after since.
+	GetWalletNetChangeSince(ctx context.Context, tx Transaction, walletID string, since time.Time) (money.Amount, error)
 	CreateTransaction(ctx context.Context, tx Transaction, transaction model.Transactions) (model.Transactions, error)
+	CreateTransactions(ctx context.Context, tx Transaction, transactions []model.Transactions, batchSize int) ([]model.Transactions, error)
+	GetTransactionsByImportID(ctx context.Context, tx Transaction, importID string) ([]model.Transactions, error)
+	DeleteTransactionsByImportID(ctx context.Context, tx Transaction, importID string) (int64, error)
+	// ReplaceTransactionSplits swaps the split lines of a transaction for
+	// splits; an empty slice leaves the transaction unsplit.
+	ReplaceTransactionSplits(ctx context.Context, tx Transaction, transactionID string, splits []model.TransactionSplits) ([]model.TransactionSplits, error)
 	UpdateTransaction(ctx context.Context, tx Transaction, transaction model.Transactions) (model.Transactions, error)
 	DeleteTransaction(ctx context.Context, tx Transaction, transaction model.Transactions) (model.Transactions, error)
+	// GetDeletedTransactions lists the soft-deleted transactions of the
+	// wallets, most recently deleted first. Cancelled scheduled transactions
+	// are left out, as they cannot be restored.
+	GetDeletedTransactions(ctx context.Context, tx Transaction, walletIDs []string) ([]model.Transactions, error)
+	// GetDeletedTransactionByID loads a soft-deleted transaction, locking it
+	// when tx is set so that a concurrent restore waits.
+	GetDeletedTransactionByID(ctx context.Context, tx Transaction, id string) (model.Transactions, error)
+	RestoreTransaction(ctx context.Context, tx Transaction, transaction model.Transactions) (model.Transactions, error)
+	// PurgeDeletedTransactions permanently removes the transactions deleted
+	// before deletedBefore; their splits, tags and attachments cascade.
+	PurgeDeletedTransactions(ctx context.Context, tx Transaction, deletedBefore time.Time) (int64, error)
+	// GetScheduledTransactions lists the scheduled transactions of the
+	// wallets still to post, soonest first.
+	GetScheduledTransactions(ctx context.Context, tx Transaction, walletIDs []string) ([]model.Transactions, error)
+	// GetDueScheduledTransactions returns up to limit scheduled transactions
+	// dated at or before due and not waiting out a failed attempt, oldest
+	// first. Only their ID and PostFailureCount are loaded.
+	GetDueScheduledTransactions(ctx context.Context, tx Transaction, due time.Time, limit int) ([]model.Transactions, error)
+	// PostScheduledTransaction clears the scheduled flag of a transaction, and
+	// any failed attempts with it. It reports false when the transaction was
+	// posted or cancelled meanwhile.
+	PostScheduledTransaction(ctx context.Context, tx Transaction, id string) (bool, error)
+	// RecordScheduledPostFailure saves a failed attempt at posting a scheduled
+	// transaction and holds it back until retryAt.
+	RecordScheduledPostFailure(ctx context.Context, tx Transaction, id string, failureCount int, reason string, retryAt time.Time) error
+	// CancelScheduledTransaction deletes a transaction that is still
+	// scheduled. It reports false when the transaction posted meanwhile.
+	CancelScheduledTransaction(ctx context.Context, tx Transaction, id string) (bool, error)
+	// GetTransfersByWalletIDs lists the transfers out of or into the wallets,
+	// most recent first.
+	GetTransfersByWalletIDs(ctx context.Context, tx Transaction, walletIDs []string) ([]model.Transfers, error)
+	// GetTransferByID loads a transfer with its legs and fee, locking the
+	// transfer row when tx is set so that concurrent edits wait.
+	GetTransferByID(ctx context.Context, tx Transaction, id string) (model.Transfers, error)
+	CreateTransfer(ctx context.Context, tx Transaction, transfer model.Transfers) (model.Transfers, error)
+	UpdateTransfer(ctx context.Context, tx Transaction, transfer model.Transfers) (model.Transfers, error)
+	DeleteTransfer(ctx context.Context, tx Transaction, transfer model.Transfers) (model.Transfers, error)
 }
 
 type transactionsRepository struct {
@@ -65,7 +177,7 @@ func (transaction_repo *transactionsRepository) GetAllTransactions(ctx context.C
 	}
 
 	var transactions []model.Transactions
-	err = db.Joins("Category").Order("transaction_date DESC").Find(&transactions).Error
+	err = preloadDetails(db.Joins("Category")).Where("NOT \"transactions\".scheduled").Order("transaction_date DESC").Find(&transactions).Error
 	if err != nil {
 		return nil, errors.New("user transactions not found")
 	}
@@ -79,7 +191,7 @@ func (trasaction_repo *transactionsRepository) GetTransactionByID(ctx context.Co
 	}
 
 	var transaction model.Transactions
-	err = db.Joins("Category").Where("\"transactions\".id = ?", id).First(&transaction).Error
+	err = preloadDetails(db.Joins("Category")).Where("\"transactions\".id = ?", id).First(&transaction).Error
 	if err != nil {
 		return model.Transactions{}, errors.New("transaction not found")
 	}
@@ -94,7 +206,7 @@ func (transaction_repo *transactionsRepository) GetTransactionsByWalletIDs(ctx c
 	}
 
 	var transactions []model.Transactions
-	err = db.Joins("Category").Preload("Attachments").Where("\"transactions\".wallet_id IN ?", ids).Order("transaction_date DESC").Find(&transactions).Error
+	err = preloadDetails(db.Joins("Category").Preload("Attachments")).Where("\"transactions\".wallet_id IN ? AND NOT \"transactions\".scheduled", ids).Order("transaction_date DESC").Find(&transactions).Error
 	if err != nil {
 		return nil, errors.New("user transactions not found")
 	}
@@ -107,20 +219,77 @@ func (transaction_repo *transactionsRepository) CreateTransaction(ctx context.Co
 		return model.Transactions{}, err
 	}
 
-	if err := db.Omit("Category", "Attachments").Create(&transaction).Error; err != nil {
+	if err := db.Omit("Category", "Attachments", "Splits", "Tags").Create(&transaction).Error; err != nil {
 		return model.Transactions{}, err
 	}
 
 	return transaction, nil
 }
 
+func (transaction_repo *transactionsRepository) CreateTransactions(ctx context.Context, tx Transaction, transactions []model.Transactions, batchSize int) ([]model.Transactions, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Omit("Category", "Attachments", "Splits", "Tags").CreateInBatches(&transactions, batchSize).Error; err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+func (transaction_repo *transactionsRepository) GetTransactionsByImportID(ctx context.Context, tx Transaction, importID string) ([]model.Transactions, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []model.Transactions
+	err = db.Joins("Category").Where("\"transactions\".import_id = ?", importID).Order("transaction_date ASC").Find(&transactions).Error
+	if err != nil {
+		return nil, errors.New("import transactions not found")
+	}
+	return transactions, nil
+}
+
+func (transaction_repo *transactionsRepository) DeleteTransactionsByImportID(ctx context.Context, tx Transaction, importID string) (int64, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.Where("import_id = ?", importID).Delete(&model.Transactions{})
+	return result.RowsAffected, result.Error
+}
+
+func (transaction_repo *transactionsRepository) ReplaceTransactionSplits(ctx context.Context, tx Transaction, transactionID string, splits []model.TransactionSplits) ([]model.TransactionSplits, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Where("transaction_id = ?", transactionID).Delete(&model.TransactionSplits{}).Error; err != nil {
+		return nil, err
+	}
+	if len(splits) == 0 {
+		return nil, nil
+	}
+
+	if err := db.Omit("Category").Create(&splits).Error; err != nil {
+		return nil, err
+	}
+
+	return splits, nil
+}
+
 func (transaction_repo *transactionsRepository) UpdateTransaction(ctx context.Context, tx Transaction, transaction model.Transactions) (model.Transactions, error) {
 	db, err := transaction_repo.getDB(ctx, tx)
 	if err != nil {
 		return model.Transactions{}, err
 	}
 
-	if err := db.Omit("Wallet", "Category").Save(&transaction).Error; err != nil {
+	if err := db.Omit("Wallet", "Category", "Splits", "Tags").Save(&transaction).Error; err != nil {
 		return model.Transactions{}, err
 	}
 
@@ -139,50 +308,271 @@ func (transaction_repo *transactionsRepository) DeleteTransaction(ctx context.Co
 	return transaction, nil
 }
 
-func (transaction_repo *transactionsRepository) GetTransactionsByCursor(ctx context.Context, tx Transaction, q CursorQuery) ([]model.Transactions, int64, error) {
+func (transaction_repo *transactionsRepository) GetDeletedTransactions(ctx context.Context, tx Transaction, walletIDs []string) ([]model.Transactions, error) {
 	db, err := transaction_repo.getDB(ctx, tx)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	// ── Base query: wallet IDs ──
-	base := db.Model(&model.Transactions{}).Where("transactions.wallet_id IN ?", q.WalletIDs)
+	var transactions []model.Transactions
+	err = preloadDetails(db.Unscoped().Joins("Category")).
+		Where("\"transactions\".wallet_id IN ?", walletIDs).
+		Where("\"transactions\".deleted_at IS NOT NULL").
+		// A cancelled scheduled transaction is gone for good, not in the trash
+		Where("NOT \"transactions\".scheduled").
+		Order("\"transactions\".deleted_at DESC").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, errors.New("deleted transactions not found")
+	}
+	return transactions, nil
+}
 
-	// ── Filters ──
-	if q.WalletID != "" {
-		base = base.Where("transactions.wallet_id = ?", q.WalletID)
+func (transaction_repo *transactionsRepository) GetDeletedTransactionByID(ctx context.Context, tx Transaction, id string) (model.Transactions, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Transactions{}, err
 	}
-	if q.CategoryID != "" {
-		base = base.Where("transactions.category_id = ?", q.CategoryID)
+	if tx != nil {
+		// Only the transaction row: Postgres cannot lock the nullable side of the category join
+		db = db.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: clause.CurrentTable}})
 	}
-	if q.CategoryType != "" {
-		base = base.Joins("JOIN categories AS cat_filter ON cat_filter.id = transactions.category_id AND cat_filter.deleted_at IS NULL").
-			Where("cat_filter.type = ?", q.CategoryType)
+
+	var transaction model.Transactions
+	err = preloadDetails(db.Unscoped().Joins("Category")).
+		Where("\"transactions\".id = ?", id).
+		Where("\"transactions\".deleted_at IS NOT NULL").
+		First(&transaction).Error
+	if err != nil {
+		return model.Transactions{}, errors.New("deleted transaction not found")
 	}
-	if q.DateFrom != "" {
-		if t, err := time.Parse(time.RFC3339, q.DateFrom); err == nil {
-			base = base.Where("transactions.transaction_date >= ?", t)
-		}
+
+	return transaction, nil
+}
+
+func (transaction_repo *transactionsRepository) RestoreTransaction(ctx context.Context, tx Transaction, transaction model.Transactions) (model.Transactions, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Transactions{}, err
 	}
-	if q.DateTo != "" {
-		if t, err := time.Parse(time.RFC3339, q.DateTo); err == nil {
-			base = base.Where("transactions.transaction_date <= ?", t)
-		}
+
+	err = db.Unscoped().Model(&model.Transactions{}).Where("id = ?", transaction.ID).Update("deleted_at", nil).Error
+	if err != nil {
+		return model.Transactions{}, err
+	}
+	transaction.DeletedAt = gorm.DeletedAt{}
+
+	return transaction, nil
+}
+
+func (transaction_repo *transactionsRepository) PurgeDeletedTransactions(ctx context.Context, tx Transaction, deletedBefore time.Time) (int64, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).Delete(&model.Transactions{})
+	return result.RowsAffected, result.Error
+}
+
+func (transaction_repo *transactionsRepository) GetScheduledTransactions(ctx context.Context, tx Transaction, walletIDs []string) ([]model.Transactions, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []model.Transactions
+	err = preloadDetails(db.Joins("Category")).
+		Where("\"transactions\".wallet_id IN ? AND \"transactions\".scheduled", walletIDs).
+		Order("\"transactions\".transaction_date ASC, \"transactions\".id ASC").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, errors.New("scheduled transactions not found")
+	}
+	return transactions, nil
+}
+
+func (transaction_repo *transactionsRepository) GetDueScheduledTransactions(ctx context.Context, tx Transaction, due time.Time, limit int) ([]model.Transactions, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []model.Transactions
+	err = db.Select("id", "post_failure_count").
+		Where("scheduled AND transaction_date <= ?", due).
+		Where("(post_retry_at IS NULL OR post_retry_at <= ?)", due).
+		Order("transaction_date ASC").
+		Limit(limit).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, errors.New("failed to get due scheduled transactions")
+	}
+	return transactions, nil
+}
+
+func (transaction_repo *transactionsRepository) PostScheduledTransaction(ctx context.Context, tx Transaction, id string) (bool, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+
+	// ? The condition makes the update a claim: a concurrent poster or cancel waits, then matches nothing
+	result := db.Model(&model.Transactions{}).Where("id = ? AND scheduled", id).Updates(map[string]interface{}{
+		"scheduled":          false,
+		"post_failure_count": 0,
+		"post_error":         nil,
+		"post_retry_at":      nil,
+	})
+	if result.Error != nil {
+		return false, errors.New("failed to post scheduled transaction")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (transaction_repo *transactionsRepository) RecordScheduledPostFailure(ctx context.Context, tx Transaction, id string, failureCount int, reason string, retryAt time.Time) error {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// A transaction cancelled meanwhile is left alone
+	err = db.Model(&model.Transactions{}).Where("id = ? AND scheduled", id).Updates(map[string]interface{}{
+		"post_failure_count": failureCount,
+		"post_error":         reason,
+		"post_retry_at":      retryAt,
+	}).Error
+	if err != nil {
+		return errors.New("failed to record scheduled transaction failure")
+	}
+	return nil
+}
+
+func (transaction_repo *transactionsRepository) CancelScheduledTransaction(ctx context.Context, tx Transaction, id string) (bool, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+
+	result := db.Where("id = ? AND scheduled", id).Delete(&model.Transactions{})
+	if result.Error != nil {
+		return false, errors.New("failed to cancel scheduled transaction")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (transaction_repo *transactionsRepository) GetTransfersByWalletIDs(ctx context.Context, tx Transaction, walletIDs []string) ([]model.Transfers, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var transfers []model.Transfers
+	err = db.Preload("CashOut.Category").Preload("CashIn.Category").Preload("Fee.Category").
+		Where("from_wallet_id IN ? OR to_wallet_id IN ?", walletIDs, walletIDs).
+		Order("transfer_date DESC").
+		Find(&transfers).Error
+	if err != nil {
+		return nil, errors.New("transfers not found")
+	}
+	return transfers, nil
+}
+
+func (transaction_repo *transactionsRepository) GetTransferByID(ctx context.Context, tx Transaction, id string) (model.Transfers, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Transfers{}, err
+	}
+	if tx != nil {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var transfer model.Transfers
+	err = db.Preload("CashOut.Category").Preload("CashIn.Category").Preload("Fee.Category").Where("id = ?", id).First(&transfer).Error
+	if err != nil {
+		return model.Transfers{}, errors.New("transfer not found")
+	}
+
+	return transfer, nil
+}
+
+func (transaction_repo *transactionsRepository) CreateTransfer(ctx context.Context, tx Transaction, transfer model.Transfers) (model.Transfers, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Transfers{}, err
 	}
-	if q.Search != "" {
-		like := "%" + strings.ToLower(q.Search) + "%"
-		base = base.Where("LOWER(transactions.description) LIKE ?", like)
+
+	if err := db.Omit("CashOut", "CashIn", "Fee").Create(&transfer).Error; err != nil {
+		return model.Transfers{}, err
+	}
+
+	return transfer, nil
+}
+
+func (transaction_repo *transactionsRepository) UpdateTransfer(ctx context.Context, tx Transaction, transfer model.Transfers) (model.Transfers, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Transfers{}, err
+	}
+
+	if err := db.Omit("CashOut", "CashIn", "Fee").Save(&transfer).Error; err != nil {
+		return model.Transfers{}, err
+	}
+
+	return transfer, nil
+}
+
+func (transaction_repo *transactionsRepository) DeleteTransfer(ctx context.Context, tx Transaction, transfer model.Transfers) (model.Transfers, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.Transfers{}, err
+	}
+
+	if err := db.Omit("CashOut", "CashIn", "Fee").Delete(&transfer).Error; err != nil {
+		return model.Transfers{}, err
+	}
+	return transfer, nil
+}
+
+func (transaction_repo *transactionsRepository) GetTransactionsByCursor(ctx context.Context, tx Transaction, q CursorQuery) ([]model.Transactions, int64, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, 0, err
 	}
 
+	// ── Base query: wallet IDs + filters ──
+	base := applyCursorFilters(db.Model(&model.Transactions{}), q)
+
 	// ── Count total (before cursor) ──
 	var total int64
 	if err := base.Count(&total).Error; err != nil {
 		return nil, 0, errors.New("failed to count transactions")
 	}
 
+	var transactions []model.Transactions
+	err = preloadDetails(applyCursorPage(base, q).Joins("Category").Preload("Attachments")).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, 0, errors.New("failed to fetch transactions")
+	}
+
+	return transactions, total, nil
+}
+
+// applyCursorPage orders the query by q's sort and keeps the page after the
+// cursor, plus one row to tell whether a next page exists. Sorting by
+// relevance falls back to the date when q has nothing to search for.
+func applyCursorPage(base *gorm.DB, q CursorQuery) *gorm.DB {
 	// ── Sort config ──
+	search, searchable := searchQuery(q.Search)
 	sortBy := q.SortBy
-	if sortBy == "" {
+	switch sortBy {
+	case "amount":
+	case "relevance":
+		if !searchable {
+			sortBy = "transaction_date"
+		}
+	default:
 		sortBy = "transaction_date"
 	}
 	sortOrder := strings.ToLower(q.SortOrder)
@@ -206,6 +596,12 @@ func (transaction_repo *transactionsRepository) GetTransactionsByCursor(ctx cont
 				fmt.Sprintf("(transactions.amount %s ?) OR (transactions.amount = ? AND transactions.id %s ?)", op, op),
 				q.CursorAmount, q.CursorAmount, q.Cursor,
 			)
+		case "relevance":
+			// The rank of the cursor row is recomputed, so the cursor is its ID alone
+			base = base.Where(
+				fmt.Sprintf("(ts_rank(transactions.search_vector, %s), transactions.id) %s ((SELECT ts_rank(cursor_row.search_vector, %s) FROM transactions AS cursor_row WHERE cursor_row.id = ?), ?)", searchTSQuery, op, searchTSQuery),
+				search, search, search, search, search, search, q.Cursor, q.Cursor,
+			)
 		default: // transaction_date
 			if q.CursorDate != "" {
 				if cursorTime, err := time.Parse(time.RFC3339, q.CursorDate); err == nil {
@@ -224,16 +620,283 @@ func (transaction_repo *transactionsRepository) GetTransactionsByCursor(ctx cont
 		pageSize = 9999
 	}
 
-	orderClause := fmt.Sprintf("transactions.%s %s, transactions.id %s", sortBy, sortOrder, sortOrder)
+	orderClause := clause.Expr{SQL: fmt.Sprintf("transactions.%s %s, transactions.id %s", sortBy, sortOrder, sortOrder)}
+	if sortBy == "relevance" {
+		orderClause = clause.Expr{
+			SQL:                fmt.Sprintf("ts_rank(transactions.search_vector, %s) %s, transactions.id %s", searchTSQuery, sortOrder, sortOrder),
+			Vars:               []any{search, search, search},
+			WithoutParentheses: true,
+		}
+	}
+
+	return base.Order(clause.OrderBy{Expression: orderClause}).
+		Limit(pageSize + 1) // fetch one extra to determine has_next
+}
 
-	var transactions []model.Transactions
-	err = base.Joins("Category").Preload("Attachments").
-		Order(orderClause).
-		Limit(pageSize + 1). // fetch one extra to determine has_next
-		Find(&transactions).Error
+func (transaction_repo *transactionsRepository) AggregateTransactions(ctx context.Context, tx Transaction, q CursorQuery, groupBy AggregateGroupBy) ([]AggregateRow, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
 	if err != nil {
-		return nil, 0, errors.New("failed to fetch transactions")
+		return nil, err
 	}
 
-	return transactions, total, nil
+	// A split transaction is reported per line; other transactions as a single line
+	base := applyCursorFilters(db.Model(&model.Transactions{}), q).
+		Joins("LEFT JOIN transaction_splits AS agg_split ON agg_split.transaction_id = transactions.id AND agg_split.deleted_at IS NULL").
+		Joins("JOIN categories AS agg_cat ON agg_cat.id = COALESCE(agg_split.category_id, transactions.category_id)").
+		Where("agg_cat.type IN ?", []model.CategoryType{model.Income, model.Expense}).
+		Where("transactions.status <> ?", model.StatusVoid)
+	if q.CategoryID != "" {
+		base = base.Where("agg_cat.id = ?", q.CategoryID)
+	}
+
+	// ── Bucket key + label ──
+	var key, label string
+	switch groupBy {
+	case AggregateByDay, AggregateByWeek, AggregateByMonth, AggregateByYear:
+		key = fmt.Sprintf("to_char(date_trunc('%s', transactions.transaction_date), 'YYYY-MM-DD')", groupBy)
+		label = key
+	case AggregateByCategory:
+		key, label = "agg_cat.id::text", "agg_cat.name"
+	case AggregateByCategoryGroup:
+		base = base.Joins("LEFT JOIN categories AS agg_parent ON agg_parent.id = agg_cat.parent_id")
+		key, label = "COALESCE(agg_parent.id, agg_cat.id)::text", "COALESCE(agg_parent.name, agg_cat.name)"
+	case AggregateByWallet:
+		key, label = "transactions.wallet_id::text", "transactions.wallet_id::text"
+	case AggregateByTag:
+		base = base.Joins("LEFT JOIN transaction_tags AS agg_link ON agg_link.transaction_id = transactions.id").
+			Joins("LEFT JOIN tags AS agg_tag ON agg_tag.id = agg_link.tag_id AND agg_tag.deleted_at IS NULL")
+		key, label = "COALESCE(agg_tag.id::text, '')", "COALESCE(agg_tag.name, '')"
+	default:
+		return nil, fmt.Errorf("invalid group by [group_by=%s]", groupBy)
+	}
+
+	// ── Amount, in the base currency when one is asked for ──
+	amount, unconverted := "COALESCE(agg_split.amount, transactions.amount)", "0"
+	var args []any
+	if q.BaseCurrency != "" {
+		// Latest rate of the day of the transaction, stored either way round
+		base = base.Joins("LEFT JOIN LATERAL "+fxRateToBase("transactions")+" AS agg_fx ON true", q.BaseCurrency, q.BaseCurrency)
+		amount = "COALESCE(agg_split.amount, transactions.amount) * CASE WHEN transactions.currency = @base THEN 1 ELSE agg_fx.rate END"
+		unconverted = "COUNT(*) FILTER (WHERE transactions.currency <> @base AND agg_fx.rate IS NULL)"
+		args = append(args, sql.Named("base", q.BaseCurrency))
+	}
+
+	var rows []AggregateRow
+	err = base.
+		Select(fmt.Sprintf("%s AS key, %s AS label, agg_cat.type AS category_type, COALESCE(SUM(%s), 0) AS total, COUNT(DISTINCT transactions.id) AS count, COALESCE(SUM(%s), 0) / COUNT(DISTINCT transactions.id) AS average, %s AS unconverted", key, label, amount, amount, unconverted), args...).
+		Group(fmt.Sprintf("%s, %s, agg_cat.type", key, label)).
+		Order("key ASC, category_type ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.New("failed to aggregate transactions")
+	}
+
+	return rows, nil
+}
+
+func (transaction_repo *transactionsRepository) SumTransferFXGainLoss(ctx context.Context, tx Transaction, q CursorQuery) (TransferFXTotal, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return TransferFXTotal{}, err
+	}
+
+	base := db.Model(&model.Transfers{}).
+		Joins("JOIN transactions AS fx_out ON fx_out.id = transfers.cash_out_transaction_id AND fx_out.deleted_at IS NULL").
+		Joins("JOIN transactions AS fx_in ON fx_in.id = transfers.cash_in_transaction_id AND fx_in.deleted_at IS NULL").
+		Joins("LEFT JOIN LATERAL "+fxRateToBase("fx_out")+" AS fx_out_rate ON true", q.BaseCurrency, q.BaseCurrency).
+		Joins("LEFT JOIN LATERAL "+fxRateToBase("fx_in")+" AS fx_in_rate ON true", q.BaseCurrency, q.BaseCurrency).
+		Where("fx_out.currency <> fx_in.currency").
+		Where("(transfers.from_wallet_id IN ? OR transfers.to_wallet_id IN ?)", q.WalletIDs, q.WalletIDs)
+	if q.WalletID != "" {
+		base = base.Where("(transfers.from_wallet_id = ? OR transfers.to_wallet_id = ?)", q.WalletID, q.WalletID)
+	}
+	if q.DateFrom != "" {
+		if t, err := time.Parse(time.RFC3339, q.DateFrom); err == nil {
+			base = base.Where("transfers.transfer_date >= ?", t)
+		}
+	}
+	if q.DateTo != "" {
+		if t, err := time.Parse(time.RFC3339, q.DateTo); err == nil {
+			base = base.Where("transfers.transfer_date <= ?", t)
+		}
+	}
+
+	// A leg without a rate makes its difference NULL, which SUM skips
+	value := func(leg string) string {
+		return fmt.Sprintf("%[1]s.amount * CASE WHEN %[1]s.currency = @base THEN 1 ELSE %[1]s_rate.rate END", leg)
+	}
+	missing := func(leg string) string {
+		return fmt.Sprintf("(%[1]s.currency <> @base AND %[1]s_rate.rate IS NULL)", leg)
+	}
+
+	var total TransferFXTotal
+	err = base.
+		Select(fmt.Sprintf("COALESCE(SUM(%s - %s), 0) AS gain_loss, COUNT(*) AS count, COUNT(*) FILTER (WHERE %s OR %s) AS unconverted", value("fx_in"), value("fx_out"), missing("fx_in"), missing("fx_out")), sql.Named("base", q.BaseCurrency)).
+		Scan(&total).Error
+	if err != nil {
+		return TransferFXTotal{}, errors.New("failed to sum transfer fx gain and loss")
+	}
+
+	return total, nil
+}
+
+// fxRateToBase is a lateral subquery giving the latest rate, on the day of the
+// transaction aliased table, from its currency into a base currency, stored
+// either way round. It takes the base currency twice.
+func fxRateToBase(table string) string {
+	return fmt.Sprintf(`(
+			SELECT fx.rate FROM (
+				SELECT rate, effective_date FROM fx_rates
+				WHERE deleted_at IS NULL AND base_currency = %[1]s.currency AND quote_currency = ? AND effective_date <= %[1]s.transaction_date
+				UNION ALL
+				SELECT 1 / rate, effective_date FROM fx_rates
+				WHERE deleted_at IS NULL AND base_currency = ? AND quote_currency = %[1]s.currency AND effective_date <= %[1]s.transaction_date
+			) AS fx ORDER BY fx.effective_date DESC LIMIT 1
+		)`, table)
+}
+
+func (transaction_repo *transactionsRepository) StreamTransactions(ctx context.Context, tx Transaction, q CursorQuery, batchSize int, fn func([]model.Transactions) error) error {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// Keyset over (transaction_date, id) so each batch is an index range scan
+	var (
+		lastDate time.Time
+		lastID   string
+	)
+	for {
+		batch := applyCursorFilters(db.Model(&model.Transactions{}), q)
+		if lastID != "" {
+			batch = batch.Where("(transactions.transaction_date, transactions.id) > (?, ?)", lastDate, lastID)
+		}
+
+		var transactions []model.Transactions
+		err := preloadDetails(batch.Joins("Category")).
+			Order("transactions.transaction_date ASC, transactions.id ASC").
+			Limit(batchSize).
+			Find(&transactions).Error
+		if err != nil {
+			return errors.New("failed to fetch transactions")
+		}
+		if len(transactions) == 0 {
+			return nil
+		}
+
+		if err := fn(transactions); err != nil {
+			return err
+		}
+		if len(transactions) < batchSize {
+			return nil
+		}
+
+		last := transactions[len(transactions)-1]
+		lastDate, lastID = last.TransactionDate, last.ID.String()
+	}
+}
+
+func (transaction_repo *transactionsRepository) GetWalletNetChangeSince(ctx context.Context, tx Transaction, walletID string, since time.Time) (money.Amount, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	// Same sign rules as the wallet balance updates: fund transfers count by leg
+	var net money.Amount
+	err = db.Model(&model.Transactions{}).
+		Joins("JOIN categories AS net_cat ON net_cat.id = transactions.category_id").
+		Where("transactions.wallet_id = ? AND transactions.transaction_date >= ?", walletID, since).
+		Where(bookedCondition).
+		Select(`COALESCE(SUM(CASE
+			WHEN net_cat.type = ? OR (net_cat.type = ? AND net_cat.name = 'Cash In') THEN transactions.amount
+			WHEN net_cat.type = ? OR (net_cat.type = ? AND net_cat.name = 'Cash Out') THEN -transactions.amount
+			ELSE 0 END), 0)`, model.Income, model.FundTransfer, model.Expense, model.FundTransfer).
+		Row().Scan(&net)
+	if err != nil {
+		return money.Zero, errors.New("failed to sum wallet transactions")
+	}
+
+	return net, nil
+}
+
+// applyCursorFilters scopes base to the wallets and filters of q shared by
+// paging and aggregation. Scheduled transactions are left out until they post.
+func applyCursorFilters(base *gorm.DB, q CursorQuery) *gorm.DB {
+	base = base.Where("transactions.wallet_id IN ? AND NOT transactions.scheduled", q.WalletIDs)
+
+	if q.WalletID != "" {
+		base = base.Where("transactions.wallet_id = ?", q.WalletID)
+	}
+	if q.CategoryID != "" {
+		base = base.Where("(transactions.category_id = ? OR EXISTS (SELECT 1 FROM transaction_splits AS cat_split WHERE cat_split.transaction_id = transactions.id AND cat_split.category_id = ? AND cat_split.deleted_at IS NULL))", q.CategoryID, q.CategoryID)
+	}
+	if q.CategoryType != "" {
+		base = base.Joins("JOIN categories AS cat_filter ON cat_filter.id = transactions.category_id AND cat_filter.deleted_at IS NULL").
+			Where("cat_filter.type = ?", q.CategoryType)
+	}
+	if q.DateFrom != "" {
+		if t, err := time.Parse(time.RFC3339, q.DateFrom); err == nil {
+			base = base.Where("transactions.transaction_date >= ?", t)
+		}
+	}
+	if q.DateTo != "" {
+		if t, err := time.Parse(time.RFC3339, q.DateTo); err == nil {
+			base = base.Where("transactions.transaction_date <= ?", t)
+		}
+	}
+	if search, ok := searchQuery(q.Search); ok {
+		base = base.Where("transactions.search_vector @@ "+searchTSQuery, search, search, search)
+	}
+	if len(q.Statuses) > 0 {
+		base = base.Where("transactions.status IN ?", q.Statuses)
+	}
+	if q.BookedOnly {
+		base = base.Where(bookedCondition)
+	}
+	if len(q.TagIDs) > 0 {
+		if q.TagMatch == TagMatchAll {
+			base = base.Where("(SELECT COUNT(DISTINCT tag_filter.tag_id) FROM transaction_tags AS tag_filter WHERE tag_filter.transaction_id = transactions.id AND tag_filter.tag_id IN ?) = ?", q.TagIDs, len(q.TagIDs))
+		} else {
+			base = base.Where("EXISTS (SELECT 1 FROM transaction_tags AS tag_filter WHERE tag_filter.transaction_id = transactions.id AND tag_filter.tag_id IN ?)", q.TagIDs)
+		}
+	}
+
+	return base
+}
+
+// bookedCondition keeps the transactions counted in their wallet balance:
+// never void or scheduled ones, and pending ones only while the wallet books
+// on entry.
+const bookedCondition = `transactions.status <> 'void' AND NOT transactions.scheduled AND (transactions.status <> 'pending' OR NOT EXISTS (
+	SELECT 1 FROM wallet_settings WHERE wallet_settings.wallet_id = transactions.wallet_id AND wallet_settings.balance_stage = 'cleared'))`
+
+// searchTSQuery matches a searchQuery against search_vector in each
+// configuration the vector is built with; it takes the query three times.
+const searchTSQuery = "(to_tsquery('simple', ?) || to_tsquery('indonesian', ?) || to_tsquery('english', ?))"
+
+var searchWordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// searchQuery turns free text into a tsquery matching every word by prefix,
+// "makan sia" becoming "makan:* & sia:*". ok is false when the text holds no
+// word to search for.
+func searchQuery(text string) (query string, ok bool) {
+	words := searchWordPattern.FindAllString(strings.ToLower(text), -1)
+	if len(words) == 0 {
+		return "", false
+	}
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & "), true
+}
+
+// preloadDetails loads the split lines of each transaction in entry order,
+// and its tags by name.
+func preloadDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("Splits", func(db *gorm.DB) *gorm.DB {
+		return db.Order("transaction_splits.position ASC")
+	}).Preload("Splits.Category").Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("LOWER(tags.name) ASC")
+	})
 }
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"testing"
+
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// ─────────────────────────────────────────────
+// searchQuery
+// ─────────────────────────────────────────────
+
+func TestSearchQuery(t *testing.T) {
+	cases := []struct {
+		name  string
+		text  string
+		query string
+		ok    bool
+	}{
+		{name: "empty", text: "", ok: false},
+		{name: "whitespace only", text: "   \t ", ok: false},
+		{name: "punctuation only", text: "!?-- ...", ok: false},
+		{name: "single word", text: "Makan", query: "makan:*", ok: true},
+		{name: "multiple words", text: "makan  siang kantor", query: "makan:* & siang:* & kantor:*", ok: true},
+		{name: "digits and letters", text: "invoice #42", query: "invoice:* & 42:*", ok: true},
+		{name: "non-latin letters", text: "Café Ñoño", query: "café:* & ñoño:*", ok: true},
+		{name: "quotes are dropped", text: `"gaji" 'bonus'`, query: "gaji:* & bonus:*", ok: true},
+		{name: "apostrophe splits the word", text: "mom's", query: "mom:* & s:*", ok: true},
+		{name: "colons cannot add weights or prefixes", text: "gaji:A bonus:*", query: "gaji:* & a:* & bonus:*", ok: true},
+		{name: "operators are dropped", text: "a & !b | (c <-> d)", query: "a:* & b:* & c:* & d:*", ok: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, ok := searchQuery(tc.text)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.query, query)
+		})
+	}
+}
+
+// ─────────────────────────────────────────────
+// applyCursorPage
+// ─────────────────────────────────────────────
+
+// pageSQL renders the query applyCursorPage builds for q, without a database.
+func pageSQL(t *testing.T, q CursorQuery) string {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("open dry-run db: %v", err)
+	}
+
+	return db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var transactions []model.Transactions
+		return applyCursorPage(tx.Model(&model.Transactions{}), q).Find(&transactions)
+	})
+}
+
+func TestApplyCursorPage_RelevanceRanksBySearch(t *testing.T) {
+	sql := pageSQL(t, CursorQuery{Search: "makan", SortBy: "relevance", Cursor: "txn-1", PageSize: 10})
+
+	assert.Contains(t, sql, "ts_rank(transactions.search_vector, (to_tsquery('simple', 'makan:*')")
+	assert.Contains(t, sql, "cursor_row.id = 'txn-1'")
+	assert.Contains(t, sql, ") desc, transactions.id desc")
+	assert.Contains(t, sql, "LIMIT 11")
+}
+
+func TestApplyCursorPage_RelevanceWithoutSearchFallsBackToDate(t *testing.T) {
+	for _, search := range []string{"", "  ", "?!"} {
+		t.Run(search, func(t *testing.T) {
+			sql := pageSQL(t, CursorQuery{
+				Search:     search,
+				SortBy:     "relevance",
+				SortOrder:  "asc",
+				Cursor:     "txn-1",
+				CursorDate: "2026-10-01T00:00:00Z",
+			})
+
+			assert.NotContains(t, sql, "ts_rank")
+			assert.NotContains(t, sql, "to_tsquery")
+			assert.Contains(t, sql, "(transactions.transaction_date > '2026-10-01 00:00:00')")
+			assert.Contains(t, sql, "transactions.id > 'txn-1'")
+			assert.Contains(t, sql, "ORDER BY transactions.transaction_date asc, transactions.id asc")
+		})
+	}
+}
+
+func TestApplyCursorPage_DateCursorWithoutDateIsFirstPage(t *testing.T) {
+	sql := pageSQL(t, CursorQuery{SortBy: "relevance", Cursor: "txn-1"})
+
+	assert.NotContains(t, sql, "transactions.id <")
+	assert.Contains(t, sql, "ORDER BY transactions.transaction_date desc, transactions.id desc")
+}
+
+func TestApplyCursorPage_AmountCursor(t *testing.T) {
+	sql := pageSQL(t, CursorQuery{SortBy: "amount", Cursor: "txn-1", CursorAmount: money.New(50000)})
+
+	assert.Contains(t, sql, "(transactions.amount < '50000.00')")
+	assert.Contains(t, sql, "transactions.id < 'txn-1'")
+	assert.Contains(t, sql, "ORDER BY transactions.amount desc, transactions.id desc")
+}
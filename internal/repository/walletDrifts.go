@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"refina-transaction/internal/types/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WalletDriftsRepository interface {
+	// GetLedgerWalletIDs lists every wallet with at least one transaction.
+	GetLedgerWalletIDs(ctx context.Context, tx Transaction) ([]string, error)
+	// GetDriftsByWalletIDs lists the drifts of the wallets, newest first.
+	GetDriftsByWalletIDs(ctx context.Context, tx Transaction, walletIDs []string) ([]model.WalletDrifts, error)
+	// GetOpenDrift returns nil when the wallet has no open drift, locking the
+	// drift when tx is set so that concurrent checks of a wallet queue up.
+	GetOpenDrift(ctx context.Context, tx Transaction, walletID string) (*model.WalletDrifts, error)
+	CreateDrift(ctx context.Context, tx Transaction, drift model.WalletDrifts) (model.WalletDrifts, error)
+	UpdateDrift(ctx context.Context, tx Transaction, drift model.WalletDrifts) (model.WalletDrifts, error)
+}
+
+type walletDriftsRepository struct {
+	db *gorm.DB
+}
+
+func NewWalletDriftsRepository(db *gorm.DB) WalletDriftsRepository {
+	return &walletDriftsRepository{db}
+}
+
+func (drift_repo *walletDriftsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return drift_repo.db.WithContext(ctx), nil
+}
+
+func (drift_repo *walletDriftsRepository) GetLedgerWalletIDs(ctx context.Context, tx Transaction) ([]string, error) {
+	db, err := drift_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var walletIDs []string
+	err = db.Model(&model.Transactions{}).Distinct("wallet_id").Order("wallet_id").Pluck("wallet_id", &walletIDs).Error
+	if err != nil {
+		return nil, errors.New("failed to get ledger wallets")
+	}
+	return walletIDs, nil
+}
+
+func (drift_repo *walletDriftsRepository) GetDriftsByWalletIDs(ctx context.Context, tx Transaction, walletIDs []string) ([]model.WalletDrifts, error) {
+	db, err := drift_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []model.WalletDrifts
+	err = db.Where("wallet_id IN ?", walletIDs).Order("created_at DESC").Find(&drifts).Error
+	if err != nil {
+		return nil, errors.New("wallet drifts not found")
+	}
+	return drifts, nil
+}
+
+func (drift_repo *walletDriftsRepository) GetOpenDrift(ctx context.Context, tx Transaction, walletID string) (*model.WalletDrifts, error) {
+	db, err := drift_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx != nil {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var drifts []model.WalletDrifts
+	err = db.Where("wallet_id = ? AND status = ?", walletID, model.WalletDriftOpen).Limit(1).Find(&drifts).Error
+	if err != nil {
+		return nil, errors.New("failed to get open wallet drift")
+	}
+	if len(drifts) == 0 {
+		return nil, nil
+	}
+	return &drifts[0], nil
+}
+
+func (drift_repo *walletDriftsRepository) CreateDrift(ctx context.Context, tx Transaction, drift model.WalletDrifts) (model.WalletDrifts, error) {
+	db, err := drift_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.WalletDrifts{}, err
+	}
+
+	if err := db.Create(&drift).Error; err != nil {
+		return model.WalletDrifts{}, err
+	}
+	return drift, nil
+}
+
+func (drift_repo *walletDriftsRepository) UpdateDrift(ctx context.Context, tx Transaction, drift model.WalletDrifts) (model.WalletDrifts, error) {
+	db, err := drift_repo.getDB(ctx, tx)
+	if err != nil {
+		return model.WalletDrifts{}, err
+	}
+
+	if err := db.Save(&drift).Error; err != nil {
+		return model.WalletDrifts{}, err
+	}
+	return drift, nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+)
+
+var (
+	ErrUnauthenticated  = errors.New("unauthenticated")
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// AuthorizationService checks that the caller owns the wallets behind the
+// transactions and attachments it acts on. Wallet ownership is resolved
+// through wallet-service on every call, so there is no local copy to go stale.
+type AuthorizationService interface {
+	GetUserWalletIDs(ctx context.Context, userID string) ([]string, error)
+	ScopeWallets(ctx context.Context, userID string, walletIDs ...string) ([]string, error)
+	AuthorizeWallets(ctx context.Context, userID string, walletIDs ...string) error
+	AuthorizeTransaction(ctx context.Context, userID, transactionID string) error
+	AuthorizeDeletedTransaction(ctx context.Context, userID, transactionID string) error
+	AuthorizeTransactionHistory(ctx context.Context, userID, transactionID string) error
+	AuthorizeBatch(ctx context.Context, userID string, request dto.BatchTransactionsRequest) error
+	AuthorizeAttachment(ctx context.Context, userID, attachmentID string) error
+	AuthorizeRecurringTransaction(ctx context.Context, userID, recurringID string) error
+	AuthorizeTransfer(ctx context.Context, userID, transferID string) error
+	AuthorizeDebt(ctx context.Context, userID, debtID string) error
+}
+
+type authorizationService struct {
+	walletClient    client.WalletClient
+	transactionRepo repository.TransactionsRepository
+	attachmentRepo  repository.AttachmentsRepository
+	recurringRepo   repository.RecurringTransactionsRepository
+	debtRepo        repository.DebtsRepository
+}
+
+func NewAuthorizationService(walletClient client.WalletClient, transactionRepo repository.TransactionsRepository, attachmentRepo repository.AttachmentsRepository, recurringRepo repository.RecurringTransactionsRepository, debtRepo repository.DebtsRepository) AuthorizationService {
+	return &authorizationService{
+		walletClient:    walletClient,
+		transactionRepo: transactionRepo,
+		attachmentRepo:  attachmentRepo,
+		recurringRepo:   recurringRepo,
+		debtRepo:        debtRepo,
+	}
+}
+
+func (authorization_serv *authorizationService) GetUserWalletIDs(ctx context.Context, userID string) ([]string, error) {
+	if userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	wallets, err := authorization_serv.walletClient.GetUserWallets(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user wallets [user_id=%s]: %w", userID, err)
+	}
+
+	ids := make([]string, 0, len(wallets))
+	for _, wallet := range wallets {
+		ids = append(ids, wallet.GetId())
+	}
+
+	return ids, nil
+}
+
+// ScopeWallets returns walletIDs when the user owns every one of them, or all
+// of the user's wallets when none are given. Empty IDs are ignored.
+func (authorization_serv *authorizationService) ScopeWallets(ctx context.Context, userID string, walletIDs ...string) ([]string, error) {
+	owned, err := authorization_serv.GetUserWalletIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ownedSet := make(map[string]struct{}, len(owned))
+	for _, id := range owned {
+		ownedSet[id] = struct{}{}
+	}
+
+	scoped := make([]string, 0, len(walletIDs))
+	for _, id := range walletIDs {
+		if id == "" {
+			continue
+		}
+		if _, ok := ownedSet[id]; !ok {
+			return nil, fmt.Errorf("%w: wallet does not belong to user [wallet_id=%s, user_id=%s]", ErrPermissionDenied, id, userID)
+		}
+		scoped = append(scoped, id)
+	}
+
+	if len(scoped) == 0 {
+		return owned, nil
+	}
+
+	return scoped, nil
+}
+
+// AuthorizeWallets fails with ErrPermissionDenied if any non-empty wallet ID
+// does not belong to the user.
+func (authorization_serv *authorizationService) AuthorizeWallets(ctx context.Context, userID string, walletIDs ...string) error {
+	_, err := authorization_serv.ScopeWallets(ctx, userID, walletIDs...)
+	return err
+}
+
+func (authorization_serv *authorizationService) AuthorizeTransaction(ctx context.Context, userID, transactionID string) error {
+	if userID == "" {
+		return ErrUnauthenticated
+	}
+
+	transaction, err := authorization_serv.transactionRepo.GetTransactionByID(ctx, nil, transactionID)
+	if err != nil {
+		return fmt.Errorf("transaction not found [id=%s]: %w", transactionID, err)
+	}
+
+	return authorization_serv.AuthorizeWallets(ctx, userID, transaction.WalletID.String())
+}
+
+// AuthorizeDeletedTransaction is AuthorizeTransaction for a transaction in the trash.
+func (authorization_serv *authorizationService) AuthorizeDeletedTransaction(ctx context.Context, userID, transactionID string) error {
+	if userID == "" {
+		return ErrUnauthenticated
+	}
+
+	transaction, err := authorization_serv.transactionRepo.GetDeletedTransactionByID(ctx, nil, transactionID)
+	if err != nil {
+		return fmt.Errorf("deleted transaction not found [id=%s]: %w", transactionID, err)
+	}
+
+	return authorization_serv.AuthorizeWallets(ctx, userID, transaction.WalletID.String())
+}
+
+// AuthorizeBatch checks the wallets a batch writes to and the wallets of the
+// transactions it changes, with one wallet-service call. Transactions that do
+// not exist are left for the batch to report per item.
+func (authorization_serv *authorizationService) AuthorizeBatch(ctx context.Context, userID string, request dto.BatchTransactionsRequest) error {
+	if userID == "" {
+		return ErrUnauthenticated
+	}
+
+	walletIDs := make([]string, 0, len(request.Items))
+	for _, item := range request.Items {
+		walletIDs = append(walletIDs, item.Transaction.WalletID)
+		if item.ID == "" {
+			continue
+		}
+
+		transaction, err := authorization_serv.transactionRepo.GetTransactionByID(ctx, nil, item.ID)
+		if err != nil {
+			continue
+		}
+		walletIDs = append(walletIDs, transaction.WalletID.String())
+	}
+
+	return authorization_serv.AuthorizeWallets(ctx, userID, walletIDs...)
+}
+
+// AuthorizeTransactionHistory is AuthorizeTransaction for a transaction that
+// may also sit in the trash, since its history stays readable after a delete.
+func (authorization_serv *authorizationService) AuthorizeTransactionHistory(ctx context.Context, userID, transactionID string) error {
+	if userID == "" {
+		return ErrUnauthenticated
+	}
+
+	transaction, err := authorization_serv.transactionRepo.GetTransactionByID(ctx, nil, transactionID)
+	if err != nil {
+		transaction, err = authorization_serv.transactionRepo.GetDeletedTransactionByID(ctx, nil, transactionID)
+		if err != nil {
+			return fmt.Errorf("transaction not found [id=%s]: %w", transactionID, err)
+		}
+	}
+
+	return authorization_serv.AuthorizeWallets(ctx, userID, transaction.WalletID.String())
+}
+
+func (authorization_serv *authorizationService) AuthorizeAttachment(ctx context.Context, userID, attachmentID string) error {
+	if userID == "" {
+		return ErrUnauthenticated
+	}
+
+	attachment, err := authorization_serv.attachmentRepo.GetAttachmentByID(ctx, nil, attachmentID)
+	if err != nil {
+		return fmt.Errorf("attachment not found [id=%s]: %w", attachmentID, err)
+	}
+
+	return authorization_serv.AuthorizeTransaction(ctx, userID, attachment.TransactionID.String())
+}
+
+func (authorization_serv *authorizationService) AuthorizeRecurringTransaction(ctx context.Context, userID, recurringID string) error {
+	if userID == "" {
+		return ErrUnauthenticated
+	}
+
+	recurring, err := authorization_serv.recurringRepo.GetRecurringTransactionByID(ctx, nil, recurringID)
+	if err != nil {
+		return fmt.Errorf("recurring transaction not found [id=%s]: %w", recurringID, err)
+	}
+
+	return authorization_serv.AuthorizeWallets(ctx, userID, recurring.WalletID.String())
+}
+
+// AuthorizeTransfer requires the user to own both wallets of a transfer.
+func (authorization_serv *authorizationService) AuthorizeTransfer(ctx context.Context, userID, transferID string) error {
+	if userID == "" {
+		return ErrUnauthenticated
+	}
+
+	transfer, err := authorization_serv.transactionRepo.GetTransferByID(ctx, nil, transferID)
+	if err != nil {
+		return fmt.Errorf("transfer not found [id=%s]: %w", transferID, err)
+	}
+
+	return authorization_serv.AuthorizeWallets(ctx, userID, transfer.FromWalletID.String(), transfer.ToWalletID.String())
+}
+
+func (authorization_serv *authorizationService) AuthorizeDebt(ctx context.Context, userID, debtID string) error {
+	if userID == "" {
+		return ErrUnauthenticated
+	}
+
+	debt, err := authorization_serv.debtRepo.GetDebtByID(ctx, nil, debtID)
+	if err != nil {
+		return fmt.Errorf("debt not found [id=%s]: %w", debtID, err)
+	}
+
+	return authorization_serv.AuthorizeWallets(ctx, userID, debt.WalletID.String())
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+
+	wpb "github.com/MuhammadMiftaa/Refina-Protobuf/wallet"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type authorizationTestDeps struct {
+	walletClient    *mocks.MockWalletClient
+	transactionRepo *mocks.MockTransactionsRepository
+	attachmentRepo  *mocks.MockAttachmentsRepository
+	recurringRepo   *mocks.MockRecurringTransactionsRepository
+	debtRepo        *mocks.MockDebtsRepository
+}
+
+func newAuthorizationTestDeps() *authorizationTestDeps {
+	return &authorizationTestDeps{
+		walletClient:    new(mocks.MockWalletClient),
+		transactionRepo: new(mocks.MockTransactionsRepository),
+		attachmentRepo:  new(mocks.MockAttachmentsRepository),
+		recurringRepo:   new(mocks.MockRecurringTransactionsRepository),
+		debtRepo:        new(mocks.MockDebtsRepository),
+	}
+}
+
+func (d *authorizationTestDeps) service() AuthorizationService {
+	return NewAuthorizationService(d.walletClient, d.transactionRepo, d.attachmentRepo, d.recurringRepo, d.debtRepo)
+}
+
+func (d *authorizationTestDeps) assertAll(t *testing.T) {
+	t.Helper()
+	d.walletClient.AssertExpectations(t)
+	d.transactionRepo.AssertExpectations(t)
+	d.attachmentRepo.AssertExpectations(t)
+	d.recurringRepo.AssertExpectations(t)
+	d.debtRepo.AssertExpectations(t)
+}
+
+var (
+	authzUserID        = "user-1"
+	authzOtherWalletID = uuid.MustParse("44444444-4444-4444-4444-444444444444")
+)
+
+func (d *authorizationTestDeps) expectUserWallets(ids ...uuid.UUID) {
+	wallets := make([]*wpb.Wallet, 0, len(ids))
+	for _, id := range ids {
+		wallets = append(wallets, sampleWalletProto(id, 0))
+	}
+	d.walletClient.On("GetUserWallets", mock.Anything, authzUserID).Return(wallets, nil)
+}
+
+// =====================================================================
+// ScopeWallets
+// =====================================================================
+
+func TestScopeWallets_OwnedWallets(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.expectUserWallets(walletTestID, authzOtherWalletID)
+
+	result, err := svc.ScopeWallets(context.Background(), authzUserID, walletTestID.String(), "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{walletTestID.String()}, result)
+	d.assertAll(t)
+}
+
+func TestScopeWallets_EmptyReturnsAllOwned(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.expectUserWallets(walletTestID, authzOtherWalletID)
+
+	result, err := svc.ScopeWallets(context.Background(), authzUserID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{walletTestID.String(), authzOtherWalletID.String()}, result)
+	d.assertAll(t)
+}
+
+func TestScopeWallets_ForeignWalletDenied(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.expectUserWallets(walletTestID)
+
+	result, err := svc.ScopeWallets(context.Background(), authzUserID, walletTestID.String(), authzOtherWalletID.String())
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.assertAll(t)
+}
+
+func TestScopeWallets_Unauthenticated(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	result, err := svc.ScopeWallets(context.Background(), "", walletTestID.String())
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	d.walletClient.AssertNotCalled(t, "GetUserWallets", mock.Anything, mock.Anything)
+}
+
+func TestScopeWallets_WalletServiceError(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.walletClient.On("GetUserWallets", mock.Anything, authzUserID).Return(nil, errors.New("unavailable"))
+
+	result, err := svc.ScopeWallets(context.Background(), authzUserID, walletTestID.String())
+
+	assert.Nil(t, result)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrPermissionDenied)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// AuthorizeTransaction
+// =====================================================================
+
+func TestAuthorizeTransaction_Owned(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, nil, txnTestID.String()).Return(sampleTransactionModel(), nil)
+	d.expectUserWallets(walletTestID)
+
+	err := svc.AuthorizeTransaction(context.Background(), authzUserID, txnTestID.String())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestAuthorizeTransaction_ForeignWalletDenied(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, nil, txnTestID.String()).Return(sampleTransactionModel(), nil)
+	d.expectUserWallets(authzOtherWalletID)
+
+	err := svc.AuthorizeTransaction(context.Background(), authzUserID, txnTestID.String())
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.assertAll(t)
+}
+
+func TestAuthorizeTransaction_NotFound(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, nil, txnTestID.String()).Return(model.Transactions{}, errors.New("transaction not found"))
+
+	err := svc.AuthorizeTransaction(context.Background(), authzUserID, txnTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	d.walletClient.AssertNotCalled(t, "GetUserWallets", mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestAuthorizeTransaction_Unauthenticated(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	err := svc.AuthorizeTransaction(context.Background(), "", txnTestID.String())
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	d.transactionRepo.AssertNotCalled(t, "GetTransactionByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthorizeDeletedTransaction_ForeignWalletDenied(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("GetDeletedTransactionByID", mock.Anything, nil, txnTestID.String()).Return(sampleTransactionModel(), nil)
+	d.expectUserWallets(authzOtherWalletID)
+
+	err := svc.AuthorizeDeletedTransaction(context.Background(), authzUserID, txnTestID.String())
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.transactionRepo.AssertNotCalled(t, "GetTransactionByID", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// AuthorizeBatch
+// =====================================================================
+
+func TestAuthorizeBatch_ChecksTargetAndExistingWallets(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	moved := sampleTransactionModel()
+	moved.WalletID = authzOtherWalletID
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, nil, txnTestID.String()).Return(moved, nil)
+	d.expectUserWallets(walletTestID)
+
+	// The update targets an owned wallet but changes a transaction of a foreign one
+	err := svc.AuthorizeBatch(context.Background(), authzUserID, dto.BatchTransactionsRequest{Items: []dto.BatchTransactionItem{
+		{Action: "update", ID: txnTestID.String(), Transaction: dto.TransactionsRequest{WalletID: walletTestID.String()}},
+	}})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.assertAll(t)
+}
+
+func TestAuthorizeBatch_LeavesMissingTransactionsToTheBatch(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, nil, txnTestID.String()).
+		Return(model.Transactions{}, errors.New("transaction not found"))
+	d.expectUserWallets(walletTestID)
+
+	err := svc.AuthorizeBatch(context.Background(), authzUserID, dto.BatchTransactionsRequest{Items: []dto.BatchTransactionItem{
+		{Action: "create", Transaction: dto.TransactionsRequest{WalletID: walletTestID.String()}},
+		{Action: "delete", ID: txnTestID.String()},
+	}})
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// AuthorizeTransactionHistory
+// =====================================================================
+
+func TestAuthorizeTransactionHistory_DeletedTransactionOwned(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, nil, txnTestID.String()).
+		Return(model.Transactions{}, errors.New("transaction not found"))
+	d.transactionRepo.On("GetDeletedTransactionByID", mock.Anything, nil, txnTestID.String()).Return(sampleTransactionModel(), nil)
+	d.expectUserWallets(walletTestID)
+
+	err := svc.AuthorizeTransactionHistory(context.Background(), authzUserID, txnTestID.String())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestAuthorizeTransactionHistory_ForeignWalletDenied(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, nil, txnTestID.String()).Return(sampleTransactionModel(), nil)
+	d.expectUserWallets(authzOtherWalletID)
+
+	err := svc.AuthorizeTransactionHistory(context.Background(), authzUserID, txnTestID.String())
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.transactionRepo.AssertNotCalled(t, "GetDeletedTransactionByID", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// AuthorizeAttachment
+// =====================================================================
+
+func TestAuthorizeAttachment_Owned(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	attachment := sampleAttachmentModel()
+	attachment.TransactionID = txnTestID
+	d.attachmentRepo.On("GetAttachmentByID", mock.Anything, nil, attID.String()).Return(attachment, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, nil, txnTestID.String()).Return(sampleTransactionModel(), nil)
+	d.expectUserWallets(walletTestID)
+
+	err := svc.AuthorizeAttachment(context.Background(), authzUserID, attID.String())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestAuthorizeAttachment_NotFound(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.attachmentRepo.On("GetAttachmentByID", mock.Anything, nil, attID.String()).Return(model.Attachments{}, errors.New("attachment not found"))
+
+	err := svc.AuthorizeAttachment(context.Background(), authzUserID, attID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	d.assertAll(t)
+}
+
+// =====================================================================
+// AuthorizeRecurringTransaction
+// =====================================================================
+
+func TestAuthorizeRecurringTransaction_ForeignWalletDenied(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.recurringRepo.On("GetRecurringTransactionByID", mock.Anything, nil, recurringTestID.String()).Return(sampleRecurringModel(model.RecurringMonthly), nil)
+	d.expectUserWallets(authzOtherWalletID)
+
+	err := svc.AuthorizeRecurringTransaction(context.Background(), authzUserID, recurringTestID.String())
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// AuthorizeTransfer
+// =====================================================================
+
+func TestAuthorizeTransfer_BothWalletsOwned(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("GetTransferByID", mock.Anything, nil, transferTestID.String()).
+		Return(model.Transfers{FromWalletID: walletTestID, ToWalletID: wallet2ID}, nil)
+	d.expectUserWallets(walletTestID, wallet2ID)
+
+	err := svc.AuthorizeTransfer(context.Background(), authzUserID, transferTestID.String())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestAuthorizeTransfer_ForeignDestinationDenied(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("GetTransferByID", mock.Anything, nil, transferTestID.String()).
+		Return(model.Transfers{FromWalletID: walletTestID, ToWalletID: wallet2ID}, nil)
+	d.expectUserWallets(walletTestID)
+
+	err := svc.AuthorizeTransfer(context.Background(), authzUserID, transferTestID.String())
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// AuthorizeDebt
+// =====================================================================
+
+func TestAuthorizeDebt_WalletOwned(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.debtRepo.On("GetDebtByID", mock.Anything, nil, debtTestID.String()).Return(sampleReceivableDebt(), nil)
+	d.expectUserWallets(walletTestID)
+
+	err := svc.AuthorizeDebt(context.Background(), authzUserID, debtTestID.String())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestAuthorizeDebt_ForeignWalletDenied(t *testing.T) {
+	d := newAuthorizationTestDeps()
+	svc := d.service()
+
+	d.debtRepo.On("GetDebtByID", mock.Anything, nil, debtTestID.String()).Return(sampleReceivableDebt(), nil)
+	d.expectUserWallets(authzOtherWalletID)
+
+	err := svc.AuthorizeDebt(context.Background(), authzUserID, debtTestID.String())
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.assertAll(t)
+}
@@ -0,0 +1,485 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+)
+
+type BudgetsService interface {
+	GetBudgets(ctx context.Context, userID string) ([]dto.BudgetsResponse, error)
+	GetBudgetByID(ctx context.Context, userID, id string) (dto.BudgetsResponse, error)
+	CreateBudget(ctx context.Context, userID string, budget dto.BudgetsRequest) (dto.BudgetsResponse, error)
+	UpdateBudget(ctx context.Context, userID, id string, budget dto.BudgetsRequest) (dto.BudgetsResponse, error)
+	DeleteBudget(ctx context.Context, userID, id string) (dto.BudgetsResponse, error)
+}
+
+type budgetsService struct {
+	budgetRepo   repository.BudgetsRepository
+	categoryRepo repository.CategoriesRepository
+	walletClient client.WalletClient
+	now          func() time.Time
+}
+
+func NewBudgetsService(budgetRepo repository.BudgetsRepository, categoryRepo repository.CategoriesRepository, walletClient client.WalletClient) BudgetsService {
+	return &budgetsService{
+		budgetRepo:   budgetRepo,
+		categoryRepo: categoryRepo,
+		walletClient: walletClient,
+		now:          time.Now,
+	}
+}
+
+func (budget_serv *budgetsService) GetBudgets(ctx context.Context, userID string) ([]dto.BudgetsResponse, error) {
+	if userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	budgets, err := budget_serv.budgetRepo.GetBudgetsByUserID(ctx, nil, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get budgets [user_id=%s]: %w", userID, err)
+	}
+
+	walletIDs, err := userWalletIDs(ctx, budget_serv.walletClient, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.BudgetsResponse, 0, len(budgets))
+	for _, budget := range budgets {
+		response, err := budget_serv.toResponse(ctx, budget, walletIDs)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+func (budget_serv *budgetsService) GetBudgetByID(ctx context.Context, userID, id string) (dto.BudgetsResponse, error) {
+	budget, err := budget_serv.ownedBudget(ctx, userID, id)
+	if err != nil {
+		return dto.BudgetsResponse{}, err
+	}
+
+	walletIDs, err := userWalletIDs(ctx, budget_serv.walletClient, userID)
+	if err != nil {
+		return dto.BudgetsResponse{}, err
+	}
+
+	return budget_serv.toResponse(ctx, budget, walletIDs)
+}
+
+func (budget_serv *budgetsService) CreateBudget(ctx context.Context, userID string, budget dto.BudgetsRequest) (dto.BudgetsResponse, error) {
+	if userID == "" {
+		return dto.BudgetsResponse{}, ErrUnauthenticated
+	}
+
+	budgetNew, err := budget_serv.buildBudget(ctx, model.Budgets{UserID: userID}, budget)
+	if err != nil {
+		return dto.BudgetsResponse{}, err
+	}
+
+	budgetNew, err = budget_serv.budgetRepo.CreateBudget(ctx, nil, budgetNew)
+	if err != nil {
+		return dto.BudgetsResponse{}, fmt.Errorf("create budget: insert to db: %w", err)
+	}
+
+	walletIDs, err := userWalletIDs(ctx, budget_serv.walletClient, userID)
+	if err != nil {
+		return dto.BudgetsResponse{}, err
+	}
+
+	return budget_serv.toResponse(ctx, budgetNew, walletIDs)
+}
+
+func (budget_serv *budgetsService) UpdateBudget(ctx context.Context, userID, id string, budget dto.BudgetsRequest) (dto.BudgetsResponse, error) {
+	existing, err := budget_serv.ownedBudget(ctx, userID, id)
+	if err != nil {
+		return dto.BudgetsResponse{}, err
+	}
+
+	updated, err := budget_serv.buildBudget(ctx, existing, budget)
+	if err != nil {
+		return dto.BudgetsResponse{}, err
+	}
+
+	updated, err = budget_serv.budgetRepo.UpdateBudget(ctx, nil, updated)
+	if err != nil {
+		return dto.BudgetsResponse{}, fmt.Errorf("update budget [id=%s]: %w", id, err)
+	}
+
+	walletIDs, err := userWalletIDs(ctx, budget_serv.walletClient, userID)
+	if err != nil {
+		return dto.BudgetsResponse{}, err
+	}
+
+	return budget_serv.toResponse(ctx, updated, walletIDs)
+}
+
+func (budget_serv *budgetsService) DeleteBudget(ctx context.Context, userID, id string) (dto.BudgetsResponse, error) {
+	budget, err := budget_serv.ownedBudget(ctx, userID, id)
+	if err != nil {
+		return dto.BudgetsResponse{}, err
+	}
+
+	budgetDeleted, err := budget_serv.budgetRepo.DeleteBudget(ctx, nil, budget)
+	if err != nil {
+		return dto.BudgetsResponse{}, fmt.Errorf("delete budget [id=%s]: %w", id, err)
+	}
+
+	return dto.BudgetsResponse{
+		ID:           budgetDeleted.ID.String(),
+		CategoryID:   budgetDeleted.CategoryID.String(),
+		CategoryName: budgetDeleted.Category.Name,
+		IsGroup:      budgetDeleted.Category.ParentID == nil,
+		Period:       string(budgetDeleted.Period),
+		Amount:       budgetDeleted.Amount,
+		Rollover:     budgetDeleted.Rollover,
+	}, nil
+}
+
+// ownedBudget loads a budget and checks that it belongs to userID.
+func (budget_serv *budgetsService) ownedBudget(ctx context.Context, userID, id string) (model.Budgets, error) {
+	if userID == "" {
+		return model.Budgets{}, ErrUnauthenticated
+	}
+
+	budget, err := budget_serv.budgetRepo.GetBudgetByID(ctx, nil, id)
+	if err != nil {
+		return model.Budgets{}, fmt.Errorf("budget not found [id=%s]: %w", id, err)
+	}
+	if budget.UserID != userID {
+		return model.Budgets{}, fmt.Errorf("%w: budget does not belong to user [budget_id=%s, user_id=%s]", ErrPermissionDenied, id, userID)
+	}
+
+	return budget, nil
+}
+
+// buildBudget validates the request and applies it on top of base.
+func (budget_serv *budgetsService) buildBudget(ctx context.Context, base model.Budgets, budget dto.BudgetsRequest) (model.Budgets, error) {
+	categoryID, err := helper.ParseUUID(budget.CategoryID)
+	if err != nil {
+		return model.Budgets{}, fmt.Errorf("invalid category id [id=%s]: %w", budget.CategoryID, err)
+	}
+
+	category, err := budget_serv.categoryRepo.GetCategoryByID(ctx, nil, budget.CategoryID)
+	if err != nil {
+		return model.Budgets{}, fmt.Errorf("category not found [id=%s]: %w", budget.CategoryID, err)
+	}
+	if category.Type != model.Expense {
+		return model.Budgets{}, fmt.Errorf("invalid budget category type [type=%s]", category.Type)
+	}
+
+	period := model.BudgetPeriod(budget.Period)
+	if period == "" {
+		period = model.BudgetMonthly
+	}
+	switch period {
+	case model.BudgetWeekly, model.BudgetMonthly, model.BudgetYearly:
+	default:
+		return model.Budgets{}, fmt.Errorf("invalid budget period [period=%s]", budget.Period)
+	}
+
+	if !budget.Amount.IsPositive() {
+		return model.Budgets{}, fmt.Errorf("invalid amount [amount=%v]", budget.Amount)
+	}
+
+	base.CategoryID = categoryID
+	base.Category = category
+	base.Period = period
+	base.Amount = budget.Amount
+	base.Rollover = budget.Rollover
+
+	return base, nil
+}
+
+func (budget_serv *budgetsService) toResponse(ctx context.Context, budget model.Budgets, walletIDs []string) (dto.BudgetsResponse, error) {
+	from, to := budgetPeriodBounds(budget.Period, budget_serv.now())
+
+	limit, err := budgetLimit(ctx, nil, budget_serv.budgetRepo, budget, walletIDs, from)
+	if err != nil {
+		return dto.BudgetsResponse{}, err
+	}
+
+	spent, err := budget_serv.budgetRepo.GetSpent(ctx, nil, budget.CategoryID.String(), walletIDs, from, to)
+	if err != nil {
+		return dto.BudgetsResponse{}, fmt.Errorf("get budget spent [budget_id=%s]: %w", budget.ID, err)
+	}
+
+	return dto.BudgetsResponse{
+		ID:           budget.ID.String(),
+		CategoryID:   budget.CategoryID.String(),
+		CategoryName: budget.Category.Name,
+		IsGroup:      budget.Category.ParentID == nil,
+		Period:       string(budget.Period),
+		Amount:       budget.Amount,
+		Rollover:     budget.Rollover,
+		PeriodStart:  from,
+		PeriodEnd:    to,
+		Limit:        limit,
+		Spent:        spent,
+		Remaining:    limit.Sub(spent),
+		Percentage:   budgetPercentage(spent, limit),
+	}, nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Budget monitor
+// ──────────────────────────────────────────────────────────────────────────────
+
+// budgetMonitor emits budget outbox events when a transaction write pushes
+// spending across a threshold. It runs inside the caller's DB transaction so
+// the events commit together with the write.
+type budgetMonitor struct {
+	budgetRepo   repository.BudgetsRepository
+	walletClient client.WalletClient
+	outboxRepo   repository.OutboxRepository
+}
+
+func newBudgetMonitor(budgetRepo repository.BudgetsRepository, walletClient client.WalletClient, outboxRepo repository.OutboxRepository) *budgetMonitor {
+	return &budgetMonitor{
+		budgetRepo:   budgetRepo,
+		walletClient: walletClient,
+		outboxRepo:   outboxRepo,
+	}
+}
+
+// TransactionChanged compares spending before and after a write. before is
+// nil for a new transaction; both must have Category loaded. Only the highest
+// threshold crossed is emitted, so one transaction never sends both events.
+//
+// Budget alerts are advisory, so when wallet-service cannot resolve the owner
+// the evaluation is logged and skipped rather than failing the write. Database
+// errors are still returned: they abort the caller's DB transaction anyway.
+func (m *budgetMonitor) TransactionChanged(ctx context.Context, tx repository.Transaction, before, after *model.Transactions) error {
+	categoryIDs := budgetCategoryIDs(before, after)
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	budgets, err := m.budgetRepo.GetBudgetsByCategoryIDs(ctx, tx, categoryIDs)
+	if err != nil {
+		return fmt.Errorf("get budgets by categories: %w", err)
+	}
+	if len(budgets) == 0 {
+		return nil
+	}
+
+	// Budgets are per user, so resolve who owns the wallet only when one may apply
+	wallet, err := m.walletClient.GetWalletByID(ctx, after.WalletID.String())
+	if err != nil {
+		m.skip(after, fmt.Errorf("wallet not found [id=%s]: %w", after.WalletID, err))
+		return nil
+	}
+	userID := wallet.GetUserId()
+
+	var walletIDs []string
+	for _, budget := range budgets {
+		if budget.UserID != userID {
+			continue
+		}
+
+		if walletIDs == nil {
+			if walletIDs, err = userWalletIDs(ctx, m.walletClient, userID); err != nil {
+				m.skip(after, err)
+				return nil
+			}
+		}
+
+		if err := m.checkBudget(ctx, tx, budget, walletIDs, before, after); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *budgetMonitor) skip(after *model.Transactions, err error) {
+	log.Warn(data.LogBudgetEvaluationSkipped, map[string]any{
+		"service":        data.BudgetService,
+		"transaction_id": after.ID.String(),
+		"wallet_id":      after.WalletID.String(),
+		"error":          err.Error(),
+	})
+}
+
+func (m *budgetMonitor) checkBudget(ctx context.Context, tx repository.Transaction, budget model.Budgets, walletIDs []string, before, after *model.Transactions) error {
+	from, to := budgetPeriodBounds(budget.Period, after.TransactionDate)
+
+	limit, err := budgetLimit(ctx, tx, m.budgetRepo, budget, walletIDs, from)
+	if err != nil {
+		return err
+	}
+
+	spentAfter, err := m.budgetRepo.GetSpent(ctx, tx, budget.CategoryID.String(), walletIDs, from, to)
+	if err != nil {
+		return fmt.Errorf("get budget spent [budget_id=%s]: %w", budget.ID, err)
+	}
+	spentBefore := spentAfter.Sub(budgetContribution(budget, after, from, to)).Add(budgetContribution(budget, before, from, to))
+
+	warning, err := limit.MulRate(data.BUDGET_THRESHOLD_WARNING)
+	if err != nil {
+		return fmt.Errorf("budget warning threshold [budget_id=%s]: %w", budget.ID, err)
+	}
+
+	var eventType string
+	switch {
+	case crossed(spentBefore, spentAfter, limit):
+		eventType = data.OUTBOX_EVENT_BUDGET_EXCEEDED
+	case crossed(spentBefore, spentAfter, warning):
+		eventType = data.OUTBOX_EVENT_BUDGET_THRESHOLD_REACHED
+	default:
+		return nil
+	}
+
+	payload, err := json.Marshal(dto.BudgetEvent{
+		BudgetID:      budget.ID.String(),
+		UserID:        budget.UserID,
+		CategoryID:    budget.CategoryID.String(),
+		Period:        string(budget.Period),
+		PeriodStart:   from,
+		PeriodEnd:     to,
+		Limit:         limit,
+		Spent:         spentAfter,
+		Percentage:    budgetPercentage(spentAfter, limit),
+		TransactionID: after.ID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal budget event [budget_id=%s]: %w", budget.ID, err)
+	}
+
+	return m.outboxRepo.Create(ctx, tx, &model.OutboxMessage{
+		AggregateID: budget.ID.String(),
+		EventType:   eventType,
+		Payload:     payload,
+		Published:   false,
+		MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+	})
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ──────────────────────────────────────────────────────────────────────────────
+
+func userWalletIDs(ctx context.Context, walletClient client.WalletClient, userID string) ([]string, error) {
+	wallets, err := walletClient.GetUserWallets(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user wallets [user_id=%s]: %w", userID, err)
+	}
+
+	ids := make([]string, 0, len(wallets))
+	for _, wallet := range wallets {
+		ids = append(ids, wallet.GetId())
+	}
+	return ids, nil
+}
+
+// budgetPeriodBounds returns the calendar period [from, to) containing t.
+// Weeks start on Monday.
+func budgetPeriodBounds(period model.BudgetPeriod, t time.Time) (time.Time, time.Time) {
+	year, month, day := t.Date()
+
+	switch period {
+	case model.BudgetWeekly:
+		offset := (int(t.Weekday()) + 6) % 7
+		from := time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+		return from, from.AddDate(0, 0, 7)
+	case model.BudgetYearly:
+		from := time.Date(year, 1, 1, 0, 0, 0, 0, t.Location())
+		return from, from.AddDate(1, 0, 0)
+	default:
+		from := time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+		return from, from.AddDate(0, 1, 0)
+	}
+}
+
+// budgetLimit is the budget amount plus, with rollover, whatever was left
+// unspent in the previous period. Overspending is not carried over.
+func budgetLimit(ctx context.Context, tx repository.Transaction, budgetRepo repository.BudgetsRepository, budget model.Budgets, walletIDs []string, from time.Time) (money.Amount, error) {
+	if !budget.Rollover {
+		return budget.Amount, nil
+	}
+
+	prevFrom, _ := budgetPeriodBounds(budget.Period, from.Add(-time.Nanosecond))
+	prevSpent, err := budgetRepo.GetSpent(ctx, tx, budget.CategoryID.String(), walletIDs, prevFrom, from)
+	if err != nil {
+		return money.Zero, fmt.Errorf("get previous budget spent [budget_id=%s]: %w", budget.ID, err)
+	}
+
+	if unspent := budget.Amount.Sub(prevSpent); unspent.IsPositive() {
+		return budget.Amount.Add(unspent), nil
+	}
+	return budget.Amount, nil
+}
+
+// budgetCategoryIDs lists the categories whose budgets may be affected: the
+// expense categories (or split lines) of both versions of the transaction
+// and their groups.
+func budgetCategoryIDs(transactions ...*model.Transactions) []string {
+	seen := make(map[string]struct{})
+	var ids []string
+	add := func(id string) {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+
+	for _, transaction := range transactions {
+		if transaction == nil || transaction.Category.Type != model.Expense || transaction.Status == model.StatusVoid || transaction.Scheduled {
+			continue
+		}
+		for _, line := range transactionLines(transaction) {
+			add(line.CategoryID.String())
+			if line.Category.ParentID != nil {
+				add(line.Category.ParentID.String())
+			}
+		}
+	}
+
+	return ids
+}
+
+// budgetContribution is what the transaction adds to the budget's spending in [from, to).
+func budgetContribution(budget model.Budgets, transaction *model.Transactions, from, to time.Time) money.Amount {
+	if transaction == nil || transaction.Category.Type != model.Expense {
+		return money.Zero
+	}
+	if transaction.TransactionDate.Before(from) || !transaction.TransactionDate.Before(to) {
+		return money.Zero
+	}
+
+	var contribution money.Amount
+	for _, line := range transactionLines(transaction) {
+		inBudget := line.CategoryID == budget.CategoryID ||
+			(line.Category.ParentID != nil && *line.Category.ParentID == budget.CategoryID)
+		if inBudget {
+			contribution = contribution.Add(line.Amount)
+		}
+	}
+
+	return contribution
+}
+
+func crossed(before, after, threshold money.Amount) bool {
+	return before.LessThan(threshold) && !after.LessThan(threshold)
+}
+
+func budgetPercentage(spent, limit money.Amount) float64 {
+	if !limit.IsPositive() {
+		return 0
+	}
+	return spent.Ratio(limit) * 100
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	wpb "github.com/MuhammadMiftaa/Refina-Protobuf/wallet"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type budgetTestDeps struct {
+	budgetRepo   *mocks.MockBudgetsRepository
+	categoryRepo *mocks.MockCategoriesRepository
+	walletClient *mocks.MockWalletClient
+	outboxRepo   *mocks.MockOutboxRepository
+	tx           *mocks.MockTransaction
+}
+
+func newBudgetTestDeps() *budgetTestDeps {
+	return &budgetTestDeps{
+		budgetRepo:   new(mocks.MockBudgetsRepository),
+		categoryRepo: new(mocks.MockCategoriesRepository),
+		walletClient: new(mocks.MockWalletClient),
+		outboxRepo:   new(mocks.MockOutboxRepository),
+		tx:           new(mocks.MockTransaction),
+	}
+}
+
+func (d *budgetTestDeps) service() BudgetsService {
+	return &budgetsService{
+		budgetRepo:   d.budgetRepo,
+		categoryRepo: d.categoryRepo,
+		walletClient: d.walletClient,
+		now:          func() time.Time { return txnFixTime },
+	}
+}
+
+func (d *budgetTestDeps) monitor() *budgetMonitor {
+	return newBudgetMonitor(d.budgetRepo, d.walletClient, d.outboxRepo)
+}
+
+func (d *budgetTestDeps) assertAll(t *testing.T) {
+	t.Helper()
+	d.budgetRepo.AssertExpectations(t)
+	d.categoryRepo.AssertExpectations(t)
+	d.walletClient.AssertExpectations(t)
+	d.outboxRepo.AssertExpectations(t)
+}
+
+// expectOwner stubs the wallet lookups that resolve the budget owner.
+func (d *budgetTestDeps) expectOwner() {
+	wallet := sampleWalletProto(walletTestID, 1000000)
+	wallet.UserId = authzUserID
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
+	d.walletClient.On("GetUserWallets", mock.Anything, authzUserID).Return([]*wpb.Wallet{wallet}, nil)
+}
+
+// ─────────────────────────────────────────────
+// Fixed UUIDs & Timestamps
+// ─────────────────────────────────────────────
+
+var (
+	budgetTestID = uuid.MustParse("66666666-6666-6666-6666-666666666666")
+	budgetFrom   = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	budgetTo     = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// ─────────────────────────────────────────────
+// Sample Data Factories
+// ─────────────────────────────────────────────
+
+func sampleBudgetModel() model.Budgets {
+	return model.Budgets{
+		Base:       model.Base{ID: budgetTestID},
+		UserID:     authzUserID,
+		CategoryID: catTestID,
+		Period:     model.BudgetMonthly,
+		Amount:     money.New(1000000),
+		Category:   sampleExpenseCategory(),
+	}
+}
+
+func sampleBudgetRequest() dto.BudgetsRequest {
+	return dto.BudgetsRequest{
+		CategoryID: catTestID.String(),
+		Period:     string(model.BudgetMonthly),
+		Amount:     money.New(1000000),
+	}
+}
+
+func sampleBudgetTransaction(amount money.Amount) *model.Transactions {
+	transaction := sampleTransactionModel()
+	transaction.Amount = amount
+	transaction.TransactionDate = txnFixTime
+	transaction.Category = sampleExpenseCategory()
+	return &transaction
+}
+
+// =====================================================================
+// budgetPeriodBounds
+// =====================================================================
+
+func TestBudgetPeriodBounds_Weekly(t *testing.T) {
+	// 2025-06-15 is a Sunday, so the week started on Monday 2025-06-09
+	from, to := budgetPeriodBounds(model.BudgetWeekly, txnFixTime)
+
+	assert.Equal(t, time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), to)
+}
+
+func TestBudgetPeriodBounds_Monthly(t *testing.T) {
+	from, to := budgetPeriodBounds(model.BudgetMonthly, txnFixTime)
+
+	assert.Equal(t, budgetFrom, from)
+	assert.Equal(t, budgetTo, to)
+}
+
+// =====================================================================
+// CreateBudget
+// =====================================================================
+
+func TestCreateBudget_Success(t *testing.T) {
+	d := newBudgetTestDeps()
+	svc := d.service()
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.budgetRepo.On("CreateBudget", mock.Anything, nil, mock.MatchedBy(func(b model.Budgets) bool {
+		return b.UserID == authzUserID && b.CategoryID == catTestID && b.Period == model.BudgetMonthly && b.Amount == money.New(1000000)
+	})).Return(sampleBudgetModel(), nil)
+	d.walletClient.On("GetUserWallets", mock.Anything, authzUserID).Return([]*wpb.Wallet{sampleWalletProto(walletTestID, 0)}, nil)
+	d.budgetRepo.On("GetSpent", mock.Anything, nil, catTestID.String(), []string{walletTestID.String()}, budgetFrom, budgetTo).Return(money.New(250000), nil)
+
+	result, err := svc.CreateBudget(context.Background(), authzUserID, sampleBudgetRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, budgetTestID.String(), result.ID)
+	assert.Equal(t, money.New(1000000), result.Limit)
+	assert.Equal(t, money.New(250000), result.Spent)
+	assert.Equal(t, money.New(750000), result.Remaining)
+	assert.Equal(t, float64(25), result.Percentage)
+	d.assertAll(t)
+}
+
+func TestCreateBudget_NonExpenseCategory(t *testing.T) {
+	d := newBudgetTestDeps()
+	svc := d.service()
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, catTestID.String()).Return(sampleIncomeCategory(), nil)
+
+	_, err := svc.CreateBudget(context.Background(), authzUserID, sampleBudgetRequest())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid budget category type")
+	d.budgetRepo.AssertNotCalled(t, "CreateBudget", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCreateBudget_Unauthenticated(t *testing.T) {
+	d := newBudgetTestDeps()
+	svc := d.service()
+
+	_, err := svc.CreateBudget(context.Background(), "", sampleBudgetRequest())
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	d.categoryRepo.AssertNotCalled(t, "GetCategoryByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// =====================================================================
+// GetBudgetByID
+// =====================================================================
+
+func TestGetBudgetByID_ForeignBudgetDenied(t *testing.T) {
+	d := newBudgetTestDeps()
+	svc := d.service()
+
+	budget := sampleBudgetModel()
+	budget.UserID = "user-2"
+	d.budgetRepo.On("GetBudgetByID", mock.Anything, nil, budgetTestID.String()).Return(budget, nil)
+
+	_, err := svc.GetBudgetByID(context.Background(), authzUserID, budgetTestID.String())
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.assertAll(t)
+}
+
+func TestGetBudgetByID_RolloverLimit(t *testing.T) {
+	d := newBudgetTestDeps()
+	svc := d.service()
+
+	budget := sampleBudgetModel()
+	budget.Rollover = true
+	walletIDs := []string{walletTestID.String()}
+	d.budgetRepo.On("GetBudgetByID", mock.Anything, nil, budgetTestID.String()).Return(budget, nil)
+	d.walletClient.On("GetUserWallets", mock.Anything, authzUserID).Return([]*wpb.Wallet{sampleWalletProto(walletTestID, 0)}, nil)
+	d.budgetRepo.On("GetSpent", mock.Anything, nil, catTestID.String(), walletIDs, time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), budgetFrom).Return(money.New(600000), nil)
+	d.budgetRepo.On("GetSpent", mock.Anything, nil, catTestID.String(), walletIDs, budgetFrom, budgetTo).Return(money.New(100000), nil)
+
+	result, err := svc.GetBudgetByID(context.Background(), authzUserID, budgetTestID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(1400000), result.Limit)
+	assert.Equal(t, money.New(1300000), result.Remaining)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// budgetMonitor.TransactionChanged
+// =====================================================================
+
+func TestBudgetMonitor_EmitsExceeded(t *testing.T) {
+	d := newBudgetTestDeps()
+	monitor := d.monitor()
+
+	d.budgetRepo.On("GetBudgetsByCategoryIDs", mock.Anything, d.tx, []string{catTestID.String()}).Return([]model.Budgets{sampleBudgetModel()}, nil)
+	d.expectOwner()
+	// 700k before, 1.1M after: skips past 80% straight to 100%
+	d.budgetRepo.On("GetSpent", mock.Anything, d.tx, catTestID.String(), []string{walletTestID.String()}, budgetFrom, budgetTo).Return(money.New(1100000), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(m *model.OutboxMessage) bool {
+		return m.EventType == data.OUTBOX_EVENT_BUDGET_EXCEEDED && m.AggregateID == budgetTestID.String()
+	})).Return(nil).Once()
+
+	err := monitor.TransactionChanged(context.Background(), d.tx, nil, sampleBudgetTransaction(money.New(400000)))
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestBudgetMonitor_EmitsThresholdReached(t *testing.T) {
+	d := newBudgetTestDeps()
+	monitor := d.monitor()
+
+	d.budgetRepo.On("GetBudgetsByCategoryIDs", mock.Anything, d.tx, []string{catTestID.String()}).Return([]model.Budgets{sampleBudgetModel()}, nil)
+	d.expectOwner()
+	d.budgetRepo.On("GetSpent", mock.Anything, d.tx, catTestID.String(), []string{walletTestID.String()}, budgetFrom, budgetTo).Return(money.New(850000), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(m *model.OutboxMessage) bool {
+		return m.EventType == data.OUTBOX_EVENT_BUDGET_THRESHOLD_REACHED
+	})).Return(nil).Once()
+
+	// Raising the amount from 50k to 150k moves spending from 750k to 850k
+	before := sampleBudgetTransaction(money.New(50000))
+	err := monitor.TransactionChanged(context.Background(), d.tx, before, sampleBudgetTransaction(money.New(150000)))
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestBudgetMonitor_NoThresholdCrossed(t *testing.T) {
+	d := newBudgetTestDeps()
+	monitor := d.monitor()
+
+	d.budgetRepo.On("GetBudgetsByCategoryIDs", mock.Anything, d.tx, []string{catTestID.String()}).Return([]model.Budgets{sampleBudgetModel()}, nil)
+	d.expectOwner()
+	d.budgetRepo.On("GetSpent", mock.Anything, d.tx, catTestID.String(), []string{walletTestID.String()}, budgetFrom, budgetTo).Return(money.New(500000), nil)
+
+	err := monitor.TransactionChanged(context.Background(), d.tx, nil, sampleBudgetTransaction(money.New(100000)))
+
+	assert.NoError(t, err)
+	d.outboxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestBudgetMonitor_IncomeSkipsLookup(t *testing.T) {
+	d := newBudgetTestDeps()
+	monitor := d.monitor()
+
+	transaction := sampleBudgetTransaction(money.New(100000))
+	transaction.Category = sampleIncomeCategory()
+
+	err := monitor.TransactionChanged(context.Background(), d.tx, nil, transaction)
+
+	assert.NoError(t, err)
+	d.budgetRepo.AssertNotCalled(t, "GetBudgetsByCategoryIDs", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBudgetMonitor_SpentError(t *testing.T) {
+	d := newBudgetTestDeps()
+	monitor := d.monitor()
+
+	d.budgetRepo.On("GetBudgetsByCategoryIDs", mock.Anything, d.tx, []string{catTestID.String()}).Return([]model.Budgets{sampleBudgetModel()}, nil)
+	d.expectOwner()
+	d.budgetRepo.On("GetSpent", mock.Anything, d.tx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(money.New(0), errors.New("db error"))
+
+	err := monitor.TransactionChanged(context.Background(), d.tx, nil, sampleBudgetTransaction(money.New(100000)))
+
+	assert.Error(t, err)
+	d.assertAll(t)
+}
+
+func TestBudgetMonitor_WalletLookupErrorSkipsEvaluation(t *testing.T) {
+	d := newBudgetTestDeps()
+	monitor := d.monitor()
+
+	d.budgetRepo.On("GetBudgetsByCategoryIDs", mock.Anything, d.tx, []string{catTestID.String()}).Return([]model.Budgets{sampleBudgetModel()}, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(nil, errors.New("wallet-service unavailable"))
+
+	err := monitor.TransactionChanged(context.Background(), d.tx, nil, sampleBudgetTransaction(money.New(100000)))
+
+	// the write goes through without budget events
+	assert.NoError(t, err)
+	d.outboxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestBudgetMonitor_UserWalletsErrorSkipsEvaluation(t *testing.T) {
+	d := newBudgetTestDeps()
+	monitor := d.monitor()
+
+	wallet := sampleWalletProto(walletTestID, 1000000)
+	wallet.UserId = authzUserID
+	d.budgetRepo.On("GetBudgetsByCategoryIDs", mock.Anything, d.tx, []string{catTestID.String()}).Return([]model.Budgets{sampleBudgetModel()}, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
+	d.walletClient.On("GetUserWallets", mock.Anything, authzUserID).Return(nil, errors.New("wallet-service unavailable"))
+
+	err := monitor.TransactionChanged(context.Background(), d.tx, nil, sampleBudgetTransaction(money.New(100000)))
+
+	assert.NoError(t, err)
+	d.budgetRepo.AssertNotCalled(t, "GetSpent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 
+	"refina-transaction/interface/grpc/interceptor"
 	"refina-transaction/internal/repository"
 	"refina-transaction/internal/types/dto"
 	"refina-transaction/internal/types/model"
 	"refina-transaction/internal/types/view"
+	"refina-transaction/internal/utils/data"
 
 	"github.com/google/uuid"
 )
@@ -110,7 +112,24 @@ func (category_serv *categoriesService) GetCategoriesByType(ctx context.Context,
 	return categories, nil
 }
 
+// authorizeCategoryWrite allows only admins to change categories. They are
+// shared by every user, so a write by anyone else would rename or remove
+// categories under other users' transactions.
+func authorizeCategoryWrite(ctx context.Context) error {
+	if interceptor.UserIDFromContext(ctx) == "" {
+		return ErrUnauthenticated
+	}
+	if interceptor.UserRoleFromContext(ctx) != data.USER_ROLE_ADMIN {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
 func (category_serv *categoriesService) CreateCategory(ctx context.Context, category dto.CategoriesRequest) (dto.CategoriesResponse, error) {
+	if err := authorizeCategoryWrite(ctx); err != nil {
+		return dto.CategoriesResponse{}, err
+	}
+
 	var newCategory model.Categories
 	var parentName string
 	var err error
@@ -163,6 +182,10 @@ func (category_serv *categoriesService) CreateCategory(ctx context.Context, cate
 }
 
 func (category_serv *categoriesService) UpdateCategory(ctx context.Context, id string, category dto.CategoriesRequest) (dto.CategoriesResponse, error) {
+	if err := authorizeCategoryWrite(ctx); err != nil {
+		return dto.CategoriesResponse{}, err
+	}
+
 	existCategory, err := category_serv.categoryRepository.GetCategoryByID(ctx, nil, id)
 	if err != nil {
 		return dto.CategoriesResponse{}, err
@@ -214,6 +237,10 @@ func (category_serv *categoriesService) UpdateCategory(ctx context.Context, id s
 }
 
 func (category_serv *categoriesService) DeleteCategory(ctx context.Context, id string) (dto.CategoriesResponse, error) {
+	if err := authorizeCategoryWrite(ctx); err != nil {
+		return dto.CategoriesResponse{}, err
+	}
+
 	existCategory, err := category_serv.categoryRepository.GetCategoryByID(ctx, nil, id)
 	if err != nil {
 		return dto.CategoriesResponse{}, err
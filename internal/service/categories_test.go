@@ -6,10 +6,12 @@ import (
 	"testing"
 	"time"
 
+	"refina-transaction/interface/grpc/interceptor"
 	"refina-transaction/internal/service/mocks"
 	"refina-transaction/internal/types/dto"
 	"refina-transaction/internal/types/model"
 	"refina-transaction/internal/types/view"
+	"refina-transaction/internal/utils/data"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -79,6 +81,15 @@ func sampleChildCategory() model.Categories {
 	}
 }
 
+// categoryAdminCtx is the context of an admin, the only caller allowed to
+// change categories.
+func categoryAdminCtx() context.Context {
+	return interceptor.WithUserMetadata(context.Background(), interceptor.UserMetadata{
+		UserID: "admin-1",
+		Role:   data.USER_ROLE_ADMIN,
+	})
+}
+
 func sampleViewCategories() []view.ViewCategoriesGroupByType {
 	return []view.ViewCategoriesGroupByType{
 		{
@@ -261,7 +272,7 @@ func TestCreateCategory_SuccessWithParent(t *testing.T) {
 		return c.Name == "Parkir" && c.ParentID != nil && *c.ParentID == catParentID
 	})).Return(newChild, nil)
 
-	result, err := svc.CreateCategory(context.Background(), req)
+	result, err := svc.CreateCategory(categoryAdminCtx(), req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "Transportasi", result.GroupName)
@@ -284,7 +295,7 @@ func TestCreateCategory_SuccessWithoutParent(t *testing.T) {
 		return c.Name == "Transportasi" && c.ParentID == nil
 	})).Return(newParent, nil)
 
-	result, err := svc.CreateCategory(context.Background(), req)
+	result, err := svc.CreateCategory(categoryAdminCtx(), req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "Transportasi", result.GroupName)
@@ -304,7 +315,7 @@ func TestCreateCategory_ParentNotFound(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, "non-existent-id").
 		Return(model.Categories{}, errors.New("record not found"))
 
-	result, err := svc.CreateCategory(context.Background(), req)
+	result, err := svc.CreateCategory(categoryAdminCtx(), req)
 
 	assert.Error(t, err)
 	assert.Empty(t, result.GroupName)
@@ -323,7 +334,7 @@ func TestCreateCategory_RepositoryCreateError(t *testing.T) {
 	d.categoryRepo.On("CreateCategory", mock.Anything, nil, mock.Anything).
 		Return(model.Categories{}, errors.New("db error"))
 
-	result, err := svc.CreateCategory(context.Background(), req)
+	result, err := svc.CreateCategory(categoryAdminCtx(), req)
 
 	assert.Error(t, err)
 	assert.Empty(t, result.GroupName)
@@ -349,7 +360,7 @@ func TestUpdateCategory_SuccessChangeName(t *testing.T) {
 		return c.Name == "Tol & Parkir"
 	})).Return(updated, nil)
 
-	result, err := svc.UpdateCategory(context.Background(), catChildID.String(), req)
+	result, err := svc.UpdateCategory(categoryAdminCtx(), catChildID.String(), req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "Transportasi", result.GroupName)
@@ -380,7 +391,7 @@ func TestUpdateCategory_SuccessChangeParent(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, newParentID.String()).Return(newParent, nil)
 	d.categoryRepo.On("UpdateCategory", mock.Anything, nil, mock.Anything).Return(updatedChild, nil)
 
-	result, err := svc.UpdateCategory(context.Background(), catChildID.String(), req)
+	result, err := svc.UpdateCategory(categoryAdminCtx(), catChildID.String(), req)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, result.GroupName)
@@ -394,7 +405,7 @@ func TestUpdateCategory_NotFound(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, "bad-id").
 		Return(model.Categories{}, errors.New("record not found"))
 
-	result, err := svc.UpdateCategory(context.Background(), "bad-id", dto.CategoriesRequest{Name: "X"})
+	result, err := svc.UpdateCategory(categoryAdminCtx(), "bad-id", dto.CategoriesRequest{Name: "X"})
 
 	assert.Error(t, err)
 	assert.Empty(t, result.GroupName)
@@ -412,7 +423,7 @@ func TestUpdateCategory_NewParentNotFound(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, "nonexistent-parent").
 		Return(model.Categories{}, errors.New("record not found"))
 
-	result, err := svc.UpdateCategory(context.Background(), catChildID.String(), req)
+	result, err := svc.UpdateCategory(categoryAdminCtx(), catChildID.String(), req)
 
 	assert.Error(t, err)
 	assert.Empty(t, result.GroupName)
@@ -430,7 +441,7 @@ func TestUpdateCategory_InvalidNewParentUUID(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, catChildID.String()).Return(existing, nil)
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, "not-a-uuid").Return(fakeParent, nil)
 
-	result, err := svc.UpdateCategory(context.Background(), catChildID.String(), req)
+	result, err := svc.UpdateCategory(categoryAdminCtx(), catChildID.String(), req)
 
 	assert.Error(t, err)
 	assert.Empty(t, result.GroupName)
@@ -448,7 +459,7 @@ func TestUpdateCategory_RepositoryUpdateError(t *testing.T) {
 	d.categoryRepo.On("UpdateCategory", mock.Anything, nil, mock.Anything).
 		Return(model.Categories{}, errors.New("db error"))
 
-	result, err := svc.UpdateCategory(context.Background(), catChildID.String(), req)
+	result, err := svc.UpdateCategory(categoryAdminCtx(), catChildID.String(), req)
 
 	assert.Error(t, err)
 	assert.Empty(t, result.GroupName)
@@ -467,7 +478,7 @@ func TestDeleteCategory_SuccessChild(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, catChildID.String()).Return(child, nil)
 	d.categoryRepo.On("DeleteCategory", mock.Anything, nil, child).Return(child, nil)
 
-	result, err := svc.DeleteCategory(context.Background(), catChildID.String())
+	result, err := svc.DeleteCategory(categoryAdminCtx(), catChildID.String())
 
 	assert.NoError(t, err)
 	assert.Equal(t, "Transportasi", result.GroupName)
@@ -483,7 +494,7 @@ func TestDeleteCategory_SuccessParent(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, catParentID.String()).Return(parent, nil)
 	d.categoryRepo.On("DeleteCategory", mock.Anything, nil, parent).Return(parent, nil)
 
-	result, err := svc.DeleteCategory(context.Background(), catParentID.String())
+	result, err := svc.DeleteCategory(categoryAdminCtx(), catParentID.String())
 
 	assert.NoError(t, err)
 	assert.Equal(t, "Transportasi", result.GroupName)
@@ -497,7 +508,7 @@ func TestDeleteCategory_NotFound(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, "bad-id").
 		Return(model.Categories{}, errors.New("record not found"))
 
-	result, err := svc.DeleteCategory(context.Background(), "bad-id")
+	result, err := svc.DeleteCategory(categoryAdminCtx(), "bad-id")
 
 	assert.Error(t, err)
 	assert.Empty(t, result.GroupName)
@@ -513,9 +524,45 @@ func TestDeleteCategory_RepositoryDeleteError(t *testing.T) {
 	d.categoryRepo.On("DeleteCategory", mock.Anything, nil, child).
 		Return(model.Categories{}, errors.New("db error"))
 
-	result, err := svc.DeleteCategory(context.Background(), catChildID.String())
+	result, err := svc.DeleteCategory(categoryAdminCtx(), catChildID.String())
 
 	assert.Error(t, err)
 	assert.Empty(t, result.GroupName)
 	d.assertAll(t)
 }
+
+// =====================================================================
+// Category write authorization
+// =====================================================================
+
+func TestCategoryWrites_RequireAuthentication(t *testing.T) {
+	d := newCategoryTestDeps()
+	svc := d.service()
+	ctx := context.Background()
+
+	_, createErr := svc.CreateCategory(ctx, dto.CategoriesRequest{Name: "Bensin"})
+	_, updateErr := svc.UpdateCategory(ctx, catChildID.String(), dto.CategoriesRequest{Name: "Bensin"})
+	_, deleteErr := svc.DeleteCategory(ctx, catChildID.String())
+
+	assert.ErrorIs(t, createErr, ErrUnauthenticated)
+	assert.ErrorIs(t, updateErr, ErrUnauthenticated)
+	assert.ErrorIs(t, deleteErr, ErrUnauthenticated)
+	d.assertAll(t)
+}
+
+func TestCategoryWrites_DeniedForNonAdmin(t *testing.T) {
+	d := newCategoryTestDeps()
+	svc := d.service()
+	ctx := interceptor.WithUserMetadata(context.Background(), interceptor.UserMetadata{UserID: "user-1"})
+
+	_, createErr := svc.CreateCategory(ctx, dto.CategoriesRequest{Name: "Bensin"})
+	_, updateErr := svc.UpdateCategory(ctx, catChildID.String(), dto.CategoriesRequest{Name: "Bensin"})
+	_, deleteErr := svc.DeleteCategory(ctx, catChildID.String())
+
+	assert.ErrorIs(t, createErr, ErrPermissionDenied)
+	assert.ErrorIs(t, updateErr, ErrPermissionDenied)
+	assert.ErrorIs(t, deleteErr, ErrPermissionDenied)
+	// the shared category tree is never touched
+	d.categoryRepo.AssertNotCalled(t, "GetCategoryByID", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
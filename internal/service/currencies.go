@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
+	"gorm.io/gorm"
+)
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type CurrenciesService interface {
+	GetFXRates(ctx context.Context, baseCurrency, quoteCurrency string) ([]dto.FXRatesResponse, error)
+	UpsertFXRates(ctx context.Context, rates []dto.FXRatesRequest) ([]dto.FXRatesResponse, error)
+	// ImportFXRates stores the rates of a CSV file with a date,base,quote,rate header.
+	ImportFXRates(ctx context.Context, content string) ([]dto.FXRatesResponse, error)
+	GetWalletCurrency(ctx context.Context, walletID string) (dto.WalletCurrencyResponse, error)
+	SetWalletCurrency(ctx context.Context, walletID string, request dto.WalletCurrencyRequest) (dto.WalletCurrencyResponse, error)
+}
+
+type currenciesService struct {
+	currencyRepo repository.CurrenciesRepository
+	converter    *currencyConverter
+}
+
+func NewCurrenciesService(currencyRepo repository.CurrenciesRepository) CurrenciesService {
+	return &currenciesService{
+		currencyRepo: currencyRepo,
+		converter:    newCurrencyConverter(currencyRepo),
+	}
+}
+
+func (currency_serv *currenciesService) GetFXRates(ctx context.Context, baseCurrency, quoteCurrency string) ([]dto.FXRatesResponse, error) {
+	rates, err := currency_serv.currencyRepo.GetFXRates(ctx, nil, strings.ToUpper(baseCurrency), strings.ToUpper(quoteCurrency))
+	if err != nil {
+		return nil, fmt.Errorf("get fx rates: %w", err)
+	}
+
+	return toFXRatesResponses(rates), nil
+}
+
+func (currency_serv *currenciesService) UpsertFXRates(ctx context.Context, requests []dto.FXRatesRequest) ([]dto.FXRatesResponse, error) {
+	if err := authorizeFXRateWrite(ctx); err != nil {
+		return nil, err
+	}
+
+	rates := make([]model.FXRates, 0, len(requests))
+	for i, request := range requests {
+		rate, err := newFXRate(request, model.FXRateManual)
+		if err != nil {
+			return nil, fmt.Errorf("%w [line=%d]", err, i+1)
+		}
+		rates = append(rates, rate)
+	}
+
+	return currency_serv.storeFXRates(ctx, rates)
+}
+
+func (currency_serv *currenciesService) ImportFXRates(ctx context.Context, content string) ([]dto.FXRatesResponse, error) {
+	if err := authorizeFXRateWrite(ctx); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid fx rate file: read csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{"date", "base", "quote", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("invalid fx rate file: csv column %q not found", name)
+		}
+	}
+
+	var rates []model.FXRates
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid fx rate file: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(fields[columns["date"]]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid fx rate date [line=%d, date=%s]", line, fields[columns["date"]])
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(fields[columns["rate"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fx rate [line=%d, rate=%s]", line, fields[columns["rate"]])
+		}
+
+		rate, err := newFXRate(dto.FXRatesRequest{
+			BaseCurrency:  fields[columns["base"]],
+			QuoteCurrency: fields[columns["quote"]],
+			Rate:          value,
+			EffectiveDate: date,
+		}, model.FXRateImport)
+		if err != nil {
+			return nil, fmt.Errorf("%w [line=%d]", err, line)
+		}
+		rates = append(rates, rate)
+	}
+
+	return currency_serv.storeFXRates(ctx, rates)
+}
+
+func (currency_serv *currenciesService) storeFXRates(ctx context.Context, rates []model.FXRates) ([]dto.FXRatesResponse, error) {
+	if len(rates) == 0 {
+		return nil, errors.New("invalid fx rates: no rates given")
+	}
+
+	stored, err := currency_serv.currencyRepo.UpsertFXRates(ctx, nil, rates)
+	if err != nil {
+		return nil, fmt.Errorf("store fx rates: %w", err)
+	}
+
+	return toFXRatesResponses(stored), nil
+}
+
+func (currency_serv *currenciesService) GetWalletCurrency(ctx context.Context, walletID string) (dto.WalletCurrencyResponse, error) {
+	currency, err := currency_serv.converter.walletCurrency(ctx, nil, walletID)
+	if err != nil {
+		return dto.WalletCurrencyResponse{}, err
+	}
+
+	return dto.WalletCurrencyResponse{WalletID: walletID, Currency: currency}, nil
+}
+
+func (currency_serv *currenciesService) SetWalletCurrency(ctx context.Context, walletID string, request dto.WalletCurrencyRequest) (dto.WalletCurrencyResponse, error) {
+	currency, err := normalizeCurrency(request.Currency)
+	if err != nil {
+		return dto.WalletCurrencyResponse{}, err
+	}
+
+	WalletID, err := helper.ParseUUID(walletID)
+	if err != nil {
+		return dto.WalletCurrencyResponse{}, fmt.Errorf("invalid wallet id [id=%s]: %w", walletID, err)
+	}
+
+	// Amounts are stored in the wallet currency, so it cannot change under existing transactions
+	count, err := currency_serv.currencyRepo.CountWalletTransactionsNotIn(ctx, nil, walletID, currency)
+	if err != nil {
+		return dto.WalletCurrencyResponse{}, fmt.Errorf("set wallet currency [wallet_id=%s]: %w", walletID, err)
+	}
+	if count > 0 {
+		return dto.WalletCurrencyResponse{}, fmt.Errorf("invalid wallet currency: wallet has %d transactions in another currency [wallet_id=%s]", count, walletID)
+	}
+
+	walletCurrency, err := currency_serv.currencyRepo.SetWalletCurrency(ctx, nil, model.WalletCurrencies{
+		WalletID: WalletID,
+		Currency: currency,
+	})
+	if err != nil {
+		return dto.WalletCurrencyResponse{}, fmt.Errorf("set wallet currency [wallet_id=%s]: %w", walletID, err)
+	}
+
+	return dto.WalletCurrencyResponse{WalletID: walletCurrency.WalletID.String(), Currency: walletCurrency.Currency}, nil
+}
+
+// authorizeFXRateWrite allows only admins to change exchange rates, which
+// every user's conversions and reports rely on.
+func authorizeFXRateWrite(ctx context.Context) error {
+	if interceptor.UserIDFromContext(ctx) == "" {
+		return ErrUnauthenticated
+	}
+	if interceptor.UserRoleFromContext(ctx) != data.USER_ROLE_ADMIN {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+func newFXRate(request dto.FXRatesRequest, source model.FXRateSource) (model.FXRates, error) {
+	base, err := normalizeCurrency(request.BaseCurrency)
+	if err != nil {
+		return model.FXRates{}, err
+	}
+	quote, err := normalizeCurrency(request.QuoteCurrency)
+	if err != nil {
+		return model.FXRates{}, err
+	}
+	if base == quote {
+		return model.FXRates{}, fmt.Errorf("invalid fx rate: %s cannot be quoted in itself", base)
+	}
+	if request.Rate <= 0 || math.IsInf(request.Rate, 0) || math.IsNaN(request.Rate) {
+		return model.FXRates{}, fmt.Errorf("invalid fx rate [rate=%v]", request.Rate)
+	}
+	if request.EffectiveDate.IsZero() {
+		return model.FXRates{}, errors.New("invalid fx rate: effective_date is required")
+	}
+
+	return model.FXRates{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          request.Rate,
+		EffectiveDate: time.Date(request.EffectiveDate.Year(), request.EffectiveDate.Month(), request.EffectiveDate.Day(), 0, 0, 0, 0, time.UTC),
+		Source:        source,
+	}, nil
+}
+
+func toFXRatesResponses(rates []model.FXRates) []dto.FXRatesResponse {
+	responses := make([]dto.FXRatesResponse, 0, len(rates))
+	for _, rate := range rates {
+		responses = append(responses, dto.FXRatesResponse{
+			ID:            rate.ID.String(),
+			BaseCurrency:  rate.BaseCurrency,
+			QuoteCurrency: rate.QuoteCurrency,
+			Rate:          rate.Rate,
+			EffectiveDate: rate.EffectiveDate,
+			Source:        string(rate.Source),
+		})
+	}
+	return responses
+}
+
+// normalizeCurrency upper-cases an ISO 4217 code and rejects anything else.
+func normalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !currencyCodePattern.MatchString(code) {
+		return "", fmt.Errorf("invalid currency [currency=%s]", code)
+	}
+	return code, nil
+}
+
+// currencyConversion is how an entered amount is booked on a wallet. The
+// original fields are nil when the amount was already in the wallet currency.
+type currencyConversion struct {
+	Currency         string
+	Amount           money.Amount
+	OriginalAmount   *money.Amount
+	OriginalCurrency *string
+	Rate             *float64
+}
+
+func (conversion currencyConversion) converted() bool {
+	return conversion.Rate != nil
+}
+
+// rate is the rate the amount was converted at, 1 when it was not.
+func (conversion currencyConversion) rate() float64 {
+	if conversion.Rate == nil {
+		return 1
+	}
+	return *conversion.Rate
+}
+
+func (conversion currencyConversion) apply(transaction *model.Transactions) {
+	transaction.Currency = conversion.Currency
+	transaction.Amount = conversion.Amount
+	transaction.OriginalAmount = conversion.OriginalAmount
+	transaction.OriginalCurrency = conversion.OriginalCurrency
+	transaction.FxRate = conversion.Rate
+}
+
+// currencyConverter resolves wallet currencies and converts amounts with the
+// stored exchange rates.
+type currencyConverter struct {
+	currencyRepo repository.CurrenciesRepository
+}
+
+func newCurrencyConverter(currencyRepo repository.CurrenciesRepository) *currencyConverter {
+	return &currencyConverter{currencyRepo: currencyRepo}
+}
+
+// walletCurrency returns the currency of the wallet, or the default currency
+// when none was set.
+func (c *currencyConverter) walletCurrency(ctx context.Context, tx repository.Transaction, walletID string) (string, error) {
+	currency, err := c.currencyRepo.GetWalletCurrency(ctx, tx, walletID)
+	if err != nil {
+		return "", fmt.Errorf("get wallet currency [wallet_id=%s]: %w", walletID, err)
+	}
+	if currency == "" {
+		return data.DEFAULT_CURRENCY, nil
+	}
+	return currency, nil
+}
+
+// rate returns what one from is worth in to on the given day, using the
+// inverse of the to/from rate when only that one is stored.
+func (c *currencyConverter) rate(ctx context.Context, tx repository.Transaction, from, to string, on time.Time) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	rate, err := c.currencyRepo.GetFXRate(ctx, tx, from, to, on)
+	if err == nil {
+		return rate.Rate, nil
+	}
+	// ! Only a missing pair falls back; a failed lookup must not pass for one
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("get fx rate [from=%s, to=%s]: %w", from, to, err)
+	}
+
+	rate, err = c.currencyRepo.GetFXRate(ctx, tx, to, from, on)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("fx rate not found [from=%s, to=%s, date=%s]", from, to, on.Format("2006-01-02"))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get fx rate [from=%s, to=%s]: %w", to, from, err)
+	}
+	return 1 / rate.Rate, nil
+}
+
+// forWallet books amount, entered in currency (the wallet's when empty), on
+// the wallet at the rate of the transaction date.
+func (c *currencyConverter) forWallet(ctx context.Context, tx repository.Transaction, walletID, currency string, amount money.Amount, on time.Time) (currencyConversion, error) {
+	walletCurrency, err := c.walletCurrency(ctx, tx, walletID)
+	if err != nil {
+		return currencyConversion{}, err
+	}
+	if currency == "" {
+		return currencyConversion{Currency: walletCurrency, Amount: amount}, nil
+	}
+
+	currency, err = normalizeCurrency(currency)
+	if err != nil {
+		return currencyConversion{}, err
+	}
+	if currency == walletCurrency {
+		return currencyConversion{Currency: walletCurrency, Amount: amount}, nil
+	}
+
+	if on.IsZero() {
+		on = time.Now()
+	}
+	rate, err := c.rate(ctx, tx, currency, walletCurrency, on)
+	if err != nil {
+		return currencyConversion{}, err
+	}
+	converted, err := amount.MulRate(rate)
+	if err != nil {
+		return currencyConversion{}, fmt.Errorf("invalid amount: converted amount [amount=%s, from=%s, to=%s, rate=%g]: %w", amount, currency, walletCurrency, rate, err)
+	}
+
+	return currencyConversion{
+		Currency:         walletCurrency,
+		Amount:           converted,
+		OriginalAmount:   &amount,
+		OriginalCurrency: &currency,
+		Rate:             &rate,
+	}, nil
+}
@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type currencyTestDeps struct {
+	currencyRepo *mocks.MockCurrenciesRepository
+}
+
+func newCurrencyTestDeps() *currencyTestDeps {
+	return &currencyTestDeps{currencyRepo: new(mocks.MockCurrenciesRepository)}
+}
+
+func (d *currencyTestDeps) service() CurrenciesService {
+	return NewCurrenciesService(d.currencyRepo)
+}
+
+func userCtx() context.Context {
+	return interceptor.WithUserMetadata(context.Background(), interceptor.UserMetadata{UserID: "user-1"})
+}
+
+// newForeignCurrencyTransactionDeps keeps the wallet in IDR and drops the
+// default currency expectation so each test states the rates it needs.
+func newForeignCurrencyTransactionDeps() *transactionTestDeps {
+	d := newTransactionTestDeps()
+	d.currencyRepo = new(mocks.MockCurrenciesRepository)
+	d.currencyRepo.On("GetWalletCurrency", mock.Anything, mock.Anything, walletTestID.String()).Return("IDR", nil)
+	return d
+}
+
+// =====================================================================
+// Conversion on CreateTransaction
+// =====================================================================
+
+func TestCreateTransaction_ConvertsForeignCurrency(t *testing.T) {
+	d := newForeignCurrencyTransactionDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	req := sampleTransactionRequest()
+	req.Amount = money.MustParse("10.5")
+	req.Currency = "usd"
+
+	d.currencyRepo.On("GetFXRate", mock.Anything, mock.Anything, "USD", "IDR", txnFixTime).
+		Return(model.FXRates{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: 16000.33}, nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 500000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.MustParse("-168003.47"), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 331996.53), nil)
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(txn model.Transactions) bool {
+		return txn.Currency == "IDR" && txn.Amount == money.MustParse("168003.47") &&
+			txn.OriginalAmount != nil && *txn.OriginalAmount == money.MustParse("10.5") &&
+			txn.OriginalCurrency != nil && *txn.OriginalCurrency == "USD" &&
+			txn.FxRate != nil && *txn.FxRate == 16000.33
+	})).Return(sampleTransactionModel(), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.CreateTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_ConvertsWithInverseRate(t *testing.T) {
+	d := newForeignCurrencyTransactionDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	req := sampleTransactionRequest()
+	req.Amount = money.New(10)
+	req.Currency = "USD"
+
+	d.currencyRepo.On("GetFXRate", mock.Anything, mock.Anything, "USD", "IDR", txnFixTime).
+		Return(model.FXRates{}, gorm.ErrRecordNotFound)
+	d.currencyRepo.On("GetFXRate", mock.Anything, mock.Anything, "IDR", "USD", txnFixTime).
+		Return(model.FXRates{BaseCurrency: "IDR", QuoteCurrency: "USD", Rate: 0.0000625}, nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 500000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-160000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 340000), nil)
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(txn model.Transactions) bool {
+		return txn.Amount == money.New(160000) && txn.FxRate != nil && *txn.FxRate == 16000
+	})).Return(sampleTransactionModel(), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.CreateTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_FXRateLookupErrorIsNotAMissingRate(t *testing.T) {
+	d := newForeignCurrencyTransactionDeps()
+	svc := d.service()
+
+	req := sampleTransactionRequest()
+	req.Currency = "USD"
+
+	d.currencyRepo.On("GetFXRate", mock.Anything, mock.Anything, "USD", "IDR", txnFixTime).
+		Return(model.FXRates{}, errors.New("connection reset by peer"))
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+
+	_, err := svc.CreateTransaction(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "connection reset by peer")
+	d.currencyRepo.AssertNotCalled(t, "GetFXRate", mock.Anything, mock.Anything, "IDR", "USD", mock.Anything)
+	d.walletClient.AssertNotCalled(t, "AdjustBalance")
+	d.transactionRepo.AssertNotCalled(t, "CreateTransaction")
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_InverseFXRateLookupError(t *testing.T) {
+	d := newForeignCurrencyTransactionDeps()
+	svc := d.service()
+
+	req := sampleTransactionRequest()
+	req.Currency = "USD"
+
+	d.currencyRepo.On("GetFXRate", mock.Anything, mock.Anything, "USD", "IDR", txnFixTime).
+		Return(model.FXRates{}, gorm.ErrRecordNotFound)
+	d.currencyRepo.On("GetFXRate", mock.Anything, mock.Anything, "IDR", "USD", txnFixTime).
+		Return(model.FXRates{}, errors.New("connection reset by peer"))
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+
+	_, err := svc.CreateTransaction(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "connection reset by peer")
+	assert.NotContains(t, err.Error(), "fx rate not found")
+	d.transactionRepo.AssertNotCalled(t, "CreateTransaction")
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_MissingFXRate(t *testing.T) {
+	d := newForeignCurrencyTransactionDeps()
+	svc := d.service()
+
+	req := sampleTransactionRequest()
+	req.Currency = "EUR"
+
+	d.currencyRepo.On("GetFXRate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(model.FXRates{}, gorm.ErrRecordNotFound)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+
+	_, err := svc.CreateTransaction(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "fx rate not found [from=EUR, to=IDR")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance")
+	d.transactionRepo.AssertNotCalled(t, "CreateTransaction")
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_InvalidCurrency(t *testing.T) {
+	d := newForeignCurrencyTransactionDeps()
+	svc := d.service()
+
+	req := sampleTransactionRequest()
+	req.Currency = "rupiah"
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+
+	_, err := svc.CreateTransaction(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid currency")
+	d.assertAll(t)
+}
+
+// =====================================================================
+// Cross-currency FundTransfer
+// =====================================================================
+
+// sampleCrossCurrencyTransfer moves 1,600,000 IDR into a USD wallet.
+func sampleCrossCurrencyTransfer(toAmount money.Amount, rate float64) (*transactionTestDeps, dto.FundTransferRequest) {
+	d := newForeignCurrencyTransactionDeps()
+	d.currencyRepo.On("GetWalletCurrency", mock.Anything, mock.Anything, wallet2ID.String()).Return("USD", nil)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 5000000), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, wallet2ID.String()).Return(sampleWalletProto(wallet2ID, 50), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	return d, dto.FundTransferRequest{
+		CashInCategoryID:  cashInCatID.String(),
+		CashOutCategoryID: cashOutCatID.String(),
+		FromWalletID:      walletTestID.String(),
+		ToWalletID:        wallet2ID.String(),
+		Amount:            money.New(1600000),
+		ToAmount:          toAmount,
+		ExchangeRate:      rate,
+		Date:              txnFixTime,
+	}
+}
+
+func TestFundTransfer_CreditsConvertedAmount(t *testing.T) {
+	d, req := sampleCrossCurrencyTransfer(money.MustParse("99.5"), 0)
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-1600000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 3400000), nil).Once()
+	d.walletClient.On("AdjustBalance", mock.Anything, wallet2ID.String(), money.MustParse("99.5"), mock.Anything).
+		Return(sampleWalletProto(wallet2ID, 149.5), nil).Once()
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(txn model.Transactions) bool {
+		return txn.CategoryID == cashOutCatID && txn.Currency == "IDR" && txn.Amount == money.New(1600000) && txn.FxRate == nil
+	})).Return(model.Transactions{Base: model.Base{ID: cashOutLegID}}, nil)
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(txn model.Transactions) bool {
+		return txn.CategoryID == cashInCatID && txn.Currency == "USD" && txn.Amount == money.MustParse("99.5") &&
+			txn.OriginalAmount != nil && *txn.OriginalAmount == money.New(1600000) &&
+			txn.OriginalCurrency != nil && *txn.OriginalCurrency == "IDR" &&
+			txn.FxRate != nil && *txn.FxRate == 0.0000621875
+	})).Return(model.Transactions{Base: model.Base{ID: cashInLegID}}, nil)
+	d.transactionRepo.On("CreateTransfer", mock.Anything, d.tx, mock.MatchedBy(func(tr model.Transfers) bool {
+		return tr.Amount == money.New(1600000) && tr.ToAmount == money.MustParse("99.5") && tr.ExchangeRate == 0.0000621875
+	})).Return(model.Transfers{}, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil).Twice()
+	d.tx.On("Commit").Return(nil)
+
+	result, err := svc.FundTransfer(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(1600000), result.Amount)
+	assert.Equal(t, money.MustParse("99.5"), result.ToAmount)
+	assert.Equal(t, 0.0000621875, result.ExchangeRate)
+	d.assertAll(t)
+}
+
+func TestFundTransfer_CrossCurrencyNeedsAmountOrRate(t *testing.T) {
+	d, req := sampleCrossCurrencyTransfer(money.Zero, 0)
+	svc := d.service()
+
+	_, err := svc.FundTransfer(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "to_amount or exchange_rate is required")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestFundTransfer_RejectsDisagreeingAmountAndRate(t *testing.T) {
+	// 1,600,000 IDR at 0.0000625 is 100 USD, not 99.5
+	d, req := sampleCrossCurrencyTransfer(money.MustParse("99.5"), 0.0000625)
+	svc := d.service()
+
+	_, err := svc.FundTransfer(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "to_amount does not match amount at exchange_rate")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestTransferConversion(t *testing.T) {
+	tests := []struct {
+		name     string
+		toAmount money.Amount
+		rate     float64
+		from, to string
+		want     money.Amount
+		wantRate float64
+		wantErr  string
+	}{
+		{name: "same currency", from: "IDR", to: "IDR", want: money.New(1600000), wantRate: 1},
+		{name: "same currency with another amount", toAmount: money.New(100), from: "IDR", to: "IDR", wantErr: "to_amount must equal amount"},
+		{name: "rate only", rate: 0.0000625, from: "IDR", to: "USD", want: money.New(100), wantRate: 0.0000625},
+		{name: "amount only", toAmount: money.New(100), from: "IDR", to: "USD", want: money.New(100), wantRate: 0.0000625},
+		{name: "both within a cent", toAmount: money.MustParse("100.01"), rate: 0.0000625, from: "IDR", to: "USD", want: money.MustParse("100.01"), wantRate: 0.0000625},
+		{name: "negative rate", rate: -1, from: "IDR", to: "USD", wantErr: "invalid fund transfer amount"},
+		{name: "rate rounding to nothing", rate: 1e-12, from: "IDR", to: "USD", wantErr: "invalid fund transfer amount"},
+		{name: "rate past the amount range", rate: 1e15, from: "USD", to: "IDR", wantErr: "out of range"},
+		{name: "rate past the amount range with to_amount", toAmount: money.New(100), rate: 1e15, from: "USD", to: "IDR", wantErr: "out of range"},
+		{name: "infinite rate", rate: math.Inf(1), from: "USD", to: "IDR", wantErr: "invalid rate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conversion, err := transferConversion(money.New(1600000), tt.toAmount, tt.rate, tt.from, tt.to)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.to, conversion.Currency)
+			assert.Equal(t, tt.want, conversion.Amount)
+			assert.Equal(t, tt.wantRate, conversion.rate())
+		})
+	}
+}
+
+// =====================================================================
+// FX rates
+// =====================================================================
+
+func TestUpsertFXRates_RequiresAdmin(t *testing.T) {
+	d := newCurrencyTestDeps()
+	svc := d.service()
+
+	_, err := svc.UpsertFXRates(userCtx(), []dto.FXRatesRequest{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: 16000, EffectiveDate: txnFixTime},
+	})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.currencyRepo.AssertNotCalled(t, "UpsertFXRates")
+}
+
+func TestUpsertFXRates_Success(t *testing.T) {
+	d := newCurrencyTestDeps()
+	svc := d.service()
+
+	effective := time.Date(2025, 6, 1, 15, 30, 0, 0, time.UTC)
+	d.currencyRepo.On("UpsertFXRates", mock.Anything, mock.Anything, []model.FXRates{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: 16000, EffectiveDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Source: model.FXRateManual},
+	}).Return([]model.FXRates{
+		{Base: model.Base{ID: uuid.New()}, BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: 16000, EffectiveDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Source: model.FXRateManual},
+	}, nil)
+
+	result, err := svc.UpsertFXRates(categoryAdminCtx(), []dto.FXRatesRequest{
+		{BaseCurrency: " usd", QuoteCurrency: "idr", Rate: 16000, EffectiveDate: effective},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "manual", result[0].Source)
+	d.currencyRepo.AssertExpectations(t)
+}
+
+func TestUpsertFXRates_InvalidRate(t *testing.T) {
+	cases := map[string]dto.FXRatesRequest{
+		"same currency": {BaseCurrency: "USD", QuoteCurrency: "USD", Rate: 1, EffectiveDate: txnFixTime},
+		"zero rate":     {BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: 0, EffectiveDate: txnFixTime},
+		"no date":       {BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: 16000},
+		"bad code":      {BaseCurrency: "US", QuoteCurrency: "IDR", Rate: 16000, EffectiveDate: txnFixTime},
+	}
+
+	for name, request := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := newCurrencyTestDeps()
+
+			_, err := d.service().UpsertFXRates(categoryAdminCtx(), []dto.FXRatesRequest{request})
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid")
+			d.currencyRepo.AssertNotCalled(t, "UpsertFXRates")
+		})
+	}
+}
+
+func TestImportFXRates_ParsesCSV(t *testing.T) {
+	d := newCurrencyTestDeps()
+	svc := d.service()
+
+	content := "\ufeffDate,Base,Quote,Rate\n2025-06-01,USD,IDR,16250.5\n2025-06-02,eur,idr,17600\n"
+	d.currencyRepo.On("UpsertFXRates", mock.Anything, mock.Anything, mock.MatchedBy(func(rates []model.FXRates) bool {
+		return len(rates) == 2 &&
+			rates[0].BaseCurrency == "USD" && rates[0].Rate == 16250.5 && rates[0].Source == model.FXRateImport &&
+			rates[1].BaseCurrency == "EUR" && rates[1].EffectiveDate.Equal(time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC))
+	})).Return([]model.FXRates{{}, {}}, nil)
+
+	result, err := svc.ImportFXRates(categoryAdminCtx(), content)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	d.currencyRepo.AssertExpectations(t)
+}
+
+func TestImportFXRates_InvalidLine(t *testing.T) {
+	d := newCurrencyTestDeps()
+	svc := d.service()
+
+	_, err := svc.ImportFXRates(categoryAdminCtx(), "date,base,quote,rate\n2025-06-01,USD,IDR,16250\n01/06/2025,EUR,IDR,17600\n")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid fx rate date [line=3")
+	d.currencyRepo.AssertNotCalled(t, "UpsertFXRates")
+}
+
+// =====================================================================
+// Wallet currency
+// =====================================================================
+
+func TestGetWalletCurrency_DefaultsWhenUnset(t *testing.T) {
+	d := newCurrencyTestDeps()
+	d.currencyRepo.On("GetWalletCurrency", mock.Anything, mock.Anything, walletTestID.String()).Return("", nil)
+
+	result, err := d.service().GetWalletCurrency(context.Background(), walletTestID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "IDR", result.Currency)
+	d.currencyRepo.AssertExpectations(t)
+}
+
+func TestSetWalletCurrency_RejectsWalletWithOtherCurrencies(t *testing.T) {
+	d := newCurrencyTestDeps()
+	d.currencyRepo.On("CountWalletTransactionsNotIn", mock.Anything, mock.Anything, walletTestID.String(), "USD").Return(int64(4), nil)
+
+	_, err := d.service().SetWalletCurrency(context.Background(), walletTestID.String(), dto.WalletCurrencyRequest{Currency: "usd"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid wallet currency")
+	d.currencyRepo.AssertNotCalled(t, "SetWalletCurrency")
+	d.currencyRepo.AssertExpectations(t)
+}
+
+func TestSetWalletCurrency_Success(t *testing.T) {
+	d := newCurrencyTestDeps()
+	d.currencyRepo.On("CountWalletTransactionsNotIn", mock.Anything, mock.Anything, walletTestID.String(), "USD").Return(int64(0), nil)
+	d.currencyRepo.On("SetWalletCurrency", mock.Anything, mock.Anything, model.WalletCurrencies{WalletID: walletTestID, Currency: "USD"}).
+		Return(model.WalletCurrencies{WalletID: walletTestID, Currency: "USD"}, nil)
+
+	result, err := d.service().SetWalletCurrency(context.Background(), walletTestID.String(), dto.WalletCurrencyRequest{Currency: "usd"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, dto.WalletCurrencyResponse{WalletID: walletTestID.String(), Currency: "USD"}, result)
+	d.currencyRepo.AssertExpectations(t)
+}
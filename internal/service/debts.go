@@ -0,0 +1,533 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+)
+
+// DebtsService tracks money lent to (receivable) or borrowed from (payable)
+// a counterparty. The principal and every repayment are booked as
+// transactions of the debt, so the outstanding balance is what the principal
+// leaves after the repayments.
+type DebtsService interface {
+	// GetDebts lists the debts of the wallets, most recent first, optionally
+	// only the open or the settled ones.
+	GetDebts(ctx context.Context, walletIDs []string, status string) ([]dto.DebtsResponse, error)
+	// GetOverdueDebts lists the open debts of the wallets past their due
+	// date, longest overdue first.
+	GetOverdueDebts(ctx context.Context, walletIDs []string) ([]dto.DebtsResponse, error)
+	GetDebtByID(ctx context.Context, id string) (dto.DebtsResponse, error)
+	// CreateDebt records a debt and books its principal on the wallet.
+	CreateDebt(ctx context.Context, request dto.DebtsRequest) (dto.DebtsResponse, error)
+	UpdateDebt(ctx context.Context, id string, request dto.UpdateDebtRequest) (dto.DebtsResponse, error)
+	// RepayDebt books a repayment of a debt and settles the debt, emitting
+	// data.OUTBOX_EVENT_DEBT_SETTLED, once nothing is outstanding.
+	RepayDebt(ctx context.Context, id string, request dto.DebtRepaymentRequest) (dto.DebtsResponse, error)
+	// DeleteDebt deletes a debt with its principal and repayments and
+	// reverses their effect on the wallets.
+	DeleteDebt(ctx context.Context, id string) (dto.DebtsResponse, error)
+}
+
+type debtsService struct {
+	txManager        repository.TxManager
+	debtRepo         repository.DebtsRepository
+	transactionRepo  repository.TransactionsRepository
+	categoryRepo     repository.CategoriesRepository
+	outboxRepository repository.OutboxRepository
+	saga             *SagaOrchestrator
+	ledger           *walletLedger
+	currencies       *currencyConverter
+	history          *historyRecorder
+}
+
+func NewDebtsService(txManager repository.TxManager, debtRepo repository.DebtsRepository, transactionRepo repository.TransactionsRepository, walletClient client.WalletClient, categoryRepo repository.CategoriesRepository, outboxRepository repository.OutboxRepository, sagaRepo repository.SagaLogRepository, currencyRepo repository.CurrenciesRepository, historyRepo repository.TransactionHistoryRepository, reconciliationRepo repository.ReconciliationsRepository) DebtsService {
+	saga := NewSagaOrchestrator(sagaRepo, walletClient)
+	return &debtsService{
+		txManager:        txManager,
+		debtRepo:         debtRepo,
+		transactionRepo:  transactionRepo,
+		categoryRepo:     categoryRepo,
+		outboxRepository: outboxRepository,
+		saga:             saga,
+		ledger:           newWalletLedger(walletClient, saga, reconciliationRepo),
+		currencies:       newCurrencyConverter(currencyRepo),
+		history:          newHistoryRecorder(historyRepo),
+	}
+}
+
+func (debt_serv *debtsService) GetDebts(ctx context.Context, walletIDs []string, status string) ([]dto.DebtsResponse, error) {
+	debtStatus := model.DebtStatus(status)
+	if debtStatus != "" && debtStatus != model.DebtOpen && debtStatus != model.DebtSettled {
+		return nil, fmt.Errorf("invalid debt status [status=%s]", status)
+	}
+
+	debts, err := debt_serv.debtRepo.GetDebtsByWalletIDs(ctx, nil, walletIDs, debtStatus)
+	if err != nil {
+		return nil, fmt.Errorf("get debts: %w", err)
+	}
+
+	now := time.Now()
+	responses := make([]dto.DebtsResponse, 0, len(debts))
+	for _, debt := range debts {
+		responses = append(responses, debtResponse(debt, now))
+	}
+	return responses, nil
+}
+
+func (debt_serv *debtsService) GetOverdueDebts(ctx context.Context, walletIDs []string) ([]dto.DebtsResponse, error) {
+	now := time.Now()
+	debts, err := debt_serv.debtRepo.GetOverdueDebts(ctx, nil, walletIDs, now)
+	if err != nil {
+		return nil, fmt.Errorf("get overdue debts: %w", err)
+	}
+
+	responses := make([]dto.DebtsResponse, 0, len(debts))
+	for _, debt := range debts {
+		responses = append(responses, debtResponse(debt, now))
+	}
+	return responses, nil
+}
+
+func (debt_serv *debtsService) GetDebtByID(ctx context.Context, id string) (dto.DebtsResponse, error) {
+	debt, err := debt_serv.debtRepo.GetDebtByID(ctx, nil, id)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("debt not found [id=%s]: %w", id, err)
+	}
+
+	return debtResponse(debt, time.Now()), nil
+}
+
+func (debt_serv *debtsService) CreateDebt(ctx context.Context, request dto.DebtsRequest) (dto.DebtsResponse, error) {
+	now := time.Now()
+
+	direction := model.DebtDirection(request.Direction)
+	if direction != model.DebtPayable && direction != model.DebtReceivable {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid debt direction [direction=%s]", request.Direction)
+	}
+	counterparty := strings.TrimSpace(request.Counterparty)
+	if counterparty == "" || len(counterparty) > 100 {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid debt counterparty [counterparty=%s]", request.Counterparty)
+	}
+	if !request.Principal.IsPositive() {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid debt principal [principal=%s]", request.Principal)
+	}
+
+	date := request.Date
+	if date.IsZero() {
+		date = now
+	}
+	if date.After(now) {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid debt date: the date is in the future [date=%s]", date.Format(time.RFC3339))
+	}
+	if request.DueDate != nil && request.DueDate.Before(date) {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid debt due date: the due date is before the debt date [due_date=%s]", request.DueDate.Format(time.RFC3339))
+	}
+
+	WalletID, err := helper.ParseUUID(request.WalletID)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid wallet id [id=%s]: %w", request.WalletID, err)
+	}
+
+	// The debt is kept, and repaid, in the currency of its wallet
+	currency, err := debt_serv.currencies.walletCurrency(ctx, nil, request.WalletID)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("create debt: %w", err)
+	}
+
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := debt_serv.saga.NewSaga(data.SAGA_TYPE_DEBT_CREATE)
+	committed := false
+	defer func() {
+		if !committed {
+			debt_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
+	tx, err := debt_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("create debt: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	DebtID := uuid.New()
+	debt := model.Debts{
+		Base:         model.Base{ID: DebtID},
+		WalletID:     WalletID,
+		Counterparty: counterparty,
+		Direction:    direction,
+		Principal:    request.Principal,
+		Currency:     currency,
+		DebtDate:     date,
+		DueDate:      request.DueDate,
+		Description:  request.Description,
+		Status:       model.DebtOpen,
+	}
+
+	// ? A debt whose money moved before it was tracked books nothing
+	if !request.RecordOnly {
+		categoryID, _ := debtCategoryIDs(direction)
+		principal, err := debt_serv.bookDebtTransaction(ctx, tx, saga, debt, model.Transactions{
+			WalletID:        WalletID,
+			Amount:          request.Principal,
+			TransactionDate: date,
+			Description:     debtTransactionDescription(debt, request.Description),
+		}, categoryID)
+		if err != nil {
+			return dto.DebtsResponse{}, fmt.Errorf("create debt: %w", err)
+		}
+		debt.PrincipalTransactionID = &principal.ID
+		debt.Transactions = []model.Transactions{principal}
+	}
+
+	debtNew, err := debt_serv.debtRepo.CreateDebt(ctx, tx, debt)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("create debt: insert to db: %w", err)
+	}
+	debtNew.Transactions = debt.Transactions
+
+	if err := debt_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("create debt: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("create debt: commit: %w", err)
+	}
+	committed = true
+
+	return debtResponse(debtNew, now), nil
+}
+
+func (debt_serv *debtsService) UpdateDebt(ctx context.Context, id string, request dto.UpdateDebtRequest) (dto.DebtsResponse, error) {
+	counterparty := strings.TrimSpace(request.Counterparty)
+	if counterparty == "" || len(counterparty) > 100 {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid debt counterparty [counterparty=%s]", request.Counterparty)
+	}
+
+	tx, err := debt_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("update debt: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	debt, err := debt_serv.debtRepo.GetDebtByID(ctx, tx, id)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("debt not found [id=%s]: %w", id, err)
+	}
+	if request.DueDate != nil && request.DueDate.Before(debt.DebtDate) {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid debt due date: the due date is before the debt date [due_date=%s]", request.DueDate.Format(time.RFC3339))
+	}
+
+	debt.Counterparty = counterparty
+	debt.DueDate = request.DueDate
+	debt.Description = request.Description
+
+	debtUpdated, err := debt_serv.debtRepo.UpdateDebt(ctx, tx, debt)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("update debt [id=%s]: update in db: %w", id, err)
+	}
+	debtUpdated.Transactions = debt.Transactions
+
+	if err := tx.Commit(); err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("update debt: commit: %w", err)
+	}
+
+	return debtResponse(debtUpdated, time.Now()), nil
+}
+
+func (debt_serv *debtsService) RepayDebt(ctx context.Context, id string, request dto.DebtRepaymentRequest) (dto.DebtsResponse, error) {
+	now := time.Now()
+
+	if !request.Amount.IsPositive() {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid repayment amount [amount=%s]", request.Amount)
+	}
+	date := request.Date
+	if date.IsZero() {
+		date = now
+	}
+	if date.After(now) {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid repayment date: the date is in the future [date=%s]", date.Format(time.RFC3339))
+	}
+
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := debt_serv.saga.NewSaga(data.SAGA_TYPE_DEBT_REPAY)
+	committed := false
+	defer func() {
+		if !committed {
+			debt_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
+	tx, err := debt_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("repay debt: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	// ? Lock the debt so that a concurrent repayment sees this one
+	debt, err := debt_serv.debtRepo.GetDebtByID(ctx, tx, id)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("debt not found [id=%s]: %w", id, err)
+	}
+	if debt.Status != model.DebtOpen {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid debt status: the debt is already settled [id=%s]", id)
+	}
+	if outstanding := debtOutstanding(debt); outstanding.LessThan(request.Amount) {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid repayment amount: the amount exceeds the outstanding balance [amount=%s, outstanding=%s]", request.Amount, outstanding)
+	}
+
+	walletID := request.WalletID
+	if walletID == "" {
+		walletID = debt.WalletID.String()
+	}
+	WalletID, err := helper.ParseUUID(walletID)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("invalid wallet id [id=%s]: %w", walletID, err)
+	}
+	if WalletID != debt.WalletID {
+		currency, err := debt_serv.currencies.walletCurrency(ctx, tx, walletID)
+		if err != nil {
+			return dto.DebtsResponse{}, fmt.Errorf("repay debt: %w", err)
+		}
+		if currency != debt.Currency {
+			return dto.DebtsResponse{}, fmt.Errorf("invalid repayment wallet: the wallet is not in the currency of the debt [wallet_id=%s, currency=%s, debt_currency=%s]", walletID, currency, debt.Currency)
+		}
+	}
+
+	_, categoryID := debtCategoryIDs(debt.Direction)
+	repayment, err := debt_serv.bookDebtTransaction(ctx, tx, saga, debt, model.Transactions{
+		WalletID:        WalletID,
+		Amount:          request.Amount,
+		TransactionDate: date,
+		Description:     debtTransactionDescription(debt, request.Description),
+	}, categoryID)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("repay debt [id=%s]: %w", id, err)
+	}
+	debt.Transactions = append(debt.Transactions, repayment)
+
+	// ? The last repayment settles the debt
+	if debtOutstanding(debt).IsZero() {
+		debt.Status = model.DebtSettled
+		debt.SettledAt = &now
+
+		debtSettled, err := debt_serv.debtRepo.UpdateDebt(ctx, tx, debt)
+		if err != nil {
+			return dto.DebtsResponse{}, fmt.Errorf("repay debt [id=%s]: update in db: %w", id, err)
+		}
+		debtSettled.Transactions = debt.Transactions
+		debt = debtSettled
+
+		payload, err := json.Marshal(debtResponse(debt, now))
+		if err != nil {
+			return dto.DebtsResponse{}, fmt.Errorf("repay debt: marshal debt response: %w", err)
+		}
+
+		if err := debt_serv.outboxRepository.Create(ctx, tx, &model.OutboxMessage{
+			AggregateID: debt.ID.String(),
+			EventType:   data.OUTBOX_EVENT_DEBT_SETTLED,
+			Payload:     payload,
+			Published:   false,
+			MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+		}); err != nil {
+			return dto.DebtsResponse{}, err
+		}
+	}
+
+	if err := debt_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("repay debt: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("repay debt: commit: %w", err)
+	}
+	committed = true
+
+	return debtResponse(debt, now), nil
+}
+
+func (debt_serv *debtsService) DeleteDebt(ctx context.Context, id string) (dto.DebtsResponse, error) {
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := debt_serv.saga.NewSaga(data.SAGA_TYPE_DEBT_DELETE)
+	committed := false
+	defer func() {
+		if !committed {
+			debt_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
+	tx, err := debt_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("delete debt: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	debt, err := debt_serv.debtRepo.GetDebtByID(ctx, tx, id)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("debt not found [id=%s]: %w", id, err)
+	}
+	for _, transaction := range debt.Transactions {
+		if transaction.Status == model.StatusReconciled {
+			return dto.DebtsResponse{}, fmt.Errorf("invalid transaction status: a debt with a reconciled transaction cannot be deleted [id=%s, transaction_id=%s]", id, transaction.ID)
+		}
+	}
+
+	// ? Reverse the principal and every repayment on their wallets
+	if err := debt_serv.ledger.moveBalances(ctx, tx, saga, debt.Transactions, nil, nil); err != nil {
+		return dto.DebtsResponse{}, err
+	}
+
+	for _, transaction := range debt.Transactions {
+		if _, err := debt_serv.transactionRepo.DeleteTransaction(ctx, tx, transaction); err != nil {
+			return dto.DebtsResponse{}, fmt.Errorf("delete debt [id=%s]: delete transaction from db [transaction_id=%s]: %w", id, transaction.ID, err)
+		}
+	}
+
+	debtDeleted, err := debt_serv.debtRepo.DeleteDebt(ctx, tx, debt)
+	if err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("delete debt [id=%s]: delete from db: %w", id, err)
+	}
+
+	if err := debt_serv.history.RecordDeleted(ctx, tx, debt.Transactions...); err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("delete debt [id=%s]: %w", id, err)
+	}
+
+	if err := emitTransactions(ctx, debt_serv.outboxRepository, tx, data.OUTBOX_EVENT_TRANSACTION_DELETED, debt.Transactions...); err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("delete debt: %w", err)
+	}
+
+	if err := debt_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("delete debt: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.DebtsResponse{}, fmt.Errorf("delete debt: commit: %w", err)
+	}
+	committed = true
+
+	return debtResponse(debtDeleted, time.Now()), nil
+}
+
+// bookDebtTransaction moves transaction, in categoryID, on its wallet and
+// stores it linked to debt.
+func (debt_serv *debtsService) bookDebtTransaction(ctx context.Context, tx repository.Transaction, saga *model.SagaLog, debt model.Debts, transaction model.Transactions, categoryID string) (model.Transactions, error) {
+	category, err := debt_serv.categoryRepo.GetCategoryByID(ctx, tx, categoryID)
+	if err != nil {
+		return model.Transactions{}, fmt.Errorf("category not found [id=%s]: %w", categoryID, err)
+	}
+
+	transaction.CategoryID = category.ID
+	transaction.Category = category
+	transaction.Currency = debt.Currency
+	transaction.Status = model.StatusCleared
+	transaction.DebtID = &debt.ID
+
+	if err := debt_serv.ledger.moveBalances(ctx, tx, saga, nil, []model.Transactions{transaction}, nil); err != nil {
+		return model.Transactions{}, err
+	}
+
+	transactionNew, err := debt_serv.transactionRepo.CreateTransaction(ctx, tx, transaction)
+	if err != nil {
+		return model.Transactions{}, fmt.Errorf("insert transaction to db: %w", err)
+	}
+	transactionNew.Category = category
+
+	if err := debt_serv.history.RecordCreated(ctx, tx, transactionNew); err != nil {
+		return model.Transactions{}, err
+	}
+
+	if err := emitTransactions(ctx, debt_serv.outboxRepository, tx, data.OUTBOX_EVENT_TRANSACTION_CREATED, transactionNew); err != nil {
+		return model.Transactions{}, err
+	}
+
+	return transactionNew, nil
+}
+
+// debtCategoryIDs returns the categories of the principal and of the
+// repayments of a debt: a receivable lends money out and collects it back,
+// a payable borrows money in and pays it back.
+func debtCategoryIDs(direction model.DebtDirection) (principal, repayment string) {
+	if direction == model.DebtReceivable {
+		return data.CATEGORY_ID_RECEIVABLE_LENT, data.CATEGORY_ID_RECEIVABLE_COLLECTED
+	}
+	return data.CATEGORY_ID_DEBT_BORROWED, data.CATEGORY_ID_DEBT_REPAID
+}
+
+func debtTransactionDescription(debt model.Debts, description string) string {
+	if description != "" {
+		return description
+	}
+	if debt.Direction == model.DebtReceivable {
+		return "Piutang " + debt.Counterparty
+	}
+	return "Hutang " + debt.Counterparty
+}
+
+// debtRepayments returns the transactions of a debt other than its principal.
+func debtRepayments(debt model.Debts) []model.Transactions {
+	repayments := make([]model.Transactions, 0, len(debt.Transactions))
+	for _, transaction := range debt.Transactions {
+		if debt.PrincipalTransactionID != nil && transaction.ID == *debt.PrincipalTransactionID {
+			continue
+		}
+		repayments = append(repayments, transaction)
+	}
+	return repayments
+}
+
+func debtOutstanding(debt model.Debts) money.Amount {
+	outstanding := debt.Principal
+	for _, repayment := range debtRepayments(debt) {
+		outstanding = outstanding.Sub(repayment.Amount)
+	}
+	return outstanding
+}
+
+func debtResponse(debt model.Debts, now time.Time) dto.DebtsResponse {
+	outstanding := debtOutstanding(debt)
+	response := dto.DebtsResponse{
+		ID:           debt.ID.String(),
+		WalletID:     debt.WalletID.String(),
+		Counterparty: debt.Counterparty,
+		Direction:    string(debt.Direction),
+		Principal:    debt.Principal,
+		Currency:     debt.Currency,
+		Repaid:       debt.Principal.Sub(outstanding),
+		Outstanding:  outstanding,
+		Date:         debt.DebtDate,
+		DueDate:      debt.DueDate,
+		Description:  debt.Description,
+		Status:       string(debt.Status),
+		Overdue:      debt.Status == model.DebtOpen && debt.DueDate != nil && debt.DueDate.Before(now),
+		SettledAt:    debt.SettledAt,
+	}
+
+	repayments := debtRepayments(debt)
+	response.Repayments = make([]dto.TransactionsResponse, 0, len(repayments))
+	for _, repayment := range repayments {
+		response.Repayments = append(response.Repayments, helper.ConvertToResponseType(repayment).(dto.TransactionsResponse))
+	}
+	for _, transaction := range debt.Transactions {
+		if debt.PrincipalTransactionID != nil && transaction.ID == *debt.PrincipalTransactionID {
+			principal := helper.ConvertToResponseType(transaction).(dto.TransactionsResponse)
+			response.PrincipalTransaction = &principal
+		}
+	}
+	return response
+}
@@ -0,0 +1,492 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type debtTestDeps struct {
+	txManager          *mocks.MockTxManager
+	debtRepo           *mocks.MockDebtsRepository
+	transactionRepo    *mocks.MockTransactionsRepository
+	categoryRepo       *mocks.MockCategoriesRepository
+	outboxRepo         *mocks.MockOutboxRepository
+	sagaRepo           *mocks.MockSagaLogRepository
+	currencyRepo       *mocks.MockCurrenciesRepository
+	historyRepo        *mocks.MockTransactionHistoryRepository
+	reconciliationRepo *mocks.MockReconciliationsRepository
+	walletClient       *mocks.MockWalletClient
+	tx                 *mocks.MockTransaction
+}
+
+func newDebtTestDeps() *debtTestDeps {
+	d := &debtTestDeps{
+		txManager:          new(mocks.MockTxManager),
+		debtRepo:           new(mocks.MockDebtsRepository),
+		transactionRepo:    new(mocks.MockTransactionsRepository),
+		categoryRepo:       new(mocks.MockCategoriesRepository),
+		outboxRepo:         new(mocks.MockOutboxRepository),
+		sagaRepo:           new(mocks.MockSagaLogRepository),
+		currencyRepo:       new(mocks.MockCurrenciesRepository),
+		historyRepo:        new(mocks.MockTransactionHistoryRepository),
+		reconciliationRepo: new(mocks.MockReconciliationsRepository),
+		walletClient:       new(mocks.MockWalletClient),
+		tx:                 new(mocks.MockTransaction),
+	}
+	d.currencyRepo.On("GetWalletCurrency", mock.Anything, mock.Anything, mock.Anything).Return("", nil).Maybe()
+	// History entries are covered in transactionHistory_test.go
+	d.historyRepo.On("CreateHistory", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return d
+}
+
+func (d *debtTestDeps) service() DebtsService {
+	return NewDebtsService(d.txManager, d.debtRepo, d.transactionRepo, d.walletClient, d.categoryRepo, d.outboxRepo, d.sagaRepo, d.currencyRepo, d.historyRepo, d.reconciliationRepo)
+}
+
+func (d *debtTestDeps) assertAll(t *testing.T) {
+	t.Helper()
+	d.txManager.AssertExpectations(t)
+	d.debtRepo.AssertExpectations(t)
+	d.transactionRepo.AssertExpectations(t)
+	d.categoryRepo.AssertExpectations(t)
+	d.outboxRepo.AssertExpectations(t)
+	d.sagaRepo.AssertExpectations(t)
+	d.currencyRepo.AssertExpectations(t)
+	d.historyRepo.AssertExpectations(t)
+	d.reconciliationRepo.AssertExpectations(t)
+	d.walletClient.AssertExpectations(t)
+	d.tx.AssertExpectations(t)
+}
+
+func (d *debtTestDeps) expectSagaLog(status model.SagaStatus) {
+	d.sagaRepo.On("CreateSaga", mock.Anything, nil, mock.Anything).Return(nil).Once()
+	d.sagaRepo.On("CreateStep", mock.Anything, nil, mock.Anything).Return(nil)
+	d.sagaRepo.On("UpdateStepStatus", mock.Anything, nil, mock.Anything, mock.Anything).Return(nil)
+	if status == model.SagaCompleted {
+		d.sagaRepo.On("CompleteSaga", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+		return
+	}
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, mock.Anything, mock.Anything).Return(true, nil).Once()
+	d.sagaRepo.On("UpdateSagaStatus", mock.Anything, mock.Anything, mock.Anything, status, mock.Anything).Return(nil).Once()
+}
+
+// ─────────────────────────────────────────────
+// Fixed UUIDs & Sample Data
+// ─────────────────────────────────────────────
+
+var (
+	debtTestID          = uuid.MustParse("99999999-9999-9999-9999-999999999999")
+	debtPrincipalTxnID  = uuid.MustParse("99999999-0000-0000-0000-000000000001")
+	debtRepaymentTxnID  = uuid.MustParse("99999999-0000-0000-0000-000000000002")
+	debtRepayment2TxnID = uuid.MustParse("99999999-0000-0000-0000-000000000003")
+)
+
+func sampleDebtCategory(id string, name string, categoryType model.CategoryType) model.Categories {
+	return model.Categories{Base: model.Base{ID: uuid.MustParse(id)}, Name: name, Type: categoryType}
+}
+
+func sampleDebtTransaction(id uuid.UUID, amount int64, category model.Categories) model.Transactions {
+	return model.Transactions{
+		Base:            model.Base{ID: id, CreatedAt: txnFixTime, UpdatedAt: txnFixTime},
+		WalletID:        walletTestID,
+		CategoryID:      category.ID,
+		Amount:          money.New(amount),
+		Currency:        data.DEFAULT_CURRENCY,
+		TransactionDate: txnFixTime,
+		Status:          model.StatusCleared,
+		DebtID:          &debtTestID,
+		Category:        category,
+	}
+}
+
+// sampleReceivableDebt is 50,000 lent from walletTestID, with the given
+// repayments collected so far.
+func sampleReceivableDebt(repayments ...int64) model.Debts {
+	debt := model.Debts{
+		Base:                   model.Base{ID: debtTestID, CreatedAt: txnFixTime, UpdatedAt: txnFixTime},
+		WalletID:               walletTestID,
+		Counterparty:           "Budi",
+		Direction:              model.DebtReceivable,
+		Principal:              money.New(50000),
+		Currency:               data.DEFAULT_CURRENCY,
+		DebtDate:               txnFixTime,
+		Status:                 model.DebtOpen,
+		PrincipalTransactionID: &debtPrincipalTxnID,
+		Transactions: []model.Transactions{
+			sampleDebtTransaction(debtPrincipalTxnID, 50000, sampleDebtCategory(data.CATEGORY_ID_RECEIVABLE_LENT, "Pinjaman Diberikan", model.Expense)),
+		},
+	}
+	for i, amount := range repayments {
+		id := debtRepaymentTxnID
+		if i > 0 {
+			id = debtRepayment2TxnID
+		}
+		debt.Transactions = append(debt.Transactions, sampleDebtTransaction(id, amount, sampleDebtCategory(data.CATEGORY_ID_RECEIVABLE_COLLECTED, "Pelunasan Piutang", model.Income)))
+	}
+	return debt
+}
+
+func sampleDebtRequest() dto.DebtsRequest {
+	return dto.DebtsRequest{
+		WalletID:     walletTestID.String(),
+		Counterparty: "Budi",
+		Direction:    string(model.DebtReceivable),
+		Principal:    money.New(50000),
+		Date:         txnFixTime,
+	}
+}
+
+// =====================================================================
+// CreateDebt
+// =====================================================================
+
+func TestCreateDebt_ReceivableLendsFromWallet(t *testing.T) {
+	d := newDebtTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	lent := sampleDebtCategory(data.CATEGORY_ID_RECEIVABLE_LENT, "Pinjaman Diberikan", model.Expense)
+	principal := sampleDebtTransaction(debtPrincipalTxnID, 50000, lent)
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, data.CATEGORY_ID_RECEIVABLE_LENT).Return(lent, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 100000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 50000), nil)
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(txn model.Transactions) bool {
+		return txn.DebtID != nil && txn.CategoryID == lent.ID && txn.Description == "Piutang Budi"
+	})).Return(principal, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_CREATED && msg.AggregateID == debtPrincipalTxnID.String()
+	})).Return(nil)
+	d.debtRepo.On("CreateDebt", mock.Anything, d.tx, mock.MatchedBy(func(debt model.Debts) bool {
+		return debt.PrincipalTransactionID != nil && *debt.PrincipalTransactionID == debtPrincipalTxnID && debt.Status == model.DebtOpen
+	})).Return(sampleReceivableDebt(), nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CreateDebt(context.Background(), sampleDebtRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, debtTestID.String(), result.ID)
+	assert.Equal(t, money.New(50000), result.Outstanding)
+	assert.True(t, result.Repaid.IsZero())
+	if assert.NotNil(t, result.PrincipalTransaction) {
+		assert.Equal(t, debtPrincipalTxnID.String(), result.PrincipalTransaction.ID)
+	}
+	assert.Empty(t, result.Repayments)
+	d.assertAll(t)
+}
+
+func TestCreateDebt_RecordOnlyMovesNothing(t *testing.T) {
+	d := newDebtTestDeps()
+	svc := d.service()
+
+	debt := sampleReceivableDebt()
+	debt.PrincipalTransactionID = nil
+	debt.Transactions = nil
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.debtRepo.On("CreateDebt", mock.Anything, d.tx, mock.MatchedBy(func(debt model.Debts) bool {
+		return debt.PrincipalTransactionID == nil
+	})).Return(debt, nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	req := sampleDebtRequest()
+	req.RecordOnly = true
+	result, err := svc.CreateDebt(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Nil(t, result.PrincipalTransaction)
+	assert.Equal(t, money.New(50000), result.Outstanding)
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.transactionRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCreateDebt_ReceivableInsufficientBalance(t *testing.T) {
+	d := newDebtTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, data.CATEGORY_ID_RECEIVABLE_LENT).
+		Return(sampleDebtCategory(data.CATEGORY_ID_RECEIVABLE_LENT, "Pinjaman Diberikan", model.Expense), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 10000), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.CreateDebt(context.Background(), sampleDebtRequest())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient wallet balance")
+	d.debtRepo.AssertNotCalled(t, "CreateDebt", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCreateDebt_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(req *dto.DebtsRequest)
+		want   string
+	}{
+		{"direction", func(req *dto.DebtsRequest) { req.Direction = "loan" }, "invalid debt direction"},
+		{"counterparty", func(req *dto.DebtsRequest) { req.Counterparty = "  " }, "invalid debt counterparty"},
+		{"principal", func(req *dto.DebtsRequest) { req.Principal = money.Zero }, "invalid debt principal"},
+		{"future date", func(req *dto.DebtsRequest) { req.Date = time.Now().Add(time.Hour) }, "invalid debt date"},
+		{"due before date", func(req *dto.DebtsRequest) {
+			due := txnFixTime.AddDate(0, 0, -1)
+			req.DueDate = &due
+		}, "invalid debt due date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDebtTestDeps()
+			svc := d.service()
+
+			req := sampleDebtRequest()
+			tt.change(&req)
+			_, err := svc.CreateDebt(context.Background(), req)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+			d.assertAll(t)
+		})
+	}
+}
+
+// =====================================================================
+// RepayDebt
+// =====================================================================
+
+func TestRepayDebt_PartialKeepsDebtOpen(t *testing.T) {
+	d := newDebtTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	collected := sampleDebtCategory(data.CATEGORY_ID_RECEIVABLE_COLLECTED, "Pelunasan Piutang", model.Income)
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.debtRepo.On("GetDebtByID", mock.Anything, d.tx, debtTestID.String()).Return(sampleReceivableDebt(), nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, data.CATEGORY_ID_RECEIVABLE_COLLECTED).Return(collected, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 0), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(20000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 20000), nil)
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).
+		Return(sampleDebtTransaction(debtRepaymentTxnID, 20000, collected), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_CREATED
+	})).Return(nil).Once()
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.RepayDebt(context.Background(), debtTestID.String(), dto.DebtRepaymentRequest{Amount: money.New(20000)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(model.DebtOpen), result.Status)
+	assert.Equal(t, money.New(20000), result.Repaid)
+	assert.Equal(t, money.New(30000), result.Outstanding)
+	assert.Len(t, result.Repayments, 1)
+	d.debtRepo.AssertNotCalled(t, "UpdateDebt", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestRepayDebt_LastRepaymentSettles(t *testing.T) {
+	d := newDebtTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	collected := sampleDebtCategory(data.CATEGORY_ID_RECEIVABLE_COLLECTED, "Pelunasan Piutang", model.Income)
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.debtRepo.On("GetDebtByID", mock.Anything, d.tx, debtTestID.String()).Return(sampleReceivableDebt(20000), nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, data.CATEGORY_ID_RECEIVABLE_COLLECTED).Return(collected, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 0), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(30000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 30000), nil)
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).
+		Return(sampleDebtTransaction(debtRepayment2TxnID, 30000, collected), nil)
+	d.debtRepo.On("UpdateDebt", mock.Anything, d.tx, mock.MatchedBy(func(debt model.Debts) bool {
+		return debt.Status == model.DebtSettled && debt.SettledAt != nil
+	})).Return(func() model.Debts {
+		debt := sampleReceivableDebt(20000, 30000)
+		debt.Status = model.DebtSettled
+		return debt
+	}(), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_CREATED
+	})).Return(nil).Once()
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_DEBT_SETTLED && msg.AggregateID == debtTestID.String()
+	})).Return(nil).Once()
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.RepayDebt(context.Background(), debtTestID.String(), dto.DebtRepaymentRequest{Amount: money.New(30000)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(model.DebtSettled), result.Status)
+	assert.True(t, result.Outstanding.IsZero())
+	assert.Len(t, result.Repayments, 2)
+	d.assertAll(t)
+}
+
+func TestRepayDebt_ExceedsOutstanding(t *testing.T) {
+	d := newDebtTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.debtRepo.On("GetDebtByID", mock.Anything, d.tx, debtTestID.String()).Return(sampleReceivableDebt(20000), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.RepayDebt(context.Background(), debtTestID.String(), dto.DebtRepaymentRequest{Amount: money.New(30001)})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the outstanding balance")
+	d.transactionRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestRepayDebt_SettledDebt(t *testing.T) {
+	d := newDebtTestDeps()
+	svc := d.service()
+
+	debt := sampleReceivableDebt(50000)
+	debt.Status = model.DebtSettled
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.debtRepo.On("GetDebtByID", mock.Anything, d.tx, debtTestID.String()).Return(debt, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.RepayDebt(context.Background(), debtTestID.String(), dto.DebtRepaymentRequest{Amount: money.New(1000)})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already settled")
+	d.assertAll(t)
+}
+
+func TestRepayDebt_WalletInOtherCurrency(t *testing.T) {
+	d := newDebtTestDeps()
+	d.currencyRepo = new(mocks.MockCurrenciesRepository)
+	svc := d.service()
+
+	d.currencyRepo.On("GetWalletCurrency", mock.Anything, mock.Anything, wallet2ID.String()).Return("USD", nil)
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.debtRepo.On("GetDebtByID", mock.Anything, d.tx, debtTestID.String()).Return(sampleReceivableDebt(), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.RepayDebt(context.Background(), debtTestID.String(), dto.DebtRepaymentRequest{WalletID: wallet2ID.String(), Amount: money.New(1000)})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid repayment wallet")
+	d.assertAll(t)
+}
+
+// =====================================================================
+// DeleteDebt
+// =====================================================================
+
+func TestDeleteDebt_ReversesPrincipalAndRepayments(t *testing.T) {
+	d := newDebtTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	// 50,000 lent and 20,000 collected: the wallet gets the other 30,000 back
+	debt := sampleReceivableDebt(20000)
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.debtRepo.On("GetDebtByID", mock.Anything, d.tx, debtTestID.String()).Return(debt, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 0), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(30000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 30000), nil)
+	d.transactionRepo.On("DeleteTransaction", mock.Anything, d.tx, mock.Anything).Return(model.Transactions{}, nil).Twice()
+	d.debtRepo.On("DeleteDebt", mock.Anything, d.tx, debt).Return(debt, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_DELETED
+	})).Return(nil).Twice()
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.DeleteDebt(context.Background(), debtTestID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, debtTestID.String(), result.ID)
+	d.assertAll(t)
+}
+
+func TestDeleteDebt_ReconciledTransaction(t *testing.T) {
+	d := newDebtTestDeps()
+	svc := d.service()
+
+	debt := sampleReceivableDebt(20000)
+	debt.Transactions[1].Status = model.StatusReconciled
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.debtRepo.On("GetDebtByID", mock.Anything, d.tx, debtTestID.String()).Return(debt, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.DeleteDebt(context.Background(), debtTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "reconciled")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestDeleteTransaction_DebtTransactionIsDeletedWithItsDebt(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	repayment := sampleDebtTransaction(debtRepaymentTxnID, 20000, sampleDebtCategory(data.CATEGORY_ID_RECEIVABLE_COLLECTED, "Pelunasan Piutang", model.Income))
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil).Maybe()
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, mock.Anything, debtRepaymentTxnID.String()).Return(repayment, nil)
+	d.tx.On("Rollback").Return(nil).Maybe()
+
+	_, err := svc.DeleteTransaction(context.Background(), debtRepaymentTxnID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "delete the debt instead")
+	d.transactionRepo.AssertNotCalled(t, "DeleteTransaction", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// GetOverdueDebts
+// =====================================================================
+
+func TestGetOverdueDebts_MarksOverdue(t *testing.T) {
+	d := newDebtTestDeps()
+	svc := d.service()
+
+	debt := sampleReceivableDebt(20000)
+	due := txnFixTime.AddDate(0, 1, 0)
+	debt.DueDate = &due
+
+	walletIDs := []string{walletTestID.String()}
+	d.debtRepo.On("GetOverdueDebts", mock.Anything, nil, walletIDs, mock.Anything).Return([]model.Debts{debt}, nil)
+
+	result, err := svc.GetOverdueDebts(context.Background(), walletIDs)
+
+	assert.NoError(t, err)
+	if assert.Len(t, result, 1) {
+		assert.True(t, result[0].Overdue)
+		assert.Equal(t, money.New(30000), result[0].Outstanding)
+	}
+	d.assertAll(t)
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"refina-transaction/config/miniofs"
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+)
+
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+)
+
+var exportCSVHeader = []string{"id", "transaction_date", "wallet_id", "category_id", "category_name", "category_type", "amount", "description"}
+
+type ExportsService interface {
+	// ExportTransactions streams the transactions matching q to w as CSV or
+	// JSON Lines. Nothing is written when the format is invalid.
+	ExportTransactions(ctx context.Context, q repository.CursorQuery, format string, w io.Writer) error
+	GetMonthlyStatement(ctx context.Context, walletID, month string) (dto.StatementResponse, []byte, error)
+	StoreMonthlyStatement(ctx context.Context, walletID, month string) (dto.StatementResponse, error)
+}
+
+type exportsService struct {
+	transactionRepo repository.TransactionsRepository
+	walletClient    client.WalletClient
+	minio           *miniofs.MinIOManager
+	now             func() time.Time
+}
+
+func NewExportsService(transactionRepo repository.TransactionsRepository, walletClient client.WalletClient, minio *miniofs.MinIOManager) ExportsService {
+	return &exportsService{
+		transactionRepo: transactionRepo,
+		walletClient:    walletClient,
+		minio:           minio,
+		now:             time.Now,
+	}
+}
+
+func (export_serv *exportsService) ExportTransactions(ctx context.Context, q repository.CursorQuery, format string, w io.Writer) error {
+	if err := normalizeTagFilter(&q); err != nil {
+		return fmt.Errorf("export transactions: %w", err)
+	}
+	if err := normalizeStatusFilter(q.Statuses); err != nil {
+		return fmt.Errorf("export transactions: %w", err)
+	}
+
+	var writeBatch func([]model.Transactions) error
+
+	switch format {
+	case ExportFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(exportCSVHeader); err != nil {
+			return fmt.Errorf("export transactions: write csv header: %w", err)
+		}
+		writeBatch = func(transactions []model.Transactions) error {
+			for _, transaction := range transactions {
+				err := writer.Write([]string{
+					transaction.ID.String(),
+					transaction.TransactionDate.Format(time.RFC3339),
+					transaction.WalletID.String(),
+					transaction.CategoryID.String(),
+					csvText(transaction.Category.Name),
+					string(transaction.Category.Type),
+					transaction.Amount.String(),
+					csvText(transaction.Description),
+				})
+				if err != nil {
+					return err
+				}
+			}
+			writer.Flush()
+			return writer.Error()
+		}
+	case ExportFormatJSONL:
+		encoder := json.NewEncoder(w)
+		writeBatch = func(transactions []model.Transactions) error {
+			for _, transaction := range transactions {
+				if err := encoder.Encode(helper.ConvertToResponseType(transaction).(dto.TransactionsResponse)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	default:
+		return fmt.Errorf("invalid export format [format=%s]", format)
+	}
+
+	err := export_serv.transactionRepo.StreamTransactions(ctx, nil, q, data.EXPORT_BATCH_SIZE, func(transactions []model.Transactions) error {
+		if err := writeBatch(transactions); err != nil {
+			return fmt.Errorf("write batch: %w", err)
+		}
+		// Push each batch to the client instead of buffering the whole export
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("export transactions [format=%s]: %w", format, err)
+	}
+
+	return nil
+}
+
+// csvText neutralises user-entered text that a spreadsheet would evaluate as a
+// formula by prefixing it with an apostrophe.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (export_serv *exportsService) GetMonthlyStatement(ctx context.Context, walletID, month string) (dto.StatementResponse, []byte, error) {
+	now := export_serv.now().UTC()
+	if month == "" {
+		month = now.Format("2006-01")
+	}
+	from, err := time.Parse("2006-01", month)
+	if err != nil {
+		return dto.StatementResponse{}, nil, fmt.Errorf("invalid statement month [month=%s]: %w", month, err)
+	}
+	if from.After(now) {
+		return dto.StatementResponse{}, nil, fmt.Errorf("invalid statement month [month=%s]: month is in the future", month)
+	}
+	to := from.AddDate(0, 1, 0)
+
+	wallet, err := export_serv.walletClient.GetWalletByID(ctx, walletID)
+	if err != nil {
+		return dto.StatementResponse{}, nil, fmt.Errorf("wallet not found [id=%s]: %w", walletID, err)
+	}
+
+	// The wallet only knows its current balance; walk it back to the start of the month
+	netSince, err := export_serv.transactionRepo.GetWalletNetChangeSince(ctx, nil, walletID, from)
+	if err != nil {
+		return dto.StatementResponse{}, nil, fmt.Errorf("get monthly statement [wallet_id=%s]: %w", walletID, err)
+	}
+
+	statement := dto.StatementResponse{
+		WalletID:       walletID,
+		Month:          month,
+		OpeningBalance: client.WalletBalance(wallet).Sub(netSince),
+		FileName:       fmt.Sprintf("statement_%s_%s.pdf", walletID, month),
+	}
+
+	lines := []string{
+		"ACCOUNT STATEMENT",
+		"",
+		fmt.Sprintf("Wallet    : %s", wallet.GetName()),
+		fmt.Sprintf("Wallet ID : %s", walletID),
+		fmt.Sprintf("Period    : %s - %s", from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02")),
+		fmt.Sprintf("Generated : %s", now.Format(time.RFC3339)),
+		"",
+		statementRow("Date", "Description", "Category", "Amount", "Balance"),
+		strings.Repeat("-", 97),
+		statementRow("", "Opening balance", "", "", formatStatementAmount(statement.OpeningBalance)),
+	}
+
+	balance := statement.OpeningBalance
+	q := repository.CursorQuery{
+		WalletIDs:  []string{walletID},
+		DateFrom:   from.Format(time.RFC3339),
+		DateTo:     to.Add(-time.Nanosecond).Format(time.RFC3339Nano),
+		BookedOnly: true,
+	}
+	err = export_serv.transactionRepo.StreamTransactions(ctx, nil, q, data.EXPORT_BATCH_SIZE, func(transactions []model.Transactions) error {
+		for _, transaction := range transactions {
+			delta := statementDelta(transaction)
+			if !delta.IsNegative() {
+				statement.TotalIncome = statement.TotalIncome.Add(delta)
+			} else {
+				statement.TotalExpense = statement.TotalExpense.Sub(delta)
+			}
+			balance = balance.Add(delta)
+			statement.Entries++
+
+			lines = append(lines, statementRow(
+				transaction.TransactionDate.Format("2006-01-02"),
+				transaction.Description,
+				transaction.Category.Name,
+				formatStatementAmount(delta),
+				formatStatementAmount(balance),
+			))
+		}
+		return nil
+	})
+	if err != nil {
+		return dto.StatementResponse{}, nil, fmt.Errorf("get monthly statement [wallet_id=%s]: %w", walletID, err)
+	}
+	statement.ClosingBalance = balance
+
+	lines = append(lines,
+		strings.Repeat("-", 97),
+		statementRow("", "Closing balance", "", "", formatStatementAmount(statement.ClosingBalance)),
+		"",
+		fmt.Sprintf("Entries       : %d", statement.Entries),
+		fmt.Sprintf("Total income  : %s", formatStatementAmount(statement.TotalIncome)),
+		fmt.Sprintf("Total expense : %s", formatStatementAmount(statement.TotalExpense)),
+	)
+
+	return statement, renderTextPDF(lines), nil
+}
+
+func (export_serv *exportsService) StoreMonthlyStatement(ctx context.Context, walletID, month string) (dto.StatementResponse, error) {
+	if export_serv.minio == nil || !export_serv.minio.IsReady() {
+		return dto.StatementResponse{}, fmt.Errorf("store monthly statement [wallet_id=%s]: statement storage is not available", walletID)
+	}
+
+	statement, pdf, err := export_serv.GetMonthlyStatement(ctx, walletID, month)
+	if err != nil {
+		return dto.StatementResponse{}, err
+	}
+
+	res, err := export_serv.minio.UploadFile(ctx, miniofs.UploadRequest{
+		Base64Data: base64.StdEncoding.EncodeToString(pdf),
+		Prefix:     fmt.Sprintf("%s_%s_%s", miniofs.TRANSACTION_STATEMENT_PREFIX, walletID, statement.Month),
+		BucketName: miniofs.TRANSACTION_ATTACHMENT_BUCKET,
+		Validation: &miniofs.FileValidationConfig{
+			AllowedExtensions: []string{".pdf"},
+			MaxFileSize:       int64(data.STATEMENT_MAX_FILE_SIZE),
+			MinFileSize:       1,
+		},
+	})
+	if err != nil {
+		return dto.StatementResponse{}, fmt.Errorf("store monthly statement [wallet_id=%s]: upload: %w", walletID, err)
+	}
+
+	url, err := export_serv.minio.GetPresignedURL(ctx, res.BucketName, res.ObjectName, data.STATEMENT_URL_EXPIRY)
+	if err != nil {
+		return dto.StatementResponse{}, fmt.Errorf("store monthly statement [wallet_id=%s]: presign: %w", walletID, err)
+	}
+
+	statement.FileName = res.ObjectName
+	statement.URL = url
+	statement.ExpiresAt = export_serv.now().Add(data.STATEMENT_URL_EXPIRY)
+
+	return statement, nil
+}
+
+// statementDelta is the signed effect of a transaction on its wallet balance.
+func statementDelta(transaction model.Transactions) money.Amount {
+	switch {
+	case transaction.Category.Type == model.Income,
+		transaction.Category.Type == model.FundTransfer && transaction.Category.Name == "Cash In":
+		return transaction.Amount
+	case transaction.Category.Type == model.Expense,
+		transaction.Category.Type == model.FundTransfer && transaction.Category.Name == "Cash Out":
+		return transaction.Amount.Neg()
+	default:
+		return money.Zero
+	}
+}
+
+func statementRow(date, description, category, amount, balance string) string {
+	return fmt.Sprintf("%-10s  %-34s  %-18s  %13s  %14s", date, statementCell(description, 34), statementCell(category, 18), amount, balance)
+}
+
+func statementCell(value string, width int) string {
+	if runes := []rune(value); len(runes) > width {
+		return string(runes[:width-1]) + "~"
+	}
+	return value
+}
+
+func formatStatementAmount(amount money.Amount) string {
+	return amount.String()
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type exportTestDeps struct {
+	transactionRepo *mocks.MockTransactionsRepository
+	walletClient    *mocks.MockWalletClient
+}
+
+func newExportTestDeps() *exportTestDeps {
+	return &exportTestDeps{
+		transactionRepo: new(mocks.MockTransactionsRepository),
+		walletClient:    new(mocks.MockWalletClient),
+	}
+}
+
+func (d *exportTestDeps) service() ExportsService {
+	return &exportsService{
+		transactionRepo: d.transactionRepo,
+		walletClient:    d.walletClient,
+		now:             func() time.Time { return txnFixTime },
+	}
+}
+
+func (d *exportTestDeps) assertAll(t *testing.T) {
+	t.Helper()
+	d.transactionRepo.AssertExpectations(t)
+	d.walletClient.AssertExpectations(t)
+}
+
+func sampleExportTransactions() []model.Transactions {
+	return []model.Transactions{
+		{
+			Base:            model.Base{ID: uuid.MustParse("00000000-0000-0000-0000-0000000000a1")},
+			WalletID:        walletTestID,
+			CategoryID:      catTestID,
+			Amount:          money.New(5000000),
+			TransactionDate: time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC),
+			Description:     "Gaji Juni",
+			Category:        model.Categories{Base: model.Base{ID: catTestID}, Name: "Gaji", Type: model.Income},
+		},
+		{
+			Base:            model.Base{ID: uuid.MustParse("00000000-0000-0000-0000-0000000000a2")},
+			WalletID:        walletTestID,
+			CategoryID:      catTestID,
+			Amount:          money.MustParse("75000.5"),
+			TransactionDate: time.Date(2025, 6, 3, 12, 0, 0, 0, time.UTC),
+			Description:     "Makan siang, kantor",
+			Category:        model.Categories{Base: model.Base{ID: catTestID}, Name: "Makanan", Type: model.Expense},
+		},
+		{
+			Base:            model.Base{ID: uuid.MustParse("00000000-0000-0000-0000-0000000000a3")},
+			WalletID:        walletTestID,
+			CategoryID:      catTestID,
+			Amount:          money.New(1000000),
+			TransactionDate: time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC),
+			Description:     "fund transfer to Dompet(Cash Out)",
+			Category:        model.Categories{Base: model.Base{ID: catTestID}, Name: "Cash Out", Type: model.FundTransfer},
+		},
+	}
+}
+
+// =====================================================================
+// ExportTransactions
+// =====================================================================
+
+func TestExportTransactions_CSV(t *testing.T) {
+	d := newExportTestDeps()
+	svc := d.service()
+	q := repository.CursorQuery{WalletIDs: []string{walletTestID.String()}}
+
+	all := sampleExportTransactions()
+	d.transactionRepo.On("StreamTransactions", mock.Anything, nil, q, data.EXPORT_BATCH_SIZE).
+		Return([][]model.Transactions{all[:2], all[2:]}, nil)
+
+	var buf bytes.Buffer
+	err := svc.ExportTransactions(context.Background(), q, ExportFormatCSV, &buf)
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "id,transaction_date,wallet_id,category_id,category_name,category_type,amount,description", lines[0])
+	assert.Contains(t, lines[2], `,Makanan,expense,75000.50,"Makan siang, kantor"`)
+	d.assertAll(t)
+}
+
+func TestExportTransactions_CSVEscapesFormulas(t *testing.T) {
+	d := newExportTestDeps()
+	svc := d.service()
+	q := repository.CursorQuery{WalletIDs: []string{walletTestID.String()}}
+
+	transactions := sampleExportTransactions()[:1]
+	transactions[0].Description = `=HYPERLINK("http://evil.example","klik")`
+	transactions[0].Category.Name = "@SUM(A1)"
+	d.transactionRepo.On("StreamTransactions", mock.Anything, nil, q, data.EXPORT_BATCH_SIZE).
+		Return([][]model.Transactions{transactions}, nil)
+
+	var buf bytes.Buffer
+	err := svc.ExportTransactions(context.Background(), q, ExportFormatCSV, &buf)
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[1], `,'@SUM(A1),income,5000000.00,"'=HYPERLINK(""http://evil.example"",""klik"")"`)
+	for _, tt := range []struct{ in, want string }{
+		{"+62 812", "'+62 812"},
+		{"-refund", "'-refund"},
+		{"\tcmd", "'\tcmd"},
+		{"Gaji", "Gaji"},
+		{"", ""},
+	} {
+		assert.Equal(t, tt.want, csvText(tt.in))
+	}
+	d.assertAll(t)
+}
+
+func TestExportTransactions_JSONL(t *testing.T) {
+	d := newExportTestDeps()
+	svc := d.service()
+	q := repository.CursorQuery{WalletIDs: []string{walletTestID.String()}}
+
+	d.transactionRepo.On("StreamTransactions", mock.Anything, nil, q, data.EXPORT_BATCH_SIZE).
+		Return([][]model.Transactions{sampleExportTransactions()}, nil)
+
+	var buf bytes.Buffer
+	err := svc.ExportTransactions(context.Background(), q, ExportFormatJSONL, &buf)
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"category_name":"Gaji"`)
+	assert.Contains(t, lines[0], `"amount":5000000`)
+	d.assertAll(t)
+}
+
+func TestExportTransactions_InvalidFormat(t *testing.T) {
+	d := newExportTestDeps()
+	svc := d.service()
+
+	var buf bytes.Buffer
+	err := svc.ExportTransactions(context.Background(), repository.CursorQuery{}, "xlsx", &buf)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid export format")
+	assert.Zero(t, buf.Len())
+	d.assertAll(t)
+}
+
+func TestExportTransactions_StreamError(t *testing.T) {
+	d := newExportTestDeps()
+	svc := d.service()
+	q := repository.CursorQuery{WalletIDs: []string{walletTestID.String()}}
+
+	d.transactionRepo.On("StreamTransactions", mock.Anything, nil, q, data.EXPORT_BATCH_SIZE).
+		Return([][]model.Transactions{}, errors.New("failed to fetch transactions"))
+
+	var buf bytes.Buffer
+	err := svc.ExportTransactions(context.Background(), q, ExportFormatJSONL, &buf)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to fetch transactions")
+	d.assertAll(t)
+}
+
+// =====================================================================
+// GetMonthlyStatement
+// =====================================================================
+
+func TestGetMonthlyStatement_Balances(t *testing.T) {
+	d := newExportTestDeps()
+	svc := d.service()
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// Current balance 10,000,000 with +3,924,999.50 booked since June 1st
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 10000000), nil)
+	d.transactionRepo.On("GetWalletNetChangeSince", mock.Anything, nil, walletTestID.String(), from).Return(money.MustParse("3924999.5"), nil)
+	d.transactionRepo.On("StreamTransactions", mock.Anything, nil, mock.MatchedBy(func(q repository.CursorQuery) bool {
+		return q.DateFrom == "2025-06-01T00:00:00Z" && strings.HasPrefix(q.DateTo, "2025-06-30T23:59:59.999")
+	}), data.EXPORT_BATCH_SIZE).Return([][]model.Transactions{sampleExportTransactions()}, nil)
+
+	statement, pdf, err := svc.GetMonthlyStatement(context.Background(), walletTestID.String(), "2025-06")
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("6075000.5"), statement.OpeningBalance)
+	assert.Equal(t, money.New(5000000), statement.TotalIncome)
+	assert.Equal(t, money.MustParse("1075000.5"), statement.TotalExpense)
+	assert.Equal(t, money.New(10000000), statement.ClosingBalance)
+	assert.Equal(t, 3, statement.Entries)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	assert.Contains(t, string(pdf), "Opening balance")
+	assert.Contains(t, string(pdf), "fund transfer to Dompet\\(Cash Out\\)")
+	d.assertAll(t)
+}
+
+func TestGetMonthlyStatement_InvalidMonth(t *testing.T) {
+	d := newExportTestDeps()
+	svc := d.service()
+
+	_, _, err := svc.GetMonthlyStatement(context.Background(), walletTestID.String(), "06-2025")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid statement month")
+	d.assertAll(t)
+}
+
+func TestGetMonthlyStatement_FutureMonth(t *testing.T) {
+	d := newExportTestDeps()
+	svc := d.service()
+
+	_, _, err := svc.GetMonthlyStatement(context.Background(), walletTestID.String(), "2025-07")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid statement month")
+	d.assertAll(t)
+}
+
+func TestGetMonthlyStatement_WalletNotFound(t *testing.T) {
+	d := newExportTestDeps()
+	svc := d.service()
+
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(nil, errors.New("wallet not found"))
+
+	_, _, err := svc.GetMonthlyStatement(context.Background(), walletTestID.String(), "")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "wallet not found")
+	d.assertAll(t)
+}
+
+// =====================================================================
+// StoreMonthlyStatement
+// =====================================================================
+
+func TestStoreMonthlyStatement_StorageUnavailable(t *testing.T) {
+	d := newExportTestDeps()
+	svc := d.service()
+
+	_, err := svc.StoreMonthlyStatement(context.Background(), walletTestID.String(), "2025-06")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "statement storage is not available")
+	d.assertAll(t)
+}
+
+// =====================================================================
+// renderTextPDF
+// =====================================================================
+
+func TestRenderTextPDF_Paginates(t *testing.T) {
+	lines := make([]string, pdfLinesPerPage+1)
+	for i := range lines {
+		lines[i] = "baris"
+	}
+
+	pdf := string(renderTextPDF(lines))
+
+	assert.Contains(t, pdf, "/Count 2")
+	assert.Contains(t, pdf, "(Page 2 of 2)")
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+)
+
+// importRecord is one statement entry as read from the file, before any
+// category mapping. Amount is signed unless Type was stated explicitly.
+type importRecord struct {
+	Line        int
+	Date        time.Time
+	Amount      money.Amount
+	Type        model.CategoryType
+	Description string
+	Label       string
+	Err         string
+}
+
+var (
+	qifDateLayouts = []string{"01/02/2006", "1/2/2006", "01/02/06", "1/2/06", "2006-01-02"}
+	ofxTagPattern  = regexp.MustCompile(`<(/?)([A-Za-z0-9.]+)>([^<]*)`)
+	amountPattern  = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)$`)
+)
+
+// parseStatement reads the file in req.Content. File-level problems are
+// returned as errors; row-level problems are recorded on the row.
+func parseStatement(req dto.ImportsRequest) ([]importRecord, error) {
+	switch model.ImportFormat(strings.ToLower(req.Format)) {
+	case model.ImportCSV:
+		return parseCSV(req.Content, req.CSV)
+	case model.ImportOFX:
+		return parseOFX(req.Content)
+	case model.ImportQIF:
+		return parseQIF(req.Content, req.DateFormat, req.DecimalSeparator)
+	default:
+		return nil, fmt.Errorf("invalid import format [format=%s]", req.Format)
+	}
+}
+
+func parseCSV(content string, mapping dto.ImportCSVMapping) ([]importRecord, error) {
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if mapping.Delimiter != "" {
+		delimiter := []rune(mapping.Delimiter)
+		if len(delimiter) != 1 {
+			return nil, fmt.Errorf("invalid csv delimiter [delimiter=%s]", mapping.Delimiter)
+		}
+		reader.Comma = delimiter[0]
+	}
+	if err := validateDecimalSeparator(mapping.DecimalSeparator); err != nil {
+		return nil, err
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid import file: read csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	// column resolves a mapped header; required columns and explicit mappings must exist
+	column := func(name, fallback string, required bool) (int, error) {
+		explicit := name != ""
+		if !explicit {
+			name = fallback
+		}
+		if name == "" {
+			return -1, nil
+		}
+		if i, ok := columns[strings.ToLower(name)]; ok {
+			return i, nil
+		}
+		if required || explicit {
+			return -1, fmt.Errorf("invalid import file: csv column %q not found", name)
+		}
+		return -1, nil
+	}
+
+	dateCol, err := column(mapping.Date, "date", true)
+	if err != nil {
+		return nil, err
+	}
+	amountCol, err := column(mapping.Amount, "amount", true)
+	if err != nil {
+		return nil, err
+	}
+	descriptionCol, err := column(mapping.Description, "description", false)
+	if err != nil {
+		return nil, err
+	}
+	categoryCol, err := column(mapping.Category, "category", false)
+	if err != nil {
+		return nil, err
+	}
+	typeCol, err := column(mapping.Type, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	dateFormat := mapping.DateFormat
+	if dateFormat == "" {
+		dateFormat = "2006-01-02"
+	}
+
+	var records []importRecord
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid import file: %w", err)
+		}
+
+		field := func(i int) string {
+			if i < 0 || i >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[i])
+		}
+		if strings.Join(fields, "") == "" {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		record := importRecord{
+			Line:        line,
+			Description: field(descriptionCol),
+			Label:       field(categoryCol),
+		}
+
+		if record.Date, err = time.Parse(dateFormat, field(dateCol)); err != nil {
+			record.Err = fmt.Sprintf("invalid date %q", field(dateCol))
+		}
+		if record.Amount, err = parseImportAmount(field(amountCol), mapping.DecimalSeparator); err != nil && record.Err == "" {
+			record.Err = fmt.Sprintf("invalid amount %q", field(amountCol))
+		}
+		if typeCol >= 0 {
+			if record.Type = parseImportType(field(typeCol)); record.Type == "" && record.Err == "" {
+				record.Err = fmt.Sprintf("invalid type %q", field(typeCol))
+			}
+			record.Amount = record.Amount.Abs()
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// parseOFX reads the STMTTRN entries of an OFX 1.x (SGML) or 2.x (XML) file.
+func parseOFX(content string) ([]importRecord, error) {
+	var (
+		records []importRecord
+		current map[string]string
+	)
+
+	for _, match := range ofxTagPattern.FindAllStringSubmatch(content, -1) {
+		closing, tag, value := match[1] == "/", strings.ToUpper(match[2]), strings.TrimSpace(match[3])
+
+		switch {
+		case tag == "STMTTRN" && !closing:
+			current = make(map[string]string)
+		case tag == "STMTTRN" && closing && current != nil:
+			records = append(records, ofxRecord(len(records)+1, current))
+			current = nil
+		case current != nil && !closing:
+			current[tag] = value
+		}
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("invalid import file: no OFX transactions found")
+	}
+	return records, nil
+}
+
+func ofxRecord(line int, fields map[string]string) importRecord {
+	record := importRecord{
+		Line:        line,
+		Description: joinDescription(fields["NAME"], fields["MEMO"]),
+	}
+
+	// DTPOSTED is YYYYMMDD[HHMMSS[.XXX]][[TZ]]; the calendar date is enough
+	posted := fields["DTPOSTED"]
+	if len(posted) < 8 {
+		record.Err = fmt.Sprintf("invalid date %q", posted)
+	} else if date, err := time.Parse("20060102", posted[:8]); err != nil {
+		record.Err = fmt.Sprintf("invalid date %q", posted)
+	} else {
+		record.Date = date
+	}
+
+	amount, err := parseImportAmount(fields["TRNAMT"], "")
+	if err != nil && record.Err == "" {
+		record.Err = fmt.Sprintf("invalid amount %q", fields["TRNAMT"])
+	}
+	record.Amount = amount
+
+	return record
+}
+
+// parseQIF reads a bank or cash QIF file. Records end with "^".
+func parseQIF(content, dateFormat, decimalSeparator string) ([]importRecord, error) {
+	if err := validateDecimalSeparator(decimalSeparator); err != nil {
+		return nil, err
+	}
+
+	layouts := qifDateLayouts
+	if dateFormat != "" {
+		layouts = []string{dateFormat}
+	}
+
+	var (
+		records      []importRecord
+		record       importRecord
+		date, amount string
+		payee, memo  string
+		started      bool
+	)
+
+	flush := func() {
+		if !started {
+			return
+		}
+		record.Description = joinDescription(payee, memo)
+
+		if parsed, ok := parseQIFDate(date, layouts); ok {
+			record.Date = parsed
+		} else {
+			record.Err = fmt.Sprintf("invalid date %q", date)
+		}
+		parsedAmount, err := parseImportAmount(amount, decimalSeparator)
+		if err != nil && record.Err == "" {
+			record.Err = fmt.Sprintf("invalid amount %q", amount)
+		}
+		record.Amount = parsedAmount
+
+		records = append(records, record)
+		record, date, amount, payee, memo, started = importRecord{}, "", "", "", "", false
+	}
+
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "!") {
+			continue
+		}
+		if line[0] == '^' {
+			flush()
+			continue
+		}
+
+		if !started {
+			record = importRecord{Line: i + 1}
+			started = true
+		}
+
+		value := strings.TrimSpace(line[1:])
+		switch line[0] {
+		case 'D':
+			date = value
+		case 'T', 'U':
+			if amount == "" {
+				amount = value
+			}
+		case 'P':
+			payee = value
+		case 'M':
+			memo = value
+		case 'L':
+			record.Label = value
+		}
+	}
+	flush()
+
+	if len(records) == 0 {
+		return nil, errors.New("invalid import file: no QIF transactions found")
+	}
+	return records, nil
+}
+
+// parseQIFDate accepts Quicken's apostrophe years (1/15'25) as well as plain layouts.
+func parseQIFDate(value string, layouts []string) (time.Time, bool) {
+	value = strings.ReplaceAll(strings.ReplaceAll(value, "' ", "/"), "'", "/")
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseImportAmount accepts thousands separators and accounting negatives like
+// (50.00). decimalSeparator is "." or ","; when empty it is detected per value:
+// the last of "." and "," wins when both appear, a repeated mark is a thousands
+// separator, and a single "," followed by exactly three digits is one too.
+func parseImportAmount(value, decimalSeparator string) (money.Amount, error) {
+	value = strings.NewReplacer(" ", "", "\u00a0", "", "'", "").Replace(strings.TrimSpace(value))
+	negative := strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")")
+	if negative {
+		value = "-" + strings.Trim(value, "()")
+	}
+
+	if decimalSeparator == "" {
+		decimalSeparator = detectDecimalSeparator(value)
+	}
+	thousands := ","
+	if decimalSeparator == "," {
+		thousands = "."
+	}
+	value = strings.ReplaceAll(value, thousands, "")
+	value = strings.ReplaceAll(value, decimalSeparator, ".")
+
+	// Statements write plain numbers; exponents are as suspect as NaN or hex
+	if !amountPattern.MatchString(value) {
+		return money.Zero, fmt.Errorf("invalid amount %q", value)
+	}
+	return money.Parse(value)
+}
+
+func detectDecimalSeparator(value string) string {
+	lastDot, lastComma := strings.LastIndex(value, "."), strings.LastIndex(value, ",")
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastComma > lastDot {
+			return ","
+		}
+		return "."
+	case lastComma >= 0:
+		if strings.Count(value, ",") > 1 || len(value)-lastComma-1 == 3 {
+			return "."
+		}
+		return ","
+	case lastDot >= 0 && strings.Count(value, ".") > 1:
+		return ","
+	default:
+		return "."
+	}
+}
+
+func validateDecimalSeparator(separator string) error {
+	switch separator {
+	case "", ".", ",":
+		return nil
+	default:
+		return fmt.Errorf("invalid decimal separator [separator=%s]", separator)
+	}
+}
+
+func parseImportType(value string) model.CategoryType {
+	switch strings.ToLower(value) {
+	case "income", "credit", "cr", "in":
+		return model.Income
+	case "expense", "debit", "dr", "out":
+		return model.Expense
+	default:
+		return ""
+	}
+}
+
+func joinDescription(name, memo string) string {
+	switch {
+	case name == "":
+		return memo
+	case memo == "" || memo == name:
+		return name
+	default:
+		return name + " - " + memo
+	}
+}
@@ -0,0 +1,522 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+)
+
+type ImportsService interface {
+	GetImports(ctx context.Context, userID string) ([]dto.ImportsResponse, error)
+	GetImportByID(ctx context.Context, userID, id string) (dto.ImportsResponse, error)
+	PreviewImport(ctx context.Context, userID string, req dto.ImportsRequest) (dto.ImportsResponse, error)
+	CommitImport(ctx context.Context, userID, id string) (dto.ImportsResponse, error)
+	UndoImport(ctx context.Context, userID, id string) (dto.ImportsResponse, error)
+}
+
+type importsService struct {
+	txManager        repository.TxManager
+	transactionRepo  repository.TransactionsRepository
+	categoryRepo     repository.CategoriesRepository
+	importRepo       repository.ImportsRepository
+	outboxRepository repository.OutboxRepository
+	walletClient     client.WalletClient
+	saga             *SagaOrchestrator
+	currencies       *currencyConverter
+	history          *historyRecorder
+	now              func() time.Time
+}
+
+func NewImportsService(txManager repository.TxManager, transactionRepo repository.TransactionsRepository, walletClient client.WalletClient, categoryRepo repository.CategoriesRepository, importRepo repository.ImportsRepository, outboxRepository repository.OutboxRepository, sagaRepo repository.SagaLogRepository, currencyRepo repository.CurrenciesRepository, historyRepo repository.TransactionHistoryRepository) ImportsService {
+	return &importsService{
+		txManager:        txManager,
+		transactionRepo:  transactionRepo,
+		categoryRepo:     categoryRepo,
+		importRepo:       importRepo,
+		outboxRepository: outboxRepository,
+		walletClient:     walletClient,
+		saga:             NewSagaOrchestrator(sagaRepo, walletClient),
+		currencies:       newCurrencyConverter(currencyRepo),
+		history:          newHistoryRecorder(historyRepo),
+		now:              time.Now,
+	}
+}
+
+func (import_serv *importsService) GetImports(ctx context.Context, userID string) ([]dto.ImportsResponse, error) {
+	if userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	imports, err := import_serv.importRepo.GetImportsByUserID(ctx, nil, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get imports [user_id=%s]: %w", userID, err)
+	}
+
+	responses := make([]dto.ImportsResponse, 0, len(imports))
+	for _, imp := range imports {
+		responses = append(responses, importResponse(imp, nil))
+	}
+
+	return responses, nil
+}
+
+func (import_serv *importsService) GetImportByID(ctx context.Context, userID, id string) (dto.ImportsResponse, error) {
+	imp, rows, err := import_serv.ownedImport(ctx, nil, userID, id)
+	if err != nil {
+		return dto.ImportsResponse{}, err
+	}
+
+	return importResponse(imp, rows), nil
+}
+
+// PreviewImport parses the file and maps every row to a category without
+// touching transactions. The result is stored so it can be committed as-is.
+func (import_serv *importsService) PreviewImport(ctx context.Context, userID string, req dto.ImportsRequest) (dto.ImportsResponse, error) {
+	if userID == "" {
+		return dto.ImportsResponse{}, ErrUnauthenticated
+	}
+
+	walletID, err := helper.ParseUUID(req.WalletID)
+	if err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("invalid wallet id [id=%s]: %w", req.WalletID, err)
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		return dto.ImportsResponse{}, fmt.Errorf("invalid import file: empty content")
+	}
+
+	records, err := parseStatement(req)
+	if err != nil {
+		return dto.ImportsResponse{}, err
+	}
+	if len(records) > data.IMPORT_MAX_ROWS {
+		return dto.ImportsResponse{}, fmt.Errorf("invalid import file: too many rows [rows=%d, max=%d]", len(records), data.IMPORT_MAX_ROWS)
+	}
+
+	categories, err := import_serv.categoryRepo.GetAllCategories(ctx, nil)
+	if err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("get categories: %w", err)
+	}
+	mapper := newImportCategoryMapper(categories, req)
+
+	rows := make([]dto.ImportRowResponse, 0, len(records))
+	imp := model.Imports{
+		UserID:    userID,
+		WalletID:  walletID,
+		Format:    model.ImportFormat(strings.ToLower(req.Format)),
+		FileName:  req.FileName,
+		Status:    model.ImportPreviewed,
+		TotalRows: len(records),
+	}
+	for _, record := range records {
+		row := mapper.row(record)
+		if row.Error == "" {
+			imp.ValidRows++
+			imp.BalanceDelta = imp.BalanceDelta.Add(importRowDelta(row))
+		}
+		rows = append(rows, row)
+	}
+
+	if imp.Rows, err = json.Marshal(rows); err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("preview import: marshal rows: %w", err)
+	}
+
+	imp, err = import_serv.importRepo.CreateImport(ctx, nil, imp)
+	if err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("preview import: insert to db: %w", err)
+	}
+
+	return importResponse(imp, rows), nil
+}
+
+// CommitImport inserts the valid rows of a previewed import in batches and
+// applies their net amount to the wallet with a single balance adjustment.
+func (import_serv *importsService) CommitImport(ctx context.Context, userID, id string) (dto.ImportsResponse, error) {
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := import_serv.saga.NewSaga(data.SAGA_TYPE_IMPORT_COMMIT)
+	committed := false
+	defer func() {
+		if !committed {
+			import_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
+	tx, err := import_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("commit import: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	imp, rows, err := import_serv.ownedImport(ctx, tx, userID, id)
+	if err != nil {
+		return dto.ImportsResponse{}, err
+	}
+	if imp.Status != model.ImportPreviewed {
+		return dto.ImportsResponse{}, fmt.Errorf("invalid import status [id=%s, status=%s]", id, imp.Status)
+	}
+
+	// Statement amounts are in the currency of the wallet they were exported from
+	currency, err := import_serv.currencies.walletCurrency(ctx, tx, imp.WalletID.String())
+	if err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("commit import [id=%s]: %w", id, err)
+	}
+
+	transactions := make([]model.Transactions, 0, imp.ValidRows)
+	for _, row := range rows {
+		if row.Error != "" {
+			continue
+		}
+
+		categoryID, err := helper.ParseUUID(row.CategoryID)
+		if err != nil {
+			return dto.ImportsResponse{}, fmt.Errorf("invalid category id [id=%s]: %w", row.CategoryID, err)
+		}
+
+		transactions = append(transactions, model.Transactions{
+			WalletID:        imp.WalletID,
+			CategoryID:      categoryID,
+			Amount:          row.Amount,
+			Currency:        currency,
+			TransactionDate: row.Date,
+			Description:     row.Description,
+			ImportID:        &imp.ID,
+			Category: model.Categories{
+				Base: model.Base{ID: categoryID},
+				Name: row.CategoryName,
+				Type: model.CategoryType(row.Type),
+			},
+		})
+	}
+	if len(transactions) == 0 {
+		return dto.ImportsResponse{}, fmt.Errorf("invalid import: no valid rows to commit [id=%s]", id)
+	}
+
+	// Apply the whole import to the wallet in one step
+	if !imp.BalanceDelta.IsZero() {
+		wallet, err := import_serv.walletClient.GetWalletByID(ctx, imp.WalletID.String())
+		if err != nil {
+			return dto.ImportsResponse{}, fmt.Errorf("wallet not found [id=%s]: %w", imp.WalletID, err)
+		}
+		if client.WalletBalance(wallet).Add(imp.BalanceDelta).IsNegative() {
+			return dto.ImportsResponse{}, fmt.Errorf("insufficient wallet balance [wallet_id=%s]", imp.WalletID)
+		}
+		if err := import_serv.saga.UpdateWalletBalance(ctx, saga, wallet, imp.BalanceDelta); err != nil {
+			return dto.ImportsResponse{}, fmt.Errorf("update wallet balance [wallet_id=%s]: %w", imp.WalletID, err)
+		}
+	}
+
+	created, err := import_serv.transactionRepo.CreateTransactions(ctx, tx, transactions, data.IMPORT_COMMIT_BATCH)
+	if err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("commit import: insert to db: %w", err)
+	}
+
+	if err := import_serv.history.RecordCreated(ctx, tx, created...); err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("commit import: %w", err)
+	}
+
+	for _, transaction := range created {
+		if err := import_serv.publish(ctx, tx, transaction, data.OUTBOX_EVENT_TRANSACTION_CREATED); err != nil {
+			return dto.ImportsResponse{}, fmt.Errorf("commit import: %w", err)
+		}
+	}
+
+	now := import_serv.now()
+	imp.Status = model.ImportCommitted
+	imp.CommittedAt = &now
+	if imp, err = import_serv.importRepo.UpdateImport(ctx, tx, imp); err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("commit import [id=%s]: update in db: %w", id, err)
+	}
+
+	if err := import_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("commit import: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("commit import: commit: %w", err)
+	}
+	committed = true
+
+	return importResponse(imp, rows), nil
+}
+
+// UndoImport deletes what is left of a committed import and reverts its
+// balance effect. Transactions already edited or deleted since the import are
+// reverted as they are now, so earlier manual changes are not applied twice.
+func (import_serv *importsService) UndoImport(ctx context.Context, userID, id string) (dto.ImportsResponse, error) {
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := import_serv.saga.NewSaga(data.SAGA_TYPE_IMPORT_UNDO)
+	committed := false
+	defer func() {
+		if !committed {
+			import_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
+	tx, err := import_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("undo import: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	imp, rows, err := import_serv.ownedImport(ctx, tx, userID, id)
+	if err != nil {
+		return dto.ImportsResponse{}, err
+	}
+	if imp.Status != model.ImportCommitted {
+		return dto.ImportsResponse{}, fmt.Errorf("invalid import status [id=%s, status=%s]", id, imp.Status)
+	}
+
+	transactions, err := import_serv.transactionRepo.GetTransactionsByImportID(ctx, tx, id)
+	if err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("undo import [id=%s]: %w", id, err)
+	}
+
+	// Transactions may have been moved to another wallet since the import
+	deltas := make(map[uuid.UUID]money.Amount)
+	var walletIDs []uuid.UUID
+	for _, transaction := range transactions {
+		var delta money.Amount
+		switch transaction.Category.Type {
+		case model.Income:
+			delta = transaction.Amount.Neg()
+		case model.Expense:
+			delta = transaction.Amount
+		default:
+			continue
+		}
+		if _, ok := deltas[transaction.WalletID]; !ok {
+			walletIDs = append(walletIDs, transaction.WalletID)
+		}
+		deltas[transaction.WalletID] = deltas[transaction.WalletID].Add(delta)
+	}
+
+	for _, walletID := range walletIDs {
+		delta := deltas[walletID]
+		if delta.IsZero() {
+			continue
+		}
+
+		wallet, err := import_serv.walletClient.GetWalletByID(ctx, walletID.String())
+		if err != nil {
+			return dto.ImportsResponse{}, fmt.Errorf("wallet not found [id=%s]: %w", walletID, err)
+		}
+		if client.WalletBalance(wallet).Add(delta).IsNegative() {
+			return dto.ImportsResponse{}, fmt.Errorf("insufficient wallet balance [wallet_id=%s]", walletID)
+		}
+		if err := import_serv.saga.UpdateWalletBalance(ctx, saga, wallet, delta); err != nil {
+			return dto.ImportsResponse{}, fmt.Errorf("update wallet balance [wallet_id=%s]: %w", walletID, err)
+		}
+	}
+
+	if _, err := import_serv.transactionRepo.DeleteTransactionsByImportID(ctx, tx, id); err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("undo import [id=%s]: delete in db: %w", id, err)
+	}
+
+	if err := import_serv.history.RecordDeleted(ctx, tx, transactions...); err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("undo import: %w", err)
+	}
+
+	for _, transaction := range transactions {
+		if err := import_serv.publish(ctx, tx, transaction, data.OUTBOX_EVENT_TRANSACTION_DELETED); err != nil {
+			return dto.ImportsResponse{}, fmt.Errorf("undo import: %w", err)
+		}
+	}
+
+	now := import_serv.now()
+	imp.Status = model.ImportUndone
+	imp.UndoneAt = &now
+	if imp, err = import_serv.importRepo.UpdateImport(ctx, tx, imp); err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("undo import [id=%s]: update in db: %w", id, err)
+	}
+
+	if err := import_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("undo import: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.ImportsResponse{}, fmt.Errorf("undo import: commit: %w", err)
+	}
+	committed = true
+
+	return importResponse(imp, rows), nil
+}
+
+// ownedImport loads an import with its rows and checks that it belongs to userID.
+func (import_serv *importsService) ownedImport(ctx context.Context, tx repository.Transaction, userID, id string) (model.Imports, []dto.ImportRowResponse, error) {
+	if userID == "" {
+		return model.Imports{}, nil, ErrUnauthenticated
+	}
+
+	imp, err := import_serv.importRepo.GetImportByID(ctx, tx, id)
+	if err != nil {
+		return model.Imports{}, nil, fmt.Errorf("import not found [id=%s]: %w", id, err)
+	}
+	if imp.UserID != userID {
+		return model.Imports{}, nil, fmt.Errorf("%w: import does not belong to user [import_id=%s, user_id=%s]", ErrPermissionDenied, id, userID)
+	}
+
+	var rows []dto.ImportRowResponse
+	if err := json.Unmarshal(imp.Rows, &rows); err != nil {
+		return model.Imports{}, nil, fmt.Errorf("unmarshal import rows [id=%s]: %w", id, err)
+	}
+
+	return imp, rows, nil
+}
+
+func (import_serv *importsService) publish(ctx context.Context, tx repository.Transaction, transaction model.Transactions, eventType string) error {
+	payload, err := json.Marshal(helper.ConvertToResponseType(transaction).(dto.TransactionsResponse))
+	if err != nil {
+		return fmt.Errorf("marshal transaction response [id=%s]: %w", transaction.ID, err)
+	}
+
+	return import_serv.outboxRepository.Create(ctx, tx, &model.OutboxMessage{
+		AggregateID: transaction.ID.String(),
+		EventType:   eventType,
+		Payload:     payload,
+		Published:   false,
+		MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+	})
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Category mapping
+// ──────────────────────────────────────────────────────────────────────────────
+
+// importCategoryMapper resolves a row's category from, in order: the explicit
+// category map, a category with the same name and type, or the default for
+// the row's type.
+type importCategoryMapper struct {
+	byID       map[string]model.Categories
+	byName     map[string][]model.Categories
+	mapping    map[string]string
+	defaultIDs map[model.CategoryType]string
+}
+
+func newImportCategoryMapper(categories []model.Categories, req dto.ImportsRequest) *importCategoryMapper {
+	mapper := &importCategoryMapper{
+		byID:    make(map[string]model.Categories, len(categories)),
+		byName:  make(map[string][]model.Categories, len(categories)),
+		mapping: make(map[string]string, len(req.CategoryMap)),
+		defaultIDs: map[model.CategoryType]string{
+			model.Income:  req.DefaultIncomeCategoryID,
+			model.Expense: req.DefaultExpenseCategoryID,
+		},
+	}
+	for _, category := range categories {
+		mapper.byID[category.ID.String()] = category
+		name := strings.ToLower(category.Name)
+		mapper.byName[name] = append(mapper.byName[name], category)
+	}
+	for label, categoryID := range req.CategoryMap {
+		mapper.mapping[strings.ToLower(strings.TrimSpace(label))] = categoryID
+	}
+
+	return mapper
+}
+
+func (mapper *importCategoryMapper) row(record importRecord) dto.ImportRowResponse {
+	row := dto.ImportRowResponse{
+		Line:        record.Line,
+		Date:        record.Date,
+		Amount:      record.Amount.Abs(),
+		Description: record.Description,
+		Label:       record.Label,
+		Error:       record.Err,
+	}
+
+	categoryType := record.Type
+	if categoryType == "" {
+		categoryType = model.Income
+		if record.Amount.IsNegative() {
+			categoryType = model.Expense
+		}
+	}
+	row.Type = string(categoryType)
+
+	if row.Error != "" {
+		return row
+	}
+	if row.Amount.IsZero() {
+		row.Error = "invalid amount: must not be zero"
+		return row
+	}
+
+	category, err := mapper.resolve(record.Label, categoryType)
+	if err != "" {
+		row.Error = err
+		return row
+	}
+	row.CategoryID = category.ID.String()
+	row.CategoryName = category.Name
+
+	return row
+}
+
+func (mapper *importCategoryMapper) resolve(label string, categoryType model.CategoryType) (model.Categories, string) {
+	key := strings.ToLower(strings.TrimSpace(label))
+
+	if categoryID, ok := mapper.mapping[key]; ok && key != "" {
+		category, ok := mapper.byID[categoryID]
+		if !ok {
+			return model.Categories{}, fmt.Sprintf("category not found [id=%s]", categoryID)
+		}
+		if category.Type != categoryType {
+			return model.Categories{}, fmt.Sprintf("invalid category type for %s row [category=%s]", categoryType, category.Name)
+		}
+		return category, ""
+	}
+
+	for _, category := range mapper.byName[key] {
+		if category.Type == categoryType {
+			return category, ""
+		}
+	}
+
+	if categoryID := mapper.defaultIDs[categoryType]; categoryID != "" {
+		category, ok := mapper.byID[categoryID]
+		if !ok || category.Type != categoryType {
+			return model.Categories{}, fmt.Sprintf("invalid default %s category [id=%s]", categoryType, categoryID)
+		}
+		return category, ""
+	}
+
+	return model.Categories{}, fmt.Sprintf("no %s category mapped for %q", categoryType, label)
+}
+
+func importRowDelta(row dto.ImportRowResponse) money.Amount {
+	if model.CategoryType(row.Type) == model.Expense {
+		return row.Amount.Neg()
+	}
+	return row.Amount
+}
+
+func importResponse(imp model.Imports, rows []dto.ImportRowResponse) dto.ImportsResponse {
+	return dto.ImportsResponse{
+		ID:           imp.ID.String(),
+		WalletID:     imp.WalletID.String(),
+		Format:       string(imp.Format),
+		FileName:     imp.FileName,
+		Status:       string(imp.Status),
+		TotalRows:    imp.TotalRows,
+		ValidRows:    imp.ValidRows,
+		InvalidRows:  imp.TotalRows - imp.ValidRows,
+		BalanceDelta: imp.BalanceDelta,
+		Rows:         rows,
+		CreatedAt:    imp.CreatedAt,
+		CommittedAt:  imp.CommittedAt,
+		UndoneAt:     imp.UndoneAt,
+	}
+}
@@ -0,0 +1,469 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type importTestDeps struct {
+	txManager       *mocks.MockTxManager
+	transactionRepo *mocks.MockTransactionsRepository
+	categoryRepo    *mocks.MockCategoriesRepository
+	importRepo      *mocks.MockImportsRepository
+	outboxRepo      *mocks.MockOutboxRepository
+	sagaRepo        *mocks.MockSagaLogRepository
+	walletClient    *mocks.MockWalletClient
+	currencyRepo    *mocks.MockCurrenciesRepository
+	historyRepo     *mocks.MockTransactionHistoryRepository
+	tx              *mocks.MockTransaction
+}
+
+func newImportTestDeps() *importTestDeps {
+	d := &importTestDeps{
+		txManager:       new(mocks.MockTxManager),
+		transactionRepo: new(mocks.MockTransactionsRepository),
+		categoryRepo:    new(mocks.MockCategoriesRepository),
+		importRepo:      new(mocks.MockImportsRepository),
+		outboxRepo:      new(mocks.MockOutboxRepository),
+		sagaRepo:        new(mocks.MockSagaLogRepository),
+		walletClient:    new(mocks.MockWalletClient),
+		currencyRepo:    new(mocks.MockCurrenciesRepository),
+		historyRepo:     new(mocks.MockTransactionHistoryRepository),
+		tx:              new(mocks.MockTransaction),
+	}
+	d.currencyRepo.On("GetWalletCurrency", mock.Anything, mock.Anything, mock.Anything).Return("", nil).Maybe()
+	// History entries are covered in transactionHistory_test.go
+	d.historyRepo.On("CreateHistory", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return d
+}
+
+func (d *importTestDeps) service() ImportsService {
+	return &importsService{
+		txManager:        d.txManager,
+		transactionRepo:  d.transactionRepo,
+		categoryRepo:     d.categoryRepo,
+		importRepo:       d.importRepo,
+		outboxRepository: d.outboxRepo,
+		walletClient:     d.walletClient,
+		saga:             NewSagaOrchestrator(d.sagaRepo, d.walletClient),
+		currencies:       newCurrencyConverter(d.currencyRepo),
+		history:          newHistoryRecorder(d.historyRepo),
+		now:              func() time.Time { return txnFixTime },
+	}
+}
+
+func (d *importTestDeps) assertAll(t *testing.T) {
+	t.Helper()
+	d.txManager.AssertExpectations(t)
+	d.transactionRepo.AssertExpectations(t)
+	d.categoryRepo.AssertExpectations(t)
+	d.importRepo.AssertExpectations(t)
+	d.outboxRepo.AssertExpectations(t)
+	d.sagaRepo.AssertExpectations(t)
+	d.walletClient.AssertExpectations(t)
+	d.currencyRepo.AssertExpectations(t)
+	d.historyRepo.AssertExpectations(t)
+	d.tx.AssertExpectations(t)
+}
+
+func (d *importTestDeps) expectTx() {
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+}
+
+func (d *importTestDeps) expectSagaLog(status model.SagaStatus) {
+	d.sagaRepo.On("CreateSaga", mock.Anything, nil, mock.Anything).Return(nil).Once()
+	d.sagaRepo.On("CreateStep", mock.Anything, nil, mock.Anything).Return(nil)
+	d.sagaRepo.On("UpdateStepStatus", mock.Anything, nil, mock.Anything, mock.Anything).Return(nil)
+	if status == model.SagaCompleted {
+		d.sagaRepo.On("CompleteSaga", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+		return
+	}
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, mock.Anything, mock.Anything).Return(true, nil).Once()
+	d.sagaRepo.On("UpdateSagaStatus", mock.Anything, mock.Anything, mock.Anything, status, mock.Anything).Return(nil).Once()
+}
+
+// ─────────────────────────────────────────────
+// Fixed UUIDs & Sample Data
+// ─────────────────────────────────────────────
+
+var (
+	importTestID   = uuid.MustParse("77777777-7777-7777-7777-777777777777")
+	importFoodID   = uuid.MustParse("00000000-0000-0000-0000-000000000101")
+	importSalaryID = uuid.MustParse("00000000-0000-0000-0000-000000000102")
+	importOtherID  = uuid.MustParse("00000000-0000-0000-0000-000000000103")
+)
+
+func sampleImportCategories() []model.Categories {
+	return []model.Categories{
+		{Base: model.Base{ID: importFoodID}, Name: "Makanan", Type: model.Expense},
+		{Base: model.Base{ID: importSalaryID}, Name: "Gaji", Type: model.Income},
+		{Base: model.Base{ID: importOtherID}, Name: "Lainnya", Type: model.Expense},
+	}
+}
+
+func sampleImportRows() []dto.ImportRowResponse {
+	return []dto.ImportRowResponse{
+		{Line: 2, Date: txnFixTime, Amount: money.New(5000000), Type: "income", CategoryID: importSalaryID.String(), CategoryName: "Gaji"},
+		{Line: 3, Date: txnFixTime, Amount: money.New(50000), Type: "expense", CategoryID: importFoodID.String(), CategoryName: "Makanan"},
+		{Line: 4, Amount: money.New(10000), Type: "expense", Error: "invalid date \"kemarin\""},
+	}
+}
+
+func sampleImportModel(status model.ImportStatus) model.Imports {
+	rows, _ := json.Marshal(sampleImportRows())
+	return model.Imports{
+		Base:         model.Base{ID: importTestID},
+		UserID:       authzUserID,
+		WalletID:     walletTestID,
+		Format:       model.ImportCSV,
+		Status:       status,
+		TotalRows:    3,
+		ValidRows:    2,
+		BalanceDelta: money.New(4950000),
+		Rows:         rows,
+	}
+}
+
+// =====================================================================
+// Parsers
+// =====================================================================
+
+func TestParseCSV_ColumnMapping(t *testing.T) {
+	content := "Tanggal;Nominal;Keterangan;Jenis\n15/06/2025;1,500.50;Kopi;debit\n16/06/2025;abc;Gaji;credit\n"
+
+	records, err := parseCSV(content, dto.ImportCSVMapping{
+		Date:        "tanggal",
+		Amount:      "Nominal",
+		Description: "Keterangan",
+		Type:        "Jenis",
+		DateFormat:  "02/01/2006",
+		Delimiter:   ";",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, 2, records[0].Line)
+	assert.Equal(t, time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC), records[0].Date)
+	assert.Equal(t, money.MustParse("1500.5"), records[0].Amount)
+	assert.Equal(t, model.Expense, records[0].Type)
+	assert.Empty(t, records[0].Err)
+	assert.Contains(t, records[1].Err, "invalid amount")
+}
+
+func TestParseCSV_MissingColumn(t *testing.T) {
+	_, err := parseCSV("when,amount\n2025-06-15,10\n", dto.ImportCSVMapping{})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid import file")
+}
+
+func TestParseCSV_DecimalSeparator(t *testing.T) {
+	content := "date;amount\n2025-06-15;12.500,00\n2025-06-16;1.500\n"
+
+	records, err := parseCSV(content, dto.ImportCSVMapping{Delimiter: ";", DecimalSeparator: ","})
+
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, money.New(12500), records[0].Amount)
+	assert.Equal(t, money.New(1500), records[1].Amount)
+}
+
+func TestParseCSV_InvalidDecimalSeparator(t *testing.T) {
+	_, err := parseCSV("date,amount\n2025-06-15,10\n", dto.ImportCSVMapping{DecimalSeparator: "'"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid decimal separator")
+}
+
+func TestParseImportAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		separator string
+		want      money.Amount
+		wantErr   bool
+	}{
+		{name: "plain", value: "1500.50", want: money.MustParse("1500.5")},
+		{name: "us thousands", value: "1,500.50", want: money.MustParse("1500.5")},
+		{name: "id thousands and decimals", value: "12.500,00", want: money.MustParse("12500")},
+		{name: "id repeated thousands", value: "1.500.000", want: money.MustParse("1500000")},
+		{name: "us repeated thousands", value: "1,500,000", want: money.MustParse("1500000")},
+		{name: "comma decimals", value: "12,5", want: money.MustParse("12.5")},
+		{name: "comma thousands", value: "1,500", want: money.MustParse("1500")},
+		{name: "accounting negative", value: "(1.250,75)", want: money.MustParse("-1250.75")},
+		{name: "spaced thousands", value: "1 500 000", want: money.MustParse("1500000")},
+		{name: "explicit comma decimal", value: "1.500", separator: ",", want: money.MustParse("1500")},
+		{name: "explicit dot decimal", value: "1,500", separator: ".", want: money.MustParse("1500")},
+		{name: "nan", value: "NaN", wantErr: true},
+		{name: "inf", value: "-Inf", wantErr: true},
+		{name: "hex float", value: "0x1p4", wantErr: true},
+		{name: "overflow", value: "1e400", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseImportAmount(tt.value, tt.separator)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseOFX_SGML(t *testing.T) {
+	content := `OFXHEADER:100
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250615120000.000[+7:WIB]
+<TRNAMT>-50000.00
+<NAME>Indomaret
+<MEMO>Belanja
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20250601
+<TRNAMT>5000000
+<NAME>Gaji
+</STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`
+
+	records, err := parseOFX(content)
+
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, money.New(-50000), records[0].Amount)
+	assert.Equal(t, "Indomaret - Belanja", records[0].Description)
+	assert.Equal(t, time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC), records[0].Date)
+	assert.Equal(t, money.New(5000000), records[1].Amount)
+}
+
+func TestParseQIF_Bank(t *testing.T) {
+	content := "!Type:Bank\nD06/15'25\nT-1,250.00\nPIndomaret\nLMakanan\n^\nD6/16/2025\nU300\nMTransfer masuk\n^\n"
+
+	records, err := parseQIF(content, "", "")
+
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC), records[0].Date)
+	assert.Equal(t, money.New(-1250), records[0].Amount)
+	assert.Equal(t, "Makanan", records[0].Label)
+	assert.Equal(t, "Transfer masuk", records[1].Description)
+	assert.Empty(t, records[1].Err)
+}
+
+// =====================================================================
+// PreviewImport
+// =====================================================================
+
+func TestPreviewImport_MapsCategories(t *testing.T) {
+	d := newImportTestDeps()
+	svc := d.service()
+
+	req := dto.ImportsRequest{
+		WalletID:                 walletTestID.String(),
+		Format:                   "csv",
+		Content:                  "date,amount,description,category\n2025-06-15,-50000,Nasi,makanan\n2025-06-16,5000000,Payroll,Salary\n2025-06-17,-20000,Parkir,\n2025-06-18,-1,Aneh,Gaji\n",
+		CategoryMap:              map[string]string{"Salary": importSalaryID.String()},
+		DefaultExpenseCategoryID: importOtherID.String(),
+	}
+
+	d.categoryRepo.On("GetAllCategories", mock.Anything, nil).Return(sampleImportCategories(), nil)
+	d.importRepo.On("CreateImport", mock.Anything, nil, mock.MatchedBy(func(imp model.Imports) bool {
+		return imp.UserID == authzUserID && imp.Status == model.ImportPreviewed && imp.TotalRows == 4 && imp.ValidRows == 4
+	})).Return(sampleImportModel(model.ImportPreviewed), nil)
+
+	result, err := svc.PreviewImport(context.Background(), authzUserID, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Rows, 4)
+	assert.Equal(t, importFoodID.String(), result.Rows[0].CategoryID)
+	assert.Equal(t, importSalaryID.String(), result.Rows[1].CategoryID)
+	assert.Equal(t, importOtherID.String(), result.Rows[2].CategoryID)
+	// "Gaji" is an income category, so the expense row falls back to the default
+	assert.Equal(t, importOtherID.String(), result.Rows[3].CategoryID)
+	d.assertAll(t)
+}
+
+func TestPreviewImport_UnmappedCategory(t *testing.T) {
+	d := newImportTestDeps()
+	svc := d.service()
+
+	req := dto.ImportsRequest{
+		WalletID: walletTestID.String(),
+		Format:   "csv",
+		Content:  "date,amount,category\n2025-06-15,-50000,Bensin\n",
+	}
+
+	d.categoryRepo.On("GetAllCategories", mock.Anything, nil).Return(sampleImportCategories(), nil)
+	d.importRepo.On("CreateImport", mock.Anything, nil, mock.MatchedBy(func(imp model.Imports) bool {
+		return imp.ValidRows == 0 && imp.BalanceDelta == money.New(0)
+	})).Return(model.Imports{}, nil)
+
+	result, err := svc.PreviewImport(context.Background(), authzUserID, req)
+
+	assert.NoError(t, err)
+	assert.Contains(t, result.Rows[0].Error, "no expense category mapped")
+	d.assertAll(t)
+}
+
+func TestPreviewImport_InvalidFormat(t *testing.T) {
+	d := newImportTestDeps()
+	svc := d.service()
+
+	_, err := svc.PreviewImport(context.Background(), authzUserID, dto.ImportsRequest{
+		WalletID: walletTestID.String(),
+		Format:   "xlsx",
+		Content:  "data",
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid import format")
+	d.assertAll(t)
+}
+
+// =====================================================================
+// CommitImport
+// =====================================================================
+
+func TestCommitImport_SingleBalanceAdjustment(t *testing.T) {
+	d := newImportTestDeps()
+	svc := d.service()
+	d.expectTx()
+	d.expectSagaLog(model.SagaCompleted)
+
+	created := []model.Transactions{
+		{Base: model.Base{ID: uuid.New()}, WalletID: walletTestID, Amount: money.New(5000000), Category: sampleImportCategories()[1]},
+		{Base: model.Base{ID: uuid.New()}, WalletID: walletTestID, Amount: money.New(50000), Category: sampleImportCategories()[0]},
+	}
+
+	d.importRepo.On("GetImportByID", mock.Anything, d.tx, importTestID.String()).Return(sampleImportModel(model.ImportPreviewed), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 100000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(4950000), mock.Anything).Return(sampleWalletProto(walletTestID, 5050000), nil).Once()
+	d.transactionRepo.On("CreateTransactions", mock.Anything, d.tx, mock.MatchedBy(func(transactions []model.Transactions) bool {
+		return len(transactions) == 2 && *transactions[0].ImportID == importTestID
+	}), data.IMPORT_COMMIT_BATCH).Return(created, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(m *model.OutboxMessage) bool {
+		return m.EventType == data.OUTBOX_EVENT_TRANSACTION_CREATED
+	})).Return(nil).Twice()
+	d.importRepo.On("UpdateImport", mock.Anything, d.tx, mock.MatchedBy(func(imp model.Imports) bool {
+		return imp.Status == model.ImportCommitted && imp.CommittedAt != nil
+	})).Return(sampleImportModel(model.ImportCommitted), nil)
+
+	result, err := svc.CommitImport(context.Background(), authzUserID, importTestID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(model.ImportCommitted), result.Status)
+	d.assertAll(t)
+}
+
+func TestCommitImport_AlreadyCommitted(t *testing.T) {
+	d := newImportTestDeps()
+	svc := d.service()
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	d.importRepo.On("GetImportByID", mock.Anything, d.tx, importTestID.String()).Return(sampleImportModel(model.ImportCommitted), nil)
+
+	_, err := svc.CommitImport(context.Background(), authzUserID, importTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid import status")
+	d.transactionRepo.AssertNotCalled(t, "CreateTransactions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCommitImport_ForeignImportDenied(t *testing.T) {
+	d := newImportTestDeps()
+	svc := d.service()
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	imp := sampleImportModel(model.ImportPreviewed)
+	imp.UserID = "user-2"
+	d.importRepo.On("GetImportByID", mock.Anything, d.tx, importTestID.String()).Return(imp, nil)
+
+	_, err := svc.CommitImport(context.Background(), authzUserID, importTestID.String())
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// UndoImport
+// =====================================================================
+
+func TestUndoImport_RevertsRemainingTransactions(t *testing.T) {
+	d := newImportTestDeps()
+	svc := d.service()
+	d.expectTx()
+	d.expectSagaLog(model.SagaCompleted)
+
+	// The imported expense was already deleted by hand; only the income is left
+	remaining := []model.Transactions{
+		{Base: model.Base{ID: uuid.New()}, WalletID: walletTestID, Amount: money.New(5000000), Category: sampleImportCategories()[1]},
+	}
+
+	d.importRepo.On("GetImportByID", mock.Anything, d.tx, importTestID.String()).Return(sampleImportModel(model.ImportCommitted), nil)
+	d.transactionRepo.On("GetTransactionsByImportID", mock.Anything, d.tx, importTestID.String()).Return(remaining, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 6000000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-5000000), mock.Anything).Return(sampleWalletProto(walletTestID, 1000000), nil).Once()
+	d.transactionRepo.On("DeleteTransactionsByImportID", mock.Anything, d.tx, importTestID.String()).Return(int64(1), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(m *model.OutboxMessage) bool {
+		return m.EventType == data.OUTBOX_EVENT_TRANSACTION_DELETED
+	})).Return(nil).Once()
+	d.importRepo.On("UpdateImport", mock.Anything, d.tx, mock.MatchedBy(func(imp model.Imports) bool {
+		return imp.Status == model.ImportUndone && imp.UndoneAt != nil
+	})).Return(sampleImportModel(model.ImportUndone), nil)
+
+	result, err := svc.UndoImport(context.Background(), authzUserID, importTestID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(model.ImportUndone), result.Status)
+	d.assertAll(t)
+}
+
+func TestUndoImport_InsufficientBalance(t *testing.T) {
+	d := newImportTestDeps()
+	svc := d.service()
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	remaining := []model.Transactions{
+		{Base: model.Base{ID: uuid.New()}, WalletID: walletTestID, Amount: money.New(5000000), Category: sampleImportCategories()[1]},
+	}
+
+	d.importRepo.On("GetImportByID", mock.Anything, d.tx, importTestID.String()).Return(sampleImportModel(model.ImportCommitted), nil)
+	d.transactionRepo.On("GetTransactionsByImportID", mock.Anything, d.tx, importTestID.String()).Return(remaining, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 100000), nil)
+
+	_, err := svc.UndoImport(context.Background(), authzUserID, importTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient wallet balance")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
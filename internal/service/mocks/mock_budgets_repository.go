@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBudgetsRepository struct {
+	mock.Mock
+}
+
+func (m *MockBudgetsRepository) GetBudgetsByUserID(ctx context.Context, tx repository.Transaction, userID string) ([]model.Budgets, error) {
+	args := m.Called(ctx, tx, userID)
+	return args.Get(0).([]model.Budgets), args.Error(1)
+}
+
+func (m *MockBudgetsRepository) GetBudgetsByCategoryIDs(ctx context.Context, tx repository.Transaction, categoryIDs []string) ([]model.Budgets, error) {
+	args := m.Called(ctx, tx, categoryIDs)
+	return args.Get(0).([]model.Budgets), args.Error(1)
+}
+
+func (m *MockBudgetsRepository) GetBudgetByID(ctx context.Context, tx repository.Transaction, id string) (model.Budgets, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(model.Budgets), args.Error(1)
+}
+
+func (m *MockBudgetsRepository) GetSpent(ctx context.Context, tx repository.Transaction, categoryID string, walletIDs []string, from, to time.Time) (money.Amount, error) {
+	args := m.Called(ctx, tx, categoryID, walletIDs, from, to)
+	return args.Get(0).(money.Amount), args.Error(1)
+}
+
+func (m *MockBudgetsRepository) CreateBudget(ctx context.Context, tx repository.Transaction, budget model.Budgets) (model.Budgets, error) {
+	args := m.Called(ctx, tx, budget)
+	return args.Get(0).(model.Budgets), args.Error(1)
+}
+
+func (m *MockBudgetsRepository) UpdateBudget(ctx context.Context, tx repository.Transaction, budget model.Budgets) (model.Budgets, error) {
+	args := m.Called(ctx, tx, budget)
+	return args.Get(0).(model.Budgets), args.Error(1)
+}
+
+func (m *MockBudgetsRepository) DeleteBudget(ctx context.Context, tx repository.Transaction, budget model.Budgets) (model.Budgets, error) {
+	args := m.Called(ctx, tx, budget)
+	return args.Get(0).(model.Budgets), args.Error(1)
+}
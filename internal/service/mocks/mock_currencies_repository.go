@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCurrenciesRepository struct {
+	mock.Mock
+}
+
+func (m *MockCurrenciesRepository) GetFXRates(ctx context.Context, tx repository.Transaction, baseCurrency, quoteCurrency string) ([]model.FXRates, error) {
+	args := m.Called(ctx, tx, baseCurrency, quoteCurrency)
+	return args.Get(0).([]model.FXRates), args.Error(1)
+}
+
+func (m *MockCurrenciesRepository) GetFXRate(ctx context.Context, tx repository.Transaction, baseCurrency, quoteCurrency string, on time.Time) (model.FXRates, error) {
+	args := m.Called(ctx, tx, baseCurrency, quoteCurrency, on)
+	return args.Get(0).(model.FXRates), args.Error(1)
+}
+
+func (m *MockCurrenciesRepository) UpsertFXRates(ctx context.Context, tx repository.Transaction, rates []model.FXRates) ([]model.FXRates, error) {
+	args := m.Called(ctx, tx, rates)
+	return args.Get(0).([]model.FXRates), args.Error(1)
+}
+
+func (m *MockCurrenciesRepository) GetWalletCurrency(ctx context.Context, tx repository.Transaction, walletID string) (string, error) {
+	args := m.Called(ctx, tx, walletID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCurrenciesRepository) SetWalletCurrency(ctx context.Context, tx repository.Transaction, walletCurrency model.WalletCurrencies) (model.WalletCurrencies, error) {
+	args := m.Called(ctx, tx, walletCurrency)
+	return args.Get(0).(model.WalletCurrencies), args.Error(1)
+}
+
+func (m *MockCurrenciesRepository) CountWalletTransactionsNotIn(ctx context.Context, tx repository.Transaction, walletID, currency string) (int64, error) {
+	args := m.Called(ctx, tx, walletID, currency)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDebtsRepository struct {
+	mock.Mock
+}
+
+func (m *MockDebtsRepository) GetDebtsByWalletIDs(ctx context.Context, tx repository.Transaction, walletIDs []string, status model.DebtStatus) ([]model.Debts, error) {
+	args := m.Called(ctx, tx, walletIDs, status)
+	return args.Get(0).([]model.Debts), args.Error(1)
+}
+
+func (m *MockDebtsRepository) GetOverdueDebts(ctx context.Context, tx repository.Transaction, walletIDs []string, due time.Time) ([]model.Debts, error) {
+	args := m.Called(ctx, tx, walletIDs, due)
+	return args.Get(0).([]model.Debts), args.Error(1)
+}
+
+func (m *MockDebtsRepository) GetDebtByID(ctx context.Context, tx repository.Transaction, id string) (model.Debts, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(model.Debts), args.Error(1)
+}
+
+func (m *MockDebtsRepository) CreateDebt(ctx context.Context, tx repository.Transaction, debt model.Debts) (model.Debts, error) {
+	args := m.Called(ctx, tx, debt)
+	return args.Get(0).(model.Debts), args.Error(1)
+}
+
+func (m *MockDebtsRepository) UpdateDebt(ctx context.Context, tx repository.Transaction, debt model.Debts) (model.Debts, error) {
+	args := m.Called(ctx, tx, debt)
+	return args.Get(0).(model.Debts), args.Error(1)
+}
+
+func (m *MockDebtsRepository) DeleteDebt(ctx context.Context, tx repository.Transaction, debt model.Debts) (model.Debts, error) {
+	args := m.Called(ctx, tx, debt)
+	return args.Get(0).(model.Debts), args.Error(1)
+}
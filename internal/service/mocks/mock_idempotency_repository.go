@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"context"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) GetByKey(ctx context.Context, tx repository.Transaction, userID, operation, key string) (*model.IdempotencyKey, error) {
+	args := m.Called(ctx, tx, userID, operation, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Create(ctx context.Context, tx repository.Transaction, record *model.IdempotencyKey) error {
+	args := m.Called(ctx, tx, record)
+	return args.Error(0)
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockImportsRepository struct {
+	mock.Mock
+}
+
+func (m *MockImportsRepository) GetImportsByUserID(ctx context.Context, tx repository.Transaction, userID string) ([]model.Imports, error) {
+	args := m.Called(ctx, tx, userID)
+	return args.Get(0).([]model.Imports), args.Error(1)
+}
+
+func (m *MockImportsRepository) GetImportByID(ctx context.Context, tx repository.Transaction, id string) (model.Imports, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(model.Imports), args.Error(1)
+}
+
+func (m *MockImportsRepository) CreateImport(ctx context.Context, tx repository.Transaction, imp model.Imports) (model.Imports, error) {
+	args := m.Called(ctx, tx, imp)
+	return args.Get(0).(model.Imports), args.Error(1)
+}
+
+func (m *MockImportsRepository) UpdateImport(ctx context.Context, tx repository.Transaction, imp model.Imports) (model.Imports, error) {
+	args := m.Called(ctx, tx, imp)
+	return args.Get(0).(model.Imports), args.Error(1)
+}
@@ -0,0 +1,75 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReconciliationsRepository struct {
+	mock.Mock
+}
+
+func (m *MockReconciliationsRepository) GetBalanceStage(ctx context.Context, tx repository.Transaction, walletID string) (model.BalanceStage, error) {
+	args := m.Called(ctx, tx, walletID)
+	return args.Get(0).(model.BalanceStage), args.Error(1)
+}
+
+func (m *MockReconciliationsRepository) SetBalanceStage(ctx context.Context, tx repository.Transaction, settings model.WalletSettings) (model.WalletSettings, error) {
+	args := m.Called(ctx, tx, settings)
+	return args.Get(0).(model.WalletSettings), args.Error(1)
+}
+
+func (m *MockReconciliationsRepository) CountWalletTransactionsByStatus(ctx context.Context, tx repository.Transaction, walletID string, status model.TransactionStatus) (int64, error) {
+	args := m.Called(ctx, tx, walletID, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReconciliationsRepository) GetReconciliationsByUserID(ctx context.Context, tx repository.Transaction, userID string) ([]model.Reconciliations, error) {
+	args := m.Called(ctx, tx, userID)
+	return args.Get(0).([]model.Reconciliations), args.Error(1)
+}
+
+func (m *MockReconciliationsRepository) GetReconciliationByID(ctx context.Context, tx repository.Transaction, id string) (model.Reconciliations, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(model.Reconciliations), args.Error(1)
+}
+
+func (m *MockReconciliationsRepository) GetOpenReconciliation(ctx context.Context, tx repository.Transaction, walletID string) (*model.Reconciliations, error) {
+	args := m.Called(ctx, tx, walletID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Reconciliations), args.Error(1)
+}
+
+func (m *MockReconciliationsRepository) CreateReconciliation(ctx context.Context, tx repository.Transaction, reconciliation model.Reconciliations) (model.Reconciliations, error) {
+	args := m.Called(ctx, tx, reconciliation)
+	return args.Get(0).(model.Reconciliations), args.Error(1)
+}
+
+func (m *MockReconciliationsRepository) UpdateReconciliation(ctx context.Context, tx repository.Transaction, reconciliation model.Reconciliations) (model.Reconciliations, error) {
+	args := m.Called(ctx, tx, reconciliation)
+	return args.Get(0).(model.Reconciliations), args.Error(1)
+}
+
+func (m *MockReconciliationsRepository) GetReconciledBalance(ctx context.Context, tx repository.Transaction, walletID string) (money.Amount, error) {
+	args := m.Called(ctx, tx, walletID)
+	return args.Get(0).(money.Amount), args.Error(1)
+}
+
+func (m *MockReconciliationsRepository) GetReconcilableTransactions(ctx context.Context, tx repository.Transaction, walletID string, until time.Time) ([]model.Transactions, error) {
+	args := m.Called(ctx, tx, walletID, until)
+	return args.Get(0).([]model.Transactions), args.Error(1)
+}
+
+func (m *MockReconciliationsRepository) MarkReconciled(ctx context.Context, tx repository.Transaction, reconciliationID uuid.UUID, transactionIDs []uuid.UUID) (int64, error) {
+	args := m.Called(ctx, tx, reconciliationID, transactionIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
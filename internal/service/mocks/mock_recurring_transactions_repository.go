@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecurringTransactionsRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecurringTransactionsRepository) GetRecurringTransactionsByWalletIDs(ctx context.Context, tx repository.Transaction, ids []string) ([]model.RecurringTransactions, error) {
+	args := m.Called(ctx, tx, ids)
+	return args.Get(0).([]model.RecurringTransactions), args.Error(1)
+}
+
+func (m *MockRecurringTransactionsRepository) GetRecurringTransactionByID(ctx context.Context, tx repository.Transaction, id string) (model.RecurringTransactions, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(model.RecurringTransactions), args.Error(1)
+}
+
+func (m *MockRecurringTransactionsRepository) GetDueRecurringTransactions(ctx context.Context, tx repository.Transaction, now time.Time, limit int) ([]model.RecurringTransactions, error) {
+	args := m.Called(ctx, tx, now, limit)
+	return args.Get(0).([]model.RecurringTransactions), args.Error(1)
+}
+
+func (m *MockRecurringTransactionsRepository) CreateRecurringTransaction(ctx context.Context, tx repository.Transaction, recurring model.RecurringTransactions) (model.RecurringTransactions, error) {
+	args := m.Called(ctx, tx, recurring)
+	return args.Get(0).(model.RecurringTransactions), args.Error(1)
+}
+
+func (m *MockRecurringTransactionsRepository) UpdateRecurringTransaction(ctx context.Context, tx repository.Transaction, recurring model.RecurringTransactions) (model.RecurringTransactions, error) {
+	args := m.Called(ctx, tx, recurring)
+	return args.Get(0).(model.RecurringTransactions), args.Error(1)
+}
+
+func (m *MockRecurringTransactionsRepository) DeleteRecurringTransaction(ctx context.Context, tx repository.Transaction, recurring model.RecurringTransactions) (model.RecurringTransactions, error) {
+	args := m.Called(ctx, tx, recurring)
+	return args.Get(0).(model.RecurringTransactions), args.Error(1)
+}
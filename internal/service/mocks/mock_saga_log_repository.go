@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSagaLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockSagaLogRepository) CreateSaga(ctx context.Context, tx repository.Transaction, saga *model.SagaLog) error {
+	args := m.Called(ctx, tx, saga)
+	return args.Error(0)
+}
+
+func (m *MockSagaLogRepository) UpdateSagaStatus(ctx context.Context, tx repository.Transaction, id string, status model.SagaStatus, reason string) error {
+	args := m.Called(ctx, tx, id, status, reason)
+	return args.Error(0)
+}
+
+func (m *MockSagaLogRepository) CompleteSaga(ctx context.Context, tx repository.Transaction, id string) (bool, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSagaLogRepository) ClaimSaga(ctx context.Context, tx repository.Transaction, id string, staleBefore time.Time) (bool, error) {
+	args := m.Called(ctx, tx, id, staleBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSagaLogRepository) CreateStep(ctx context.Context, tx repository.Transaction, step *model.SagaLogStep) error {
+	args := m.Called(ctx, tx, step)
+	return args.Error(0)
+}
+
+func (m *MockSagaLogRepository) UpdateStepStatus(ctx context.Context, tx repository.Transaction, id string, status model.SagaStepStatus) error {
+	args := m.Called(ctx, tx, id, status)
+	return args.Error(0)
+}
+
+func (m *MockSagaLogRepository) GetIncompleteSagas(ctx context.Context, olderThan time.Time, limit int) ([]model.SagaLog, error) {
+	args := m.Called(ctx, olderThan, limit)
+	return args.Get(0).([]model.SagaLog), args.Error(1)
+}
@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"context"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTagsRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagsRepository) GetTagsByUserID(ctx context.Context, tx repository.Transaction, userID string) ([]model.Tags, error) {
+	args := m.Called(ctx, tx, userID)
+	return args.Get(0).([]model.Tags), args.Error(1)
+}
+
+func (m *MockTagsRepository) GetTagsByIDs(ctx context.Context, tx repository.Transaction, ids []string) ([]model.Tags, error) {
+	args := m.Called(ctx, tx, ids)
+	return args.Get(0).([]model.Tags), args.Error(1)
+}
+
+func (m *MockTagsRepository) GetTagByID(ctx context.Context, tx repository.Transaction, id string) (model.Tags, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(model.Tags), args.Error(1)
+}
+
+func (m *MockTagsRepository) GetTagByName(ctx context.Context, tx repository.Transaction, userID, name string) (model.Tags, error) {
+	args := m.Called(ctx, tx, userID, name)
+	return args.Get(0).(model.Tags), args.Error(1)
+}
+
+func (m *MockTagsRepository) CreateTag(ctx context.Context, tx repository.Transaction, tag model.Tags) (model.Tags, error) {
+	args := m.Called(ctx, tx, tag)
+	return args.Get(0).(model.Tags), args.Error(1)
+}
+
+func (m *MockTagsRepository) UpdateTag(ctx context.Context, tx repository.Transaction, tag model.Tags) (model.Tags, error) {
+	args := m.Called(ctx, tx, tag)
+	return args.Get(0).(model.Tags), args.Error(1)
+}
+
+func (m *MockTagsRepository) DeleteTag(ctx context.Context, tx repository.Transaction, tag model.Tags) (model.Tags, error) {
+	args := m.Called(ctx, tx, tag)
+	return args.Get(0).(model.Tags), args.Error(1)
+}
+
+func (m *MockTagsRepository) ReplaceTransactionTags(ctx context.Context, tx repository.Transaction, transactionID uuid.UUID, tagIDs []uuid.UUID) error {
+	args := m.Called(ctx, tx, transactionID, tagIDs)
+	return args.Error(0)
+}
@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"context"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransactionHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockTransactionHistoryRepository) CreateHistory(ctx context.Context, tx repository.Transaction, entries []model.TransactionHistory) error {
+	args := m.Called(ctx, tx, entries)
+	return args.Error(0)
+}
+
+func (m *MockTransactionHistoryRepository) GetHistoryByTransactionID(ctx context.Context, tx repository.Transaction, transactionID string) ([]model.TransactionHistory, error) {
+	args := m.Called(ctx, tx, transactionID)
+	return args.Get(0).([]model.TransactionHistory), args.Error(1)
+}
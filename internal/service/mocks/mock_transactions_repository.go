@@ -2,9 +2,11 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"refina-transaction/internal/repository"
 	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -33,11 +35,58 @@ func (m *MockTransactionsRepository) GetTransactionsByCursor(ctx context.Context
 	return args.Get(0).([]model.Transactions), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockTransactionsRepository) CreateTransactions(ctx context.Context, tx repository.Transaction, transactions []model.Transactions, batchSize int) ([]model.Transactions, error) {
+	args := m.Called(ctx, tx, transactions, batchSize)
+	return args.Get(0).([]model.Transactions), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) GetTransactionsByImportID(ctx context.Context, tx repository.Transaction, importID string) ([]model.Transactions, error) {
+	args := m.Called(ctx, tx, importID)
+	return args.Get(0).([]model.Transactions), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) DeleteTransactionsByImportID(ctx context.Context, tx repository.Transaction, importID string) (int64, error) {
+	args := m.Called(ctx, tx, importID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) AggregateTransactions(ctx context.Context, tx repository.Transaction, q repository.CursorQuery, groupBy repository.AggregateGroupBy) ([]repository.AggregateRow, error) {
+	args := m.Called(ctx, tx, q, groupBy)
+	return args.Get(0).([]repository.AggregateRow), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) SumTransferFXGainLoss(ctx context.Context, tx repository.Transaction, q repository.CursorQuery) (repository.TransferFXTotal, error) {
+	args := m.Called(ctx, tx, q)
+	return args.Get(0).(repository.TransferFXTotal), args.Error(1)
+}
+
+// StreamTransactions replays the [][]model.Transactions batches given to Return
+// through fn, stopping at the first error fn returns.
+func (m *MockTransactionsRepository) StreamTransactions(ctx context.Context, tx repository.Transaction, q repository.CursorQuery, batchSize int, fn func([]model.Transactions) error) error {
+	args := m.Called(ctx, tx, q, batchSize)
+	for _, batch := range args.Get(0).([][]model.Transactions) {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionsRepository) GetWalletNetChangeSince(ctx context.Context, tx repository.Transaction, walletID string, since time.Time) (money.Amount, error) {
+	args := m.Called(ctx, tx, walletID, since)
+	return args.Get(0).(money.Amount), args.Error(1)
+}
+
 func (m *MockTransactionsRepository) CreateTransaction(ctx context.Context, tx repository.Transaction, transaction model.Transactions) (model.Transactions, error) {
 	args := m.Called(ctx, tx, transaction)
 	return args.Get(0).(model.Transactions), args.Error(1)
 }
 
+func (m *MockTransactionsRepository) ReplaceTransactionSplits(ctx context.Context, tx repository.Transaction, transactionID string, splits []model.TransactionSplits) ([]model.TransactionSplits, error) {
+	args := m.Called(ctx, tx, transactionID, splits)
+	return args.Get(0).([]model.TransactionSplits), args.Error(1)
+}
+
 func (m *MockTransactionsRepository) UpdateTransaction(ctx context.Context, tx repository.Transaction, transaction model.Transactions) (model.Transactions, error) {
 	args := m.Called(ctx, tx, transaction)
 	return args.Get(0).(model.Transactions), args.Error(1)
@@ -47,3 +96,73 @@ func (m *MockTransactionsRepository) DeleteTransaction(ctx context.Context, tx r
 	args := m.Called(ctx, tx, transaction)
 	return args.Get(0).(model.Transactions), args.Error(1)
 }
+
+func (m *MockTransactionsRepository) GetDeletedTransactions(ctx context.Context, tx repository.Transaction, walletIDs []string) ([]model.Transactions, error) {
+	args := m.Called(ctx, tx, walletIDs)
+	return args.Get(0).([]model.Transactions), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) GetDeletedTransactionByID(ctx context.Context, tx repository.Transaction, id string) (model.Transactions, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(model.Transactions), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) RestoreTransaction(ctx context.Context, tx repository.Transaction, transaction model.Transactions) (model.Transactions, error) {
+	args := m.Called(ctx, tx, transaction)
+	return args.Get(0).(model.Transactions), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) PurgeDeletedTransactions(ctx context.Context, tx repository.Transaction, deletedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, tx, deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) GetScheduledTransactions(ctx context.Context, tx repository.Transaction, walletIDs []string) ([]model.Transactions, error) {
+	args := m.Called(ctx, tx, walletIDs)
+	return args.Get(0).([]model.Transactions), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) GetDueScheduledTransactions(ctx context.Context, tx repository.Transaction, due time.Time, limit int) ([]model.Transactions, error) {
+	args := m.Called(ctx, tx, due, limit)
+	return args.Get(0).([]model.Transactions), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) PostScheduledTransaction(ctx context.Context, tx repository.Transaction, id string) (bool, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) RecordScheduledPostFailure(ctx context.Context, tx repository.Transaction, id string, failureCount int, reason string, retryAt time.Time) error {
+	args := m.Called(ctx, tx, id, failureCount, reason, retryAt)
+	return args.Error(0)
+}
+
+func (m *MockTransactionsRepository) CancelScheduledTransaction(ctx context.Context, tx repository.Transaction, id string) (bool, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) GetTransfersByWalletIDs(ctx context.Context, tx repository.Transaction, walletIDs []string) ([]model.Transfers, error) {
+	args := m.Called(ctx, tx, walletIDs)
+	return args.Get(0).([]model.Transfers), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) GetTransferByID(ctx context.Context, tx repository.Transaction, id string) (model.Transfers, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(model.Transfers), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) CreateTransfer(ctx context.Context, tx repository.Transaction, transfer model.Transfers) (model.Transfers, error) {
+	args := m.Called(ctx, tx, transfer)
+	return args.Get(0).(model.Transfers), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) UpdateTransfer(ctx context.Context, tx repository.Transaction, transfer model.Transfers) (model.Transfers, error) {
+	args := m.Called(ctx, tx, transfer)
+	return args.Get(0).(model.Transfers), args.Error(1)
+}
+
+func (m *MockTransactionsRepository) DeleteTransfer(ctx context.Context, tx repository.Transaction, transfer model.Transfers) (model.Transfers, error) {
+	args := m.Called(ctx, tx, transfer)
+	return args.Get(0).(model.Transfers), args.Error(1)
+}
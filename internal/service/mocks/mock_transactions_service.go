@@ -0,0 +1,124 @@
+package mocks
+
+import (
+	"context"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransactionsService struct {
+	mock.Mock
+}
+
+func (m *MockTransactionsService) GetAllTransactions(ctx context.Context) ([]dto.TransactionsResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]dto.TransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) GetTransactionByID(ctx context.Context, id string) (dto.TransactionsResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(dto.TransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) GetTransactionsByWalletIDs(ctx context.Context, ids []string) ([]dto.TransactionsResponse, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]dto.TransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) GetTransactionsByCursor(ctx context.Context, q repository.CursorQuery) ([]dto.TransactionsResponse, int64, error) {
+	args := m.Called(ctx, q)
+	return args.Get(0).([]dto.TransactionsResponse), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTransactionsService) CreateTransaction(ctx context.Context, transaction dto.TransactionsRequest) (dto.TransactionsResponse, error) {
+	args := m.Called(ctx, transaction)
+	return args.Get(0).(dto.TransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) FundTransfer(ctx context.Context, transaction dto.FundTransferRequest) (dto.FundTransferResponse, error) {
+	args := m.Called(ctx, transaction)
+	return args.Get(0).(dto.FundTransferResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) UploadAttachment(ctx context.Context, tx repository.Transaction, transactionID string, files []string) ([]dto.AttachmentsResponse, error) {
+	args := m.Called(ctx, tx, transactionID, files)
+	return args.Get(0).([]dto.AttachmentsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) UpdateTransaction(ctx context.Context, id string, transaction dto.TransactionsRequest) (dto.TransactionsResponse, error) {
+	args := m.Called(ctx, id, transaction)
+	return args.Get(0).(dto.TransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) DeleteTransaction(ctx context.Context, id string) (dto.TransactionsResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(dto.TransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) BatchTransactions(ctx context.Context, request dto.BatchTransactionsRequest) (dto.BatchTransactionsResponse, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(dto.BatchTransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) GetDeletedTransactions(ctx context.Context, walletIDs []string) ([]dto.TransactionsResponse, error) {
+	args := m.Called(ctx, walletIDs)
+	return args.Get(0).([]dto.TransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) RestoreTransaction(ctx context.Context, id string) (dto.TransactionsResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(dto.TransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) PurgeDeletedTransactions(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTransactionsService) GetScheduledTransactions(ctx context.Context, walletIDs []string) ([]dto.TransactionsResponse, error) {
+	args := m.Called(ctx, walletIDs)
+	return args.Get(0).([]dto.TransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) CancelScheduledTransaction(ctx context.Context, id string) (dto.TransactionsResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(dto.TransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) PostDueScheduledTransactions(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTransactionsService) GetTransactionHistory(ctx context.Context, id string) ([]dto.TransactionHistoryResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]dto.TransactionHistoryResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) UpdateTransactionStatus(ctx context.Context, id string, status string) (dto.TransactionsResponse, error) {
+	args := m.Called(ctx, id, status)
+	return args.Get(0).(dto.TransactionsResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) GetTransfers(ctx context.Context, walletIDs []string) ([]dto.TransfersResponse, error) {
+	args := m.Called(ctx, walletIDs)
+	return args.Get(0).([]dto.TransfersResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) GetTransferByID(ctx context.Context, id string) (dto.TransfersResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(dto.TransfersResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) UpdateTransfer(ctx context.Context, id string, request dto.UpdateTransferRequest) (dto.TransfersResponse, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(dto.TransfersResponse), args.Error(1)
+}
+
+func (m *MockTransactionsService) DeleteTransfer(ctx context.Context, id string) (dto.TransfersResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(dto.TransfersResponse), args.Error(1)
+}
@@ -3,6 +3,8 @@ package mocks
 import (
 	"context"
 
+	"refina-transaction/internal/types/money"
+
 	wpb "github.com/MuhammadMiftaa/Refina-Protobuf/wallet"
 	"github.com/stretchr/testify/mock"
 )
@@ -26,3 +28,19 @@ func (m *MockWalletClient) UpdateWallet(ctx context.Context, wallet *wpb.Wallet)
 	}
 	return args.Get(0).(*wpb.Wallet), args.Error(1)
 }
+
+func (m *MockWalletClient) AdjustBalance(ctx context.Context, walletID string, delta money.Amount, idempotencyKey string) (*wpb.Wallet, error) {
+	args := m.Called(ctx, walletID, delta, idempotencyKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*wpb.Wallet), args.Error(1)
+}
+
+func (m *MockWalletClient) GetUserWallets(ctx context.Context, userID string) ([]*wpb.Wallet, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*wpb.Wallet), args.Error(1)
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWalletDriftsRepository struct {
+	mock.Mock
+}
+
+func (m *MockWalletDriftsRepository) GetLedgerWalletIDs(ctx context.Context, tx repository.Transaction) ([]string, error) {
+	args := m.Called(ctx, tx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockWalletDriftsRepository) GetDriftsByWalletIDs(ctx context.Context, tx repository.Transaction, walletIDs []string) ([]model.WalletDrifts, error) {
+	args := m.Called(ctx, tx, walletIDs)
+	return args.Get(0).([]model.WalletDrifts), args.Error(1)
+}
+
+func (m *MockWalletDriftsRepository) GetOpenDrift(ctx context.Context, tx repository.Transaction, walletID string) (*model.WalletDrifts, error) {
+	args := m.Called(ctx, tx, walletID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.WalletDrifts), args.Error(1)
+}
+
+func (m *MockWalletDriftsRepository) CreateDrift(ctx context.Context, tx repository.Transaction, drift model.WalletDrifts) (model.WalletDrifts, error) {
+	args := m.Called(ctx, tx, drift)
+	return args.Get(0).(model.WalletDrifts), args.Error(1)
+}
+
+func (m *MockWalletDriftsRepository) UpdateDrift(ctx context.Context, tx repository.Transaction, drift model.WalletDrifts) (model.WalletDrifts, error) {
+	args := m.Called(ctx, tx, drift)
+	return args.Get(0).(model.WalletDrifts), args.Error(1)
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+)
+
+// ErrReconciliationMismatch is returned when the transactions picked to
+// complete a reconciliation do not add up to the statement balance.
+var ErrReconciliationMismatch = errors.New("invalid reconciliation: cleared balance does not match the statement balance")
+
+type ReconciliationsService interface {
+	GetReconciliations(ctx context.Context, userID string) ([]dto.ReconciliationsResponse, error)
+	// GetReconciliationByID lists the candidate transactions of an open session.
+	GetReconciliationByID(ctx context.Context, userID, id string) (dto.ReconciliationsResponse, error)
+	// StartReconciliation opens a session for a wallet, at most one at a time.
+	StartReconciliation(ctx context.Context, userID string, req dto.ReconciliationsRequest) (dto.ReconciliationsResponse, error)
+	// CompleteReconciliation marks the picked transactions reconciled when
+	// they bring the reconciled balance to the statement balance. On a
+	// mismatch it returns the session with the difference and
+	// ErrReconciliationMismatch.
+	CompleteReconciliation(ctx context.Context, userID, id string, req dto.CompleteReconciliationRequest) (dto.ReconciliationsResponse, error)
+	CancelReconciliation(ctx context.Context, userID, id string) (dto.ReconciliationsResponse, error)
+	GetBalanceStage(ctx context.Context, walletID string) (dto.BalanceStageResponse, error)
+	// SetBalanceStage is refused while the wallet has pending transactions,
+	// whose amounts were booked under the current stage.
+	SetBalanceStage(ctx context.Context, walletID string, req dto.BalanceStageRequest) (dto.BalanceStageResponse, error)
+}
+
+type reconciliationsService struct {
+	txManager          repository.TxManager
+	reconciliationRepo repository.ReconciliationsRepository
+	outboxRepository   repository.OutboxRepository
+	history            *historyRecorder
+	now                func() time.Time
+}
+
+func NewReconciliationsService(txManager repository.TxManager, reconciliationRepo repository.ReconciliationsRepository, outboxRepository repository.OutboxRepository, historyRepo repository.TransactionHistoryRepository) ReconciliationsService {
+	return &reconciliationsService{
+		txManager:          txManager,
+		reconciliationRepo: reconciliationRepo,
+		outboxRepository:   outboxRepository,
+		history:            newHistoryRecorder(historyRepo),
+		now:                time.Now,
+	}
+}
+
+func (reconciliation_serv *reconciliationsService) GetReconciliations(ctx context.Context, userID string) ([]dto.ReconciliationsResponse, error) {
+	if userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	reconciliations, err := reconciliation_serv.reconciliationRepo.GetReconciliationsByUserID(ctx, nil, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get reconciliations [user_id=%s]: %w", userID, err)
+	}
+
+	responses := make([]dto.ReconciliationsResponse, 0, len(reconciliations))
+	for _, reconciliation := range reconciliations {
+		responses = append(responses, reconciliationResponse(reconciliation, nil))
+	}
+	return responses, nil
+}
+
+func (reconciliation_serv *reconciliationsService) GetReconciliationByID(ctx context.Context, userID, id string) (dto.ReconciliationsResponse, error) {
+	reconciliation, err := reconciliation_serv.ownedReconciliation(ctx, nil, userID, id)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, err
+	}
+	if reconciliation.Status != model.ReconciliationOpen {
+		return reconciliationResponse(reconciliation, nil), nil
+	}
+
+	candidates, err := reconciliation_serv.reconciliationRepo.GetReconcilableTransactions(ctx, nil, reconciliation.WalletID.String(), reconciliation.StatementDate)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("get reconciliation [id=%s]: %w", id, err)
+	}
+
+	return reconciliationResponse(reconciliation, candidates), nil
+}
+
+func (reconciliation_serv *reconciliationsService) StartReconciliation(ctx context.Context, userID string, req dto.ReconciliationsRequest) (dto.ReconciliationsResponse, error) {
+	if userID == "" {
+		return dto.ReconciliationsResponse{}, ErrUnauthenticated
+	}
+
+	walletID, err := helper.ParseUUID(req.WalletID)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("invalid wallet id [id=%s]: %w", req.WalletID, err)
+	}
+	if req.StatementDate.IsZero() {
+		return dto.ReconciliationsResponse{}, errors.New("invalid reconciliation: statement date is required")
+	}
+	if req.StatementDate.After(reconciliation_serv.now()) {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("invalid reconciliation: statement date is in the future [statement_date=%s]", req.StatementDate.Format("2006-01-02"))
+	}
+
+	tx, err := reconciliation_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("start reconciliation: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	open, err := reconciliation_serv.reconciliationRepo.GetOpenReconciliation(ctx, tx, req.WalletID)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("start reconciliation [wallet_id=%s]: %w", req.WalletID, err)
+	}
+	if open != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("invalid reconciliation: wallet already has an open reconciliation [wallet_id=%s, id=%s]", req.WalletID, open.ID)
+	}
+
+	opening, err := reconciliation_serv.reconciliationRepo.GetReconciledBalance(ctx, tx, req.WalletID)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("start reconciliation [wallet_id=%s]: %w", req.WalletID, err)
+	}
+
+	reconciliation, err := reconciliation_serv.reconciliationRepo.CreateReconciliation(ctx, tx, model.Reconciliations{
+		UserID:           userID,
+		WalletID:         walletID,
+		StatementDate:    req.StatementDate,
+		StatementBalance: req.StatementBalance,
+		OpeningBalance:   opening,
+		Status:           model.ReconciliationOpen,
+	})
+	if err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("start reconciliation: insert to db: %w", err)
+	}
+
+	candidates, err := reconciliation_serv.reconciliationRepo.GetReconcilableTransactions(ctx, tx, req.WalletID, req.StatementDate)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("start reconciliation [wallet_id=%s]: %w", req.WalletID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("start reconciliation: commit: %w", err)
+	}
+
+	return reconciliationResponse(reconciliation, candidates), nil
+}
+
+func (reconciliation_serv *reconciliationsService) CompleteReconciliation(ctx context.Context, userID, id string, req dto.CompleteReconciliationRequest) (dto.ReconciliationsResponse, error) {
+	tx, err := reconciliation_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("complete reconciliation: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	reconciliation, err := reconciliation_serv.ownedReconciliation(ctx, tx, userID, id)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, err
+	}
+	if reconciliation.Status != model.ReconciliationOpen {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("invalid reconciliation status [id=%s, status=%s]", id, reconciliation.Status)
+	}
+
+	// Only cleared transactions of the wallet up to the statement date may be picked
+	candidates, err := reconciliation_serv.reconciliationRepo.GetReconcilableTransactions(ctx, tx, reconciliation.WalletID.String(), reconciliation.StatementDate)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("complete reconciliation [id=%s]: %w", id, err)
+	}
+	byID := make(map[string]model.Transactions, len(candidates))
+	for _, candidate := range candidates {
+		byID[candidate.ID.String()] = candidate
+	}
+
+	picked := make([]model.Transactions, 0, len(req.TransactionIDs))
+	ids := make([]uuid.UUID, 0, len(req.TransactionIDs))
+	seen := make(map[string]bool, len(req.TransactionIDs))
+	for _, transactionID := range req.TransactionIDs {
+		if seen[transactionID] {
+			continue
+		}
+		seen[transactionID] = true
+
+		transaction, ok := byID[transactionID]
+		if !ok {
+			return dto.ReconciliationsResponse{}, fmt.Errorf("invalid reconciliation: transaction is not a cleared transaction of the wallet dated on or before the statement [id=%s, transaction_id=%s]", id, transactionID)
+		}
+		picked = append(picked, transaction)
+		ids = append(ids, transaction.ID)
+	}
+
+	response := reconciliationResponse(reconciliation, picked)
+	response.Transactions = nil
+	if !response.Difference.IsZero() {
+		return response, fmt.Errorf("%w [id=%s, cleared=%s, statement=%s]", ErrReconciliationMismatch, id, response.ClearedBalance, reconciliation.StatementBalance)
+	}
+
+	marked, err := reconciliation_serv.reconciliationRepo.MarkReconciled(ctx, tx, reconciliation.ID, ids)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("complete reconciliation [id=%s]: %w", id, err)
+	}
+	if marked != int64(len(ids)) {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("complete reconciliation [id=%s]: marked %d of %d transactions", id, marked, len(ids))
+	}
+
+	for _, before := range picked {
+		after := before
+		after.Status = model.StatusReconciled
+		after.ReconciliationID = &reconciliation.ID
+
+		if err := reconciliation_serv.history.RecordUpdated(ctx, tx, before, after); err != nil {
+			return dto.ReconciliationsResponse{}, fmt.Errorf("complete reconciliation [id=%s]: %w", id, err)
+		}
+		if err := reconciliation_serv.publish(ctx, tx, after); err != nil {
+			return dto.ReconciliationsResponse{}, fmt.Errorf("complete reconciliation [id=%s]: %w", id, err)
+		}
+	}
+
+	completedAt := reconciliation_serv.now()
+	reconciliation.Status = model.ReconciliationCompleted
+	reconciliation.TransactionCount = len(ids)
+	reconciliation.CompletedAt = &completedAt
+	if reconciliation, err = reconciliation_serv.reconciliationRepo.UpdateReconciliation(ctx, tx, reconciliation); err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("complete reconciliation [id=%s]: update in db: %w", id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("complete reconciliation: commit: %w", err)
+	}
+
+	return reconciliationResponse(reconciliation, nil), nil
+}
+
+func (reconciliation_serv *reconciliationsService) CancelReconciliation(ctx context.Context, userID, id string) (dto.ReconciliationsResponse, error) {
+	tx, err := reconciliation_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("cancel reconciliation: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	reconciliation, err := reconciliation_serv.ownedReconciliation(ctx, tx, userID, id)
+	if err != nil {
+		return dto.ReconciliationsResponse{}, err
+	}
+	if reconciliation.Status != model.ReconciliationOpen {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("invalid reconciliation status [id=%s, status=%s]", id, reconciliation.Status)
+	}
+
+	reconciliation.Status = model.ReconciliationCancelled
+	if reconciliation, err = reconciliation_serv.reconciliationRepo.UpdateReconciliation(ctx, tx, reconciliation); err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("cancel reconciliation [id=%s]: update in db: %w", id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.ReconciliationsResponse{}, fmt.Errorf("cancel reconciliation: commit: %w", err)
+	}
+
+	return reconciliationResponse(reconciliation, nil), nil
+}
+
+func (reconciliation_serv *reconciliationsService) GetBalanceStage(ctx context.Context, walletID string) (dto.BalanceStageResponse, error) {
+	stage, err := reconciliation_serv.reconciliationRepo.GetBalanceStage(ctx, nil, walletID)
+	if err != nil {
+		return dto.BalanceStageResponse{}, fmt.Errorf("get balance stage [wallet_id=%s]: %w", walletID, err)
+	}
+
+	return dto.BalanceStageResponse{WalletID: walletID, BalanceStage: string(stage)}, nil
+}
+
+func (reconciliation_serv *reconciliationsService) SetBalanceStage(ctx context.Context, walletID string, req dto.BalanceStageRequest) (dto.BalanceStageResponse, error) {
+	stage := model.BalanceStage(req.BalanceStage)
+	if stage != model.BalanceStagePending && stage != model.BalanceStageCleared {
+		return dto.BalanceStageResponse{}, fmt.Errorf("invalid balance stage [balance_stage=%s]", req.BalanceStage)
+	}
+
+	WalletID, err := helper.ParseUUID(walletID)
+	if err != nil {
+		return dto.BalanceStageResponse{}, fmt.Errorf("invalid wallet id [id=%s]: %w", walletID, err)
+	}
+
+	tx, err := reconciliation_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.BalanceStageResponse{}, fmt.Errorf("set balance stage: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	current, err := reconciliation_serv.reconciliationRepo.GetBalanceStage(ctx, tx, walletID)
+	if err != nil {
+		return dto.BalanceStageResponse{}, fmt.Errorf("set balance stage [wallet_id=%s]: %w", walletID, err)
+	}
+	if current == stage {
+		return dto.BalanceStageResponse{WalletID: walletID, BalanceStage: string(stage)}, nil
+	}
+
+	// Pending amounts were booked, or not, under the current stage
+	pending, err := reconciliation_serv.reconciliationRepo.CountWalletTransactionsByStatus(ctx, tx, walletID, model.StatusPending)
+	if err != nil {
+		return dto.BalanceStageResponse{}, fmt.Errorf("set balance stage [wallet_id=%s]: %w", walletID, err)
+	}
+	if pending > 0 {
+		return dto.BalanceStageResponse{}, fmt.Errorf("invalid balance stage: wallet has %d pending transactions; clear or void them first [wallet_id=%s]", pending, walletID)
+	}
+
+	if _, err := reconciliation_serv.reconciliationRepo.SetBalanceStage(ctx, tx, model.WalletSettings{WalletID: WalletID, BalanceStage: stage}); err != nil {
+		return dto.BalanceStageResponse{}, fmt.Errorf("set balance stage [wallet_id=%s]: %w", walletID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.BalanceStageResponse{}, fmt.Errorf("set balance stage: commit: %w", err)
+	}
+
+	return dto.BalanceStageResponse{WalletID: walletID, BalanceStage: string(stage)}, nil
+}
+
+func (reconciliation_serv *reconciliationsService) ownedReconciliation(ctx context.Context, tx repository.Transaction, userID, id string) (model.Reconciliations, error) {
+	if userID == "" {
+		return model.Reconciliations{}, ErrUnauthenticated
+	}
+
+	reconciliation, err := reconciliation_serv.reconciliationRepo.GetReconciliationByID(ctx, tx, id)
+	if err != nil {
+		return model.Reconciliations{}, fmt.Errorf("reconciliation not found [id=%s]: %w", id, err)
+	}
+	if reconciliation.UserID != userID {
+		return model.Reconciliations{}, fmt.Errorf("%w: reconciliation does not belong to user [reconciliation_id=%s, user_id=%s]", ErrPermissionDenied, id, userID)
+	}
+
+	return reconciliation, nil
+}
+
+func (reconciliation_serv *reconciliationsService) publish(ctx context.Context, tx repository.Transaction, transaction model.Transactions) error {
+	payload, err := json.Marshal(helper.ConvertToResponseType(transaction).(dto.TransactionsResponse))
+	if err != nil {
+		return fmt.Errorf("marshal transaction response [id=%s]: %w", transaction.ID, err)
+	}
+
+	return reconciliation_serv.outboxRepository.Create(ctx, tx, &model.OutboxMessage{
+		AggregateID: transaction.ID.String(),
+		EventType:   data.OUTBOX_EVENT_TRANSACTION_UPDATED,
+		Payload:     payload,
+		Published:   false,
+		MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+	})
+}
+
+// reconciliationResponse adds the balance effect of transactions to the
+// opening balance of an open session; closed sessions are shown as they ended.
+func reconciliationResponse(reconciliation model.Reconciliations, transactions []model.Transactions) dto.ReconciliationsResponse {
+	response := dto.ReconciliationsResponse{
+		ID:               reconciliation.ID.String(),
+		WalletID:         reconciliation.WalletID.String(),
+		StatementDate:    reconciliation.StatementDate,
+		StatementBalance: reconciliation.StatementBalance,
+		OpeningBalance:   reconciliation.OpeningBalance,
+		Status:           string(reconciliation.Status),
+		TransactionCount: reconciliation.TransactionCount,
+		CompletedAt:      reconciliation.CompletedAt,
+		CreatedAt:        reconciliation.CreatedAt,
+	}
+
+	switch reconciliation.Status {
+	case model.ReconciliationCompleted:
+		response.ClearedBalance = reconciliation.StatementBalance
+		return response
+	case model.ReconciliationCancelled:
+		return response
+	}
+
+	cleared := reconciliation.OpeningBalance
+	response.Transactions = make([]dto.TransactionsResponse, 0, len(transactions))
+	for _, transaction := range transactions {
+		// Transactions outside income, expense and fund transfers never move a balance
+		if effect, err := balanceEffect(transaction); err == nil {
+			cleared = cleared.Add(effect)
+		}
+		response.Transactions = append(response.Transactions, helper.ConvertToResponseType(transaction).(dto.TransactionsResponse))
+	}
+	response.ClearedBalance = cleared
+	response.Difference = reconciliation.StatementBalance.Sub(cleared)
+
+	return response
+}
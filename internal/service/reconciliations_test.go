@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type reconciliationTestDeps struct {
+	txManager          *mocks.MockTxManager
+	reconciliationRepo *mocks.MockReconciliationsRepository
+	outboxRepo         *mocks.MockOutboxRepository
+	historyRepo        *mocks.MockTransactionHistoryRepository
+	tx                 *mocks.MockTransaction
+}
+
+func newReconciliationTestDeps() *reconciliationTestDeps {
+	d := &reconciliationTestDeps{
+		txManager:          new(mocks.MockTxManager),
+		reconciliationRepo: new(mocks.MockReconciliationsRepository),
+		outboxRepo:         new(mocks.MockOutboxRepository),
+		historyRepo:        new(mocks.MockTransactionHistoryRepository),
+		tx:                 new(mocks.MockTransaction),
+	}
+	// History entries are covered in transactionHistory_test.go
+	d.historyRepo.On("CreateHistory", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return d
+}
+
+func (d *reconciliationTestDeps) service() ReconciliationsService {
+	return &reconciliationsService{
+		txManager:          d.txManager,
+		reconciliationRepo: d.reconciliationRepo,
+		outboxRepository:   d.outboxRepo,
+		history:            newHistoryRecorder(d.historyRepo),
+		now:                func() time.Time { return txnFixTime },
+	}
+}
+
+func (d *reconciliationTestDeps) assertAll(t *testing.T) {
+	t.Helper()
+	d.txManager.AssertExpectations(t)
+	d.reconciliationRepo.AssertExpectations(t)
+	d.outboxRepo.AssertExpectations(t)
+	d.historyRepo.AssertExpectations(t)
+	d.tx.AssertExpectations(t)
+}
+
+var reconciliationTestID = uuid.MustParse("77777777-7777-7777-7777-777777777777")
+
+func sampleReconciliationModel(statementBalance int64) model.Reconciliations {
+	return model.Reconciliations{
+		Base:             model.Base{ID: reconciliationTestID, CreatedAt: txnFixTime},
+		UserID:           "user-1",
+		WalletID:         walletTestID,
+		StatementDate:    txnFixTime,
+		StatementBalance: money.New(statementBalance),
+		OpeningBalance:   money.New(100000),
+		Status:           model.ReconciliationOpen,
+	}
+}
+
+func sampleClearedTransaction(id uuid.UUID, amount int64, category model.Categories) model.Transactions {
+	txn := sampleTransactionModel()
+	txn.ID = id
+	txn.Amount = money.New(amount)
+	txn.Category = category
+	txn.CategoryID = category.ID
+	txn.Status = model.StatusCleared
+	return txn
+}
+
+// =====================================================================
+// StartReconciliation
+// =====================================================================
+
+func TestStartReconciliation_OpensFromReconciledBalance(t *testing.T) {
+	d := newReconciliationTestDeps()
+	svc := d.service()
+
+	request := dto.ReconciliationsRequest{WalletID: walletTestID.String(), StatementDate: txnFixTime, StatementBalance: money.New(70000)}
+	candidate := sampleClearedTransaction(txnTestID, 30000, sampleExpenseCategory())
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.reconciliationRepo.On("GetOpenReconciliation", mock.Anything, d.tx, walletTestID.String()).Return(nil, nil)
+	d.reconciliationRepo.On("GetReconciledBalance", mock.Anything, d.tx, walletTestID.String()).Return(money.New(100000), nil)
+	d.reconciliationRepo.On("CreateReconciliation", mock.Anything, d.tx, mock.MatchedBy(func(r model.Reconciliations) bool {
+		return r.OpeningBalance == money.New(100000) && r.Status == model.ReconciliationOpen && r.UserID == "user-1"
+	})).Return(sampleReconciliationModel(70000), nil)
+	d.reconciliationRepo.On("GetReconcilableTransactions", mock.Anything, d.tx, walletTestID.String(), txnFixTime).
+		Return([]model.Transactions{candidate}, nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.StartReconciliation(context.Background(), "user-1", request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(70000), result.ClearedBalance)
+	assert.True(t, result.Difference.IsZero())
+	assert.Len(t, result.Transactions, 1)
+	d.assertAll(t)
+}
+
+func TestStartReconciliation_RejectsSecondOpenSession(t *testing.T) {
+	d := newReconciliationTestDeps()
+	svc := d.service()
+
+	open := sampleReconciliationModel(70000)
+	request := dto.ReconciliationsRequest{WalletID: walletTestID.String(), StatementDate: txnFixTime, StatementBalance: money.New(70000)}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.reconciliationRepo.On("GetOpenReconciliation", mock.Anything, d.tx, walletTestID.String()).Return(&open, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.StartReconciliation(context.Background(), "user-1", request)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already has an open reconciliation")
+	d.reconciliationRepo.AssertNotCalled(t, "CreateReconciliation", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// CompleteReconciliation
+// =====================================================================
+
+func TestCompleteReconciliation_MarksPickedTransactions(t *testing.T) {
+	d := newReconciliationTestDeps()
+	svc := d.service()
+
+	income := sampleClearedTransaction(txnTestID, 20000, sampleIncomeCategory())
+	expense := sampleClearedTransaction(batchUpdateID, 50000, sampleExpenseCategory())
+	unpicked := sampleClearedTransaction(batchDeleteID, 10000, sampleExpenseCategory())
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.reconciliationRepo.On("GetReconciliationByID", mock.Anything, d.tx, reconciliationTestID.String()).Return(sampleReconciliationModel(70000), nil)
+	d.reconciliationRepo.On("GetReconcilableTransactions", mock.Anything, d.tx, walletTestID.String(), txnFixTime).
+		Return([]model.Transactions{income, expense, unpicked}, nil)
+	d.reconciliationRepo.On("MarkReconciled", mock.Anything, d.tx, reconciliationTestID, []uuid.UUID{txnTestID, batchUpdateID}).Return(int64(2), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil).Times(2)
+	d.reconciliationRepo.On("UpdateReconciliation", mock.Anything, d.tx, mock.MatchedBy(func(r model.Reconciliations) bool {
+		return r.Status == model.ReconciliationCompleted && r.TransactionCount == 2 && r.CompletedAt != nil
+	})).Return(func() model.Reconciliations {
+		completed := sampleReconciliationModel(70000)
+		completed.Status = model.ReconciliationCompleted
+		completed.TransactionCount = 2
+		return completed
+	}(), nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CompleteReconciliation(context.Background(), "user-1", reconciliationTestID.String(), dto.CompleteReconciliationRequest{
+		TransactionIDs: []string{txnTestID.String(), batchUpdateID.String()},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(model.ReconciliationCompleted), result.Status)
+	assert.Equal(t, 2, result.TransactionCount)
+	d.assertAll(t)
+}
+
+func TestCompleteReconciliation_MismatchReturnsDifference(t *testing.T) {
+	d := newReconciliationTestDeps()
+	svc := d.service()
+
+	expense := sampleClearedTransaction(txnTestID, 50000, sampleExpenseCategory())
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.reconciliationRepo.On("GetReconciliationByID", mock.Anything, d.tx, reconciliationTestID.String()).Return(sampleReconciliationModel(40000), nil)
+	d.reconciliationRepo.On("GetReconcilableTransactions", mock.Anything, d.tx, walletTestID.String(), txnFixTime).
+		Return([]model.Transactions{expense}, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CompleteReconciliation(context.Background(), "user-1", reconciliationTestID.String(), dto.CompleteReconciliationRequest{
+		TransactionIDs: []string{txnTestID.String()},
+	})
+
+	assert.ErrorIs(t, err, ErrReconciliationMismatch)
+	assert.Equal(t, money.New(50000), result.ClearedBalance)
+	assert.Equal(t, money.New(-10000), result.Difference)
+	d.reconciliationRepo.AssertNotCalled(t, "MarkReconciled", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCompleteReconciliation_RejectsOtherUsersSession(t *testing.T) {
+	d := newReconciliationTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.reconciliationRepo.On("GetReconciliationByID", mock.Anything, d.tx, reconciliationTestID.String()).Return(sampleReconciliationModel(70000), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.CompleteReconciliation(context.Background(), "user-2", reconciliationTestID.String(), dto.CompleteReconciliationRequest{})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// SetBalanceStage
+// =====================================================================
+
+func TestSetBalanceStage_RejectsWhilePending(t *testing.T) {
+	d := newReconciliationTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.reconciliationRepo.On("GetBalanceStage", mock.Anything, d.tx, walletTestID.String()).Return(model.BalanceStagePending, nil)
+	d.reconciliationRepo.On("CountWalletTransactionsByStatus", mock.Anything, d.tx, walletTestID.String(), model.StatusPending).Return(int64(3), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.SetBalanceStage(context.Background(), walletTestID.String(), dto.BalanceStageRequest{BalanceStage: string(model.BalanceStageCleared)})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "3 pending transactions")
+	d.reconciliationRepo.AssertNotCalled(t, "SetBalanceStage", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestSetBalanceStage_Success(t *testing.T) {
+	d := newReconciliationTestDeps()
+	svc := d.service()
+
+	settings := model.WalletSettings{WalletID: walletTestID, BalanceStage: model.BalanceStageCleared}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.reconciliationRepo.On("GetBalanceStage", mock.Anything, d.tx, walletTestID.String()).Return(model.BalanceStagePending, nil)
+	d.reconciliationRepo.On("CountWalletTransactionsByStatus", mock.Anything, d.tx, walletTestID.String(), model.StatusPending).Return(int64(0), nil)
+	d.reconciliationRepo.On("SetBalanceStage", mock.Anything, d.tx, settings).Return(settings, nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.SetBalanceStage(context.Background(), walletTestID.String(), dto.BalanceStageRequest{BalanceStage: string(model.BalanceStageCleared)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(model.BalanceStageCleared), result.BalanceStage)
+	d.assertAll(t)
+}
@@ -0,0 +1,463 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"refina-transaction/config/log"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+)
+
+type RecurringTransactionsService interface {
+	GetRecurringTransactionsByWalletIDs(ctx context.Context, ids []string) ([]dto.RecurringTransactionsResponse, error)
+	GetRecurringTransactionByID(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error)
+	CreateRecurringTransaction(ctx context.Context, recurring dto.RecurringTransactionsRequest) (dto.RecurringTransactionsResponse, error)
+	UpdateRecurringTransaction(ctx context.Context, id string, recurring dto.RecurringTransactionsRequest) (dto.RecurringTransactionsResponse, error)
+	DeleteRecurringTransaction(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error)
+	PauseRecurringTransaction(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error)
+	ResumeRecurringTransaction(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error)
+	SkipNextOccurrence(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error)
+	ProcessDueOccurrences(ctx context.Context) (int, error)
+}
+
+type recurringTransactionsService struct {
+	txManager          repository.TxManager
+	recurringRepo      repository.RecurringTransactionsRepository
+	categoryRepo       repository.CategoriesRepository
+	transactionService TransactionsService
+	now                func() time.Time
+}
+
+func NewRecurringTransactionsService(txManager repository.TxManager, recurringRepo repository.RecurringTransactionsRepository, categoryRepo repository.CategoriesRepository, transactionService TransactionsService) RecurringTransactionsService {
+	return &recurringTransactionsService{
+		txManager:          txManager,
+		recurringRepo:      recurringRepo,
+		categoryRepo:       categoryRepo,
+		transactionService: transactionService,
+		now:                time.Now,
+	}
+}
+
+func (recurring_serv *recurringTransactionsService) GetRecurringTransactionsByWalletIDs(ctx context.Context, ids []string) ([]dto.RecurringTransactionsResponse, error) {
+	recurrings, err := recurring_serv.recurringRepo.GetRecurringTransactionsByWalletIDs(ctx, nil, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get recurring transactions by wallet ids: %w", err)
+	}
+
+	responses := make([]dto.RecurringTransactionsResponse, 0, len(recurrings))
+	for _, recurring := range recurrings {
+		responses = append(responses, helper.ConvertToResponseType(recurring).(dto.RecurringTransactionsResponse))
+	}
+
+	return responses, nil
+}
+
+func (recurring_serv *recurringTransactionsService) GetRecurringTransactionByID(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error) {
+	recurring, err := recurring_serv.recurringRepo.GetRecurringTransactionByID(ctx, nil, id)
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, fmt.Errorf("recurring transaction not found [id=%s]: %w", id, err)
+	}
+
+	return helper.ConvertToResponseType(recurring).(dto.RecurringTransactionsResponse), nil
+}
+
+func (recurring_serv *recurringTransactionsService) CreateRecurringTransaction(ctx context.Context, recurring dto.RecurringTransactionsRequest) (dto.RecurringTransactionsResponse, error) {
+	recurringNew, err := recurring_serv.buildRecurring(ctx, model.RecurringTransactions{Status: model.RecurringActive}, recurring)
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, err
+	}
+
+	scheduleFrom(&recurringNew, recurringNew.StartDate)
+
+	recurringNew, err = recurring_serv.recurringRepo.CreateRecurringTransaction(ctx, nil, recurringNew)
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, fmt.Errorf("create recurring transaction: insert to db: %w", err)
+	}
+
+	return helper.ConvertToResponseType(recurringNew).(dto.RecurringTransactionsResponse), nil
+}
+
+func (recurring_serv *recurringTransactionsService) UpdateRecurringTransaction(ctx context.Context, id string, recurring dto.RecurringTransactionsRequest) (dto.RecurringTransactionsResponse, error) {
+	existing, err := recurring_serv.recurringRepo.GetRecurringTransactionByID(ctx, nil, id)
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, fmt.Errorf("recurring transaction not found [id=%s]: %w", id, err)
+	}
+
+	updated, err := recurring_serv.buildRecurring(ctx, existing, recurring)
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, err
+	}
+
+	// Never materialise an occurrence twice when the schedule moves back in time
+	from := updated.StartDate
+	if updated.LastRunAt != nil && !updated.LastRunAt.Before(from) {
+		from = updated.LastRunAt.Add(time.Nanosecond)
+	}
+	scheduleFrom(&updated, from)
+
+	updated, err = recurring_serv.recurringRepo.UpdateRecurringTransaction(ctx, nil, updated)
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, fmt.Errorf("update recurring transaction [id=%s]: %w", id, err)
+	}
+
+	return helper.ConvertToResponseType(updated).(dto.RecurringTransactionsResponse), nil
+}
+
+func (recurring_serv *recurringTransactionsService) DeleteRecurringTransaction(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error) {
+	recurring, err := recurring_serv.recurringRepo.GetRecurringTransactionByID(ctx, nil, id)
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, fmt.Errorf("recurring transaction not found [id=%s]: %w", id, err)
+	}
+
+	recurringDeleted, err := recurring_serv.recurringRepo.DeleteRecurringTransaction(ctx, nil, recurring)
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, fmt.Errorf("delete recurring transaction [id=%s]: %w", id, err)
+	}
+
+	return helper.ConvertToResponseType(recurringDeleted).(dto.RecurringTransactionsResponse), nil
+}
+
+func (recurring_serv *recurringTransactionsService) PauseRecurringTransaction(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error) {
+	return recurring_serv.changeSchedule(ctx, id, func(recurring *model.RecurringTransactions) error {
+		if recurring.Status != model.RecurringActive {
+			return fmt.Errorf("invalid recurring transaction status [status=%s]", recurring.Status)
+		}
+		recurring.Status = model.RecurringPaused
+		return nil
+	})
+}
+
+func (recurring_serv *recurringTransactionsService) ResumeRecurringTransaction(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error) {
+	return recurring_serv.changeSchedule(ctx, id, func(recurring *model.RecurringTransactions) error {
+		if recurring.Status != model.RecurringPaused {
+			return fmt.Errorf("invalid recurring transaction status [status=%s]", recurring.Status)
+		}
+		recurring.Status = model.RecurringActive
+		clearFailures(recurring)
+
+		// Occurrences that fell due while paused are skipped, not caught up
+		if now := recurring_serv.now(); recurring.NextRunAt.Before(now) {
+			scheduleFrom(recurring, now)
+		}
+		return nil
+	})
+}
+
+func (recurring_serv *recurringTransactionsService) SkipNextOccurrence(ctx context.Context, id string) (dto.RecurringTransactionsResponse, error) {
+	return recurring_serv.changeSchedule(ctx, id, func(recurring *model.RecurringTransactions) error {
+		if recurring.Status == model.RecurringCompleted {
+			return fmt.Errorf("invalid recurring transaction status [status=%s]", recurring.Status)
+		}
+		clearFailures(recurring)
+		advanceSchedule(recurring)
+		return nil
+	})
+}
+
+// ProcessDueOccurrences materialises every occurrence that is due, including
+// the ones missed while the service was down, and returns how many
+// transactions were created. The batch stays locked until it is processed, so
+// schedulers on other replicas skip it instead of booking it twice.
+func (recurring_serv *recurringTransactionsService) ProcessDueOccurrences(ctx context.Context) (int, error) {
+	now := recurring_serv.now()
+
+	tx, err := recurring_serv.txManager.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	recurrings, err := recurring_serv.recurringRepo.GetDueRecurringTransactions(ctx, tx, now, data.RECURRING_SCHEDULER_BATCH)
+	if err != nil {
+		return 0, fmt.Errorf("get due recurring transactions: %w", err)
+	}
+
+	created := 0
+	for _, recurring := range recurrings {
+		count, err := recurring_serv.materialise(ctx, tx, recurring, now)
+		created += count
+		if err != nil {
+			log.Error(data.LogRecurringOccurrenceFailed, map[string]any{
+				"service":      data.RecurringService,
+				"recurring_id": recurring.ID.String(),
+				"occurrence":   recurring.Occurrences + count,
+				"error":        err.Error(),
+			})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		// Occurrences already booked replay by key on the next tick
+		return created, fmt.Errorf("commit recurring schedules: %w", err)
+	}
+
+	return created, nil
+}
+
+func (recurring_serv *recurringTransactionsService) materialise(ctx context.Context, tx repository.Transaction, recurring model.RecurringTransactions, now time.Time) (int, error) {
+	created := 0
+	for created < data.RECURRING_CATCHUP_LIMIT && recurring.Status == model.RecurringActive && !recurring.NextRunAt.After(now) {
+		occurrenceAt := recurring.NextRunAt
+
+		// Keyed by the occurrence date, which stays put when scheduleFrom
+		// renumbers Occurrences, so a retry replays instead of booking twice
+		key := fmt.Sprintf("recurring:%s:%d", recurring.ID, occurrenceAt.UTC().Unix())
+		_, err := recurring_serv.transactionService.CreateTransaction(helper.WithIdempotencyKey(ctx, key), dto.TransactionsRequest{
+			WalletID:    recurring.WalletID.String(),
+			CategoryID:  recurring.CategoryID.String(),
+			Amount:      recurring.Amount,
+			Date:        occurrenceAt,
+			Description: recurring.Description,
+		})
+		// The schedule was edited after this occurrence was booked, so it is
+		// already done
+		if err != nil && !errors.Is(err, ErrIdempotencyKeyReused) {
+			err = fmt.Errorf("create occurrence transaction [recurring_id=%s]: %w", recurring.ID, err)
+			return created, recurring_serv.recordFailure(ctx, tx, recurring, now, err)
+		}
+
+		recurring.LastRunAt = &occurrenceAt
+		clearFailures(&recurring)
+		advanceSchedule(&recurring)
+
+		if _, err := recurring_serv.recurringRepo.UpdateRecurringTransaction(ctx, tx, recurring); err != nil {
+			return created, fmt.Errorf("update recurring schedule [recurring_id=%s]: %w", recurring.ID, err)
+		}
+		created++
+	}
+
+	return created, nil
+}
+
+// recordFailure backs the schedule off after a failed occurrence and pauses
+// it once the occurrence has failed RECURRING_MAX_FAILURES times in a row, so
+// a permanently failing schedule stops retrying until the user resumes it.
+// It returns cause, or the error of saving the failure.
+func (recurring_serv *recurringTransactionsService) recordFailure(ctx context.Context, tx repository.Transaction, recurring model.RecurringTransactions, now time.Time, cause error) error {
+	recurring.FailureCount++
+	recurring.LastError = cause.Error()
+
+	if recurring.FailureCount >= data.RECURRING_MAX_FAILURES {
+		recurring.Status = model.RecurringPaused
+		recurring.RetryAt = nil
+		log.Warn(data.LogRecurringAutoPaused, map[string]any{
+			"service":       data.RecurringService,
+			"recurring_id":  recurring.ID.String(),
+			"failure_count": recurring.FailureCount,
+			"error":         cause.Error(),
+		})
+	} else {
+		retryAt := now.Add(retryBackoff(recurring.FailureCount, data.RECURRING_RETRY_BACKOFF, data.RECURRING_RETRY_BACKOFF_MAX))
+		recurring.RetryAt = &retryAt
+	}
+
+	if _, err := recurring_serv.recurringRepo.UpdateRecurringTransaction(ctx, tx, recurring); err != nil {
+		return fmt.Errorf("record occurrence failure [recurring_id=%s]: %w", recurring.ID, err)
+	}
+
+	return cause
+}
+
+// retryBackoff is the wait before retrying something that failed failures
+// times in a row: initial, doubled after every further failure up to limit.
+func retryBackoff(failures int, initial, limit time.Duration) time.Duration {
+	backoff := initial
+	for i := 1; i < failures && backoff < limit; i++ {
+		backoff *= 2
+	}
+	return min(backoff, limit)
+}
+
+func clearFailures(recurring *model.RecurringTransactions) {
+	recurring.FailureCount = 0
+	recurring.LastError = ""
+	recurring.RetryAt = nil
+}
+
+func (recurring_serv *recurringTransactionsService) changeSchedule(ctx context.Context, id string, change func(recurring *model.RecurringTransactions) error) (dto.RecurringTransactionsResponse, error) {
+	recurring, err := recurring_serv.recurringRepo.GetRecurringTransactionByID(ctx, nil, id)
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, fmt.Errorf("recurring transaction not found [id=%s]: %w", id, err)
+	}
+
+	if err := change(&recurring); err != nil {
+		return dto.RecurringTransactionsResponse{}, err
+	}
+
+	recurring, err = recurring_serv.recurringRepo.UpdateRecurringTransaction(ctx, nil, recurring)
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, fmt.Errorf("update recurring transaction [id=%s]: %w", id, err)
+	}
+
+	return helper.ConvertToResponseType(recurring).(dto.RecurringTransactionsResponse), nil
+}
+
+// buildRecurring validates the request and applies it on top of base.
+func (recurring_serv *recurringTransactionsService) buildRecurring(ctx context.Context, base model.RecurringTransactions, recurring dto.RecurringTransactionsRequest) (model.RecurringTransactions, error) {
+	walletID, err := helper.ParseUUID(recurring.WalletID)
+	if err != nil {
+		return model.RecurringTransactions{}, fmt.Errorf("invalid wallet id [id=%s]: %w", recurring.WalletID, err)
+	}
+
+	categoryID, err := helper.ParseUUID(recurring.CategoryID)
+	if err != nil {
+		return model.RecurringTransactions{}, fmt.Errorf("invalid category id [id=%s]: %w", recurring.CategoryID, err)
+	}
+
+	category, err := recurring_serv.categoryRepo.GetCategoryByID(ctx, nil, recurring.CategoryID)
+	if err != nil {
+		return model.RecurringTransactions{}, fmt.Errorf("category not found [id=%s]: %w", recurring.CategoryID, err)
+	}
+	// Occurrences go through CreateTransaction, which only books income and expense
+	if category.Type != model.Income && category.Type != model.Expense {
+		return model.RecurringTransactions{}, fmt.Errorf("invalid transaction type [type=%s]", category.Type)
+	}
+
+	if !recurring.Amount.IsPositive() {
+		return model.RecurringTransactions{}, fmt.Errorf("invalid amount [amount=%v]", recurring.Amount)
+	}
+
+	frequency := model.RecurringFrequency(recurring.Frequency)
+	switch frequency {
+	case model.RecurringDaily, model.RecurringWeekly, model.RecurringMonthly, model.RecurringYearly:
+	default:
+		return model.RecurringTransactions{}, fmt.Errorf("invalid frequency [frequency=%s]", recurring.Frequency)
+	}
+
+	interval := recurring.Interval
+	if interval == 0 {
+		interval = 1
+	}
+	if interval < 0 {
+		return model.RecurringTransactions{}, fmt.Errorf("invalid interval [interval=%d]", recurring.Interval)
+	}
+
+	if recurring.StartDate.IsZero() {
+		return model.RecurringTransactions{}, fmt.Errorf("invalid start date")
+	}
+	if recurring.EndDate != nil && recurring.EndDate.Before(recurring.StartDate) {
+		return model.RecurringTransactions{}, fmt.Errorf("invalid end date [end_date=%s]", recurring.EndDate.Format(time.RFC3339))
+	}
+	if recurring.Count < 0 {
+		return model.RecurringTransactions{}, fmt.Errorf("invalid count [count=%d]", recurring.Count)
+	}
+
+	base.WalletID = walletID
+	base.CategoryID = categoryID
+	base.Category = category
+	base.Amount = recurring.Amount
+	base.Description = recurring.Description
+	base.Frequency = frequency
+	base.Interval = interval
+	base.StartDate = recurring.StartDate
+	base.EndDate = recurring.EndDate
+	base.Count = recurring.Count
+
+	return base, nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Schedule
+// ──────────────────────────────────────────────────────────────────────────────
+
+// recurringOccurrence returns the n-th (0-based) occurrence of the schedule.
+// Monthly and yearly schedules keep the day of StartDate and clamp it to the
+// last day of shorter months, so a schedule on the 31st runs on Feb 28/29.
+func recurringOccurrence(recurring model.RecurringTransactions, n int) time.Time {
+	step := n * recurring.Interval
+
+	switch recurring.Frequency {
+	case model.RecurringWeekly:
+		return recurring.StartDate.AddDate(0, 0, 7*step)
+	case model.RecurringMonthly:
+		return addMonthsClamped(recurring.StartDate, step)
+	case model.RecurringYearly:
+		return addMonthsClamped(recurring.StartDate, 12*step)
+	default:
+		return recurring.StartDate.AddDate(0, 0, step)
+	}
+}
+
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	day := min(t.Day(), first.AddDate(0, 1, -1).Day())
+	return first.AddDate(0, 0, day-1)
+}
+
+// scheduleFrom moves the schedule to its first occurrence not before from.
+// Occurrences counts every slot passed over, so skipped slots still count
+// towards Count like an RRULE COUNT.
+func scheduleFrom(recurring *model.RecurringTransactions, from time.Time) {
+	n := 0
+	for recurringOccurrence(*recurring, n).Before(from) {
+		n++
+	}
+	recurring.Occurrences = n
+	recurring.NextRunAt = recurringOccurrence(*recurring, n)
+	settleStatus(recurring)
+}
+
+// advanceSchedule consumes the next occurrence, whether it was materialised or skipped.
+func advanceSchedule(recurring *model.RecurringTransactions) {
+	recurring.Occurrences++
+	recurring.NextRunAt = recurringOccurrence(*recurring, recurring.Occurrences)
+	settleStatus(recurring)
+}
+
+func settleStatus(recurring *model.RecurringTransactions) {
+	finished := (recurring.Count > 0 && recurring.Occurrences >= recurring.Count) ||
+		(recurring.EndDate != nil && recurring.NextRunAt.After(*recurring.EndDate))
+
+	switch {
+	case finished:
+		recurring.Status = model.RecurringCompleted
+	case recurring.Status == model.RecurringCompleted:
+		recurring.Status = model.RecurringActive
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Scheduler
+// ──────────────────────────────────────────────────────────────────────────────
+
+type RecurringScheduler struct {
+	recurringService RecurringTransactionsService
+	interval         time.Duration
+}
+
+func NewRecurringScheduler(recurringService RecurringTransactionsService) *RecurringScheduler {
+	return &RecurringScheduler{
+		recurringService: recurringService,
+		interval:         data.RECURRING_SCHEDULER_INTERVAL,
+	}
+}
+
+// Start materialises due occurrences right away, to catch up after downtime,
+// and then on every tick until ctx is cancelled.
+func (s *RecurringScheduler) Start(ctx context.Context) {
+	ctx = helper.WithRequestSource(ctx, data.REQUEST_SOURCE_SCHEDULER, "")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		created, err := s.recurringService.ProcessDueOccurrences(ctx)
+		if err != nil {
+			log.Error(data.LogRecurringProcessFailed, map[string]any{"service": data.RecurringService, "error": err.Error()})
+		} else if created > 0 {
+			log.Info(data.LogRecurringOccurrencesCreated, map[string]any{"service": data.RecurringService, "count": created})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,449 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type recurringTestDeps struct {
+	txManager          *mocks.MockTxManager
+	tx                 *mocks.MockTransaction
+	recurringRepo      *mocks.MockRecurringTransactionsRepository
+	categoryRepo       *mocks.MockCategoriesRepository
+	transactionService *mocks.MockTransactionsService
+}
+
+func newRecurringTestDeps() *recurringTestDeps {
+	return &recurringTestDeps{
+		txManager:          new(mocks.MockTxManager),
+		tx:                 new(mocks.MockTransaction),
+		recurringRepo:      new(mocks.MockRecurringTransactionsRepository),
+		categoryRepo:       new(mocks.MockCategoriesRepository),
+		transactionService: new(mocks.MockTransactionsService),
+	}
+}
+
+func (d *recurringTestDeps) service(now time.Time) RecurringTransactionsService {
+	return &recurringTransactionsService{
+		txManager:          d.txManager,
+		recurringRepo:      d.recurringRepo,
+		categoryRepo:       d.categoryRepo,
+		transactionService: d.transactionService,
+		now:                func() time.Time { return now },
+	}
+}
+
+func (d *recurringTestDeps) assertAll(t *testing.T) {
+	t.Helper()
+	d.txManager.AssertExpectations(t)
+	d.tx.AssertExpectations(t)
+	d.recurringRepo.AssertExpectations(t)
+	d.categoryRepo.AssertExpectations(t)
+	d.transactionService.AssertExpectations(t)
+}
+
+// ─────────────────────────────────────────────
+// Fixed UUIDs & Timestamps
+// ─────────────────────────────────────────────
+
+var (
+	recurringTestID = uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	recurringStart  = time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+)
+
+// ─────────────────────────────────────────────
+// Sample Data Factories
+// ─────────────────────────────────────────────
+
+func sampleRecurringModel(frequency model.RecurringFrequency) model.RecurringTransactions {
+	return model.RecurringTransactions{
+		Base:        model.Base{ID: recurringTestID},
+		WalletID:    walletTestID,
+		CategoryID:  catTestID,
+		Amount:      money.New(1500000),
+		Description: "Sewa kos",
+		Frequency:   frequency,
+		Interval:    1,
+		StartDate:   recurringStart,
+		NextRunAt:   recurringStart,
+		Status:      model.RecurringActive,
+		Category:    sampleExpenseCategory(),
+	}
+}
+
+func sampleRecurringRequest() dto.RecurringTransactionsRequest {
+	return dto.RecurringTransactionsRequest{
+		WalletID:    walletTestID.String(),
+		CategoryID:  catTestID.String(),
+		Amount:      money.New(1500000),
+		Description: "Sewa kos",
+		Frequency:   "monthly",
+		StartDate:   recurringStart,
+	}
+}
+
+// =====================================================================
+// Schedule
+// =====================================================================
+
+func TestRecurringOccurrence_MonthlyClampsToMonthEnd(t *testing.T) {
+	recurring := sampleRecurringModel(model.RecurringMonthly)
+
+	assert.Equal(t, time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC), recurringOccurrence(recurring, 1))
+	assert.Equal(t, time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC), recurringOccurrence(recurring, 2))
+	assert.Equal(t, time.Date(2025, 4, 30, 9, 0, 0, 0, time.UTC), recurringOccurrence(recurring, 3))
+}
+
+func TestRecurringOccurrence_WeeklyInterval(t *testing.T) {
+	recurring := sampleRecurringModel(model.RecurringWeekly)
+	recurring.Interval = 2
+
+	assert.Equal(t, recurringStart.AddDate(0, 0, 28), recurringOccurrence(recurring, 2))
+}
+
+func TestAdvanceSchedule_CompletesAfterCount(t *testing.T) {
+	recurring := sampleRecurringModel(model.RecurringDaily)
+	recurring.Count = 2
+
+	advanceSchedule(&recurring)
+	assert.Equal(t, model.RecurringActive, recurring.Status)
+
+	advanceSchedule(&recurring)
+	assert.Equal(t, model.RecurringCompleted, recurring.Status)
+}
+
+func TestAdvanceSchedule_CompletesAfterEndDate(t *testing.T) {
+	recurring := sampleRecurringModel(model.RecurringDaily)
+	endDate := recurringStart.AddDate(0, 0, 1)
+	recurring.EndDate = &endDate
+
+	advanceSchedule(&recurring)
+	assert.Equal(t, model.RecurringActive, recurring.Status)
+
+	advanceSchedule(&recurring)
+	assert.Equal(t, model.RecurringCompleted, recurring.Status)
+}
+
+// =====================================================================
+// CreateRecurringTransaction
+// =====================================================================
+
+func TestCreateRecurringTransaction_Success(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.recurringRepo.On("CreateRecurringTransaction", mock.Anything, nil, mock.MatchedBy(func(r model.RecurringTransactions) bool {
+		return r.NextRunAt.Equal(recurringStart) && r.Interval == 1 && r.Status == model.RecurringActive
+	})).Return(sampleRecurringModel(model.RecurringMonthly), nil)
+
+	result, err := svc.CreateRecurringTransaction(context.Background(), sampleRecurringRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, recurringTestID.String(), result.ID)
+	assert.Equal(t, "active", result.Status)
+	d.assertAll(t)
+}
+
+func TestCreateRecurringTransaction_InvalidFrequency(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	req := sampleRecurringRequest()
+	req.Frequency = "hourly"
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, catTestID.String()).Return(sampleExpenseCategory(), nil)
+
+	_, err := svc.CreateRecurringTransaction(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid frequency")
+	d.recurringRepo.AssertNotCalled(t, "CreateRecurringTransaction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateRecurringTransaction_FundTransferCategoryRejected(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, nil, catTestID.String()).Return(sampleFundTransferCashOut(), nil)
+
+	_, err := svc.CreateRecurringTransaction(context.Background(), sampleRecurringRequest())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid transaction type")
+	d.recurringRepo.AssertNotCalled(t, "CreateRecurringTransaction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// =====================================================================
+// ProcessDueOccurrences
+// =====================================================================
+
+func TestProcessDueOccurrences_CatchesUpMissedOccurrences(t *testing.T) {
+	d := newRecurringTestDeps()
+	now := recurringStart.AddDate(0, 0, 2).Add(time.Hour)
+	svc := d.service(now)
+
+	recurring := sampleRecurringModel(model.RecurringDaily)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.recurringRepo.On("GetDueRecurringTransactions", mock.Anything, d.tx, now, mock.Anything).Return([]model.RecurringTransactions{recurring}, nil)
+	d.transactionService.On("CreateTransaction", mock.Anything, mock.Anything).Return(dto.TransactionsResponse{}, nil).Times(3)
+	d.recurringRepo.On("UpdateRecurringTransaction", mock.Anything, d.tx, mock.Anything).Return(recurring, nil).Times(3)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	created, err := svc.ProcessDueOccurrences(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, created)
+
+	// Each occurrence is booked on its own date with its own idempotency key
+	for i, call := range d.transactionService.Calls {
+		req := call.Arguments.Get(1).(dto.TransactionsRequest)
+		assert.Equal(t, recurringStart.AddDate(0, 0, i), req.Date)
+		assert.Equal(t,
+			fmt.Sprintf("recurring:%s:%d", recurringTestID, recurringStart.AddDate(0, 0, i).Unix()),
+			helper.IdempotencyKeyFromContext(call.Arguments.Get(0).(context.Context)))
+	}
+	last := d.recurringRepo.Calls[len(d.recurringRepo.Calls)-1].Arguments.Get(2).(model.RecurringTransactions)
+	assert.Equal(t, 3, last.Occurrences)
+	assert.Equal(t, recurringStart.AddDate(0, 0, 3), last.NextRunAt)
+	d.assertAll(t)
+}
+
+func TestProcessDueOccurrences_KeyIgnoresOccurrenceNumbering(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	// scheduleFrom renumbered the schedule, but the occurrence date is the same
+	renumbered := sampleRecurringModel(model.RecurringDaily)
+	renumbered.Occurrences = 7
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.recurringRepo.On("GetDueRecurringTransactions", mock.Anything, d.tx, recurringStart, mock.Anything).Return([]model.RecurringTransactions{renumbered}, nil)
+	d.transactionService.On("CreateTransaction", mock.MatchedBy(func(ctx context.Context) bool {
+		return helper.IdempotencyKeyFromContext(ctx) == fmt.Sprintf("recurring:%s:%d", recurringTestID, recurringStart.Unix())
+	}), mock.Anything).Return(dto.TransactionsResponse{}, nil).Once()
+	d.recurringRepo.On("UpdateRecurringTransaction", mock.Anything, d.tx, mock.Anything).Return(renumbered, nil).Once()
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	created, err := svc.ProcessDueOccurrences(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created)
+	d.assertAll(t)
+}
+
+func TestProcessDueOccurrences_AlreadyBookedOccurrenceAdvances(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	recurring := sampleRecurringModel(model.RecurringDaily)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.recurringRepo.On("GetDueRecurringTransactions", mock.Anything, d.tx, recurringStart, mock.Anything).Return([]model.RecurringTransactions{recurring}, nil)
+	d.transactionService.On("CreateTransaction", mock.Anything, mock.Anything).Return(dto.TransactionsResponse{}, ErrIdempotencyKeyReused).Once()
+	d.recurringRepo.On("UpdateRecurringTransaction", mock.Anything, d.tx, mock.MatchedBy(func(r model.RecurringTransactions) bool {
+		return r.Occurrences == 1 && r.FailureCount == 0
+	})).Return(recurring, nil).Once()
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	created, err := svc.ProcessDueOccurrences(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created)
+	d.assertAll(t)
+}
+
+func TestProcessDueOccurrences_FailureBacksOff(t *testing.T) {
+	d := newRecurringTestDeps()
+	now := recurringStart.AddDate(0, 0, 2)
+	svc := d.service(now)
+
+	recurring := sampleRecurringModel(model.RecurringDaily)
+	recurring.FailureCount = 1
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.recurringRepo.On("GetDueRecurringTransactions", mock.Anything, d.tx, now, mock.Anything).Return([]model.RecurringTransactions{recurring}, nil)
+	d.transactionService.On("CreateTransaction", mock.Anything, mock.Anything).Return(dto.TransactionsResponse{}, errors.New("insufficient wallet balance")).Once()
+	d.recurringRepo.On("UpdateRecurringTransaction", mock.Anything, d.tx, mock.MatchedBy(func(r model.RecurringTransactions) bool {
+		// the schedule stays on the failed occurrence, retried after the backoff
+		return r.Occurrences == 0 &&
+			r.Status == model.RecurringActive &&
+			r.FailureCount == 2 &&
+			r.RetryAt != nil && r.RetryAt.Equal(now.Add(2*data.RECURRING_RETRY_BACKOFF)) &&
+			strings.Contains(r.LastError, "insufficient wallet balance")
+	})).Return(recurring, nil).Once()
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	created, err := svc.ProcessDueOccurrences(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, created)
+	d.assertAll(t)
+}
+
+func TestProcessDueOccurrences_PausesAfterMaxFailures(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	recurring := sampleRecurringModel(model.RecurringDaily)
+	recurring.FailureCount = data.RECURRING_MAX_FAILURES - 1
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.recurringRepo.On("GetDueRecurringTransactions", mock.Anything, d.tx, recurringStart, mock.Anything).Return([]model.RecurringTransactions{recurring}, nil)
+	d.transactionService.On("CreateTransaction", mock.Anything, mock.Anything).Return(dto.TransactionsResponse{}, errors.New("category not found")).Once()
+	d.recurringRepo.On("UpdateRecurringTransaction", mock.Anything, d.tx, mock.MatchedBy(func(r model.RecurringTransactions) bool {
+		return r.Status == model.RecurringPaused && r.FailureCount == data.RECURRING_MAX_FAILURES && r.RetryAt == nil
+	})).Return(recurring, nil).Once()
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	created, err := svc.ProcessDueOccurrences(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, created)
+	d.assertAll(t)
+}
+
+func TestProcessDueOccurrences_RepoError(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.recurringRepo.On("GetDueRecurringTransactions", mock.Anything, d.tx, recurringStart, mock.Anything).Return([]model.RecurringTransactions{}, errors.New("db error"))
+	d.tx.On("Rollback").Return(nil)
+
+	created, err := svc.ProcessDueOccurrences(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, created)
+	d.tx.AssertNotCalled(t, "Commit")
+	d.assertAll(t)
+}
+
+func TestRetryBackoff_DoublesUpToMax(t *testing.T) {
+	assert.Equal(t, data.RECURRING_RETRY_BACKOFF, retryBackoff(1, data.RECURRING_RETRY_BACKOFF, data.RECURRING_RETRY_BACKOFF_MAX))
+	assert.Equal(t, 2*data.RECURRING_RETRY_BACKOFF, retryBackoff(2, data.RECURRING_RETRY_BACKOFF, data.RECURRING_RETRY_BACKOFF_MAX))
+	assert.Equal(t, 4*data.RECURRING_RETRY_BACKOFF, retryBackoff(3, data.RECURRING_RETRY_BACKOFF, data.RECURRING_RETRY_BACKOFF_MAX))
+	assert.Equal(t, data.RECURRING_RETRY_BACKOFF_MAX, retryBackoff(100, data.RECURRING_RETRY_BACKOFF, data.RECURRING_RETRY_BACKOFF_MAX))
+}
+
+// =====================================================================
+// Pause / Resume / Skip
+// =====================================================================
+
+func TestPauseRecurringTransaction_Success(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	d.recurringRepo.On("GetRecurringTransactionByID", mock.Anything, nil, recurringTestID.String()).Return(sampleRecurringModel(model.RecurringMonthly), nil)
+	d.recurringRepo.On("UpdateRecurringTransaction", mock.Anything, nil, mock.MatchedBy(func(r model.RecurringTransactions) bool {
+		return r.Status == model.RecurringPaused
+	})).Return(model.RecurringTransactions{Status: model.RecurringPaused}, nil)
+
+	result, err := svc.PauseRecurringTransaction(context.Background(), recurringTestID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "paused", result.Status)
+	d.assertAll(t)
+}
+
+func TestPauseRecurringTransaction_NotActive(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	recurring := sampleRecurringModel(model.RecurringMonthly)
+	recurring.Status = model.RecurringCompleted
+	d.recurringRepo.On("GetRecurringTransactionByID", mock.Anything, nil, recurringTestID.String()).Return(recurring, nil)
+
+	_, err := svc.PauseRecurringTransaction(context.Background(), recurringTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid recurring transaction status")
+	d.assertAll(t)
+}
+
+func TestResumeRecurringTransaction_SkipsOccurrencesMissedWhilePaused(t *testing.T) {
+	d := newRecurringTestDeps()
+	now := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+	svc := d.service(now)
+
+	recurring := sampleRecurringModel(model.RecurringMonthly)
+	recurring.Status = model.RecurringPaused
+	d.recurringRepo.On("GetRecurringTransactionByID", mock.Anything, nil, recurringTestID.String()).Return(recurring, nil)
+	d.recurringRepo.On("UpdateRecurringTransaction", mock.Anything, nil, mock.MatchedBy(func(r model.RecurringTransactions) bool {
+		return r.Status == model.RecurringActive &&
+			r.Occurrences == 3 &&
+			r.NextRunAt.Equal(time.Date(2025, 4, 30, 9, 0, 0, 0, time.UTC))
+	})).Return(recurring, nil)
+
+	_, err := svc.ResumeRecurringTransaction(context.Background(), recurringTestID.String())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestResumeRecurringTransaction_ClearsAutoPauseFailures(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	recurring := sampleRecurringModel(model.RecurringMonthly)
+	recurring.Status = model.RecurringPaused
+	recurring.FailureCount = data.RECURRING_MAX_FAILURES
+	recurring.LastError = "category not found"
+	d.recurringRepo.On("GetRecurringTransactionByID", mock.Anything, nil, recurringTestID.String()).Return(recurring, nil)
+	d.recurringRepo.On("UpdateRecurringTransaction", mock.Anything, nil, mock.MatchedBy(func(r model.RecurringTransactions) bool {
+		return r.Status == model.RecurringActive && r.FailureCount == 0 && r.LastError == "" && r.RetryAt == nil
+	})).Return(recurring, nil)
+
+	_, err := svc.ResumeRecurringTransaction(context.Background(), recurringTestID.String())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestSkipNextOccurrence_Success(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	d.recurringRepo.On("GetRecurringTransactionByID", mock.Anything, nil, recurringTestID.String()).Return(sampleRecurringModel(model.RecurringMonthly), nil)
+	d.recurringRepo.On("UpdateRecurringTransaction", mock.Anything, nil, mock.MatchedBy(func(r model.RecurringTransactions) bool {
+		return r.Occurrences == 1 && r.NextRunAt.Equal(time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC))
+	})).Return(sampleRecurringModel(model.RecurringMonthly), nil)
+
+	_, err := svc.SkipNextOccurrence(context.Background(), recurringTestID.String())
+
+	assert.NoError(t, err)
+	d.transactionService.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestSkipNextOccurrence_NotFound(t *testing.T) {
+	d := newRecurringTestDeps()
+	svc := d.service(recurringStart)
+
+	d.recurringRepo.On("GetRecurringTransactionByID", mock.Anything, nil, recurringTestID.String()).Return(model.RecurringTransactions{}, errors.New("recurring transaction not found"))
+
+	_, err := svc.SkipNextOccurrence(context.Background(), recurringTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	d.assertAll(t)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+)
+
+type ReportsService interface {
+	GetTransactionSummary(ctx context.Context, q repository.CursorQuery, groupBy string) (dto.TransactionSummaryResponse, error)
+}
+
+type reportsService struct {
+	transactionRepo repository.TransactionsRepository
+}
+
+func NewReportsService(transactionRepo repository.TransactionsRepository) ReportsService {
+	return &reportsService{
+		transactionRepo: transactionRepo,
+	}
+}
+
+func (report_serv *reportsService) GetTransactionSummary(ctx context.Context, q repository.CursorQuery, groupBy string) (dto.TransactionSummaryResponse, error) {
+	if groupBy == "" {
+		groupBy = string(repository.AggregateByMonth)
+	}
+	if q.BaseCurrency != "" {
+		baseCurrency, err := normalizeCurrency(q.BaseCurrency)
+		if err != nil {
+			return dto.TransactionSummaryResponse{}, fmt.Errorf("get transaction summary: %w", err)
+		}
+		q.BaseCurrency = baseCurrency
+	}
+	if err := normalizeTagFilter(&q); err != nil {
+		return dto.TransactionSummaryResponse{}, fmt.Errorf("get transaction summary: %w", err)
+	}
+	if err := normalizeStatusFilter(q.Statuses); err != nil {
+		return dto.TransactionSummaryResponse{}, fmt.Errorf("get transaction summary: %w", err)
+	}
+
+	rows, err := report_serv.transactionRepo.AggregateTransactions(ctx, nil, q, repository.AggregateGroupBy(groupBy))
+	if err != nil {
+		return dto.TransactionSummaryResponse{}, fmt.Errorf("get transaction summary [group_by=%s]: %w", groupBy, err)
+	}
+
+	// Rows arrive ordered by key with one row per category type; fold them into buckets
+	summary := dto.TransactionSummaryResponse{
+		GroupBy:  groupBy,
+		Currency: q.BaseCurrency,
+		Buckets:  make([]dto.SummaryBucket, 0, len(rows)),
+	}
+	var unconverted int64
+	for _, row := range rows {
+		unconverted += row.Unconverted
+
+		if n := len(summary.Buckets); n == 0 || summary.Buckets[n-1].Key != row.Key {
+			summary.Buckets = append(summary.Buckets, dto.SummaryBucket{Key: row.Key, Label: row.Label})
+		}
+		bucket := &summary.Buckets[len(summary.Buckets)-1]
+
+		totals := dto.SummaryTotals{Total: row.Total, Count: row.Count, Average: row.Average}
+		switch model.CategoryType(row.CategoryType) {
+		case model.Income:
+			bucket.Income = totals
+			summary.Income = addSummaryTotals(summary.Income, totals)
+		case model.Expense:
+			bucket.Expense = totals
+			summary.Expense = addSummaryTotals(summary.Expense, totals)
+		}
+		bucket.Net = bucket.Income.Total.Sub(bucket.Expense.Total)
+	}
+	summary.Net = summary.Income.Total.Sub(summary.Expense.Total)
+
+	// ? Exchanging at a rate other than the day's gains or loses value, which no income or expense shows
+	if q.BaseCurrency != "" {
+		fx, err := report_serv.transactionRepo.SumTransferFXGainLoss(ctx, nil, q)
+		if err != nil {
+			return dto.TransactionSummaryResponse{}, fmt.Errorf("get transaction summary: %w", err)
+		}
+		unconverted += fx.Unconverted
+		summary.FXGainLoss = &fx.GainLoss
+	}
+
+	// A partial total in the base currency would look right and be wrong
+	if unconverted > 0 {
+		return dto.TransactionSummaryResponse{}, fmt.Errorf("fx rate not found for %d transaction lines [base_currency=%s]", unconverted, q.BaseCurrency)
+	}
+
+	return summary, nil
+}
+
+func addSummaryTotals(a, b dto.SummaryTotals) dto.SummaryTotals {
+	sum := dto.SummaryTotals{Total: a.Total.Add(b.Total), Count: a.Count + b.Count}
+	if sum.Count > 0 {
+		sum.Average = sum.Total.Div(sum.Count)
+	}
+	return sum
+}
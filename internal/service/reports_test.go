@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type reportTestDeps struct {
+	transactionRepo *mocks.MockTransactionsRepository
+}
+
+func newReportTestDeps() *reportTestDeps {
+	return &reportTestDeps{
+		transactionRepo: new(mocks.MockTransactionsRepository),
+	}
+}
+
+func (d *reportTestDeps) service() ReportsService {
+	return NewReportsService(d.transactionRepo)
+}
+
+func (d *reportTestDeps) assertAll(t *testing.T) {
+	t.Helper()
+	d.transactionRepo.AssertExpectations(t)
+}
+
+func sampleReportQuery() repository.CursorQuery {
+	return repository.CursorQuery{WalletIDs: []string{walletTestID.String()}}
+}
+
+// =====================================================================
+// GetTransactionSummary
+// =====================================================================
+
+func TestGetTransactionSummary_FoldsRowsIntoBuckets(t *testing.T) {
+	d := newReportTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("AggregateTransactions", mock.Anything, nil, sampleReportQuery(), repository.AggregateByMonth).Return([]repository.AggregateRow{
+		{Key: "2025-05-01", Label: "2025-05-01", CategoryType: "expense", Total: money.New(300000), Count: 3, Average: money.New(100000)},
+		{Key: "2025-05-01", Label: "2025-05-01", CategoryType: "income", Total: money.New(5000000), Count: 1, Average: money.New(5000000)},
+		{Key: "2025-06-01", Label: "2025-06-01", CategoryType: "expense", Total: money.New(100000), Count: 1, Average: money.New(100000)},
+	}, nil)
+
+	result, err := svc.GetTransactionSummary(context.Background(), sampleReportQuery(), "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "month", result.GroupBy)
+	assert.Len(t, result.Buckets, 2)
+	assert.Equal(t, money.New(5000000), result.Buckets[0].Income.Total)
+	assert.Equal(t, money.New(300000), result.Buckets[0].Expense.Total)
+	assert.Equal(t, money.New(4700000), result.Buckets[0].Net)
+	assert.Equal(t, money.New(-100000), result.Buckets[1].Net)
+	assert.Equal(t, int64(4), result.Expense.Count)
+	assert.Equal(t, money.New(100000), result.Expense.Average)
+	assert.Equal(t, money.New(4600000), result.Net)
+	d.assertAll(t)
+}
+
+func TestGetTransactionSummary_Empty(t *testing.T) {
+	d := newReportTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("AggregateTransactions", mock.Anything, nil, sampleReportQuery(), repository.AggregateByCategory).Return([]repository.AggregateRow{}, nil)
+
+	result, err := svc.GetTransactionSummary(context.Background(), sampleReportQuery(), "category")
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Buckets)
+	assert.Zero(t, result.Income.Average)
+	assert.Nil(t, result.FXGainLoss)
+	d.assertAll(t)
+}
+
+func TestGetTransactionSummary_InvalidGroupBy(t *testing.T) {
+	d := newReportTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("AggregateTransactions", mock.Anything, nil, sampleReportQuery(), repository.AggregateGroupBy("hour")).Return([]repository.AggregateRow(nil), errors.New("invalid group by [group_by=hour]"))
+
+	_, err := svc.GetTransactionSummary(context.Background(), sampleReportQuery(), "hour")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid group by")
+	d.assertAll(t)
+}
+
+func TestGetTransactionSummary_BaseCurrency(t *testing.T) {
+	d := newReportTestDeps()
+	svc := d.service()
+
+	q := sampleReportQuery()
+	q.BaseCurrency = "USD"
+	d.transactionRepo.On("AggregateTransactions", mock.Anything, nil, q, repository.AggregateByMonth).Return([]repository.AggregateRow{
+		{Key: "2025-06-01", Label: "2025-06-01", CategoryType: "expense", Total: money.MustParse("12.5"), Count: 2, Average: money.MustParse("6.25")},
+	}, nil)
+
+	d.transactionRepo.On("SumTransferFXGainLoss", mock.Anything, nil, q).Return(repository.TransferFXTotal{GainLoss: money.MustParse("-0.42"), Count: 1}, nil)
+
+	q.BaseCurrency = "usd"
+	result, err := svc.GetTransactionSummary(context.Background(), q, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", result.Currency)
+	assert.Equal(t, money.MustParse("12.5"), result.Expense.Total)
+	if assert.NotNil(t, result.FXGainLoss) {
+		assert.Equal(t, money.MustParse("-0.42"), *result.FXGainLoss)
+	}
+	d.assertAll(t)
+}
+
+func TestGetTransactionSummary_BaseCurrencyMissingRate(t *testing.T) {
+	d := newReportTestDeps()
+	svc := d.service()
+
+	q := sampleReportQuery()
+	q.BaseCurrency = "USD"
+	d.transactionRepo.On("AggregateTransactions", mock.Anything, nil, q, repository.AggregateByMonth).Return([]repository.AggregateRow{
+		{Key: "2025-06-01", Label: "2025-06-01", CategoryType: "expense", Total: money.MustParse("12.5"), Count: 3, Average: money.MustParse("4.17"), Unconverted: 1},
+	}, nil)
+	d.transactionRepo.On("SumTransferFXGainLoss", mock.Anything, nil, q).Return(repository.TransferFXTotal{}, nil)
+
+	_, err := svc.GetTransactionSummary(context.Background(), q, "")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "fx rate not found for 1 transaction lines")
+	d.assertAll(t)
+}
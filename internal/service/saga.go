@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
+	wpb "github.com/MuhammadMiftaa/Refina-Protobuf/wallet"
+	"github.com/google/uuid"
+)
+
+const (
+	sagaReasonNotCommitted = "local transaction was not committed"
+	sagaReasonResumed      = "resumed after interruption"
+
+	sagaCompensationKeySuffix = ":compensate"
+)
+
+// ErrSagaClaimed is returned by Complete when the saga was claimed for
+// compensation first: its wallet updates are being reverted, so the local
+// rows must not be committed.
+var ErrSagaClaimed = errors.New("saga claimed for compensation")
+
+// SagaOrchestrator coordinates wallet-service balance updates with the local
+// database transaction. Every remote update is recorded as a saga step before
+// it is sent, the saga is marked completed inside the local transaction, and
+// any saga that does not reach completion is compensated in reverse order.
+type SagaOrchestrator struct {
+	sagaRepo     repository.SagaLogRepository
+	walletClient client.WalletClient
+	interval     time.Duration
+	resumeAfter  time.Duration
+	batchSize    int
+}
+
+func NewSagaOrchestrator(sagaRepo repository.SagaLogRepository, walletClient client.WalletClient) *SagaOrchestrator {
+	return &SagaOrchestrator{
+		sagaRepo:     sagaRepo,
+		walletClient: walletClient,
+		interval:     data.SAGA_RESUME_INTERVAL,
+		resumeAfter:  data.SAGA_RESUME_AFTER,
+		batchSize:    data.SAGA_RESUME_BATCH,
+	}
+}
+
+// NewSaga returns an in-memory saga. It is persisted lazily on the first
+// wallet step, so operations that never touch wallet-service leave no rows.
+func (o *SagaOrchestrator) NewSaga(sagaType string) *model.SagaLog {
+	return &model.SagaLog{
+		Type:   sagaType,
+		Status: model.SagaStarted,
+	}
+}
+
+// UpdateWalletBalance records a step for the saga and adjusts the wallet by
+// delta through wallet-service. The step ID doubles as the idempotency key.
+func (o *SagaOrchestrator) UpdateWalletBalance(ctx context.Context, saga *model.SagaLog, wallet *wpb.Wallet, delta money.Amount) error {
+	walletID, err := helper.ParseUUID(wallet.GetId())
+	if err != nil {
+		return fmt.Errorf("invalid wallet id [id=%s]: %w", wallet.GetId(), err)
+	}
+
+	if saga.ID == uuid.Nil {
+		saga.ID = uuid.New()
+		if err := o.sagaRepo.CreateSaga(ctx, nil, saga); err != nil {
+			saga.ID = uuid.Nil
+			return fmt.Errorf("create saga log: %w", err)
+		}
+	}
+
+	balance := client.WalletBalance(wallet)
+	step := model.SagaLogStep{
+		Base:            model.Base{ID: uuid.New()},
+		SagaID:          saga.ID,
+		Sequence:        len(saga.Steps) + 1,
+		WalletID:        walletID,
+		Delta:           delta,
+		PreviousBalance: balance,
+		NewBalance:      balance.Add(delta),
+		Status:          model.SagaStepPending,
+	}
+	if err := o.sagaRepo.CreateStep(ctx, nil, &step); err != nil {
+		return fmt.Errorf("create saga step [saga_id=%s]: %w", saga.ID, err)
+	}
+	saga.Steps = append(saga.Steps, step)
+	idx := len(saga.Steps) - 1
+
+	// A failed call may still have reached wallet-service (e.g. a timeout), so
+	// the step stays pending and compensation replays it by its key first.
+	updated, err := o.walletClient.AdjustBalance(ctx, wallet.GetId(), delta, step.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if updated != nil {
+		wallet.Balance = updated.GetBalance()
+	} else {
+		wallet.Balance = step.NewBalance.Float64()
+	}
+
+	o.setStepStatus(ctx, saga, idx, model.SagaStepApplied)
+	return nil
+}
+
+// Complete marks the saga as completed inside the caller's database
+// transaction, so the completion is committed atomically with the local rows.
+// It fails with ErrSagaClaimed when the saga was claimed for compensation,
+// e.g. by the resumer after a slow request, and the caller must roll back.
+func (o *SagaOrchestrator) Complete(ctx context.Context, tx repository.Transaction, saga *model.SagaLog) error {
+	if saga.ID == uuid.Nil {
+		return nil
+	}
+
+	completed, err := o.sagaRepo.CompleteSaga(ctx, tx, saga.ID.String())
+	if err != nil {
+		return fmt.Errorf("complete saga [saga_id=%s]: %w", saga.ID, err)
+	}
+	if !completed {
+		return fmt.Errorf("complete saga [saga_id=%s]: %w", saga.ID, ErrSagaClaimed)
+	}
+
+	return nil
+}
+
+// Compensate reverts every applied step of the saga in reverse order. A step
+// left pending by a failed call or a crash is first replayed with its own
+// idempotency key: wallet-service applies it if the original never arrived
+// and deduplicates it otherwise, so afterwards the step is known to be
+// applied and can be reverted like any other. The saga is claimed first, so
+// one that completed or is reverted by another caller is left alone.
+func (o *SagaOrchestrator) Compensate(ctx context.Context, saga *model.SagaLog, reason string) error {
+	if saga.ID == uuid.Nil || (saga.Status != model.SagaStarted && saga.Status != model.SagaCompensating) {
+		return nil
+	}
+
+	// The request context may already be cancelled; compensation must still run.
+	ctx = context.WithoutCancel(ctx)
+
+	// ! Claim first: a completion committing concurrently holds the saga row,
+	// so the claim waits for it and then finds the saga completed
+	claimed, err := o.sagaRepo.ClaimSaga(ctx, nil, saga.ID.String(), time.Now().Add(-o.resumeAfter))
+	if err != nil {
+		return fmt.Errorf("claim saga [saga_id=%s]: %w", saga.ID, err)
+	}
+	if !claimed {
+		return nil
+	}
+	saga.Status = model.SagaCompensating
+
+	for i := len(saga.Steps) - 1; i >= 0; i-- {
+		step := saga.Steps[i]
+		if step.Status != model.SagaStepApplied && step.Status != model.SagaStepPending {
+			continue
+		}
+
+		if step.Status == model.SagaStepPending {
+			if _, err := o.walletClient.AdjustBalance(ctx, step.WalletID.String(), step.Delta, step.ID.String()); err != nil {
+				return o.failSaga(ctx, saga, fmt.Errorf("compensate step %d: replay pending update [wallet_id=%s]: %w", step.Sequence, step.WalletID, err))
+			}
+			o.setStepStatus(ctx, saga, i, model.SagaStepApplied)
+		}
+
+		if _, err := o.walletClient.AdjustBalance(ctx, step.WalletID.String(), step.Delta.Neg(), step.ID.String()+sagaCompensationKeySuffix); err != nil {
+			return o.failSaga(ctx, saga, fmt.Errorf("compensate step %d: update wallet balance [id=%s]: %w", step.Sequence, step.WalletID, err))
+		}
+
+		o.setStepStatus(ctx, saga, i, model.SagaStepCompensated)
+	}
+
+	if err := o.sagaRepo.UpdateSagaStatus(ctx, nil, saga.ID.String(), model.SagaCompensated, reason); err != nil {
+		log.Error(data.LogSagaStatusUpdateFailed, map[string]any{
+			"service": data.SagaService,
+			"saga_id": saga.ID.String(),
+			"status":  model.SagaCompensated,
+			"error":   err.Error(),
+		})
+	}
+	saga.Status = model.SagaCompensated
+
+	log.Info(data.LogSagaCompensated, map[string]any{
+		"service":   data.SagaService,
+		"saga_id":   saga.ID.String(),
+		"saga_type": saga.Type,
+		"steps":     len(saga.Steps),
+		"cause":     reason,
+	})
+
+	return nil
+}
+
+// Start compensates incomplete sagas on every tick, so sagas left behind by
+// a crash are picked up once they are older than resumeAfter instead of
+// waiting for the next restart.
+func (o *SagaOrchestrator) Start(ctx context.Context) {
+	if err := o.ResumeIncompleteSagas(ctx); err != nil {
+		log.Error(data.LogSagaResumeFailed, map[string]any{"service": data.SagaService, "error": err.Error()})
+	}
+
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := o.ResumeIncompleteSagas(ctx); err != nil {
+				log.Error(data.LogSagaResumeFailed, map[string]any{"service": data.SagaService, "error": err.Error()})
+			}
+		}
+	}
+}
+
+// ResumeIncompleteSagas compensates sagas left in the started state by a
+// previous process, e.g. after a crash between the wallet update and commit,
+// and sagas whose compensation stopped half way. Each saga is claimed before
+// it is reverted, so a request still completing it wins. Batches are
+// processed until none are left.
+func (o *SagaOrchestrator) ResumeIncompleteSagas(ctx context.Context) error {
+	total := 0
+	var lastID uuid.UUID
+	for {
+		sagas, err := o.sagaRepo.GetIncompleteSagas(ctx, time.Now().Add(-o.resumeAfter), o.batchSize)
+		if err != nil {
+			return fmt.Errorf("get incomplete sagas: %w", err)
+		}
+		// Stop when nothing is left, or when a saga whose status could not be
+		// saved comes back first again and the loop would make no progress
+		if len(sagas) == 0 || sagas[0].ID == lastID {
+			break
+		}
+		lastID = sagas[0].ID
+
+		for i := range sagas {
+			if err := o.Compensate(ctx, &sagas[i], sagaReasonResumed); err != nil {
+				log.Error(data.LogSagaResumeFailed, map[string]any{
+					"service": data.SagaService,
+					"saga_id": sagas[i].ID.String(),
+					"error":   err.Error(),
+				})
+			}
+		}
+		total += len(sagas)
+
+		if len(sagas) < o.batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Info(data.LogSagaResumeCompleted, map[string]any{
+			"service": data.SagaService,
+			"count":   total,
+		})
+	}
+
+	return nil
+}
+
+func (o *SagaOrchestrator) failSaga(ctx context.Context, saga *model.SagaLog, err error) error {
+	if updateErr := o.sagaRepo.UpdateSagaStatus(ctx, nil, saga.ID.String(), model.SagaFailed, err.Error()); updateErr != nil {
+		log.Error(data.LogSagaStatusUpdateFailed, map[string]any{
+			"service": data.SagaService,
+			"saga_id": saga.ID.String(),
+			"status":  model.SagaFailed,
+			"error":   updateErr.Error(),
+		})
+	}
+	saga.Status = model.SagaFailed
+
+	log.Error(data.LogSagaCompensationFailed, map[string]any{
+		"service":   data.SagaService,
+		"saga_id":   saga.ID.String(),
+		"saga_type": saga.Type,
+		"error":     err.Error(),
+	})
+
+	return err
+}
+
+func (o *SagaOrchestrator) setStepStatus(ctx context.Context, saga *model.SagaLog, idx int, status model.SagaStepStatus) {
+	saga.Steps[idx].Status = status
+	if err := o.sagaRepo.UpdateStepStatus(ctx, nil, saga.Steps[idx].ID.String(), status); err != nil {
+		log.Error(data.LogSagaStepUpdateFailed, map[string]any{
+			"service": data.SagaService,
+			"saga_id": saga.ID.String(),
+			"step_id": saga.Steps[idx].ID.String(),
+			"status":  status,
+			"error":   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,454 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type sagaTestDeps struct {
+	sagaRepo     *mocks.MockSagaLogRepository
+	walletClient *mocks.MockWalletClient
+}
+
+func newSagaTestDeps() *sagaTestDeps {
+	return &sagaTestDeps{
+		sagaRepo:     new(mocks.MockSagaLogRepository),
+		walletClient: new(mocks.MockWalletClient),
+	}
+}
+
+func (d *sagaTestDeps) orchestrator() *SagaOrchestrator {
+	return NewSagaOrchestrator(d.sagaRepo, d.walletClient)
+}
+
+func (d *sagaTestDeps) assertAll(t *testing.T) {
+	t.Helper()
+	d.sagaRepo.AssertExpectations(t)
+	d.walletClient.AssertExpectations(t)
+}
+
+// ─────────────────────────────────────────────
+// Sample Data Factories
+// ─────────────────────────────────────────────
+
+func sampleSagaLog(steps ...model.SagaLogStep) model.SagaLog {
+	sagaID := uuid.MustParse("88888888-8888-8888-8888-888888888888")
+	for i := range steps {
+		steps[i].SagaID = sagaID
+		steps[i].Sequence = i + 1
+	}
+	return model.SagaLog{
+		Base:   model.Base{ID: sagaID},
+		Type:   data.SAGA_TYPE_TRANSACTION_TRANSFER,
+		Status: model.SagaStarted,
+		Steps:  steps,
+	}
+}
+
+func sampleSagaStep(walletID uuid.UUID, previous, delta money.Amount, status model.SagaStepStatus) model.SagaLogStep {
+	return model.SagaLogStep{
+		Base:            model.Base{ID: uuid.New()},
+		WalletID:        walletID,
+		Delta:           delta,
+		PreviousBalance: previous,
+		NewBalance:      previous.Add(delta),
+		Status:          status,
+	}
+}
+
+// =====================================================================
+// UpdateWalletBalance
+// =====================================================================
+
+func TestUpdateWalletBalance_Success(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	saga := o.NewSaga(data.SAGA_TYPE_TRANSACTION_CREATE)
+	wallet := sampleWalletProto(walletTestID, 200000)
+
+	d.sagaRepo.On("CreateSaga", mock.Anything, nil, saga).Return(nil)
+	d.sagaRepo.On("CreateStep", mock.Anything, nil, mock.MatchedBy(func(s *model.SagaLogStep) bool {
+		return s.Delta == money.New(-50000) && s.PreviousBalance == money.New(200000) && s.NewBalance == money.New(150000) && s.Status == model.SagaStepPending
+	})).Return(nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 150000), nil)
+	d.sagaRepo.On("UpdateStepStatus", mock.Anything, nil, mock.Anything, model.SagaStepApplied).Return(nil)
+
+	err := o.UpdateWalletBalance(context.Background(), saga, wallet, money.New(-50000))
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, saga.ID)
+	assert.Equal(t, float64(150000), wallet.GetBalance())
+	assert.Len(t, saga.Steps, 1)
+	assert.Equal(t, model.SagaStepApplied, saga.Steps[0].Status)
+	// the step ID is reused as the wallet-service idempotency key
+	d.walletClient.AssertCalled(t, "AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), saga.Steps[0].ID.String())
+	d.assertAll(t)
+}
+
+func TestUpdateWalletBalance_CreateSagaError(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	saga := o.NewSaga(data.SAGA_TYPE_TRANSACTION_CREATE)
+	wallet := sampleWalletProto(walletTestID, 200000)
+
+	d.sagaRepo.On("CreateSaga", mock.Anything, nil, saga).Return(errors.New("db error"))
+
+	err := o.UpdateWalletBalance(context.Background(), saga, wallet, money.New(-50000))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "create saga log")
+	assert.Equal(t, uuid.Nil, saga.ID)
+	assert.Equal(t, float64(200000), wallet.GetBalance())
+	d.walletClient.AssertNotCalled(t, "AdjustBalance")
+	d.assertAll(t)
+}
+
+func TestUpdateWalletBalance_AdjustBalanceErrorLeavesStepPending(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	saga := o.NewSaga(data.SAGA_TYPE_TRANSACTION_CREATE)
+	wallet := sampleWalletProto(walletTestID, 200000)
+
+	d.sagaRepo.On("CreateSaga", mock.Anything, nil, saga).Return(nil)
+	d.sagaRepo.On("CreateStep", mock.Anything, nil, mock.Anything).Return(nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).
+		Return(nil, errors.New("grpc timeout"))
+
+	err := o.UpdateWalletBalance(context.Background(), saga, wallet, money.New(-50000))
+
+	assert.Error(t, err)
+	assert.Equal(t, float64(200000), wallet.GetBalance())
+	assert.Equal(t, model.SagaStepPending, saga.Steps[0].Status)
+	d.sagaRepo.AssertNotCalled(t, "UpdateStepStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// Complete
+// =====================================================================
+
+func TestComplete_NotPersistedIsNoop(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	err := o.Complete(context.Background(), nil, o.NewSaga(data.SAGA_TYPE_TRANSACTION_CREATE))
+
+	assert.NoError(t, err)
+	d.sagaRepo.AssertNotCalled(t, "CompleteSaga")
+	d.assertAll(t)
+}
+
+func TestComplete_UpdateStatusError(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	saga := sampleSagaLog()
+	tx := new(mocks.MockTransaction)
+
+	d.sagaRepo.On("CompleteSaga", mock.Anything, tx, saga.ID.String()).Return(false, errors.New("db error"))
+
+	err := o.Complete(context.Background(), tx, &saga)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "complete saga")
+	d.assertAll(t)
+}
+
+func TestComplete_ClaimedSagaFailsCommit(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	// The resumer claimed the saga while the request was still running
+	saga := sampleSagaLog(sampleSagaStep(walletTestID, money.New(200000), money.New(-50000), model.SagaStepApplied))
+	tx := new(mocks.MockTransaction)
+
+	d.sagaRepo.On("CompleteSaga", mock.Anything, tx, saga.ID.String()).Return(false, nil)
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, saga.ID.String(), mock.Anything).Return(false, nil)
+
+	err := o.Complete(context.Background(), tx, &saga)
+	assert.ErrorIs(t, err, ErrSagaClaimed)
+
+	// The caller's own compensation leaves the saga to its claimant
+	assert.NoError(t, o.Compensate(context.Background(), &saga, sagaReasonNotCommitted))
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// Compensate
+// =====================================================================
+
+func TestCompensate_RevertsStepsInReverseOrder(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	saga := sampleSagaLog(
+		sampleSagaStep(walletTestID, money.New(500000), money.New(-102000), model.SagaStepApplied),
+		sampleSagaStep(wallet2ID, money.New(50000), money.New(100000), model.SagaStepApplied),
+	)
+	fromWallet := sampleWalletProto(walletTestID, 398000)
+	toWallet := sampleWalletProto(wallet2ID, 150000)
+	toWallet.Id = wallet2ID.String()
+
+	var order []string
+	d.walletClient.On("AdjustBalance", mock.Anything, wallet2ID.String(), money.New(-100000), mock.Anything).
+		Run(func(args mock.Arguments) { order = append(order, args.String(1)) }).
+		Return(toWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(102000), mock.Anything).
+		Run(func(args mock.Arguments) { order = append(order, args.String(1)) }).
+		Return(fromWallet, nil)
+	d.sagaRepo.On("UpdateStepStatus", mock.Anything, nil, mock.Anything, model.SagaStepCompensated).Return(nil).Times(2)
+	d.sagaRepo.On("UpdateSagaStatus", mock.Anything, nil, saga.ID.String(), model.SagaCompensated, sagaReasonNotCommitted).Return(nil)
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, saga.ID.String(), mock.Anything).Return(true, nil)
+
+	err := o.Compensate(context.Background(), &saga, sagaReasonNotCommitted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{wallet2ID.String(), walletTestID.String()}, order)
+	// applied steps are reverted without re-reading the wallet
+	d.walletClient.AssertNotCalled(t, "GetWalletByID", mock.Anything, mock.Anything)
+	assert.Equal(t, model.SagaCompensated, saga.Status)
+	d.assertAll(t)
+}
+
+func TestCompensate_ReplaysPendingStepBeforeReverting(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	saga := sampleSagaLog(sampleSagaStep(walletTestID, money.New(200000), money.New(-50000), model.SagaStepPending))
+	stepID := saga.Steps[0].ID.String()
+
+	// Whether or not the original call arrived, replaying its key leaves it applied exactly once
+	var order []string
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), stepID).
+		Run(func(args mock.Arguments) { order = append(order, args.String(3)) }).
+		Return(sampleWalletProto(walletTestID, 150000), nil).Once()
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(50000), stepID+sagaCompensationKeySuffix).
+		Run(func(args mock.Arguments) { order = append(order, args.String(3)) }).
+		Return(sampleWalletProto(walletTestID, 200000), nil).Once()
+	d.sagaRepo.On("UpdateStepStatus", mock.Anything, nil, stepID, model.SagaStepApplied).Return(nil).Once()
+	d.sagaRepo.On("UpdateStepStatus", mock.Anything, nil, stepID, model.SagaStepCompensated).Return(nil).Once()
+	d.sagaRepo.On("UpdateSagaStatus", mock.Anything, nil, saga.ID.String(), model.SagaCompensated, mock.Anything).Return(nil)
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, saga.ID.String(), mock.Anything).Return(true, nil)
+
+	err := o.Compensate(context.Background(), &saga, sagaReasonResumed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{stepID, stepID + sagaCompensationKeySuffix}, order)
+	// the decision never depends on a balance snapshot
+	d.walletClient.AssertNotCalled(t, "GetWalletByID", mock.Anything, mock.Anything)
+	assert.Equal(t, model.SagaCompensated, saga.Status)
+	d.assertAll(t)
+}
+
+func TestCompensate_ReplayErrorMarksSagaFailed(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	saga := sampleSagaLog(sampleSagaStep(walletTestID, money.New(200000), money.New(-50000), model.SagaStepPending))
+
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), saga.Steps[0].ID.String()).
+		Return(nil, errors.New("grpc error"))
+	d.sagaRepo.On("UpdateSagaStatus", mock.Anything, nil, saga.ID.String(), model.SagaFailed, mock.Anything).Return(nil)
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, saga.ID.String(), mock.Anything).Return(true, nil)
+
+	err := o.Compensate(context.Background(), &saga, sagaReasonResumed)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "replay pending update")
+	assert.Equal(t, model.SagaFailed, saga.Status)
+	d.assertAll(t)
+}
+
+func TestCompensate_AdjustBalanceErrorMarksSagaFailed(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	saga := sampleSagaLog(sampleSagaStep(walletTestID, money.New(200000), money.New(-50000), model.SagaStepApplied))
+
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(50000), mock.Anything).
+		Return(nil, errors.New("grpc error"))
+	d.sagaRepo.On("UpdateSagaStatus", mock.Anything, nil, saga.ID.String(), model.SagaFailed, mock.Anything).Return(nil)
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, saga.ID.String(), mock.Anything).Return(true, nil)
+
+	err := o.Compensate(context.Background(), &saga, sagaReasonNotCommitted)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "compensate step 1")
+	assert.Equal(t, model.SagaFailed, saga.Status)
+	d.assertAll(t)
+}
+
+func TestCompensate_NotStartedIsNoop(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	saga := sampleSagaLog(sampleSagaStep(walletTestID, money.New(200000), money.New(-50000), model.SagaStepApplied))
+	saga.Status = model.SagaCompleted
+
+	err := o.Compensate(context.Background(), &saga, sagaReasonNotCommitted)
+
+	assert.NoError(t, err)
+	d.walletClient.AssertNotCalled(t, "AdjustBalance")
+	d.assertAll(t)
+}
+
+func TestCompensate_ClaimedElsewhereIsNoop(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	// Completed by its request, or reverted by another process, since it was loaded
+	saga := sampleSagaLog(sampleSagaStep(walletTestID, money.New(200000), money.New(-50000), model.SagaStepApplied))
+
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, saga.ID.String(), mock.Anything).Return(false, nil)
+
+	err := o.Compensate(context.Background(), &saga, sagaReasonResumed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.SagaStarted, saga.Status)
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.sagaRepo.AssertNotCalled(t, "UpdateSagaStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCompensate_ClaimError(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	saga := sampleSagaLog(sampleSagaStep(walletTestID, money.New(200000), money.New(-50000), model.SagaStepApplied))
+
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, saga.ID.String(), mock.Anything).Return(false, errors.New("db error"))
+
+	err := o.Compensate(context.Background(), &saga, sagaReasonResumed)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "claim saga")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// ResumeIncompleteSagas
+// =====================================================================
+
+func TestResumeIncompleteSagas_CompensatesEachSaga(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	saga := sampleSagaLog(sampleSagaStep(walletTestID, money.New(200000), money.New(-50000), model.SagaStepApplied))
+
+	d.sagaRepo.On("GetIncompleteSagas", mock.Anything, mock.Anything, data.SAGA_RESUME_BATCH).Return([]model.SagaLog{saga}, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 200000), nil)
+	d.sagaRepo.On("UpdateStepStatus", mock.Anything, nil, mock.Anything, model.SagaStepCompensated).Return(nil)
+	d.sagaRepo.On("UpdateSagaStatus", mock.Anything, nil, saga.ID.String(), model.SagaCompensated, sagaReasonResumed).Return(nil)
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, saga.ID.String(), mock.Anything).Return(true, nil)
+
+	err := o.ResumeIncompleteSagas(context.Background())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestResumeIncompleteSagas_TakesOverStaleClaim(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	// A compensator stopped half way: the first step is already reverted
+	saga := sampleSagaLog(
+		sampleSagaStep(walletTestID, money.New(200000), money.New(-50000), model.SagaStepApplied),
+		sampleSagaStep(wallet2ID, money.New(0), money.New(50000), model.SagaStepCompensated),
+	)
+	saga.Status = model.SagaCompensating
+
+	d.sagaRepo.On("GetIncompleteSagas", mock.Anything, mock.Anything, data.SAGA_RESUME_BATCH).Return([]model.SagaLog{saga}, nil)
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, saga.ID.String(), mock.MatchedBy(func(staleBefore time.Time) bool {
+		return time.Since(staleBefore) >= data.SAGA_RESUME_AFTER
+	})).Return(true, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 200000), nil).Once()
+	d.sagaRepo.On("UpdateStepStatus", mock.Anything, nil, mock.Anything, model.SagaStepCompensated).Return(nil).Once()
+	d.sagaRepo.On("UpdateSagaStatus", mock.Anything, nil, saga.ID.String(), model.SagaCompensated, sagaReasonResumed).Return(nil)
+
+	err := o.ResumeIncompleteSagas(context.Background())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestResumeIncompleteSagas_DrainsFullBatches(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+	o.batchSize = 1
+
+	first := sampleSagaLog(sampleSagaStep(walletTestID, money.New(200000), money.New(-50000), model.SagaStepApplied))
+	second := sampleSagaLog(sampleSagaStep(walletTestID, money.New(150000), money.New(-10000), model.SagaStepApplied))
+	second.ID = uuid.New()
+
+	d.sagaRepo.On("GetIncompleteSagas", mock.Anything, mock.Anything, 1).Return([]model.SagaLog{first}, nil).Once()
+	d.sagaRepo.On("GetIncompleteSagas", mock.Anything, mock.Anything, 1).Return([]model.SagaLog{second}, nil).Once()
+	d.sagaRepo.On("GetIncompleteSagas", mock.Anything, mock.Anything, 1).Return([]model.SagaLog{}, nil).Once()
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), mock.Anything, mock.Anything).
+		Return(sampleWalletProto(walletTestID, 200000), nil).Times(2)
+	d.sagaRepo.On("UpdateStepStatus", mock.Anything, nil, mock.Anything, model.SagaStepCompensated).Return(nil).Times(2)
+	d.sagaRepo.On("UpdateSagaStatus", mock.Anything, nil, mock.Anything, model.SagaCompensated, sagaReasonResumed).Return(nil).Times(2)
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, mock.Anything, mock.Anything).Return(true, nil).Times(2)
+
+	err := o.ResumeIncompleteSagas(context.Background())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestResumeIncompleteSagas_StopsWhenNoProgress(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+	o.batchSize = 1
+
+	// The saga is already compensated in memory, so it is skipped; its row keeps coming back
+	stuck := sampleSagaLog()
+	stuck.Status = model.SagaStarted
+
+	d.sagaRepo.On("GetIncompleteSagas", mock.Anything, mock.Anything, 1).Return([]model.SagaLog{stuck}, nil).Times(2)
+	d.sagaRepo.On("UpdateSagaStatus", mock.Anything, nil, stuck.ID.String(), model.SagaCompensated, sagaReasonResumed).
+		Return(errors.New("db error")).Once()
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, stuck.ID.String(), mock.Anything).Return(true, nil).Once()
+
+	err := o.ResumeIncompleteSagas(context.Background())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestResumeIncompleteSagas_GetIncompleteError(t *testing.T) {
+	d := newSagaTestDeps()
+	o := d.orchestrator()
+
+	d.sagaRepo.On("GetIncompleteSagas", mock.Anything, mock.Anything, data.SAGA_RESUME_BATCH).
+		Return([]model.SagaLog{}, errors.New("db error"))
+
+	err := o.ResumeIncompleteSagas(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "get incomplete sagas")
+	d.assertAll(t)
+}
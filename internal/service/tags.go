@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+)
+
+type TagsService interface {
+	GetTags(ctx context.Context, userID string) ([]dto.TagsResponse, error)
+	GetTagByID(ctx context.Context, userID, id string) (dto.TagsResponse, error)
+	CreateTag(ctx context.Context, userID string, tag dto.TagsRequest) (dto.TagsResponse, error)
+	UpdateTag(ctx context.Context, userID, id string, tag dto.TagsRequest) (dto.TagsResponse, error)
+	DeleteTag(ctx context.Context, userID, id string) (dto.TagsResponse, error)
+}
+
+type tagsService struct {
+	txManager repository.TxManager
+	tagRepo   repository.TagsRepository
+}
+
+func NewTagsService(txManager repository.TxManager, tagRepo repository.TagsRepository) TagsService {
+	return &tagsService{
+		txManager: txManager,
+		tagRepo:   tagRepo,
+	}
+}
+
+func (tag_serv *tagsService) GetTags(ctx context.Context, userID string) ([]dto.TagsResponse, error) {
+	if userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	tags, err := tag_serv.tagRepo.GetTagsByUserID(ctx, nil, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get tags [user_id=%s]: %w", userID, err)
+	}
+
+	return helper.ConvertToResponseType(tags).([]dto.TagsResponse), nil
+}
+
+func (tag_serv *tagsService) GetTagByID(ctx context.Context, userID, id string) (dto.TagsResponse, error) {
+	tag, err := tag_serv.ownedTag(ctx, nil, userID, id)
+	if err != nil {
+		return dto.TagsResponse{}, err
+	}
+
+	return helper.ConvertToResponseType(tag).(dto.TagsResponse), nil
+}
+
+func (tag_serv *tagsService) CreateTag(ctx context.Context, userID string, tag dto.TagsRequest) (dto.TagsResponse, error) {
+	if userID == "" {
+		return dto.TagsResponse{}, ErrUnauthenticated
+	}
+
+	name, err := tag_serv.validName(ctx, userID, "", tag.Name)
+	if err != nil {
+		return dto.TagsResponse{}, fmt.Errorf("create tag: %w", err)
+	}
+
+	tagNew, err := tag_serv.tagRepo.CreateTag(ctx, nil, model.Tags{UserID: userID, Name: name})
+	if err != nil {
+		return dto.TagsResponse{}, fmt.Errorf("create tag: insert to db: %w", err)
+	}
+
+	return helper.ConvertToResponseType(tagNew).(dto.TagsResponse), nil
+}
+
+func (tag_serv *tagsService) UpdateTag(ctx context.Context, userID, id string, tag dto.TagsRequest) (dto.TagsResponse, error) {
+	existing, err := tag_serv.ownedTag(ctx, nil, userID, id)
+	if err != nil {
+		return dto.TagsResponse{}, err
+	}
+
+	name, err := tag_serv.validName(ctx, userID, existing.ID.String(), tag.Name)
+	if err != nil {
+		return dto.TagsResponse{}, fmt.Errorf("update tag [id=%s]: %w", id, err)
+	}
+	existing.Name = name
+
+	tagUpdated, err := tag_serv.tagRepo.UpdateTag(ctx, nil, existing)
+	if err != nil {
+		return dto.TagsResponse{}, fmt.Errorf("update tag [id=%s]: %w", id, err)
+	}
+
+	return helper.ConvertToResponseType(tagUpdated).(dto.TagsResponse), nil
+}
+
+func (tag_serv *tagsService) DeleteTag(ctx context.Context, userID, id string) (dto.TagsResponse, error) {
+	tx, err := tag_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.TagsResponse{}, fmt.Errorf("delete tag: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	tag, err := tag_serv.ownedTag(ctx, tx, userID, id)
+	if err != nil {
+		return dto.TagsResponse{}, err
+	}
+
+	tagDeleted, err := tag_serv.tagRepo.DeleteTag(ctx, tx, tag)
+	if err != nil {
+		return dto.TagsResponse{}, fmt.Errorf("delete tag [id=%s]: %w", id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.TagsResponse{}, fmt.Errorf("delete tag: commit: %w", err)
+	}
+
+	return helper.ConvertToResponseType(tagDeleted).(dto.TagsResponse), nil
+}
+
+// ownedTag loads a tag and checks that it belongs to userID.
+func (tag_serv *tagsService) ownedTag(ctx context.Context, tx repository.Transaction, userID, id string) (model.Tags, error) {
+	if userID == "" {
+		return model.Tags{}, ErrUnauthenticated
+	}
+
+	tag, err := tag_serv.tagRepo.GetTagByID(ctx, tx, id)
+	if err != nil {
+		return model.Tags{}, fmt.Errorf("tag not found [id=%s]: %w", id, err)
+	}
+	if tag.UserID != userID {
+		return model.Tags{}, fmt.Errorf("%w: tag does not belong to user [tag_id=%s, user_id=%s]", ErrPermissionDenied, id, userID)
+	}
+
+	return tag, nil
+}
+
+// validName trims a tag name and checks that no other tag of userID, than
+// the one with selfID, already uses it.
+func (tag_serv *tagsService) validName(ctx context.Context, userID, selfID, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > data.TAG_NAME_MAX_LENGTH {
+		return "", fmt.Errorf("invalid tag name [name=%s]", name)
+	}
+
+	if existing, err := tag_serv.tagRepo.GetTagByName(ctx, nil, userID, name); err == nil && existing.ID.String() != selfID {
+		return "", fmt.Errorf("invalid tag name: already used [name=%s]", name)
+	}
+
+	return name, nil
+}
+
+// resolveTags loads the tags a transaction is labelled with and checks that
+// they all belong to the caller.
+func resolveTags(ctx context.Context, tx repository.Transaction, tagRepo repository.TagsRepository, userID string, tagIDs []string) ([]model.Tags, error) {
+	if len(tagIDs) == 0 {
+		return []model.Tags{}, nil
+	}
+	if userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	ids, err := uniqueTagIDs(tagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := tagRepo.GetTagsByIDs(ctx, tx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get tags: %w", err)
+	}
+	if len(tags) != len(ids) {
+		return nil, errors.New("tag not found")
+	}
+	for _, tag := range tags {
+		if tag.UserID != userID {
+			return nil, fmt.Errorf("%w: tag does not belong to user [tag_id=%s, user_id=%s]", ErrPermissionDenied, tag.ID, userID)
+		}
+	}
+
+	return tags, nil
+}
+
+// normalizeTagFilter checks the tag filter of q, so that bad ids are a 400
+// rather than a failing query.
+func normalizeTagFilter(q *repository.CursorQuery) error {
+	switch q.TagMatch {
+	case "", repository.TagMatchAny, repository.TagMatchAll:
+	default:
+		return fmt.Errorf("invalid tag match [tag_match=%s]", q.TagMatch)
+	}
+
+	if len(q.TagIDs) == 0 {
+		return nil
+	}
+	ids, err := uniqueTagIDs(q.TagIDs)
+	if err != nil {
+		return err
+	}
+	q.TagIDs = ids
+
+	return nil
+}
+
+func uniqueTagIDs(tagIDs []string) ([]string, error) {
+	seen := make(map[uuid.UUID]bool, len(tagIDs))
+	ids := make([]string, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		id, err := helper.ParseUUID(tagID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag id [id=%s]: %w", tagID, err)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id.String())
+		}
+	}
+	return ids, nil
+}
+
+func tagIDsOf(tags []model.Tags) []uuid.UUID {
+	ids := make([]uuid.UUID, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	return ids
+}
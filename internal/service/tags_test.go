@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type tagTestDeps struct {
+	txManager *mocks.MockTxManager
+	tagRepo   *mocks.MockTagsRepository
+	tx        *mocks.MockTransaction
+}
+
+func newTagTestDeps() *tagTestDeps {
+	return &tagTestDeps{
+		txManager: new(mocks.MockTxManager),
+		tagRepo:   new(mocks.MockTagsRepository),
+		tx:        new(mocks.MockTransaction),
+	}
+}
+
+func (d *tagTestDeps) service() TagsService {
+	return NewTagsService(d.txManager, d.tagRepo)
+}
+
+func (d *tagTestDeps) assertAll(t *testing.T) {
+	t.Helper()
+	d.txManager.AssertExpectations(t)
+	d.tagRepo.AssertExpectations(t)
+	d.tx.AssertExpectations(t)
+}
+
+var (
+	tagTravelID = uuid.MustParse("66666666-0000-0000-0000-000000000001")
+	tagWorkID   = uuid.MustParse("66666666-0000-0000-0000-000000000002")
+)
+
+func sampleTag(id uuid.UUID, userID, name string) model.Tags {
+	return model.Tags{Base: model.Base{ID: id}, UserID: userID, Name: name}
+}
+
+// =====================================================================
+// Tag CRUD
+// =====================================================================
+
+func TestCreateTag_Success(t *testing.T) {
+	d := newTagTestDeps()
+	svc := d.service()
+
+	d.tagRepo.On("GetTagByName", mock.Anything, nil, "user-1", "Liburan Bali").Return(model.Tags{}, errors.New("tag not found"))
+	d.tagRepo.On("CreateTag", mock.Anything, nil, model.Tags{UserID: "user-1", Name: "Liburan Bali"}).Return(sampleTag(tagTravelID, "user-1", "Liburan Bali"), nil)
+
+	result, err := svc.CreateTag(context.Background(), "user-1", dto.TagsRequest{Name: "  Liburan Bali "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, dto.TagsResponse{ID: tagTravelID.String(), Name: "Liburan Bali"}, result)
+	d.assertAll(t)
+}
+
+func TestCreateTag_DuplicateName(t *testing.T) {
+	d := newTagTestDeps()
+	svc := d.service()
+
+	d.tagRepo.On("GetTagByName", mock.Anything, nil, "user-1", "liburan bali").Return(sampleTag(tagTravelID, "user-1", "Liburan Bali"), nil)
+
+	_, err := svc.CreateTag(context.Background(), "user-1", dto.TagsRequest{Name: "liburan bali"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid tag name: already used")
+	d.tagRepo.AssertNotCalled(t, "CreateTag")
+	d.assertAll(t)
+}
+
+func TestCreateTag_InvalidName(t *testing.T) {
+	d := newTagTestDeps()
+	svc := d.service()
+
+	_, err := svc.CreateTag(context.Background(), "user-1", dto.TagsRequest{Name: "   "})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid tag name")
+	d.assertAll(t)
+}
+
+func TestCreateTag_Unauthenticated(t *testing.T) {
+	d := newTagTestDeps()
+	svc := d.service()
+
+	_, err := svc.CreateTag(context.Background(), "", dto.TagsRequest{Name: "Kantor"})
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	d.assertAll(t)
+}
+
+func TestUpdateTag_KeepsOwnName(t *testing.T) {
+	d := newTagTestDeps()
+	svc := d.service()
+
+	existing := sampleTag(tagTravelID, "user-1", "Liburan Bali")
+	d.tagRepo.On("GetTagByID", mock.Anything, nil, tagTravelID.String()).Return(existing, nil)
+	// Renaming only the case of a tag does not collide with itself
+	d.tagRepo.On("GetTagByName", mock.Anything, nil, "user-1", "liburan bali").Return(existing, nil)
+	d.tagRepo.On("UpdateTag", mock.Anything, nil, sampleTag(tagTravelID, "user-1", "liburan bali")).Return(sampleTag(tagTravelID, "user-1", "liburan bali"), nil)
+
+	result, err := svc.UpdateTag(context.Background(), "user-1", tagTravelID.String(), dto.TagsRequest{Name: "liburan bali"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "liburan bali", result.Name)
+	d.assertAll(t)
+}
+
+func TestUpdateTag_NotOwner(t *testing.T) {
+	d := newTagTestDeps()
+	svc := d.service()
+
+	d.tagRepo.On("GetTagByID", mock.Anything, nil, tagTravelID.String()).Return(sampleTag(tagTravelID, "user-2", "Liburan Bali"), nil)
+
+	_, err := svc.UpdateTag(context.Background(), "user-1", tagTravelID.String(), dto.TagsRequest{Name: "Mudik"})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.tagRepo.AssertNotCalled(t, "UpdateTag")
+	d.assertAll(t)
+}
+
+func TestDeleteTag_Success(t *testing.T) {
+	d := newTagTestDeps()
+	svc := d.service()
+
+	tag := sampleTag(tagTravelID, "user-1", "Liburan Bali")
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.tagRepo.On("GetTagByID", mock.Anything, d.tx, tagTravelID.String()).Return(tag, nil)
+	d.tagRepo.On("DeleteTag", mock.Anything, d.tx, tag).Return(tag, nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.DeleteTag(context.Background(), "user-1", tagTravelID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, tagTravelID.String(), result.ID)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// Tag filter
+// =====================================================================
+
+func TestNormalizeTagFilter(t *testing.T) {
+	q := repository.CursorQuery{TagIDs: []string{tagTravelID.String(), tagWorkID.String(), tagTravelID.String()}, TagMatch: repository.TagMatchAll}
+	assert.NoError(t, normalizeTagFilter(&q))
+	assert.Equal(t, []string{tagTravelID.String(), tagWorkID.String()}, q.TagIDs)
+
+	q = repository.CursorQuery{TagIDs: []string{"not-a-uuid"}}
+	assert.ErrorContains(t, normalizeTagFilter(&q), "invalid tag id")
+
+	q = repository.CursorQuery{TagMatch: "some"}
+	assert.ErrorContains(t, normalizeTagFilter(&q), "invalid tag match")
+}
+
+// =====================================================================
+// Transactions (tags)
+// =====================================================================
+
+func TestCreateTransaction_WithTags(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	tags := []model.Tags{sampleTag(tagTravelID, "user-1", "Liburan Bali"), sampleTag(tagWorkID, "user-1", "Kantor")}
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.tagRepo.On("GetTagsByIDs", mock.Anything, nil, []string{tagTravelID.String(), tagWorkID.String()}).Return(tags, nil)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 200000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).Return(sampleWalletProto(walletTestID, 150000), nil)
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).Return(sampleTransactionModel(), nil)
+	d.tagRepo.On("ReplaceTransactionTags", mock.Anything, d.tx, txnTestID, []uuid.UUID{tagTravelID, tagWorkID}).Return(nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CreateTransaction(userCtx(), dto.TransactionsRequest{
+		WalletID:   walletTestID.String(),
+		CategoryID: catTestID.String(),
+		Amount:     money.New(50000),
+		Date:       txnFixTime,
+		TagIDs:     []string{tagTravelID.String(), tagWorkID.String(), tagTravelID.String()},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.TagsResponse{{ID: tagTravelID.String(), Name: "Liburan Bali"}, {ID: tagWorkID.String(), Name: "Kantor"}}, result.Tags)
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_ForeignTagRejected(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.tagRepo.On("GetTagsByIDs", mock.Anything, nil, []string{tagTravelID.String()}).Return([]model.Tags{sampleTag(tagTravelID, "user-2", "Liburan Bali")}, nil)
+
+	_, err := svc.CreateTransaction(userCtx(), dto.TransactionsRequest{
+		WalletID:   walletTestID.String(),
+		CategoryID: catTestID.String(),
+		Amount:     money.New(50000),
+		Date:       txnFixTime,
+		TagIDs:     []string{tagTravelID.String()},
+	})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	d.txManager.AssertNotCalled(t, "Begin", mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_UnknownTagRejected(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.tagRepo.On("GetTagsByIDs", mock.Anything, nil, []string{tagTravelID.String()}).Return([]model.Tags{}, nil)
+
+	_, err := svc.CreateTransaction(userCtx(), dto.TransactionsRequest{
+		WalletID:   walletTestID.String(),
+		CategoryID: catTestID.String(),
+		Amount:     money.New(50000),
+		Date:       txnFixTime,
+		TagIDs:     []string{tagTravelID.String()},
+	})
+
+	assert.ErrorContains(t, err, "tag not found")
+	d.assertAll(t)
+}
+
+func TestUpdateTransaction_TagsReplaced(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	existing := sampleTransactionModel()
+	existing.Tags = []model.Tags{sampleTag(tagTravelID, "user-1", "Liburan Bali")}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(existing, nil)
+	d.tagRepo.On("GetTagsByIDs", mock.Anything, d.tx, []string{tagWorkID.String()}).Return([]model.Tags{sampleTag(tagWorkID, "user-1", "Kantor")}, nil)
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.Anything).Return(existing, nil)
+	d.tagRepo.On("ReplaceTransactionTags", mock.Anything, d.tx, txnTestID, []uuid.UUID{tagWorkID}).Return(nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.UpdateTransaction(userCtx(), txnTestID.String(), dto.TransactionsRequest{
+		WalletID:   walletTestID.String(),
+		CategoryID: catTestID.String(),
+		Amount:     money.New(50000),
+		TagIDs:     []string{tagWorkID.String()},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.TagsResponse{{ID: tagWorkID.String(), Name: "Kantor"}}, result.Tags)
+	d.assertAll(t)
+}
+
+func TestUpdateTransaction_TagsKeptWhenOmitted(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	existing := sampleTransactionModel()
+	existing.Tags = []model.Tags{sampleTag(tagTravelID, "user-1", "Liburan Bali")}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(existing, nil)
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.Anything).Return(existing, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.UpdateTransaction(userCtx(), txnTestID.String(), dto.TransactionsRequest{
+		WalletID:    walletTestID.String(),
+		CategoryID:  catTestID.String(),
+		Amount:      money.New(50000),
+		Description: "Makan malam",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.TagsResponse{{ID: tagTravelID.String(), Name: "Liburan Bali"}}, result.Tags)
+	d.tagRepo.AssertNotCalled(t, "ReplaceTransactionTags")
+	d.assertAll(t)
+}
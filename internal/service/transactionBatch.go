@@ -0,0 +1,492 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
+	wpb "github.com/MuhammadMiftaa/Refina-Protobuf/wallet"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidBatch is returned, with the per-item results, when an item of a
+// batch failed and none of them was applied.
+var ErrInvalidBatch = errors.New("invalid batch")
+
+// batchChange is the planned effect of one batch item: Before is nil for a
+// create and After nil for a delete.
+type batchChange struct {
+	index  int
+	action string
+	before *model.Transactions
+	after  *model.Transactions
+
+	splits        []model.TransactionSplits
+	replaceSplits bool
+	tags          []model.Tags
+	replaceTags   bool
+}
+
+// BatchTransactions validates every item, then applies them all in one DB
+// transaction with a single balance update per wallet. When an item fails,
+// nothing is applied and the error wraps ErrInvalidBatch.
+func (transaction_serv *transactionsService) BatchTransactions(ctx context.Context, request dto.BatchTransactionsRequest) (dto.BatchTransactionsResponse, error) {
+	// Replay the original response if this request was already processed
+	idempotencyKey := helper.IdempotencyKeyFromContext(ctx)
+	requestHash := idempotencyRequestHash(request)
+	var replayed dto.BatchTransactionsResponse
+	if found, err := transaction_serv.replayIdempotent(ctx, data.IDEMPOTENCY_OPERATION_TRANSACTION_BATCH, idempotencyKey, requestHash, &replayed); err != nil {
+		return dto.BatchTransactionsResponse{}, fmt.Errorf("batch transactions: %w", err)
+	} else if found {
+		return replayed, nil
+	}
+
+	if len(request.Items) == 0 || len(request.Items) > data.TRANSACTION_BATCH_MAX_ITEMS {
+		return dto.BatchTransactionsResponse{}, fmt.Errorf("%w: expected 1 to %d items, got %d", ErrInvalidBatch, data.TRANSACTION_BATCH_MAX_ITEMS, len(request.Items))
+	}
+
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := transaction_serv.saga.NewSaga(data.SAGA_TYPE_TRANSACTION_BATCH)
+	committed := false
+	defer func() {
+		if !committed {
+			transaction_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
+	tx, err := transaction_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.BatchTransactionsResponse{}, fmt.Errorf("batch transactions: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	results := make([]dto.BatchTransactionResult, len(request.Items))
+	failed := 0
+	fail := func(index int, err error) {
+		if results[index].Status != data.BATCH_ITEM_FAILED {
+			failed++
+		}
+		results[index].Status = data.BATCH_ITEM_FAILED
+		results[index].Error = err.Error()
+	}
+
+	// ? Plan every item before touching any balance
+	changes := make([]batchChange, 0, len(request.Items))
+	seen := make(map[string]int)
+	for i, item := range request.Items {
+		results[i] = dto.BatchTransactionResult{Index: i, Action: item.Action, ID: item.ID, Status: data.BATCH_ITEM_NOT_APPLIED}
+
+		if item.ID != "" {
+			if first, ok := seen[item.ID]; ok {
+				fail(i, fmt.Errorf("invalid batch item: transaction already changed by item %d [id=%s]", first, item.ID))
+				continue
+			}
+			seen[item.ID] = i
+		}
+
+		change, err := transaction_serv.planBatchItem(ctx, tx, item)
+		if err != nil {
+			fail(i, err)
+			continue
+		}
+		change.index = i
+		changes = append(changes, change)
+	}
+
+	// ? Net the balance effect of all items per wallet
+	deltas := make(map[uuid.UUID]money.Amount)
+	walletItems := make(map[uuid.UUID][]int)
+	var walletIDs []uuid.UUID
+	addDelta := func(index int, walletID uuid.UUID, delta money.Amount) {
+		if _, ok := deltas[walletID]; !ok {
+			walletIDs = append(walletIDs, walletID)
+		}
+		deltas[walletID] = deltas[walletID].Add(delta)
+		walletItems[walletID] = append(walletItems[walletID], index)
+	}
+	for _, change := range changes {
+		if change.before != nil {
+			effect, err := transaction_serv.ledger.bookedEffect(ctx, tx, *change.before)
+			if err != nil {
+				fail(change.index, err)
+				continue
+			}
+			addDelta(change.index, change.before.WalletID, effect.Neg())
+		}
+		if change.after != nil {
+			effect, err := transaction_serv.ledger.bookedEffect(ctx, tx, *change.after)
+			if err != nil {
+				fail(change.index, err)
+				continue
+			}
+			addDelta(change.index, change.after.WalletID, effect)
+		}
+	}
+
+	wallets := make(map[uuid.UUID]*wpb.Wallet)
+	for _, walletID := range walletIDs {
+		wallet, err := transaction_serv.walletClient.GetWalletByID(ctx, walletID.String())
+		if err != nil {
+			err = fmt.Errorf("wallet not found [id=%s]: %w", walletID, err)
+		} else if client.WalletBalance(wallet).Add(deltas[walletID]).IsNegative() {
+			err = fmt.Errorf("insufficient wallet balance [wallet_id=%s]", walletID)
+		}
+		if err != nil {
+			for _, index := range walletItems[walletID] {
+				fail(index, err)
+			}
+			continue
+		}
+		wallets[walletID] = wallet
+	}
+
+	if failed > 0 {
+		return dto.BatchTransactionsResponse{Results: results}, fmt.Errorf("%w: %d of %d items failed", ErrInvalidBatch, failed, len(request.Items))
+	}
+
+	// Update wallet balances, once per wallet
+	for _, walletID := range walletIDs {
+		if deltas[walletID].IsZero() {
+			continue
+		}
+		if err := transaction_serv.saga.UpdateWalletBalance(ctx, saga, wallets[walletID], deltas[walletID]); err != nil {
+			return dto.BatchTransactionsResponse{}, fmt.Errorf("update wallet balance [wallet_id=%s]: %w", walletID, err)
+		}
+	}
+
+	for _, change := range changes {
+		transaction, err := transaction_serv.applyBatchChange(ctx, tx, change)
+		if err != nil {
+			return dto.BatchTransactionsResponse{}, fmt.Errorf("batch transactions [index=%d]: %w", change.index, err)
+		}
+
+		transactionResponse := helper.ConvertToResponseType(transaction).(dto.TransactionsResponse)
+		results[change.index].ID = transactionResponse.ID
+		results[change.index].Status = data.BATCH_ITEM_APPLIED
+		results[change.index].Transaction = &transactionResponse
+	}
+
+	response := dto.BatchTransactionsResponse{Applied: true, Results: results}
+
+	if err := transaction_serv.saveIdempotent(ctx, tx, data.IDEMPOTENCY_OPERATION_TRANSACTION_BATCH, idempotencyKey, requestHash, response); err != nil {
+		return dto.BatchTransactionsResponse{}, fmt.Errorf("batch transactions: %w", err)
+	}
+
+	if err := transaction_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.BatchTransactionsResponse{}, fmt.Errorf("batch transactions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.BatchTransactionsResponse{}, fmt.Errorf("batch transactions: commit: %w", err)
+	}
+	committed = true
+
+	return response, nil
+}
+
+// planBatchItem checks one item against the current data, the same way the
+// single create, update and delete do, without writing anything.
+func (transaction_serv *transactionsService) planBatchItem(ctx context.Context, tx repository.Transaction, item dto.BatchTransactionItem) (batchChange, error) {
+	if item.Action != data.BATCH_ACTION_DELETE && len(item.Transaction.Attachments) > 0 {
+		return batchChange{}, errors.New("invalid batch item: attachments must be uploaded separately")
+	}
+	if item.Action == data.BATCH_ACTION_CREATE && item.Transaction.Scheduled {
+		return batchChange{}, errors.New("invalid batch item: scheduled transactions are created one at a time")
+	}
+
+	switch item.Action {
+	case data.BATCH_ACTION_CREATE:
+		if item.ID != "" {
+			return batchChange{}, errors.New("invalid batch item: create takes no id")
+		}
+		return transaction_serv.planBatchCreate(ctx, tx, item.Transaction)
+	case data.BATCH_ACTION_UPDATE:
+		return transaction_serv.planBatchUpdate(ctx, tx, item.ID, item.Transaction)
+	case data.BATCH_ACTION_DELETE:
+		transactionExist, err := transaction_serv.transactionRepo.GetTransactionByID(ctx, tx, item.ID)
+		if err != nil {
+			return batchChange{}, fmt.Errorf("transaction not found [id=%s]: %w", item.ID, err)
+		}
+		if transactionExist.Status == model.StatusReconciled {
+			return batchChange{}, fmt.Errorf("invalid transaction status: a reconciled transaction cannot be deleted [id=%s]", item.ID)
+		}
+		if transactionExist.TransferID != nil {
+			return batchChange{}, fmt.Errorf("invalid transaction: a transfer leg cannot be deleted alone, delete the transfer instead [id=%s, transfer_id=%s]", item.ID, transactionExist.TransferID)
+		}
+		if transactionExist.DebtID != nil {
+			return batchChange{}, fmt.Errorf("invalid transaction: a debt transaction cannot be deleted alone, delete the debt instead [id=%s, debt_id=%s]", item.ID, transactionExist.DebtID)
+		}
+		if transactionExist.Scheduled {
+			return batchChange{}, fmt.Errorf("invalid transaction: a scheduled transaction is cancelled, not deleted [id=%s]", item.ID)
+		}
+		return batchChange{action: item.Action, before: &transactionExist}, nil
+	default:
+		return batchChange{}, fmt.Errorf("invalid batch action [action=%s]", item.Action)
+	}
+}
+
+func (transaction_serv *transactionsService) planBatchCreate(ctx context.Context, tx repository.Transaction, transaction dto.TransactionsRequest) (batchChange, error) {
+	if !transaction.Amount.IsPositive() {
+		return batchChange{}, fmt.Errorf("invalid amount [amount=%s]", transaction.Amount)
+	}
+
+	// A split without an explicit category is filed under its first line
+	if transaction.CategoryID == "" && len(transaction.Splits) > 0 {
+		transaction.CategoryID = transaction.Splits[0].CategoryID
+	}
+
+	CategoryID, err := helper.ParseUUID(transaction.CategoryID)
+	if err != nil {
+		return batchChange{}, fmt.Errorf("invalid category id [id=%s]: %w", transaction.CategoryID, err)
+	}
+
+	WalletID, err := helper.ParseUUID(transaction.WalletID)
+	if err != nil {
+		return batchChange{}, fmt.Errorf("invalid wallet id [id=%s]: %w", transaction.WalletID, err)
+	}
+
+	category, err := transaction_serv.categoryRepo.GetCategoryByID(ctx, tx, transaction.CategoryID)
+	if err != nil {
+		return batchChange{}, fmt.Errorf("category not found [id=%s]: %w", transaction.CategoryID, err)
+	}
+	if category.Type != model.Expense && category.Type != model.Income {
+		return batchChange{}, fmt.Errorf("invalid transaction type [type=%s]", category.Type)
+	}
+
+	splits, err := transaction_serv.resolveSplits(ctx, tx, transaction.Splits, category, transaction.Amount)
+	if err != nil {
+		return batchChange{}, err
+	}
+
+	tags, err := resolveTags(ctx, tx, transaction_serv.tagRepo, interceptor.UserIDFromContext(ctx), transaction.TagIDs)
+	if err != nil {
+		return batchChange{}, err
+	}
+
+	status, err := initialStatus(transaction.Status)
+	if err != nil {
+		return batchChange{}, err
+	}
+
+	// Book an amount entered in another currency in the wallet's, at the rate of its date
+	conversion, err := transaction_serv.currencies.forWallet(ctx, tx, transaction.WalletID, transaction.Currency, transaction.Amount, transaction.Date)
+	if err != nil {
+		return batchChange{}, err
+	}
+	if conversion.converted() && len(splits) > 0 {
+		if splits, err = rescaleSplits(splits, conversion.Amount); err != nil {
+			return batchChange{}, err
+		}
+	}
+
+	transactionModel := model.Transactions{
+		WalletID:        WalletID,
+		CategoryID:      CategoryID,
+		TransactionDate: transaction.Date,
+		Description:     transaction.Description,
+		Category:        category,
+		Status:          status,
+	}
+	conversion.apply(&transactionModel)
+
+	return batchChange{
+		action:        data.BATCH_ACTION_CREATE,
+		after:         &transactionModel,
+		splits:        splits,
+		replaceSplits: len(splits) > 0,
+		tags:          tags,
+		replaceTags:   len(tags) > 0,
+	}, nil
+}
+
+func (transaction_serv *transactionsService) planBatchUpdate(ctx context.Context, tx repository.Transaction, id string, transaction dto.TransactionsRequest) (batchChange, error) {
+	// Lock the row so that it does not change between the check and the write
+	transactionExist, err := transaction_serv.transactionRepo.GetTransactionByID(ctx, tx, id)
+	if err != nil {
+		return batchChange{}, fmt.Errorf("transaction not found [id=%s]: %w", id, err)
+	}
+	transactionBefore := transactionExist
+
+	if !transaction.Amount.IsPositive() {
+		return batchChange{}, fmt.Errorf("invalid amount [amount=%s]", transaction.Amount)
+	}
+
+	if transaction.CategoryID != transactionExist.CategoryID.String() {
+		CategoryID, err := helper.ParseUUID(transaction.CategoryID)
+		if err != nil {
+			return batchChange{}, fmt.Errorf("invalid category id [id=%s]: %w", transaction.CategoryID, err)
+		}
+
+		category, err := transaction_serv.categoryRepo.GetCategoryByID(ctx, tx, transaction.CategoryID)
+		if err != nil {
+			return batchChange{}, fmt.Errorf("category not found [id=%s]: %w", transaction.CategoryID, err)
+		}
+
+		transactionExist.CategoryID = CategoryID
+		transactionExist.Category = category
+	}
+
+	// Book an amount entered in another currency in the wallet's, at the rate of its date
+	conversionDate := transaction.Date
+	if conversionDate.IsZero() {
+		conversionDate = transactionExist.TransactionDate
+	}
+	conversion, err := transaction_serv.currencies.forWallet(ctx, tx, transaction.WalletID, transaction.Currency, transaction.Amount, conversionDate)
+	if err != nil {
+		return batchChange{}, err
+	}
+	if transaction.WalletID != transactionExist.WalletID.String() {
+		WalletID, err := helper.ParseUUID(transaction.WalletID)
+		if err != nil {
+			return batchChange{}, fmt.Errorf("invalid wallet id [id=%s]: %w", transaction.WalletID, err)
+		}
+
+		oldCurrency, err := transaction_serv.currencies.walletCurrency(ctx, tx, transactionExist.WalletID.String())
+		if err != nil {
+			return batchChange{}, err
+		}
+		if oldCurrency != conversion.Currency {
+			return batchChange{}, fmt.Errorf("invalid wallet: cannot move a transaction from %s to a %s wallet [id=%s]", oldCurrency, conversion.Currency, id)
+		}
+
+		transactionExist.WalletID = WalletID
+	}
+	if err := checkEditable(transactionBefore, transaction.WalletID, conversion.Amount, transactionExist.Category); err != nil {
+		return batchChange{}, err
+	}
+
+	change := batchChange{action: data.BATCH_ACTION_UPDATE, before: &transactionBefore}
+
+	if transaction.TagIDs != nil {
+		if change.tags, err = resolveTags(ctx, tx, transaction_serv.tagRepo, interceptor.UserIDFromContext(ctx), transaction.TagIDs); err != nil {
+			return batchChange{}, err
+		}
+		change.replaceTags = true
+	}
+
+	// New split lines replace the current ones, which must otherwise still fit
+	if transaction.Splits != nil {
+		splits, err := transaction_serv.resolveSplits(ctx, tx, transaction.Splits, transactionExist.Category, transaction.Amount)
+		if err != nil {
+			return batchChange{}, err
+		}
+		if conversion.converted() && len(splits) > 0 {
+			if splits, err = rescaleSplits(splits, conversion.Amount); err != nil {
+				return batchChange{}, err
+			}
+		}
+		change.splits, change.replaceSplits = splits, true
+	} else if err := validateSplits(transactionExist.Splits, transactionExist.Category.Type, conversion.Amount); err != nil {
+		return batchChange{}, err
+	}
+
+	// Keep the entered amount of a converted transaction until the amount is re-entered
+	if conversion.converted() || conversion.Amount != transactionExist.Amount {
+		conversion.apply(&transactionExist)
+	}
+	transactionExist.Currency = conversion.Currency
+
+	if !transaction.Date.IsZero() && !utils.SameDate(transaction.Date, transactionExist.TransactionDate) {
+		transactionExist.TransactionDate = transaction.Date
+	}
+	if transaction.Description != "" {
+		transactionExist.Description = transaction.Description
+	}
+
+	change.after = &transactionExist
+	return change, nil
+}
+
+// applyBatchChange writes a planned change with its history, budget and
+// outbox entries. Wallet balances were already updated for the whole batch.
+func (transaction_serv *transactionsService) applyBatchChange(ctx context.Context, tx repository.Transaction, change batchChange) (model.Transactions, error) {
+	var (
+		transaction model.Transactions
+		eventType   string
+		err         error
+	)
+
+	switch change.action {
+	case data.BATCH_ACTION_CREATE:
+		if transaction, err = transaction_serv.transactionRepo.CreateTransaction(ctx, tx, *change.after); err != nil {
+			return model.Transactions{}, fmt.Errorf("create transaction: insert to db: %w", err)
+		}
+		eventType = data.OUTBOX_EVENT_TRANSACTION_CREATED
+	case data.BATCH_ACTION_UPDATE:
+		if transaction, err = transaction_serv.transactionRepo.UpdateTransaction(ctx, tx, *change.after); err != nil {
+			return model.Transactions{}, fmt.Errorf("update transaction [id=%s]: update in db: %w", change.before.ID, err)
+		}
+		eventType = data.OUTBOX_EVENT_TRANSACTION_UPDATED
+	case data.BATCH_ACTION_DELETE:
+		if transaction, err = transaction_serv.transactionRepo.DeleteTransaction(ctx, tx, *change.before); err != nil {
+			return model.Transactions{}, fmt.Errorf("delete transaction [id=%s]: delete from db: %w", change.before.ID, err)
+		}
+		eventType = data.OUTBOX_EVENT_TRANSACTION_DELETED
+	}
+
+	if change.replaceSplits {
+		for i := range change.splits {
+			change.splits[i].TransactionID = transaction.ID
+		}
+		if transaction.Splits, err = transaction_serv.transactionRepo.ReplaceTransactionSplits(ctx, tx, transaction.ID.String(), change.splits); err != nil {
+			return model.Transactions{}, fmt.Errorf("replace splits [id=%s]: %w", transaction.ID, err)
+		}
+	}
+
+	if change.replaceTags {
+		if err := transaction_serv.tagRepo.ReplaceTransactionTags(ctx, tx, transaction.ID, tagIDsOf(change.tags)); err != nil {
+			return model.Transactions{}, fmt.Errorf("replace tags [id=%s]: %w", transaction.ID, err)
+		}
+		transaction.Tags = change.tags
+	}
+
+	switch change.action {
+	case data.BATCH_ACTION_CREATE:
+		transaction.Category = change.after.Category
+		err = transaction_serv.history.RecordCreated(ctx, tx, transaction)
+	case data.BATCH_ACTION_UPDATE:
+		transaction.Category = change.after.Category
+		err = transaction_serv.history.RecordUpdated(ctx, tx, *change.before, transaction)
+	case data.BATCH_ACTION_DELETE:
+		err = transaction_serv.history.RecordDeleted(ctx, tx, *change.before)
+	}
+	if err != nil {
+		return model.Transactions{}, err
+	}
+
+	// ? Emit budget events if the change pushes spending across a budget threshold
+	if change.action != data.BATCH_ACTION_DELETE {
+		if err := transaction_serv.budgets.TransactionChanged(ctx, tx, change.before, &transaction); err != nil {
+			return model.Transactions{}, fmt.Errorf("evaluate budgets: %w", err)
+		}
+	}
+
+	payload, err := json.Marshal(helper.ConvertToResponseType(transaction).(dto.TransactionsResponse))
+	if err != nil {
+		return model.Transactions{}, fmt.Errorf("marshal transaction response [id=%s]: %w", transaction.ID, err)
+	}
+
+	if err := transaction_serv.outboxRepository.Create(ctx, tx, &model.OutboxMessage{
+		AggregateID: transaction.ID.String(),
+		EventType:   eventType,
+		Payload:     payload,
+		Published:   false,
+		MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+	}); err != nil {
+		return model.Transactions{}, err
+	}
+
+	return transaction, nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	batchUpdateID = uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	batchDeleteID = uuid.MustParse("66666666-6666-6666-6666-666666666666")
+)
+
+func sampleBatchTransaction(id uuid.UUID, amount int64) model.Transactions {
+	txn := sampleTransactionModel()
+	txn.ID = id
+	txn.Amount = money.New(amount)
+	return txn
+}
+
+func sampleBatchCreate(amount int64) dto.BatchTransactionItem {
+	request := sampleTransactionRequest()
+	request.Amount = money.New(amount)
+	return dto.BatchTransactionItem{Action: data.BATCH_ACTION_CREATE, Transaction: request}
+}
+
+// =====================================================================
+// BatchTransactions
+// =====================================================================
+
+func TestBatchTransactions_AppliesAllWithOneBalanceUpdatePerWallet(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	update := sampleTransactionRequest()
+	update.Amount = money.New(30000)
+	request := dto.BatchTransactionsRequest{Items: []dto.BatchTransactionItem{
+		sampleBatchCreate(20000),
+		{Action: data.BATCH_ACTION_UPDATE, ID: batchUpdateID.String(), Transaction: update},
+		{Action: data.BATCH_ACTION_DELETE, ID: batchDeleteID.String()},
+	}}
+
+	created := sampleBatchTransaction(txnTestID, 20000)
+	updated := sampleBatchTransaction(batchUpdateID, 30000)
+	deleted := sampleBatchTransaction(batchDeleteID, 10000)
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, batchUpdateID.String()).Return(sampleBatchTransaction(batchUpdateID, 50000), nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, batchDeleteID.String()).Return(deleted, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 0), nil).Once()
+	// -20000 for the new expense, +20000 for the smaller one, +10000 for the deleted one
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(10000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 10000), nil).Once()
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(txn model.Transactions) bool {
+		return txn.Amount == money.New(20000) && txn.WalletID == walletTestID
+	})).Return(created, nil)
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(txn model.Transactions) bool {
+		return txn.ID == batchUpdateID && txn.Amount == money.New(30000)
+	})).Return(updated, nil)
+	d.transactionRepo.On("DeleteTransaction", mock.Anything, d.tx, deleted).Return(deleted, nil)
+	for _, eventType := range []string{data.OUTBOX_EVENT_TRANSACTION_CREATED, data.OUTBOX_EVENT_TRANSACTION_UPDATED, data.OUTBOX_EVENT_TRANSACTION_DELETED} {
+		d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+			return msg.EventType == eventType
+		})).Return(nil).Once()
+	}
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.BatchTransactions(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Applied)
+	if assert.Len(t, result.Results, 3) {
+		assert.Equal(t, txnTestID.String(), result.Results[0].ID)
+		assert.Equal(t, batchUpdateID.String(), result.Results[1].ID)
+		assert.Equal(t, batchDeleteID.String(), result.Results[2].ID)
+		for i, item := range result.Results {
+			assert.Equal(t, i, item.Index)
+			assert.Equal(t, data.BATCH_ITEM_APPLIED, item.Status)
+			assert.NotNil(t, item.Transaction)
+		}
+	}
+	d.assertAll(t)
+}
+
+func TestBatchTransactions_FailedItemAppliesNothing(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	unknownCategory := sampleBatchCreate(20000)
+	unknownCategory.Transaction.CategoryID = uuid.NewString()
+	request := dto.BatchTransactionsRequest{Items: []dto.BatchTransactionItem{
+		unknownCategory,
+		{Action: data.BATCH_ACTION_DELETE, ID: batchDeleteID.String()},
+		{Action: "archive", ID: batchUpdateID.String()},
+	}}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, unknownCategory.Transaction.CategoryID).
+		Return(model.Categories{}, errors.New("category not found"))
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, batchDeleteID.String()).Return(sampleBatchTransaction(batchDeleteID, 10000), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 0), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.BatchTransactions(context.Background(), request)
+
+	assert.ErrorIs(t, err, ErrInvalidBatch)
+	assert.Contains(t, err.Error(), "2 of 3 items failed")
+	assert.False(t, result.Applied)
+	if assert.Len(t, result.Results, 3) {
+		assert.Equal(t, data.BATCH_ITEM_FAILED, result.Results[0].Status)
+		assert.Contains(t, result.Results[0].Error, "category not found")
+		assert.Equal(t, data.BATCH_ITEM_NOT_APPLIED, result.Results[1].Status)
+		assert.Empty(t, result.Results[1].Error)
+		assert.Equal(t, data.BATCH_ITEM_FAILED, result.Results[2].Status)
+		assert.Contains(t, result.Results[2].Error, "invalid batch action")
+	}
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.transactionRepo.AssertNotCalled(t, "DeleteTransaction", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestBatchTransactions_InsufficientNetBalance(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	// Each expense fits the balance, both together do not
+	request := dto.BatchTransactionsRequest{Items: []dto.BatchTransactionItem{
+		sampleBatchCreate(60000),
+		sampleBatchCreate(60000),
+	}}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 100000), nil).Once()
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.BatchTransactions(context.Background(), request)
+
+	assert.ErrorIs(t, err, ErrInvalidBatch)
+	for _, item := range result.Results {
+		assert.Equal(t, data.BATCH_ITEM_FAILED, item.Status)
+		assert.Contains(t, item.Error, "insufficient wallet balance")
+	}
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestBatchTransactions_SameTransactionTwice(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	request := dto.BatchTransactionsRequest{Items: []dto.BatchTransactionItem{
+		{Action: data.BATCH_ACTION_DELETE, ID: batchDeleteID.String()},
+		{Action: data.BATCH_ACTION_DELETE, ID: batchDeleteID.String()},
+	}}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, batchDeleteID.String()).Return(sampleBatchTransaction(batchDeleteID, 10000), nil).Once()
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 0), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.BatchTransactions(context.Background(), request)
+
+	assert.ErrorIs(t, err, ErrInvalidBatch)
+	assert.Equal(t, data.BATCH_ITEM_NOT_APPLIED, result.Results[0].Status)
+	assert.Equal(t, data.BATCH_ITEM_FAILED, result.Results[1].Status)
+	assert.Contains(t, result.Results[1].Error, "already changed by item 0")
+	d.assertAll(t)
+}
+
+func TestBatchTransactions_RejectsEmptyBatch(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	result, err := svc.BatchTransactions(context.Background(), dto.BatchTransactionsRequest{})
+
+	assert.ErrorIs(t, err, ErrInvalidBatch)
+	assert.Empty(t, result.Results)
+	d.txManager.AssertNotCalled(t, "Begin", mock.Anything)
+	d.assertAll(t)
+}
+
+func TestBatchTransactions_DBErrorCompensatesWallet(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompensated)
+
+	request := dto.BatchTransactionsRequest{Items: []dto.BatchTransactionItem{sampleBatchCreate(20000)}}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 100000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-20000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 80000), nil).Once()
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(20000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 100000), nil).Once()
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).
+		Return(model.Transactions{}, errors.New("db insert error"))
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.BatchTransactions(context.Background(), request)
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidBatch)
+	assert.Contains(t, err.Error(), "insert to db")
+	d.assertAll(t)
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	helper "refina-transaction/internal/utils"
+)
+
+// historyRecorder appends changes of transactions to their audit trail,
+// inside the DB transaction of the change so that neither is kept without
+// the other. The actor, source and request ID are taken from ctx.
+type historyRecorder struct {
+	historyRepo repository.TransactionHistoryRepository
+}
+
+func newHistoryRecorder(historyRepo repository.TransactionHistoryRepository) *historyRecorder {
+	return &historyRecorder{historyRepo: historyRepo}
+}
+
+// historyChange is the state of a transaction around a change: Before is nil
+// for a new transaction and After nil for a deleted one.
+type historyChange struct {
+	Before *model.Transactions
+	After  *model.Transactions
+}
+
+func (r *historyRecorder) RecordCreated(ctx context.Context, tx repository.Transaction, transactions ...model.Transactions) error {
+	changes := make([]historyChange, len(transactions))
+	for i := range transactions {
+		changes[i] = historyChange{After: &transactions[i]}
+	}
+	return r.record(ctx, tx, model.HistoryCreated, changes)
+}
+
+func (r *historyRecorder) RecordUpdated(ctx context.Context, tx repository.Transaction, before, after model.Transactions) error {
+	return r.record(ctx, tx, model.HistoryUpdated, []historyChange{{Before: &before, After: &after}})
+}
+
+func (r *historyRecorder) RecordDeleted(ctx context.Context, tx repository.Transaction, transactions ...model.Transactions) error {
+	changes := make([]historyChange, len(transactions))
+	for i := range transactions {
+		changes[i] = historyChange{Before: &transactions[i]}
+	}
+	return r.record(ctx, tx, model.HistoryDeleted, changes)
+}
+
+func (r *historyRecorder) RecordRestored(ctx context.Context, tx repository.Transaction, transaction model.Transactions) error {
+	return r.record(ctx, tx, model.HistoryRestored, []historyChange{{After: &transaction}})
+}
+
+func (r *historyRecorder) record(ctx context.Context, tx repository.Transaction, action model.HistoryAction, changes []historyChange) error {
+	actorID := interceptor.UserIDFromContext(ctx)
+	source := helper.RequestSourceFromContext(ctx)
+	requestID := helper.RequestIDFromContext(ctx)
+
+	entries := make([]model.TransactionHistory, 0, len(changes))
+	for _, change := range changes {
+		current := change.After
+		if current == nil {
+			current = change.Before
+		}
+
+		before, err := historySnapshot(change.Before)
+		if err != nil {
+			return err
+		}
+		after, err := historySnapshot(change.After)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, model.TransactionHistory{
+			TransactionID: current.ID,
+			WalletID:      current.WalletID,
+			Action:        action,
+			ActorID:       actorID,
+			Source:        source,
+			RequestID:     requestID,
+			Before:        before,
+			After:         after,
+		})
+	}
+
+	if err := r.historyRepo.CreateHistory(ctx, tx, entries); err != nil {
+		return fmt.Errorf("record transaction history: %w", err)
+	}
+	return nil
+}
+
+// historySnapshot stores a transaction the way the API returns it.
+func historySnapshot(transaction *model.Transactions) ([]byte, error) {
+	if transaction == nil {
+		return nil, nil
+	}
+
+	snapshot, err := json.Marshal(helper.ConvertToResponseType(*transaction).(dto.TransactionsResponse))
+	if err != nil {
+		return nil, fmt.Errorf("marshal history snapshot [id=%s]: %w", transaction.ID, err)
+	}
+	return snapshot, nil
+}
+
+func (transaction_serv *transactionsService) GetTransactionHistory(ctx context.Context, id string) ([]dto.TransactionHistoryResponse, error) {
+	entries, err := transaction_serv.history.historyRepo.GetHistoryByTransactionID(ctx, nil, id)
+	if err != nil {
+		return nil, fmt.Errorf("get transaction history [id=%s]: %w", id, err)
+	}
+
+	return helper.ConvertToResponseType(entries).([]dto.TransactionHistoryResponse), nil
+}
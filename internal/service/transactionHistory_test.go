@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"refina-transaction/interface/grpc/interceptor"
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func historyTestContext() context.Context {
+	ctx := interceptor.WithUserMetadata(context.Background(), interceptor.UserMetadata{UserID: "user-1"})
+	return helper.WithRequestSource(ctx, data.REQUEST_SOURCE_HTTP, "req-1")
+}
+
+// capturedHistory records the entries passed to CreateHistory.
+func capturedHistory(historyRepo *mocks.MockTransactionHistoryRepository, tx any) *[]model.TransactionHistory {
+	var captured []model.TransactionHistory
+	historyRepo.On("CreateHistory", mock.Anything, tx, mock.Anything).
+		Run(func(args mock.Arguments) {
+			captured = append(captured, args.Get(2).([]model.TransactionHistory)...)
+		}).
+		Return(nil)
+	return &captured
+}
+
+// =====================================================================
+// historyRecorder
+// =====================================================================
+
+func TestHistoryRecorder_RecordUpdatedCapturesActorSourceAndSnapshots(t *testing.T) {
+	historyRepo := new(mocks.MockTransactionHistoryRepository)
+	tx := new(mocks.MockTransaction)
+	captured := capturedHistory(historyRepo, tx)
+
+	before := sampleTransactionModel()
+	after := sampleTransactionModel()
+	after.Description = "Edited"
+
+	err := newHistoryRecorder(historyRepo).RecordUpdated(historyTestContext(), tx, before, after)
+
+	assert.NoError(t, err)
+	if assert.Len(t, *captured, 1) {
+		entry := (*captured)[0]
+		assert.Equal(t, txnTestID, entry.TransactionID)
+		assert.Equal(t, walletTestID, entry.WalletID)
+		assert.Equal(t, model.HistoryUpdated, entry.Action)
+		assert.Equal(t, "user-1", entry.ActorID)
+		assert.Equal(t, data.REQUEST_SOURCE_HTTP, entry.Source)
+		assert.Equal(t, "req-1", entry.RequestID)
+
+		var beforeSnapshot, afterSnapshot dto.TransactionsResponse
+		assert.NoError(t, json.Unmarshal(entry.Before, &beforeSnapshot))
+		assert.NoError(t, json.Unmarshal(entry.After, &afterSnapshot))
+		assert.Equal(t, before.Description, beforeSnapshot.Description)
+		assert.Equal(t, "Edited", afterSnapshot.Description)
+	}
+	historyRepo.AssertExpectations(t)
+}
+
+func TestHistoryRecorder_RecordCreatedHasNoBefore(t *testing.T) {
+	historyRepo := new(mocks.MockTransactionHistoryRepository)
+	tx := new(mocks.MockTransaction)
+	captured := capturedHistory(historyRepo, tx)
+
+	from := sampleTransactionModel()
+	to := sampleTransactionModel()
+	to.ID = uuid.New()
+
+	err := newHistoryRecorder(historyRepo).RecordCreated(historyTestContext(), tx, from, to)
+
+	assert.NoError(t, err)
+	if assert.Len(t, *captured, 2) {
+		assert.Equal(t, from.ID, (*captured)[0].TransactionID)
+		assert.Equal(t, to.ID, (*captured)[1].TransactionID)
+		for _, entry := range *captured {
+			assert.Equal(t, model.HistoryCreated, entry.Action)
+			assert.Nil(t, entry.Before)
+			assert.NotNil(t, entry.After)
+		}
+	}
+	historyRepo.AssertExpectations(t)
+}
+
+func TestHistoryRecorder_RecordDeletedHasNoAfter(t *testing.T) {
+	historyRepo := new(mocks.MockTransactionHistoryRepository)
+	tx := new(mocks.MockTransaction)
+	captured := capturedHistory(historyRepo, tx)
+
+	err := newHistoryRecorder(historyRepo).RecordDeleted(context.Background(), tx, sampleTransactionModel())
+
+	assert.NoError(t, err)
+	if assert.Len(t, *captured, 1) {
+		entry := (*captured)[0]
+		assert.Equal(t, model.HistoryDeleted, entry.Action)
+		assert.NotNil(t, entry.Before)
+		assert.Nil(t, entry.After)
+		assert.Empty(t, entry.ActorID)
+		assert.Empty(t, entry.Source)
+	}
+	historyRepo.AssertExpectations(t)
+}
+
+func TestHistoryRecorder_RepositoryError(t *testing.T) {
+	historyRepo := new(mocks.MockTransactionHistoryRepository)
+	tx := new(mocks.MockTransaction)
+	historyRepo.On("CreateHistory", mock.Anything, tx, mock.Anything).Return(errors.New("db insert error"))
+
+	err := newHistoryRecorder(historyRepo).RecordRestored(context.Background(), tx, sampleTransactionModel())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "record transaction history")
+	historyRepo.AssertExpectations(t)
+}
+
+// =====================================================================
+// GetTransactionHistory
+// =====================================================================
+
+func TestGetTransactionHistory_Success(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	entries := []model.TransactionHistory{
+		{ID: uuid.New(), TransactionID: txnTestID, Action: model.HistoryCreated, ActorID: "user-1", Source: data.REQUEST_SOURCE_HTTP, After: []byte(`{"id":"x"}`)},
+		{ID: uuid.New(), TransactionID: txnTestID, Action: model.HistoryDeleted, ActorID: "user-1", Source: data.REQUEST_SOURCE_GRPC, Before: []byte(`{"id":"x"}`)},
+	}
+	d.historyRepo.On("GetHistoryByTransactionID", mock.Anything, nil, txnTestID.String()).Return(entries, nil)
+
+	result, err := svc.GetTransactionHistory(context.Background(), txnTestID.String())
+
+	assert.NoError(t, err)
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, string(model.HistoryCreated), result[0].Action)
+		assert.Equal(t, string(model.HistoryDeleted), result[1].Action)
+		assert.Equal(t, data.REQUEST_SOURCE_GRPC, result[1].Source)
+	}
+	d.assertAll(t)
+}
+
+func TestGetTransactionHistory_RepositoryError(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.historyRepo.On("GetHistoryByTransactionID", mock.Anything, nil, txnTestID.String()).
+		Return([]model.TransactionHistory{}, errors.New("db error"))
+
+	_, err := svc.GetTransactionHistory(context.Background(), txnTestID.String())
+
+	assert.Error(t, err)
+	d.assertAll(t)
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+)
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Scheduled transactions
+// ──────────────────────────────────────────────────────────────────────────────
+
+func (transaction_serv *transactionsService) GetScheduledTransactions(ctx context.Context, walletIDs []string) ([]dto.TransactionsResponse, error) {
+	transactions, err := transaction_serv.transactionRepo.GetScheduledTransactions(ctx, nil, walletIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get scheduled transactions: %w", err)
+	}
+
+	responses := make([]dto.TransactionsResponse, 0, len(transactions))
+	for _, transaction := range transactions {
+		responses = append(responses, helper.ConvertToResponseType(transaction).(dto.TransactionsResponse))
+	}
+
+	return responses, nil
+}
+
+func (transaction_serv *transactionsService) CancelScheduledTransaction(ctx context.Context, id string) (dto.TransactionsResponse, error) {
+	tx, err := transaction_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("cancel scheduled transaction: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	transactionExist, err := transaction_serv.transactionRepo.GetTransactionByID(ctx, tx, id)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("transaction not found [id=%s]: %w", id, err)
+	}
+	if !transactionExist.Scheduled {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction: the transaction is not scheduled, delete it instead [id=%s]", id)
+	}
+
+	// ? The poster may have claimed it since it was read
+	cancelled, err := transaction_serv.transactionRepo.CancelScheduledTransaction(ctx, tx, id)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("cancel scheduled transaction [id=%s]: delete from db: %w", id, err)
+	}
+	if !cancelled {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction: the transaction posted before it could be cancelled [id=%s]", id)
+	}
+
+	if err := transaction_serv.history.RecordDeleted(ctx, tx, transactionExist); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("cancel scheduled transaction [id=%s]: %w", id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("cancel scheduled transaction: commit: %w", err)
+	}
+
+	return helper.ConvertToResponseType(transactionExist).(dto.TransactionsResponse), nil
+}
+
+func (transaction_serv *transactionsService) PostDueScheduledTransactions(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := transaction_serv.transactionRepo.GetDueScheduledTransactions(ctx, nil, now, data.SCHEDULED_POST_BATCH)
+	if err != nil {
+		return 0, fmt.Errorf("post scheduled transactions: %w", err)
+	}
+
+	// One failing transaction does not hold back the others; it is backed off
+	// and tried again later
+	posted := 0
+	for _, scheduled := range due {
+		if ctx.Err() != nil {
+			return posted, ctx.Err()
+		}
+
+		id := scheduled.ID.String()
+		ok, err := transaction_serv.postScheduled(ctx, id)
+		if err != nil {
+			transaction_serv.recordPostFailure(ctx, scheduled, now, err)
+			continue
+		}
+		if ok {
+			posted++
+		}
+	}
+	return posted, nil
+}
+
+// recordPostFailure backs a scheduled transaction off after a failed post, so
+// one that cannot post, for lack of balance say, is retried ever less often
+// instead of on every tick.
+func (transaction_serv *transactionsService) recordPostFailure(ctx context.Context, scheduled model.Transactions, now time.Time, cause error) {
+	id := scheduled.ID.String()
+	failureCount := scheduled.PostFailureCount + 1
+	retryAt := now.Add(retryBackoff(failureCount, data.SCHEDULED_POST_RETRY_BACKOFF, data.SCHEDULED_POST_RETRY_BACKOFF_MAX))
+
+	log.Error(data.LogScheduledPostFailed, map[string]any{
+		"service":        data.TransactionService,
+		"transaction_id": id,
+		"failure_count":  failureCount,
+		"retry_at":       retryAt,
+		"error":          cause.Error(),
+	})
+
+	if err := transaction_serv.transactionRepo.RecordScheduledPostFailure(ctx, nil, id, failureCount, cause.Error(), retryAt); err != nil {
+		log.Error(data.LogScheduledPostFailureNotSaved, map[string]any{"service": data.TransactionService, "transaction_id": id, "error": err.Error()})
+	}
+}
+
+// postScheduled books a due scheduled transaction on its wallet and announces
+// it with data.OUTBOX_EVENT_TRANSACTION_CREATED. It reports false when the
+// transaction was posted or cancelled meanwhile.
+func (transaction_serv *transactionsService) postScheduled(ctx context.Context, id string) (bool, error) {
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := transaction_serv.saga.NewSaga(data.SAGA_TYPE_TRANSACTION_POST)
+	committed := false
+	defer func() {
+		if !committed {
+			transaction_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
+	tx, err := transaction_serv.txManager.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("post scheduled transaction: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	claimed, err := transaction_serv.transactionRepo.PostScheduledTransaction(ctx, tx, id)
+	if err != nil {
+		return false, fmt.Errorf("post scheduled transaction [id=%s]: %w", id, err)
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	transactionPosted, err := transaction_serv.transactionRepo.GetTransactionByID(ctx, tx, id)
+	if err != nil {
+		return false, fmt.Errorf("transaction not found [id=%s]: %w", id, err)
+	}
+	transactionScheduled := transactionPosted
+	transactionScheduled.Scheduled = true
+
+	// Book the amount now, as a transaction created today would have been
+	effect, err := transaction_serv.ledger.bookedEffect(ctx, tx, transactionPosted)
+	if err != nil {
+		return false, fmt.Errorf("post scheduled transaction [id=%s]: %w", id, err)
+	}
+
+	if !effect.IsZero() {
+		wallet, err := transaction_serv.walletClient.GetWalletByID(ctx, transactionPosted.WalletID.String())
+		if err != nil {
+			return false, fmt.Errorf("wallet not found [id=%s]: %w", transactionPosted.WalletID.String(), err)
+		}
+		if effect.IsNegative() && client.WalletBalance(wallet).LessThan(effect.Neg()) {
+			return false, fmt.Errorf("insufficient wallet balance [wallet_id=%s]", transactionPosted.WalletID.String())
+		}
+
+		if err := transaction_serv.saga.UpdateWalletBalance(ctx, saga, wallet, effect); err != nil {
+			return false, fmt.Errorf("update wallet balance: %w", err)
+		}
+	}
+
+	// ? The expense counts against its budgets from now on
+	if err := transaction_serv.budgets.TransactionChanged(ctx, tx, nil, &transactionPosted); err != nil {
+		return false, fmt.Errorf("post scheduled transaction [id=%s]: evaluate budgets: %w", id, err)
+	}
+
+	if err := transaction_serv.history.RecordUpdated(ctx, tx, transactionScheduled, transactionPosted); err != nil {
+		return false, fmt.Errorf("post scheduled transaction [id=%s]: %w", id, err)
+	}
+
+	payload, err := json.Marshal(helper.ConvertToResponseType(transactionPosted).(dto.TransactionsResponse))
+	if err != nil {
+		return false, fmt.Errorf("post scheduled transaction: marshal transaction response: %w", err)
+	}
+
+	if err := transaction_serv.outboxRepository.Create(ctx, tx, &model.OutboxMessage{
+		AggregateID: transactionPosted.ID.String(),
+		EventType:   data.OUTBOX_EVENT_TRANSACTION_CREATED,
+		Payload:     payload,
+		Published:   false,
+		MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+	}); err != nil {
+		return false, err
+	}
+
+	if err := transaction_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return false, fmt.Errorf("post scheduled transaction: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("post scheduled transaction: commit: %w", err)
+	}
+	committed = true
+
+	return true, nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Poster
+// ──────────────────────────────────────────────────────────────────────────────
+
+// ScheduledTransactionPoster posts scheduled transactions on their date. A
+// transaction that cannot post, for lack of balance say, stays scheduled and
+// is retried with a growing backoff, up to SCHEDULED_POST_RETRY_BACKOFF_MAX.
+type ScheduledTransactionPoster struct {
+	transactionService TransactionsService
+	interval           time.Duration
+}
+
+func NewScheduledTransactionPoster(transactionService TransactionsService) *ScheduledTransactionPoster {
+	return &ScheduledTransactionPoster{
+		transactionService: transactionService,
+		interval:           data.SCHEDULED_POST_INTERVAL,
+	}
+}
+
+// Start posts due transactions right away, to catch up after downtime, and
+// then on every tick until ctx is cancelled.
+func (p *ScheduledTransactionPoster) Start(ctx context.Context) {
+	ctx = helper.WithRequestSource(ctx, data.REQUEST_SOURCE_SCHEDULER, "")
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		posted, err := p.transactionService.PostDueScheduledTransactions(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Error(data.LogScheduledPostFailed, map[string]any{"service": data.TransactionService, "error": err.Error()})
+		} else if posted > 0 {
+			log.Info(data.LogScheduledTransactionsPosted, map[string]any{"service": data.TransactionService, "count": posted})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func sampleScheduledTransactionModel() model.Transactions {
+	txn := sampleTransactionModel()
+	txn.Scheduled = true
+	return txn
+}
+
+// =====================================================================
+// CreateTransaction (scheduled)
+// =====================================================================
+
+func TestCreateTransaction_ScheduledLeavesWalletAlone(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	req := sampleTransactionRequest()
+	req.Date = time.Now().Add(48 * time.Hour)
+	req.Scheduled = true
+	created := sampleScheduledTransactionModel()
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(txn model.Transactions) bool {
+		return txn.Scheduled
+	})).Return(created, nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CreateTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Scheduled)
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.outboxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_ScheduledNeedsFutureDate(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	req := sampleTransactionRequest()
+	req.Scheduled = true
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil).Maybe()
+
+	_, err := svc.CreateTransaction(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "needs a future date")
+	d.transactionRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// PostDueScheduledTransactions
+// =====================================================================
+
+func TestPostDueScheduledTransactions_BooksAndAnnounces(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	d.transactionRepo.On("GetDueScheduledTransactions", mock.Anything, nil, mock.Anything, data.SCHEDULED_POST_BATCH).
+		Return([]model.Transactions{sampleScheduledTransactionModel()}, nil)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("PostScheduledTransaction", mock.Anything, d.tx, txnTestID.String()).Return(true, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(sampleTransactionModel(), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 100000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 50000), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_CREATED && msg.AggregateID == txnTestID.String()
+	})).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	posted, err := svc.PostDueScheduledTransactions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, posted)
+	d.assertAll(t)
+}
+
+func TestPostDueScheduledTransactions_SkipsAlreadyClaimed(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("GetDueScheduledTransactions", mock.Anything, nil, mock.Anything, data.SCHEDULED_POST_BATCH).
+		Return([]model.Transactions{sampleScheduledTransactionModel()}, nil)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("PostScheduledTransaction", mock.Anything, d.tx, txnTestID.String()).Return(false, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	posted, err := svc.PostDueScheduledTransactions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Zero(t, posted)
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.outboxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestPostDueScheduledTransactions_InsufficientBalanceStaysScheduled(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.transactionRepo.On("GetDueScheduledTransactions", mock.Anything, nil, mock.Anything, data.SCHEDULED_POST_BATCH).
+		Return([]model.Transactions{sampleScheduledTransactionModel()}, nil)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("PostScheduledTransaction", mock.Anything, d.tx, txnTestID.String()).Return(true, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(sampleTransactionModel(), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 10000), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	d.transactionRepo.On("RecordScheduledPostFailure", mock.Anything, nil, txnTestID.String(), 1, mock.MatchedBy(func(reason string) bool {
+		return strings.Contains(reason, "insufficient wallet balance")
+	}), mock.MatchedBy(func(retryAt time.Time) bool {
+		return retryAt.After(time.Now().Add(data.SCHEDULED_POST_RETRY_BACKOFF - time.Minute))
+	})).Return(nil)
+
+	posted, err := svc.PostDueScheduledTransactions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Zero(t, posted)
+	d.tx.AssertNotCalled(t, "Commit")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestPostDueScheduledTransactions_RepeatedFailureBacksOffFurther(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	failing := sampleScheduledTransactionModel()
+	failing.PostFailureCount = 3
+	posting := sampleScheduledTransactionModel()
+	posting.ID = uuid.New()
+
+	d.transactionRepo.On("GetDueScheduledTransactions", mock.Anything, nil, mock.Anything, data.SCHEDULED_POST_BATCH).
+		Return([]model.Transactions{failing, posting}, nil)
+	d.txManager.On("Begin", mock.Anything).Return(nil, errors.New("connection refused")).Once()
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil).Once()
+	d.transactionRepo.On("PostScheduledTransaction", mock.Anything, d.tx, posting.ID.String()).Return(false, nil)
+	d.tx.On("Rollback").Return(nil)
+	d.transactionRepo.On("RecordScheduledPostFailure", mock.Anything, nil, txnTestID.String(), 4, mock.Anything, mock.MatchedBy(func(retryAt time.Time) bool {
+		return retryAt.After(time.Now().Add(8*data.SCHEDULED_POST_RETRY_BACKOFF - time.Minute))
+	})).Return(errors.New("db down"))
+
+	posted, err := svc.PostDueScheduledTransactions(context.Background())
+
+	// The failure is recorded, even unsuccessfully, without holding back the next one
+	assert.NoError(t, err)
+	assert.Zero(t, posted)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// CancelScheduledTransaction
+// =====================================================================
+
+func TestCancelScheduledTransaction_Success(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(sampleScheduledTransactionModel(), nil)
+	d.transactionRepo.On("CancelScheduledTransaction", mock.Anything, d.tx, txnTestID.String()).Return(true, nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CancelScheduledTransaction(context.Background(), txnTestID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, txnTestID.String(), result.ID)
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.outboxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCancelScheduledTransaction_NotScheduled(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(sampleTransactionModel(), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.CancelScheduledTransaction(context.Background(), txnTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not scheduled")
+	d.assertAll(t)
+}
+
+func TestCancelScheduledTransaction_PostedMeanwhile(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(sampleScheduledTransactionModel(), nil)
+	d.transactionRepo.On("CancelScheduledTransaction", mock.Anything, d.tx, txnTestID.String()).Return(false, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.CancelScheduledTransaction(context.Background(), txnTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "posted before it could be cancelled")
+	d.assertAll(t)
+}
+
+func TestDeleteTransaction_ScheduledIsCancelledInstead(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil).Maybe()
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, mock.Anything, txnTestID.String()).Return(sampleScheduledTransactionModel(), nil)
+	d.tx.On("Rollback").Return(nil).Maybe()
+
+	_, err := svc.DeleteTransaction(context.Background(), txnTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cancelled, not deleted")
+	d.transactionRepo.AssertNotCalled(t, "DeleteTransaction", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+)
+
+// resolveSplits turns the requested split lines into models, checking each
+// category exists and the lines fit a transaction of category and amount.
+func (transaction_serv *transactionsService) resolveSplits(ctx context.Context, tx repository.Transaction, requests []dto.TransactionSplitsRequest, category model.Categories, amount money.Amount) ([]model.TransactionSplits, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	splits := make([]model.TransactionSplits, 0, len(requests))
+	for i, request := range requests {
+		splitCategory, err := transaction_serv.categoryRepo.GetCategoryByID(ctx, tx, request.CategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("split category not found [line=%d, id=%s]: %w", i+1, request.CategoryID, err)
+		}
+
+		CategoryID, err := helper.ParseUUID(request.CategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid split category id [line=%d, id=%s]: %w", i+1, request.CategoryID, err)
+		}
+
+		splits = append(splits, model.TransactionSplits{
+			CategoryID: CategoryID,
+			Amount:     request.Amount,
+			Note:       request.Note,
+			Position:   i,
+			Category:   splitCategory,
+		})
+	}
+
+	if err := validateSplits(splits, category.Type, amount); err != nil {
+		return nil, err
+	}
+
+	return splits, nil
+}
+
+// validateSplits checks that split lines share the transaction's category
+// type and add up to its amount. No lines means the transaction is unsplit.
+func validateSplits(splits []model.TransactionSplits, categoryType model.CategoryType, amount money.Amount) error {
+	if len(splits) == 0 {
+		return nil
+	}
+	if categoryType != model.Income && categoryType != model.Expense {
+		return fmt.Errorf("invalid split: %s transactions cannot be split", categoryType)
+	}
+	if len(splits) < 2 {
+		return fmt.Errorf("invalid split: at least 2 lines are required")
+	}
+
+	var total money.Amount
+	for i, split := range splits {
+		if !split.Amount.IsPositive() {
+			return fmt.Errorf("invalid split amount [line=%d, amount=%s]", i+1, split.Amount)
+		}
+		if split.Category.Type != categoryType {
+			return fmt.Errorf("invalid split category type [line=%d, type=%s]: must be %s", i+1, split.Category.Type, categoryType)
+		}
+		total = total.Add(split.Amount)
+	}
+	if total != amount {
+		return fmt.Errorf("invalid split: lines sum to %s but amount is %s", total, amount)
+	}
+
+	return nil
+}
+
+// transactionLines lists what a transaction books per category: its split
+// lines, or the transaction itself when it is not split.
+func transactionLines(transaction *model.Transactions) []model.TransactionSplits {
+	if len(transaction.Splits) > 0 {
+		return transaction.Splits
+	}
+	return []model.TransactionSplits{{
+		TransactionID: transaction.ID,
+		CategoryID:    transaction.CategoryID,
+		Amount:        transaction.Amount,
+		Category:      transaction.Category,
+	}}
+}
+
+// rescaleSplits returns new lines with the categories and notes of splits and
+// amounts scaled to add up to amount. The last line absorbs rounding.
+func rescaleSplits(splits []model.TransactionSplits, amount money.Amount) ([]model.TransactionSplits, error) {
+	var before money.Amount
+	for _, split := range splits {
+		before = before.Add(split.Amount)
+	}
+
+	rescaled := make([]model.TransactionSplits, 0, len(splits))
+	var assigned money.Amount
+	for i, split := range splits {
+		line := amount.Sub(assigned)
+		if i < len(splits)-1 && !before.IsZero() {
+			scaled, err := amount.MulRatio(split.Amount, before)
+			if err != nil {
+				return nil, fmt.Errorf("rescale split amount [line=%d, amount=%s]: %w", i+1, split.Amount, err)
+			}
+			line = scaled
+		}
+		assigned = assigned.Add(line)
+
+		rescaled = append(rescaled, model.TransactionSplits{
+			CategoryID: split.CategoryID,
+			Amount:     line,
+			Note:       split.Note,
+			Position:   split.Position,
+			Category:   split.Category,
+		})
+	}
+
+	return rescaled, nil
+}
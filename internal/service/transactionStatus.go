@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+)
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Status lifecycle
+// ──────────────────────────────────────────────────────────────────────────────
+
+func (transaction_serv *transactionsService) UpdateTransactionStatus(ctx context.Context, id string, status string) (dto.TransactionsResponse, error) {
+	next := model.TransactionStatus(status)
+
+	// Compensate wallet-service updates unless the local transaction commits
+	saga := transaction_serv.saga.NewSaga(data.SAGA_TYPE_TRANSACTION_STATUS)
+	committed := false
+	defer func() {
+		if !committed {
+			transaction_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
+	tx, err := transaction_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction status: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	transactionBefore, err := transaction_serv.transactionRepo.GetTransactionByID(ctx, tx, id)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("transaction not found [id=%s]: %w", id, err)
+	}
+	if !transactionBefore.Status.CanTransition(next) {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid status transition [id=%s, from=%s, to=%s]", id, transactionBefore.Status, next)
+	}
+	// ? Voiding one leg would move a single wallet; a transfer is undone as a whole
+	if next == model.StatusVoid && transactionBefore.TransferID != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid status transition: a transfer leg cannot be voided, delete the transfer instead [id=%s, transfer_id=%s]", id, transactionBefore.TransferID)
+	}
+	// ? The outstanding balance of a debt counts every repayment
+	if next == model.StatusVoid && transactionBefore.DebtID != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid status transition: a debt transaction cannot be voided, delete the debt instead [id=%s, debt_id=%s]", id, transactionBefore.DebtID)
+	}
+	if transactionBefore.Scheduled {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid status transition: a scheduled transaction keeps its status until it posts [id=%s]", id)
+	}
+
+	transactionAfter := transactionBefore
+	transactionAfter.Status = next
+
+	// Book or reverse the amount when the transaction starts or stops counting in the balance
+	effectBefore, err := transaction_serv.ledger.bookedEffect(ctx, tx, transactionBefore)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction status [id=%s]: %w", id, err)
+	}
+	effectAfter, err := transaction_serv.ledger.bookedEffect(ctx, tx, transactionAfter)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction status [id=%s]: %w", id, err)
+	}
+
+	if delta := effectAfter.Sub(effectBefore); !delta.IsZero() {
+		wallet, err := transaction_serv.walletClient.GetWalletByID(ctx, transactionBefore.WalletID.String())
+		if err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("wallet not found [id=%s]: %w", transactionBefore.WalletID.String(), err)
+		}
+		if delta.IsNegative() && client.WalletBalance(wallet).LessThan(delta.Neg()) {
+			return dto.TransactionsResponse{}, fmt.Errorf("insufficient wallet balance [wallet_id=%s]", transactionBefore.WalletID.String())
+		}
+
+		if err := transaction_serv.saga.UpdateWalletBalance(ctx, saga, wallet, delta); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("update wallet balance: %w", err)
+		}
+	}
+
+	transactionUpdated, err := transaction_serv.transactionRepo.UpdateTransaction(ctx, tx, transactionAfter)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction status [id=%s]: update in db: %w", id, err)
+	}
+
+	// ? A voided expense no longer counts against its budgets
+	if err := transaction_serv.budgets.TransactionChanged(ctx, tx, &transactionBefore, &transactionUpdated); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction status [id=%s]: evaluate budgets: %w", id, err)
+	}
+
+	if err := transaction_serv.history.RecordUpdated(ctx, tx, transactionBefore, transactionUpdated); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction status [id=%s]: %w", id, err)
+	}
+
+	transactionResponse := helper.ConvertToResponseType(transactionUpdated).(dto.TransactionsResponse)
+
+	payload, err := json.Marshal(transactionResponse)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction status: marshal transaction response: %w", err)
+	}
+
+	if err := transaction_serv.outboxRepository.Create(ctx, tx, &model.OutboxMessage{
+		AggregateID: transactionResponse.ID,
+		EventType:   data.OUTBOX_EVENT_TRANSACTION_UPDATED,
+		Payload:     payload,
+		Published:   false,
+		MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+	}); err != nil {
+		return dto.TransactionsResponse{}, err
+	}
+
+	if err := transaction_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction status: commit: %w", err)
+	}
+	committed = true
+
+	return transactionResponse, nil
+}
+
+// initialStatus is the status a new transaction is created in: cleared
+// unless the caller enters it as pending.
+func initialStatus(status string) (model.TransactionStatus, error) {
+	switch model.TransactionStatus(status) {
+	case "", model.StatusCleared:
+		return model.StatusCleared, nil
+	case model.StatusPending:
+		return model.StatusPending, nil
+	}
+	return "", fmt.Errorf("invalid transaction status: a transaction starts pending or cleared [status=%s]", status)
+}
+
+// checkEditable rejects edits that the status of a transaction rules out:
+// void transactions are final, and reconciled ones keep the wallet, amount
+// and direction that were matched against a statement. The legs of a
+// transfer keep theirs too; they change through the transfer. So do the
+// transactions of a debt, which its outstanding balance adds up. Scheduled
+// transactions are cancelled and entered again rather than edited.
+func checkEditable(before model.Transactions, walletID string, amount money.Amount, category model.Categories) error {
+	if before.Scheduled {
+		return fmt.Errorf("invalid transaction: a scheduled transaction cannot be changed before it posts, cancel it instead [id=%s]", before.ID)
+	}
+
+	switch before.Status {
+	case model.StatusVoid:
+		return fmt.Errorf("invalid transaction status: a void transaction cannot be changed [id=%s]", before.ID)
+	case model.StatusReconciled:
+		if walletID != before.WalletID.String() || amount != before.Amount || category.Type != before.Category.Type {
+			return fmt.Errorf("invalid transaction status: the wallet, amount and type of a reconciled transaction cannot be changed [id=%s]", before.ID)
+		}
+	}
+	if before.TransferID != nil && (walletID != before.WalletID.String() || amount != before.Amount || category.ID != before.CategoryID) {
+		return fmt.Errorf("invalid transaction: the wallet, amount and category of a transfer leg cannot be changed, edit the transfer instead [id=%s, transfer_id=%s]", before.ID, before.TransferID)
+	}
+	if before.DebtID != nil && (walletID != before.WalletID.String() || amount != before.Amount || category.ID != before.CategoryID) {
+		return fmt.Errorf("invalid transaction: the wallet, amount and category of a debt transaction cannot be changed [id=%s, debt_id=%s]", before.ID, before.DebtID)
+	}
+	return nil
+}
+
+// normalizeStatusFilter rejects status filters that are not a transaction status.
+func normalizeStatusFilter(statuses []string) error {
+	for _, status := range statuses {
+		switch model.TransactionStatus(status) {
+		case model.StatusPending, model.StatusCleared, model.StatusReconciled, model.StatusVoid:
+		default:
+			return fmt.Errorf("invalid status filter [status=%s]", status)
+		}
+	}
+	return nil
+}
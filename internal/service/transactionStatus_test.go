@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func sampleStatusTransaction(status model.TransactionStatus) model.Transactions {
+	txn := sampleTransactionModel()
+	txn.Status = status
+	return txn
+}
+
+// =====================================================================
+// UpdateTransactionStatus
+// =====================================================================
+
+func TestUpdateTransactionStatus_ClearingBooksPendingUnderClearedStage(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	cleared := sampleStatusTransaction(model.StatusCleared)
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(sampleStatusTransaction(model.StatusPending), nil)
+	d.reconciliationRepo.On("GetBalanceStage", mock.Anything, d.tx, walletTestID.String()).Return(model.BalanceStageCleared, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 100000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 50000), nil).Once()
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, cleared).Return(cleared, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_UPDATED
+	})).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.UpdateTransactionStatus(context.Background(), txnTestID.String(), string(model.StatusCleared))
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(model.StatusCleared), result.Status)
+	d.assertAll(t)
+}
+
+func TestUpdateTransactionStatus_ClearingPendingUnderPendingStageKeepsBalance(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	cleared := sampleStatusTransaction(model.StatusCleared)
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(sampleStatusTransaction(model.StatusPending), nil)
+	d.reconciliationRepo.On("GetBalanceStage", mock.Anything, d.tx, walletTestID.String()).Return(model.BalanceStagePending, nil)
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, cleared).Return(cleared, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.UpdateTransactionStatus(context.Background(), txnTestID.String(), string(model.StatusCleared))
+
+	assert.NoError(t, err)
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestUpdateTransactionStatus_VoidReversesBookedExpense(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	voided := sampleStatusTransaction(model.StatusVoid)
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(sampleStatusTransaction(model.StatusCleared), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 0), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 50000), nil).Once()
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, voided).Return(voided, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.UpdateTransactionStatus(context.Background(), txnTestID.String(), string(model.StatusVoid))
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(model.StatusVoid), result.Status)
+	d.assertAll(t)
+}
+
+func TestUpdateTransactionStatus_RejectsInvalidTransition(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(sampleStatusTransaction(model.StatusReconciled), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.UpdateTransactionStatus(context.Background(), txnTestID.String(), string(model.StatusPending))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid status transition")
+	d.transactionRepo.AssertNotCalled(t, "UpdateTransaction", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
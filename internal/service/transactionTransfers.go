@@ -0,0 +1,439 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
+	wpb "github.com/MuhammadMiftaa/Refina-Protobuf/wallet"
+	"github.com/google/uuid"
+)
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Transfers
+// ──────────────────────────────────────────────────────────────────────────────
+
+func (transaction_serv *transactionsService) GetTransfers(ctx context.Context, walletIDs []string) ([]dto.TransfersResponse, error) {
+	transfers, err := transaction_serv.transactionRepo.GetTransfersByWalletIDs(ctx, nil, walletIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get transfers: %w", err)
+	}
+
+	responses := make([]dto.TransfersResponse, 0, len(transfers))
+	for _, transfer := range transfers {
+		responses = append(responses, helper.ConvertToResponseType(transfer).(dto.TransfersResponse))
+	}
+	return responses, nil
+}
+
+func (transaction_serv *transactionsService) GetTransferByID(ctx context.Context, id string) (dto.TransfersResponse, error) {
+	transfer, err := transaction_serv.transactionRepo.GetTransferByID(ctx, nil, id)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("transfer not found [id=%s]: %w", id, err)
+	}
+
+	return helper.ConvertToResponseType(transfer).(dto.TransfersResponse), nil
+}
+
+func (transaction_serv *transactionsService) UpdateTransfer(ctx context.Context, id string, request dto.UpdateTransferRequest) (dto.TransfersResponse, error) {
+	if !request.Amount.IsPositive() || request.AdminFee.IsNegative() {
+		return dto.TransfersResponse{}, fmt.Errorf("invalid fund transfer amount [amount=%s, admin_fee=%s]", request.Amount, request.AdminFee)
+	}
+	if request.FromWalletID == request.ToWalletID {
+		return dto.TransfersResponse{}, fmt.Errorf("source wallet and destination wallet cannot be the same [wallet_id=%s]", request.FromWalletID)
+	}
+
+	FromWalletID, err := helper.ParseUUID(request.FromWalletID)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("invalid from wallet id [id=%s]: %w", request.FromWalletID, err)
+	}
+
+	ToWalletID, err := helper.ParseUUID(request.ToWalletID)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("invalid to wallet id [id=%s]: %w", request.ToWalletID, err)
+	}
+
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := transaction_serv.saga.NewSaga(data.SAGA_TYPE_TRANSFER_UPDATE)
+	committed := false
+	defer func() {
+		if !committed {
+			transaction_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
+	tx, err := transaction_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	// ? Lock the transfer so that a concurrent edit waits for this one
+	transfer, err := transaction_serv.transactionRepo.GetTransferByID(ctx, tx, id)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("transfer not found [id=%s]: %w", id, err)
+	}
+
+	fromWallet, err := transaction_serv.walletClient.GetWalletByID(ctx, request.FromWalletID)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("source wallet not found [id=%s]: %w", request.FromWalletID, err)
+	}
+
+	toWallet, err := transaction_serv.walletClient.GetWalletByID(ctx, request.ToWalletID)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("destination wallet not found [id=%s]: %w", request.ToWalletID, err)
+	}
+
+	// ? The cash-in leg carries the amount converted into the destination currency
+	fromCurrency, err := transaction_serv.currencies.walletCurrency(ctx, tx, request.FromWalletID)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer: %w", err)
+	}
+	toCurrency, err := transaction_serv.currencies.walletCurrency(ctx, tx, request.ToWalletID)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer: %w", err)
+	}
+	conversion, err := transferConversion(request.Amount, request.ToAmount, request.ExchangeRate, fromCurrency, toCurrency)
+	if err != nil {
+		return dto.TransfersResponse{}, err
+	}
+
+	// ? The cash-out leg carries the amount; the admin fee is an expense of its own
+	cashOutBefore, cashInBefore := transfer.CashOut, transfer.CashIn
+	cashOutAfter, cashInAfter := cashOutBefore, cashInBefore
+
+	if !request.Date.IsZero() {
+		transfer.TransferDate = request.Date
+	}
+
+	cashOutAfter.WalletID = FromWalletID
+	cashOutAfter.Amount = request.Amount
+	cashOutAfter.Currency = fromCurrency
+	cashOutAfter.TransactionDate = transfer.TransferDate
+	cashOutAfter.Description = "fund transfer to " + toWallet.GetName() + "(Cash Out)"
+
+	cashInAfter.WalletID = ToWalletID
+	conversion.apply(&cashInAfter)
+	cashInAfter.TransactionDate = transfer.TransferDate
+	cashInAfter.Description = "fund transfer from " + fromWallet.GetName() + "(Cash In)"
+
+	// ? Book, change or drop the fee expense
+	feeBefore := transfer.Fee
+	var feeAfter *model.Transactions
+	if request.AdminFee.IsPositive() {
+		fee := model.Transactions{TransferID: &transfer.ID}
+		if feeBefore != nil {
+			fee = *feeBefore
+		}
+		if feeBefore == nil || request.AdminFeeCategoryID != "" {
+			category, err := transaction_serv.adminFeeCategory(ctx, tx, request.AdminFeeCategoryID)
+			if err != nil {
+				return dto.TransfersResponse{}, fmt.Errorf("update transfer: %w", err)
+			}
+			fee.CategoryID = category.ID
+			fee.Category = category
+		}
+		fee.WalletID = FromWalletID
+		fee.Amount = request.AdminFee
+		fee.Currency = fromCurrency
+		fee.TransactionDate = transfer.TransferDate
+		fee.Description = "admin fee for fund transfer to " + toWallet.GetName()
+		feeAfter = &fee
+	}
+
+	// ? The legs keep the checks of their status; only the transfer may move them
+	checks := []struct{ before, after model.Transactions }{{cashOutBefore, cashOutAfter}, {cashInBefore, cashInAfter}}
+	if feeBefore != nil && feeAfter != nil {
+		checks = append(checks, struct{ before, after model.Transactions }{*feeBefore, *feeAfter})
+	}
+	for _, leg := range checks {
+		leg.before.TransferID = nil
+		if err := checkEditable(leg.before, leg.after.WalletID.String(), leg.after.Amount, leg.after.Category); err != nil {
+			return dto.TransfersResponse{}, err
+		}
+	}
+	if feeBefore != nil && feeAfter == nil && feeBefore.Status == model.StatusReconciled {
+		return dto.TransfersResponse{}, fmt.Errorf("invalid transaction status: a reconciled admin fee cannot be removed [id=%s, transaction_id=%s]", id, feeBefore.ID)
+	}
+
+	legsBefore := []model.Transactions{cashOutBefore, cashInBefore}
+	if feeBefore != nil {
+		legsBefore = append(legsBefore, *feeBefore)
+	}
+	legsAfter := []model.Transactions{cashOutAfter, cashInAfter}
+	if feeAfter != nil {
+		legsAfter = append(legsAfter, *feeAfter)
+	}
+	if err := transaction_serv.ledger.moveBalances(ctx, tx, saga, legsBefore, legsAfter, map[uuid.UUID]*wpb.Wallet{FromWalletID: fromWallet, ToWalletID: toWallet}); err != nil {
+		return dto.TransfersResponse{}, err
+	}
+
+	cashOutUpdated, err := transaction_serv.transactionRepo.UpdateTransaction(ctx, tx, cashOutAfter)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer [id=%s]: update cash out in db: %w", id, err)
+	}
+
+	cashInUpdated, err := transaction_serv.transactionRepo.UpdateTransaction(ctx, tx, cashInAfter)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer [id=%s]: update cash in in db: %w", id, err)
+	}
+
+	if err := transaction_serv.history.RecordUpdated(ctx, tx, cashOutBefore, cashOutUpdated); err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer [id=%s]: %w", id, err)
+	}
+	if err := transaction_serv.history.RecordUpdated(ctx, tx, cashInBefore, cashInUpdated); err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer [id=%s]: %w", id, err)
+	}
+
+	if err := emitTransactions(ctx, transaction_serv.outboxRepository, tx, data.OUTBOX_EVENT_TRANSACTION_UPDATED, cashOutUpdated, cashInUpdated); err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer: %w", err)
+	}
+
+	feeUpdated, err := transaction_serv.saveTransferFee(ctx, tx, feeBefore, feeAfter)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer [id=%s]: %w", id, err)
+	}
+
+	transfer.FromWalletID = FromWalletID
+	transfer.ToWalletID = ToWalletID
+	transfer.Amount = request.Amount
+	transfer.ToAmount = conversion.Amount
+	transfer.ExchangeRate = conversion.rate()
+	transfer.AdminFee = request.AdminFee
+	transfer.Description = request.Description
+	transfer.FeeTransactionID = nil
+	if feeUpdated != nil {
+		transfer.FeeTransactionID = &feeUpdated.ID
+	}
+
+	transferUpdated, err := transaction_serv.transactionRepo.UpdateTransfer(ctx, tx, transfer)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer [id=%s]: update in db: %w", id, err)
+	}
+	transferUpdated.CashOut, transferUpdated.CashIn, transferUpdated.Fee = cashOutUpdated, cashInUpdated, feeUpdated
+
+	if err := transaction_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("update transfer: commit: %w", err)
+	}
+	committed = true
+
+	return helper.ConvertToResponseType(transferUpdated).(dto.TransfersResponse), nil
+}
+
+// saveTransferFee writes the fee expense of a transfer as it is after an
+// update: created, changed or deleted, with its history and outbox event.
+// It returns the fee the transfer keeps, or nil.
+func (transaction_serv *transactionsService) saveTransferFee(ctx context.Context, tx repository.Transaction, before, after *model.Transactions) (*model.Transactions, error) {
+	var (
+		fee       model.Transactions
+		eventType string
+		err       error
+	)
+
+	switch {
+	case before == nil && after == nil:
+		return nil, nil
+	case before == nil:
+		if fee, err = transaction_serv.transactionRepo.CreateTransaction(ctx, tx, *after); err != nil {
+			return nil, fmt.Errorf("create fee transaction: insert to db: %w", err)
+		}
+		fee.Category = after.Category
+		err = transaction_serv.history.RecordCreated(ctx, tx, fee)
+		eventType = data.OUTBOX_EVENT_TRANSACTION_CREATED
+	case after == nil:
+		if fee, err = transaction_serv.transactionRepo.DeleteTransaction(ctx, tx, *before); err != nil {
+			return nil, fmt.Errorf("delete fee transaction [id=%s]: delete from db: %w", before.ID, err)
+		}
+		err = transaction_serv.history.RecordDeleted(ctx, tx, *before)
+		eventType = data.OUTBOX_EVENT_TRANSACTION_DELETED
+	default:
+		if fee, err = transaction_serv.transactionRepo.UpdateTransaction(ctx, tx, *after); err != nil {
+			return nil, fmt.Errorf("update fee transaction [id=%s]: update in db: %w", before.ID, err)
+		}
+		fee.Category = after.Category
+		err = transaction_serv.history.RecordUpdated(ctx, tx, *before, fee)
+		eventType = data.OUTBOX_EVENT_TRANSACTION_UPDATED
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// ? Bank fees count against the budgets of their category
+	if after != nil {
+		if err := transaction_serv.budgets.TransactionChanged(ctx, tx, before, &fee); err != nil {
+			return nil, fmt.Errorf("evaluate budgets: %w", err)
+		}
+	}
+
+	if err := emitTransactions(ctx, transaction_serv.outboxRepository, tx, eventType, fee); err != nil {
+		return nil, err
+	}
+
+	if after == nil {
+		return nil, nil
+	}
+	return &fee, nil
+}
+
+func (transaction_serv *transactionsService) DeleteTransfer(ctx context.Context, id string) (dto.TransfersResponse, error) {
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := transaction_serv.saga.NewSaga(data.SAGA_TYPE_TRANSFER_DELETE)
+	committed := false
+	defer func() {
+		if !committed {
+			transaction_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
+	tx, err := transaction_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("delete transfer: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	transfer, err := transaction_serv.transactionRepo.GetTransferByID(ctx, tx, id)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("transfer not found [id=%s]: %w", id, err)
+	}
+	legs := []model.Transactions{transfer.CashOut, transfer.CashIn}
+	if transfer.Fee != nil {
+		legs = append(legs, *transfer.Fee)
+	}
+	for _, leg := range legs {
+		if leg.Status == model.StatusReconciled {
+			return dto.TransfersResponse{}, fmt.Errorf("invalid transaction status: a transfer with a reconciled leg cannot be deleted [id=%s, transaction_id=%s]", id, leg.ID)
+		}
+	}
+
+	// ? Reverse every leg: the source wallet gets the amount and fee back
+	if err := transaction_serv.ledger.moveBalances(ctx, tx, saga, legs, nil, nil); err != nil {
+		return dto.TransfersResponse{}, err
+	}
+
+	for _, leg := range legs {
+		if _, err := transaction_serv.transactionRepo.DeleteTransaction(ctx, tx, leg); err != nil {
+			return dto.TransfersResponse{}, fmt.Errorf("delete transfer [id=%s]: delete transaction from db [transaction_id=%s]: %w", id, leg.ID, err)
+		}
+	}
+
+	transferDeleted, err := transaction_serv.transactionRepo.DeleteTransfer(ctx, tx, transfer)
+	if err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("delete transfer [id=%s]: delete from db: %w", id, err)
+	}
+
+	if err := transaction_serv.history.RecordDeleted(ctx, tx, legs...); err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("delete transfer [id=%s]: %w", id, err)
+	}
+
+	if err := emitTransactions(ctx, transaction_serv.outboxRepository, tx, data.OUTBOX_EVENT_TRANSACTION_DELETED, legs...); err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("delete transfer: %w", err)
+	}
+
+	if err := transaction_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("delete transfer: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.TransfersResponse{}, fmt.Errorf("delete transfer: commit: %w", err)
+	}
+	committed = true
+
+	return helper.ConvertToResponseType(transferDeleted).(dto.TransfersResponse), nil
+}
+
+// transferConversion is how amount, leaving a wallet in fromCurrency, is
+// credited to a wallet in toCurrency. Between two currencies toAmount or rate
+// says what it was exchanged for; given together they must agree to the cent.
+func transferConversion(amount, toAmount money.Amount, rate float64, fromCurrency, toCurrency string) (currencyConversion, error) {
+	if toAmount.IsNegative() || rate < 0 {
+		return currencyConversion{}, fmt.Errorf("invalid fund transfer amount [to_amount=%s, exchange_rate=%g]", toAmount, rate)
+	}
+
+	if fromCurrency == toCurrency {
+		if (!toAmount.IsZero() && toAmount != amount) || (rate != 0 && rate != 1) {
+			return currencyConversion{}, fmt.Errorf("invalid fund transfer: wallets hold the same currency, to_amount must equal amount [currency=%s, amount=%s, to_amount=%s, exchange_rate=%g]", fromCurrency, amount, toAmount, rate)
+		}
+		return currencyConversion{Currency: toCurrency, Amount: amount}, nil
+	}
+
+	if toAmount.IsZero() && rate == 0 {
+		return currencyConversion{}, fmt.Errorf("invalid fund transfer: wallets hold different currencies, to_amount or exchange_rate is required [from=%s, to=%s]", fromCurrency, toCurrency)
+	}
+	if rate == 0 {
+		rate = toAmount.Ratio(amount)
+	} else {
+		// ? A user-supplied rate can take the amount past what a wallet holds
+		expected, err := amount.MulRate(rate)
+		if err != nil {
+			return currencyConversion{}, fmt.Errorf("invalid fund transfer amount [amount=%s, exchange_rate=%g]: %w", amount, rate, err)
+		}
+		switch {
+		case toAmount.IsZero():
+			toAmount = expected
+		case expected.Sub(toAmount).Abs().GreaterThan(money.FromCents(1)):
+			return currencyConversion{}, fmt.Errorf("invalid fund transfer: to_amount does not match amount at exchange_rate [amount=%s, exchange_rate=%g, to_amount=%s, expected=%s]", amount, rate, toAmount, expected)
+		}
+	}
+	if !toAmount.IsPositive() {
+		return currencyConversion{}, fmt.Errorf("invalid fund transfer amount [to_amount=%s, exchange_rate=%g]", toAmount, rate)
+	}
+
+	return currencyConversion{
+		Currency:         toCurrency,
+		Amount:           toAmount,
+		OriginalAmount:   &amount,
+		OriginalCurrency: &fromCurrency,
+		Rate:             &rate,
+	}, nil
+}
+
+// adminFeeCategory loads the category an admin fee is booked in, the seeded
+// "Biaya Admin" when id is empty. It must be an expense category.
+func (transaction_serv *transactionsService) adminFeeCategory(ctx context.Context, tx repository.Transaction, id string) (model.Categories, error) {
+	if id == "" {
+		id = data.CATEGORY_ID_ADMIN_FEE
+	}
+
+	category, err := transaction_serv.categoryRepo.GetCategoryByID(ctx, tx, id)
+	if err != nil {
+		return model.Categories{}, fmt.Errorf("admin fee category not found [id=%s]: %w", id, err)
+	}
+	if category.Type != model.Expense {
+		return model.Categories{}, fmt.Errorf("invalid admin fee category: an expense category is required [id=%s, type=%s]", id, category.Type)
+	}
+	return category, nil
+}
+
+// emitTransactions writes an outbox event of eventType for each transaction.
+func emitTransactions(ctx context.Context, outboxRepository repository.OutboxRepository, tx repository.Transaction, eventType string, transactions ...model.Transactions) error {
+	for _, transaction := range transactions {
+		payload, err := json.Marshal(helper.ConvertToResponseType(transaction).(dto.TransactionsResponse))
+		if err != nil {
+			return fmt.Errorf("marshal transaction response [id=%s]: %w", transaction.ID, err)
+		}
+
+		if err := outboxRepository.Create(ctx, tx, &model.OutboxMessage{
+			AggregateID: transaction.ID.String(),
+			EventType:   eventType,
+			Payload:     payload,
+			Published:   false,
+			MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	transferTestID = uuid.MustParse("77777777-7777-7777-7777-777777777777")
+	cashOutLegID   = uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	cashInLegID    = uuid.MustParse("66666666-6666-6666-6666-666666666666")
+	feeLegID       = uuid.MustParse("88888888-8888-8888-8888-888888888888")
+)
+
+func sampleAdminFeeCategory() model.Categories {
+	return model.Categories{
+		Base: model.Base{ID: uuid.MustParse(data.CATEGORY_ID_ADMIN_FEE)},
+		Name: "Biaya Admin",
+		Type: model.Expense,
+	}
+}
+
+// sampleTransfer moves 100000 from walletTestID to wallet2ID for a 2000 fee.
+func sampleTransfer() model.Transfers {
+	transferID := transferTestID
+	feeID := feeLegID
+	return model.Transfers{
+		Base:                 model.Base{ID: transferTestID},
+		FromWalletID:         walletTestID,
+		ToWalletID:           wallet2ID,
+		CashOutTransactionID: cashOutLegID,
+		CashInTransactionID:  cashInLegID,
+		FeeTransactionID:     &feeID,
+		Amount:               money.New(100000),
+		AdminFee:             money.New(2000),
+		TransferDate:         txnFixTime,
+		CashOut: model.Transactions{
+			Base:            model.Base{ID: cashOutLegID},
+			WalletID:        walletTestID,
+			CategoryID:      cashOutCatID,
+			Amount:          money.New(100000),
+			TransactionDate: txnFixTime,
+			Status:          model.StatusCleared,
+			TransferID:      &transferID,
+			Category:        sampleFundTransferCashOut(),
+		},
+		CashIn: model.Transactions{
+			Base:            model.Base{ID: cashInLegID},
+			WalletID:        wallet2ID,
+			CategoryID:      cashInCatID,
+			Amount:          money.New(100000),
+			TransactionDate: txnFixTime,
+			Status:          model.StatusCleared,
+			TransferID:      &transferID,
+			Category:        sampleFundTransferCashIn(),
+		},
+		Fee: &model.Transactions{
+			Base:            model.Base{ID: feeLegID},
+			WalletID:        walletTestID,
+			CategoryID:      uuid.MustParse(data.CATEGORY_ID_ADMIN_FEE),
+			Amount:          money.New(2000),
+			TransactionDate: txnFixTime,
+			Status:          model.StatusCleared,
+			TransferID:      &transferID,
+			Category:        sampleAdminFeeCategory(),
+		},
+	}
+}
+
+func sampleUpdateTransferRequest(amount, fee int64) dto.UpdateTransferRequest {
+	return dto.UpdateTransferRequest{
+		FromWalletID: walletTestID.String(),
+		ToWalletID:   wallet2ID.String(),
+		Amount:       money.New(amount),
+		AdminFee:     money.New(fee),
+		Date:         txnFixTime,
+		Description:  "Transfer dana",
+	}
+}
+
+// =====================================================================
+// UpdateTransfer
+// =====================================================================
+
+func TestUpdateTransfer_MovesBothWalletsByTheDifference(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	transfer := sampleTransfer()
+	updated := sampleTransfer()
+	updated.Amount, updated.AdminFee, updated.FeeTransactionID, updated.Fee = money.New(150000), money.Zero, nil, nil
+	updated.CashOut.Amount, updated.CashIn.Amount = money.New(150000), money.New(150000)
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransferByID", mock.Anything, d.tx, transferTestID.String()).Return(sampleTransfer(), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 500000), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, wallet2ID.String()).Return(sampleWalletProto(wallet2ID, 100000), nil)
+	// 100000 and the 2000 fee come back to the source and 150000 leaves it; the destination gets 50000 more
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-48000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 452000), nil).Once()
+	d.walletClient.On("AdjustBalance", mock.Anything, wallet2ID.String(), money.New(50000), mock.Anything).
+		Return(sampleWalletProto(wallet2ID, 150000), nil).Once()
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(t model.Transactions) bool {
+		return t.ID == cashOutLegID && t.Amount == money.New(150000)
+	})).Return(updated.CashOut, nil).Once()
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(t model.Transactions) bool {
+		return t.ID == cashInLegID && t.Amount == money.New(150000)
+	})).Return(updated.CashIn, nil).Once()
+	d.transactionRepo.On("DeleteTransaction", mock.Anything, d.tx, *transfer.Fee).Return(*transfer.Fee, nil).Once()
+	d.transactionRepo.On("UpdateTransfer", mock.Anything, d.tx, mock.MatchedBy(func(tr model.Transfers) bool {
+		return tr.Amount == money.New(150000) && tr.AdminFee.IsZero() && tr.FeeTransactionID == nil
+	})).Return(updated, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_UPDATED
+	})).Return(nil).Times(2)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_DELETED && msg.AggregateID == feeLegID.String()
+	})).Return(nil).Once()
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.UpdateTransfer(context.Background(), transferTestID.String(), sampleUpdateTransferRequest(150000, 0))
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(150000), result.Amount)
+	assert.Equal(t, money.New(150000), result.CashOut.Amount)
+	assert.Equal(t, money.New(150000), result.CashIn.Amount)
+	assert.Nil(t, result.Fee)
+	d.assertAll(t)
+}
+
+func TestUpdateTransfer_InsufficientBalanceMovesNothing(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransferByID", mock.Anything, d.tx, transferTestID.String()).Return(sampleTransfer(), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 10000), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, wallet2ID.String()).Return(sampleWalletProto(wallet2ID, 100000), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.UpdateTransfer(context.Background(), transferTestID.String(), sampleUpdateTransferRequest(150000, 0))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.transactionRepo.AssertNotCalled(t, "UpdateTransaction", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestUpdateTransfer_RejectsReconciledLegMove(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	transfer := sampleTransfer()
+	transfer.CashIn.Status = model.StatusReconciled
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransferByID", mock.Anything, d.tx, transferTestID.String()).Return(transfer, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 500000), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, wallet2ID.String()).Return(sampleWalletProto(wallet2ID, 100000), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.UpdateTransfer(context.Background(), transferTestID.String(), sampleUpdateTransferRequest(150000, 0))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "reconciled")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestUpdateTransfer_BooksNewFeeAsExpense(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	// A transfer without a fee gets one of 5000
+	transfer := sampleTransfer()
+	transfer.AdminFee, transfer.FeeTransactionID, transfer.Fee = money.Zero, nil, nil
+	fee := model.Transactions{
+		Base:     model.Base{ID: feeLegID},
+		WalletID: walletTestID, CategoryID: sampleAdminFeeCategory().ID, Amount: money.New(5000),
+	}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransferByID", mock.Anything, d.tx, transferTestID.String()).Return(transfer, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 500000), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, wallet2ID.String()).Return(sampleWalletProto(wallet2ID, 100000), nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, data.CATEGORY_ID_ADMIN_FEE).Return(sampleAdminFeeCategory(), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-5000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 495000), nil).Once()
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.Anything).Return(transfer.CashOut, nil).Twice()
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(t model.Transactions) bool {
+		return t.CategoryID == fee.CategoryID && t.Amount == money.New(5000) && t.TransferID != nil && *t.TransferID == transferTestID
+	})).Return(fee, nil)
+	d.transactionRepo.On("UpdateTransfer", mock.Anything, d.tx, mock.MatchedBy(func(tr model.Transfers) bool {
+		return tr.FeeTransactionID != nil && *tr.FeeTransactionID == feeLegID && tr.AdminFee == money.New(5000)
+	})).Return(transfer, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_CREATED && msg.AggregateID == feeLegID.String()
+	})).Return(nil).Once()
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil).Twice()
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.UpdateTransfer(context.Background(), transferTestID.String(), sampleUpdateTransferRequest(100000, 5000))
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, result.Fee) {
+		assert.Equal(t, feeLegID.String(), result.Fee.ID)
+		assert.Equal(t, "Biaya Admin", result.Fee.CategoryName)
+	}
+	d.assertAll(t)
+}
+
+func TestFundTransfer_RejectsNonExpenseFeeCategory(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	toWallet := sampleWalletProto(wallet2ID, 0)
+	toWallet.Name = "Mandiri"
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 500000), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, wallet2ID.String()).Return(toWallet, nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, catTestID.String()).Return(sampleIncomeCategory(), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.FundTransfer(context.Background(), dto.FundTransferRequest{
+		CashInCategoryID:   cashInCatID.String(),
+		CashOutCategoryID:  cashOutCatID.String(),
+		FromWalletID:       walletTestID.String(),
+		ToWalletID:         wallet2ID.String(),
+		Amount:             money.New(100000),
+		AdminFee:           money.New(2000),
+		AdminFeeCategoryID: catTestID.String(),
+		Date:               txnFixTime,
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid admin fee category")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.transactionRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// DeleteTransfer
+// =====================================================================
+
+func TestDeleteTransfer_ReversesBothWallets(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	transfer := sampleTransfer()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransferByID", mock.Anything, d.tx, transferTestID.String()).Return(transfer, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 0), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, wallet2ID.String()).Return(sampleWalletProto(wallet2ID, 100000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(102000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 102000), nil).Once()
+	d.walletClient.On("AdjustBalance", mock.Anything, wallet2ID.String(), money.New(-100000), mock.Anything).
+		Return(sampleWalletProto(wallet2ID, 0), nil).Once()
+	d.transactionRepo.On("DeleteTransaction", mock.Anything, d.tx, transfer.CashOut).Return(transfer.CashOut, nil).Once()
+	d.transactionRepo.On("DeleteTransaction", mock.Anything, d.tx, transfer.CashIn).Return(transfer.CashIn, nil).Once()
+	d.transactionRepo.On("DeleteTransaction", mock.Anything, d.tx, *transfer.Fee).Return(*transfer.Fee, nil).Once()
+	d.transactionRepo.On("DeleteTransfer", mock.Anything, d.tx, transfer).Return(transfer, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_DELETED
+	})).Return(nil).Times(3)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.DeleteTransfer(context.Background(), transferTestID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, transferTestID.String(), result.ID)
+	d.assertAll(t)
+}
+
+func TestDeleteTransfer_RejectsReconciledLeg(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	transfer := sampleTransfer()
+	transfer.CashOut.Status = model.StatusReconciled
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransferByID", mock.Anything, d.tx, transferTestID.String()).Return(transfer, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.DeleteTransfer(context.Background(), transferTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "reconciled")
+	d.transactionRepo.AssertNotCalled(t, "DeleteTransaction", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// Single legs
+// =====================================================================
+
+func TestDeleteTransaction_RejectsTransferLeg(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, cashOutLegID.String()).Return(sampleTransfer().CashOut, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.DeleteTransaction(context.Background(), cashOutLegID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "delete the transfer instead")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestUpdateTransactionStatus_RejectsVoidingTransferLeg(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, cashInLegID.String()).Return(sampleTransfer().CashIn, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.UpdateTransactionStatus(context.Background(), cashInLegID.String(), string(model.StatusVoid))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "delete the transfer instead")
+	d.assertAll(t)
+}
+
+func TestCheckEditable_TransferLegKeepsWalletAmountAndCategory(t *testing.T) {
+	leg := sampleTransfer().CashOut
+
+	assert.NoError(t, checkEditable(leg, walletTestID.String(), leg.Amount, leg.Category))
+	assert.Error(t, checkEditable(leg, wallet2ID.String(), leg.Amount, leg.Category))
+	assert.Error(t, checkEditable(leg, walletTestID.String(), money.New(1), leg.Category))
+	assert.Error(t, checkEditable(leg, walletTestID.String(), leg.Amount, sampleExpenseCategory()))
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+)
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Trash
+// ──────────────────────────────────────────────────────────────────────────────
+
+func (transaction_serv *transactionsService) GetDeletedTransactions(ctx context.Context, walletIDs []string) ([]dto.TransactionsResponse, error) {
+	transactions, err := transaction_serv.transactionRepo.GetDeletedTransactions(ctx, nil, walletIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get deleted transactions: %w", err)
+	}
+
+	responses := make([]dto.TransactionsResponse, 0, len(transactions))
+	for _, transaction := range transactions {
+		responses = append(responses, helper.ConvertToResponseType(transaction).(dto.TransactionsResponse))
+	}
+
+	return responses, nil
+}
+
+func (transaction_serv *transactionsService) RestoreTransaction(ctx context.Context, id string) (dto.TransactionsResponse, error) {
+	// Compensate wallet-service updates unless the local transaction commits
+	saga := transaction_serv.saga.NewSaga(data.SAGA_TYPE_TRANSACTION_RESTORE)
+	committed := false
+	defer func() {
+		if !committed {
+			transaction_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
+	tx, err := transaction_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("restore transaction: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	// Lock the deleted row so that a second restore waits and then finds nothing
+	transactionDeleted, err := transaction_serv.transactionRepo.GetDeletedTransactionByID(ctx, tx, id)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("deleted transaction not found [id=%s]: %w", id, err)
+	}
+	// ? Restoring one leg would move a single wallet; deleted transfers stay deleted
+	if transactionDeleted.TransferID != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction: a leg of a deleted transfer cannot be restored [id=%s, transfer_id=%s]", id, transactionDeleted.TransferID)
+	}
+	if transactionDeleted.DebtID != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction: a transaction of a deleted debt cannot be restored [id=%s, debt_id=%s]", id, transactionDeleted.DebtID)
+	}
+	// ? A cancelled scheduled transaction never posted, so there is nothing to bring back
+	if transactionDeleted.Scheduled {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction: a cancelled scheduled transaction cannot be restored, schedule it again [id=%s]", id)
+	}
+
+	// Book the amount again, as when the transaction was created
+	effect, err := transaction_serv.ledger.bookedEffect(ctx, tx, transactionDeleted)
+	if err != nil {
+		return dto.TransactionsResponse{}, err
+	}
+
+	if !effect.IsZero() {
+		wallet, err := transaction_serv.walletClient.GetWalletByID(ctx, transactionDeleted.WalletID.String())
+		if err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("wallet not found [id=%s]: %w", transactionDeleted.WalletID.String(), err)
+		}
+		if effect.IsNegative() && client.WalletBalance(wallet).LessThan(effect.Neg()) {
+			return dto.TransactionsResponse{}, fmt.Errorf("insufficient wallet balance [wallet_id=%s]", transactionDeleted.WalletID.String())
+		}
+
+		if err := transaction_serv.saga.UpdateWalletBalance(ctx, saga, wallet, effect); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("update wallet balance: %w", err)
+		}
+	}
+
+	transactionRestored, err := transaction_serv.transactionRepo.RestoreTransaction(ctx, tx, transactionDeleted)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("restore transaction [id=%s]: update db: %w", id, err)
+	}
+
+	// ? The restored expense counts against its budgets again
+	if err := transaction_serv.budgets.TransactionChanged(ctx, tx, nil, &transactionRestored); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("restore transaction: evaluate budgets: %w", err)
+	}
+
+	if err := transaction_serv.history.RecordRestored(ctx, tx, transactionRestored); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("restore transaction [id=%s]: %w", id, err)
+	}
+
+	transactionResponse := helper.ConvertToResponseType(transactionRestored).(dto.TransactionsResponse)
+
+	payload, err := json.Marshal(transactionResponse)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("restore transaction: marshal transaction response: %w", err)
+	}
+
+	outboxMsg := &model.OutboxMessage{
+		AggregateID: transactionResponse.ID,
+		EventType:   data.OUTBOX_EVENT_TRANSACTION_RESTORED,
+		Payload:     payload,
+		Published:   false,
+		MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+	}
+
+	if err := transaction_serv.outboxRepository.Create(ctx, tx, outboxMsg); err != nil {
+		return dto.TransactionsResponse{}, err
+	}
+
+	if err := transaction_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("restore transaction: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("restore transaction: commit: %w", err)
+	}
+	committed = true
+
+	return transactionResponse, nil
+}
+
+func (transaction_serv *transactionsService) PurgeDeletedTransactions(ctx context.Context) (int64, error) {
+	purged, err := transaction_serv.transactionRepo.PurgeDeletedTransactions(ctx, nil, time.Now().Add(-data.TRASH_RETENTION))
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted transactions: %w", err)
+	}
+
+	return purged, nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Purger
+// ──────────────────────────────────────────────────────────────────────────────
+
+// TrashPurger empties the trash of transactions deleted longer than
+// data.TRASH_RETENTION ago. The wallet balance was reversed on delete, so a
+// purge only removes rows.
+type TrashPurger struct {
+	transactionService TransactionsService
+	interval           time.Duration
+}
+
+func NewTrashPurger(transactionService TransactionsService) *TrashPurger {
+	return &TrashPurger{
+		transactionService: transactionService,
+		interval:           data.TRASH_PURGE_INTERVAL,
+	}
+}
+
+// Start purges right away and then on every tick until ctx is cancelled.
+func (p *TrashPurger) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.transactionService.PurgeDeletedTransactions(ctx)
+		if err != nil {
+			log.Error(data.LogTrashPurgeFailed, map[string]any{"service": data.TransactionService, "error": err.Error()})
+		} else if purged > 0 {
+			log.Info(data.LogTrashPurged, map[string]any{"service": data.TransactionService, "count": purged})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
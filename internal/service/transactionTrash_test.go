@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func sampleDeletedTransactionModel() model.Transactions {
+	txn := sampleTransactionModel()
+	txn.DeletedAt = gorm.DeletedAt{Time: txnFixTime.Add(time.Hour), Valid: true}
+	return txn
+}
+
+// =====================================================================
+// GetDeletedTransactions
+// =====================================================================
+
+func TestGetDeletedTransactions_Success(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	walletIDs := []string{walletTestID.String()}
+	d.transactionRepo.On("GetDeletedTransactions", mock.Anything, nil, walletIDs).
+		Return([]model.Transactions{sampleDeletedTransactionModel()}, nil)
+
+	result, err := svc.GetDeletedTransactions(context.Background(), walletIDs)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, txnTestID.String(), result[0].ID)
+	if assert.NotNil(t, result[0].DeletedAt) {
+		assert.Equal(t, txnFixTime.Add(time.Hour), *result[0].DeletedAt)
+	}
+	d.assertAll(t)
+}
+
+// =====================================================================
+// RestoreTransaction
+// =====================================================================
+
+func TestRestoreTransaction_ExpenseDebitsWalletAgain(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	deleted := sampleDeletedTransactionModel()
+	restored := sampleTransactionModel()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetDeletedTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(deleted, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 100000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 50000), nil)
+	d.transactionRepo.On("RestoreTransaction", mock.Anything, d.tx, deleted).Return(restored, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_RESTORED && msg.AggregateID == txnTestID.String()
+	})).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.RestoreTransaction(context.Background(), txnTestID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, txnTestID.String(), result.ID)
+	assert.Nil(t, result.DeletedAt)
+	d.assertAll(t)
+}
+
+func TestRestoreTransaction_IncomeCreditsWalletAgain(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	deleted := sampleDeletedTransactionModel()
+	deleted.Category = sampleIncomeCategory()
+	restored := deleted
+	restored.DeletedAt = gorm.DeletedAt{}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetDeletedTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(deleted, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 0), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 50000), nil)
+	d.transactionRepo.On("RestoreTransaction", mock.Anything, d.tx, deleted).Return(restored, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.RestoreTransaction(context.Background(), txnTestID.String())
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestRestoreTransaction_InsufficientBalance(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetDeletedTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(sampleDeletedTransactionModel(), nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 10000), nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.RestoreTransaction(context.Background(), txnTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient wallet balance")
+	assert.Empty(t, result.ID)
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestRestoreTransaction_NotInTrash(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetDeletedTransactionByID", mock.Anything, d.tx, txnTestID.String()).
+		Return(model.Transactions{}, errors.New("deleted transaction not found"))
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.RestoreTransaction(context.Background(), txnTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	d.assertAll(t)
+}
+
+func TestRestoreTransaction_DBErrorCompensatesWallet(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompensated)
+
+	deleted := sampleDeletedTransactionModel()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetDeletedTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(deleted, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 100000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 50000), nil).Once()
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 100000), nil).Once()
+	d.transactionRepo.On("RestoreTransaction", mock.Anything, d.tx, deleted).
+		Return(model.Transactions{}, errors.New("db update error"))
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.RestoreTransaction(context.Background(), txnTestID.String())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "update db")
+	d.assertAll(t)
+}
+
+// =====================================================================
+// PurgeDeletedTransactions
+// =====================================================================
+
+func TestPurgeDeletedTransactions_UsesRetention(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	before := time.Now().Add(-data.TRASH_RETENTION)
+	d.transactionRepo.On("PurgeDeletedTransactions", mock.Anything, nil, mock.MatchedBy(func(cutoff time.Time) bool {
+		return !cutoff.Before(before) && cutoff.Before(time.Now().Add(-data.TRASH_RETENTION+time.Minute))
+	})).Return(int64(3), nil)
+
+	purged, err := svc.PurgeDeletedTransactions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	d.assertAll(t)
+}
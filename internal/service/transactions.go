@@ -2,14 +2,20 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"refina-transaction/config/miniofs"
 	"refina-transaction/interface/grpc/client"
+	"refina-transaction/interface/grpc/interceptor"
 	"refina-transaction/internal/repository"
 	"refina-transaction/internal/types/dto"
 	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
 	"refina-transaction/internal/utils"
 	helper "refina-transaction/internal/utils"
 	"refina-transaction/internal/utils/data"
@@ -17,6 +23,10 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrIdempotencyKeyReused is returned when an idempotency key is sent again
+// with a request that differs from the one it was first used for.
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused for a different request")
+
 type TransactionsService interface {
 	GetAllTransactions(ctx context.Context) ([]dto.TransactionsResponse, error)
 	GetTransactionByID(ctx context.Context, id string) (dto.TransactionsResponse, error)
@@ -27,27 +37,79 @@ type TransactionsService interface {
 	UploadAttachment(ctx context.Context, tx repository.Transaction, transactionID string, files []string) ([]dto.AttachmentsResponse, error)
 	UpdateTransaction(ctx context.Context, id string, transaction dto.TransactionsRequest) (dto.TransactionsResponse, error)
 	DeleteTransaction(ctx context.Context, id string) (dto.TransactionsResponse, error)
+	// BatchTransactions applies creates, updates and deletes all together or
+	// not at all, reporting the outcome of each item.
+	BatchTransactions(ctx context.Context, request dto.BatchTransactionsRequest) (dto.BatchTransactionsResponse, error)
+	// GetDeletedTransactions lists the trash of the wallets, most recently deleted first.
+	GetDeletedTransactions(ctx context.Context, walletIDs []string) ([]dto.TransactionsResponse, error)
+	// RestoreTransaction takes a transaction out of the trash and books its
+	// amount on the wallet again.
+	RestoreTransaction(ctx context.Context, id string) (dto.TransactionsResponse, error)
+	// PurgeDeletedTransactions permanently removes the transactions deleted
+	// longer than data.TRASH_RETENTION ago and returns how many it removed.
+	PurgeDeletedTransactions(ctx context.Context) (int64, error)
+	// GetScheduledTransactions lists the scheduled transactions of the wallets
+	// still to post, soonest first.
+	GetScheduledTransactions(ctx context.Context, walletIDs []string) ([]dto.TransactionsResponse, error)
+	// CancelScheduledTransaction deletes a scheduled transaction before it
+	// posts; it never moved the balance, so nothing is reversed.
+	CancelScheduledTransaction(ctx context.Context, id string) (dto.TransactionsResponse, error)
+	// PostDueScheduledTransactions books the scheduled transactions whose
+	// date has come and returns how many it posted.
+	PostDueScheduledTransactions(ctx context.Context) (int, error)
+	// GetTransactionHistory lists the audit trail of a transaction, oldest first.
+	GetTransactionHistory(ctx context.Context, id string) ([]dto.TransactionHistoryResponse, error)
+	// UpdateTransactionStatus moves a transaction along its lifecycle,
+	// booking or reversing its amount when that changes whether it counts
+	// in the wallet balance.
+	UpdateTransactionStatus(ctx context.Context, id string, status string) (dto.TransactionsResponse, error)
+	// GetTransfers lists the transfers out of or into the wallets, most recent first.
+	GetTransfers(ctx context.Context, walletIDs []string) ([]dto.TransfersResponse, error)
+	GetTransferByID(ctx context.Context, id string) (dto.TransfersResponse, error)
+	// UpdateTransfer changes both legs of a transfer and moves the balances of
+	// every wallet involved, before and after, in one go.
+	UpdateTransfer(ctx context.Context, id string, request dto.UpdateTransferRequest) (dto.TransfersResponse, error)
+	// DeleteTransfer deletes both legs of a transfer and reverses their
+	// effect on both wallets.
+	DeleteTransfer(ctx context.Context, id string) (dto.TransfersResponse, error)
 }
 
 type transactionsService struct {
-	txManager        repository.TxManager
-	transactionRepo  repository.TransactionsRepository
-	categoryRepo     repository.CategoriesRepository
-	attachmentRepo   repository.AttachmentsRepository
-	outboxRepository repository.OutboxRepository
-	minio            *miniofs.MinIOManager
-	walletClient     client.WalletClient
+	txManager          repository.TxManager
+	transactionRepo    repository.TransactionsRepository
+	categoryRepo       repository.CategoriesRepository
+	attachmentRepo     repository.AttachmentsRepository
+	outboxRepository   repository.OutboxRepository
+	minio              *miniofs.MinIOManager
+	walletClient       client.WalletClient
+	saga               *SagaOrchestrator
+	idempotencyRepo    repository.IdempotencyRepository
+	budgets            *budgetMonitor
+	currencies         *currencyConverter
+	tagRepo            repository.TagsRepository
+	history            *historyRecorder
+	reconciliationRepo repository.ReconciliationsRepository
+	ledger             *walletLedger
 }
 
-func NewTransactionService(txManager repository.TxManager, transactionRepo repository.TransactionsRepository, walletRepo client.WalletClient, categoryRepo repository.CategoriesRepository, attachmentRepo repository.AttachmentsRepository, outboxRepository repository.OutboxRepository, minio *miniofs.MinIOManager) TransactionsService {
+func NewTransactionService(txManager repository.TxManager, transactionRepo repository.TransactionsRepository, walletRepo client.WalletClient, categoryRepo repository.CategoriesRepository, attachmentRepo repository.AttachmentsRepository, outboxRepository repository.OutboxRepository, sagaRepo repository.SagaLogRepository, idempotencyRepo repository.IdempotencyRepository, budgetRepo repository.BudgetsRepository, currencyRepo repository.CurrenciesRepository, tagRepo repository.TagsRepository, historyRepo repository.TransactionHistoryRepository, reconciliationRepo repository.ReconciliationsRepository, minio *miniofs.MinIOManager) TransactionsService {
+	saga := NewSagaOrchestrator(sagaRepo, walletRepo)
 	return &transactionsService{
-		txManager:        txManager,
-		transactionRepo:  transactionRepo,
-		categoryRepo:     categoryRepo,
-		attachmentRepo:   attachmentRepo,
-		outboxRepository: outboxRepository,
-		minio:            minio,
-		walletClient:     walletRepo,
+		txManager:          txManager,
+		transactionRepo:    transactionRepo,
+		categoryRepo:       categoryRepo,
+		attachmentRepo:     attachmentRepo,
+		outboxRepository:   outboxRepository,
+		minio:              minio,
+		walletClient:       walletRepo,
+		saga:               saga,
+		idempotencyRepo:    idempotencyRepo,
+		budgets:            newBudgetMonitor(budgetRepo, walletRepo, outboxRepository),
+		currencies:         newCurrencyConverter(currencyRepo),
+		tagRepo:            tagRepo,
+		history:            newHistoryRecorder(historyRepo),
+		reconciliationRepo: reconciliationRepo,
+		ledger:             newWalletLedger(walletRepo, saga, reconciliationRepo),
 	}
 }
 
@@ -115,6 +177,13 @@ func (transaction_serv *transactionsService) GetTransactionsByWalletIDs(ctx cont
 }
 
 func (transaction_serv *transactionsService) GetTransactionsByCursor(ctx context.Context, q repository.CursorQuery) ([]dto.TransactionsResponse, int64, error) {
+	if err := normalizeTagFilter(&q); err != nil {
+		return nil, 0, fmt.Errorf("get transactions by cursor: %w", err)
+	}
+	if err := normalizeStatusFilter(q.Statuses); err != nil {
+		return nil, 0, fmt.Errorf("get transactions by cursor: %w", err)
+	}
+
 	transactions, total, err := transaction_serv.transactionRepo.GetTransactionsByCursor(ctx, nil, q)
 	if err != nil {
 		return nil, 0, fmt.Errorf("get transactions by cursor: %w", err)
@@ -130,11 +199,65 @@ func (transaction_serv *transactionsService) GetTransactionsByCursor(ctx context
 }
 
 func (transaction_serv *transactionsService) CreateTransaction(ctx context.Context, transaction dto.TransactionsRequest) (dto.TransactionsResponse, error) {
+	// Replay the original response if this request was already processed
+	idempotencyKey := helper.IdempotencyKeyFromContext(ctx)
+	requestHash := idempotencyRequestHash(transaction)
+	var replayed dto.TransactionsResponse
+	if found, err := transaction_serv.replayIdempotent(ctx, data.IDEMPOTENCY_OPERATION_TRANSACTION_CREATE, idempotencyKey, requestHash, &replayed); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: %w", err)
+	} else if found {
+		return replayed, nil
+	}
+
+	// A split without an explicit category is filed under its first line
+	if transaction.CategoryID == "" && len(transaction.Splits) > 0 {
+		transaction.CategoryID = transaction.Splits[0].CategoryID
+	}
+
 	category, err := transaction_serv.categoryRepo.GetCategoryByID(ctx, nil, transaction.CategoryID)
 	if err != nil {
 		return dto.TransactionsResponse{}, fmt.Errorf("category not found [id=%s]: %w", transaction.CategoryID, err)
 	}
 
+	splits, err := transaction_serv.resolveSplits(ctx, nil, transaction.Splits, category, transaction.Amount)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: %w", err)
+	}
+
+	tags, err := resolveTags(ctx, nil, transaction_serv.tagRepo, interceptor.UserIDFromContext(ctx), transaction.TagIDs)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: %w", err)
+	}
+
+	status, err := initialStatus(transaction.Status)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: %w", err)
+	}
+	if transaction.Scheduled && !transaction.Date.After(time.Now()) {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction: a scheduled transaction needs a future date [date=%s]", transaction.Date.Format(time.RFC3339))
+	}
+
+	// Book an amount entered in another currency in the wallet's, at the rate of its date
+	conversion, err := transaction_serv.currencies.forWallet(ctx, nil, transaction.WalletID, transaction.Currency, transaction.Amount, transaction.Date)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: %w", err)
+	}
+	if conversion.converted() && len(splits) > 0 {
+		if splits, err = rescaleSplits(splits, conversion.Amount); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("create transaction: %w", err)
+		}
+	}
+	transaction.Amount = conversion.Amount
+
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := transaction_serv.saga.NewSaga(data.SAGA_TYPE_TRANSACTION_CREATE)
+	committed := false
+	defer func() {
+		if !committed {
+			transaction_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
 	tx, err := transaction_serv.txManager.Begin(ctx)
 	if err != nil {
 		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: begin transaction: %w", err)
@@ -153,48 +276,88 @@ func (transaction_serv *transactionsService) CreateTransaction(ctx context.Conte
 		return dto.TransactionsResponse{}, fmt.Errorf("invalid wallet id [id=%s]: %w", transaction.WalletID, err)
 	}
 
+	// A pending transaction waits for clearing when the wallet books cleared ones only,
+	// and a scheduled one for its date
+	booked, err := transaction_serv.ledger.isBooked(ctx, tx, model.Transactions{WalletID: WalletID, Status: status, Scheduled: transaction.Scheduled})
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: %w", err)
+	}
+
 	// Check if wallet and category exist
-	if !transaction.IsWalletNotCreated {
+	if !transaction.IsWalletNotCreated && booked {
 		wallet, err := transaction_serv.walletClient.GetWalletByID(ctx, transaction.WalletID)
 		if err != nil {
 			return dto.TransactionsResponse{}, fmt.Errorf("wallet not found [id=%s]: %w", transaction.WalletID, err)
 		}
 
-		// Check if transaction type is valid and update wallet balance
+		// Check if transaction type is valid and compute the balance change
+		var delta money.Amount
 		switch category.Type {
 		case "expense":
 			// Check if wallet has sufficient balance
-			if wallet.GetBalance() < transaction.Amount {
+			if client.WalletBalance(wallet).LessThan(transaction.Amount) {
 				return dto.TransactionsResponse{}, fmt.Errorf("insufficient wallet balance [wallet_id=%s]", transaction.WalletID)
 			}
 
-			wallet.Balance -= transaction.Amount
+			delta = transaction.Amount.Neg()
 		case "income":
-			wallet.Balance += transaction.Amount
+			delta = transaction.Amount
 		default:
 			return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction type [type=%s]", category.Type)
 		}
 
 		// Update wallet balance
-		_, err = transaction_serv.walletClient.UpdateWallet(ctx, wallet)
-		if err != nil {
+		if err = transaction_serv.saga.UpdateWalletBalance(ctx, saga, wallet, delta); err != nil {
 			return dto.TransactionsResponse{}, fmt.Errorf("update wallet balance [wallet_id=%s]: %w", transaction.WalletID, err)
 		}
 	}
 
 	// Create transaction
-	transactionNew, err := transaction_serv.transactionRepo.CreateTransaction(ctx, tx, model.Transactions{
+	transactionModel := model.Transactions{
 		WalletID:        WalletID,
 		CategoryID:      CategoryID,
-		Amount:          transaction.Amount,
 		TransactionDate: transaction.Date,
 		Description:     transaction.Description,
 		Category:        category,
-	})
+		Status:          status,
+		Scheduled:       transaction.Scheduled,
+	}
+	conversion.apply(&transactionModel)
+
+	transactionNew, err := transaction_serv.transactionRepo.CreateTransaction(ctx, tx, transactionModel)
 	if err != nil {
 		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: insert to db: %w", err)
 	}
 
+	// ? Store split lines; the wallet balance was adjusted once for the whole amount
+	if len(splits) > 0 {
+		for i := range splits {
+			splits[i].TransactionID = transactionNew.ID
+		}
+		if transactionNew.Splits, err = transaction_serv.transactionRepo.ReplaceTransactionSplits(ctx, tx, transactionNew.ID.String(), splits); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("create transaction: insert splits: %w", err)
+		}
+	}
+
+	// ? Label the transaction with its tags
+	if len(tags) > 0 {
+		if err := transaction_serv.tagRepo.ReplaceTransactionTags(ctx, tx, transactionNew.ID, tagIDsOf(tags)); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("create transaction: insert tags: %w", err)
+		}
+	}
+	transactionNew.Tags = tags
+
+	if err := transaction_serv.history.RecordCreated(ctx, tx, transactionNew); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: %w", err)
+	}
+
+	// ? Emit budget events if the new expense crosses a budget threshold
+	if !transaction.IsWalletNotCreated {
+		if err := transaction_serv.budgets.TransactionChanged(ctx, tx, nil, &transactionNew); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("create transaction: evaluate budgets: %w", err)
+		}
+	}
+
 	// ? If attachments exist, upload attachments
 	if len(transaction.Attachments) > 0 {
 		for _, attachment := range transaction.Attachments {
@@ -216,27 +379,64 @@ func (transaction_serv *transactionsService) CreateTransaction(ctx context.Conte
 		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: marshal transaction response: %w", err)
 	}
 
-	outboxMsg := &model.OutboxMessage{
-		AggregateID: transactionResponse.ID,
-		EventType:   data.OUTBOX_EVENT_TRANSACTION_CREATED,
-		Payload:     payload,
-		Published:   false,
-		MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+	// ? A scheduled transaction is announced when it posts
+	if !transactionNew.Scheduled {
+		outboxMsg := &model.OutboxMessage{
+			AggregateID: transactionResponse.ID,
+			EventType:   data.OUTBOX_EVENT_TRANSACTION_CREATED,
+			Payload:     payload,
+			Published:   false,
+			MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+		}
+
+		if err := transaction_serv.outboxRepository.Create(ctx, tx, outboxMsg); err != nil {
+			return dto.TransactionsResponse{}, err
+		}
 	}
 
-	if err := transaction_serv.outboxRepository.Create(ctx, tx, outboxMsg); err != nil {
-		return dto.TransactionsResponse{}, err
+	if err := transaction_serv.saveIdempotent(ctx, tx, data.IDEMPOTENCY_OPERATION_TRANSACTION_CREATE, idempotencyKey, requestHash, transactionResponse); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: %w", err)
+	}
+
+	if err := transaction_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: %w", err)
 	}
 
 	// Commit transaksi jika semua sukses
 	if err := tx.Commit(); err != nil {
 		return dto.TransactionsResponse{}, fmt.Errorf("create transaction: commit: %w", err)
 	}
+	committed = true
 
 	return transactionResponse, nil
 }
 
 func (transaction_serv *transactionsService) FundTransfer(ctx context.Context, transaction dto.FundTransferRequest) (dto.FundTransferResponse, error) {
+	// Replay the original response if this request was already processed
+	idempotencyKey := helper.IdempotencyKeyFromContext(ctx)
+	requestHash := idempotencyRequestHash(transaction)
+	var replayed dto.FundTransferResponse
+	if found, err := transaction_serv.replayIdempotent(ctx, data.IDEMPOTENCY_OPERATION_FUND_TRANSFER, idempotencyKey, requestHash, &replayed); err != nil {
+		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: %w", err)
+	} else if found {
+		return replayed, nil
+	}
+
+	if !transaction.Amount.IsPositive() || transaction.AdminFee.IsNegative() {
+		return dto.FundTransferResponse{}, fmt.Errorf("invalid fund transfer amount [amount=%s, admin_fee=%s]", transaction.Amount, transaction.AdminFee)
+	}
+	// The source wallet pays the amount and the admin fee
+	debit := transaction.Amount.Add(transaction.AdminFee)
+
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := transaction_serv.saga.NewSaga(data.SAGA_TYPE_TRANSACTION_TRANSFER)
+	committed := false
+	defer func() {
+		if !committed {
+			transaction_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
 	tx, err := transaction_serv.txManager.Begin(ctx)
 	if err != nil {
 		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: begin transaction: %w", err)
@@ -256,7 +456,7 @@ func (transaction_serv *transactionsService) FundTransfer(ctx context.Context, t
 	}
 
 	// Check if wallet has sufficient balance
-	if fromWallet.GetBalance() < (transaction.Amount + transaction.AdminFee) {
+	if client.WalletBalance(fromWallet).LessThan(debit) {
 		return dto.FundTransferResponse{}, fmt.Errorf("insufficient wallet balance [wallet_id=%s]", transaction.FromWalletID)
 	}
 
@@ -265,8 +465,19 @@ func (transaction_serv *transactionsService) FundTransfer(ctx context.Context, t
 		return dto.FundTransferResponse{}, fmt.Errorf("source wallet and destination wallet cannot be the same [wallet_id=%s]", transaction.FromWalletID)
 	}
 
-	fromWallet.Balance -= (transaction.Amount + transaction.AdminFee)
-	toWallet.Balance += transaction.Amount
+	// The cash-in leg carries the amount converted into the destination currency
+	fromCurrency, err := transaction_serv.currencies.walletCurrency(ctx, tx, transaction.FromWalletID)
+	if err != nil {
+		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: %w", err)
+	}
+	toCurrency, err := transaction_serv.currencies.walletCurrency(ctx, tx, transaction.ToWalletID)
+	if err != nil {
+		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: %w", err)
+	}
+	conversion, err := transferConversion(transaction.Amount, transaction.ToAmount, transaction.ExchangeRate, fromCurrency, toCurrency)
+	if err != nil {
+		return dto.FundTransferResponse{}, err
+	}
 
 	// Parse ID from JSON to valid UUID
 	FromWalletID, err := helper.ParseUUID(transaction.FromWalletID)
@@ -290,80 +501,134 @@ func (transaction_serv *transactionsService) FundTransfer(ctx context.Context, t
 		return dto.FundTransferResponse{}, fmt.Errorf("invalid to category id [id=%s]: %w", transaction.CashInCategoryID, err)
 	}
 
+	// The admin fee is booked as an expense of its own
+	var feeCategory model.Categories
+	if transaction.AdminFee.IsPositive() {
+		if feeCategory, err = transaction_serv.adminFeeCategory(ctx, tx, transaction.AdminFeeCategoryID); err != nil {
+			return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: %w", err)
+		}
+	}
+
 	// Update wallet balance
-	if _, err = transaction_serv.walletClient.UpdateWallet(ctx, fromWallet); err != nil {
+	if err = transaction_serv.saga.UpdateWalletBalance(ctx, saga, fromWallet, debit.Neg()); err != nil {
 		return dto.FundTransferResponse{}, fmt.Errorf("update from wallet balance: %w", err)
 	}
-	if _, err = transaction_serv.walletClient.UpdateWallet(ctx, toWallet); err != nil {
+	if err = transaction_serv.saga.UpdateWalletBalance(ctx, saga, toWallet, conversion.Amount); err != nil {
 		return dto.FundTransferResponse{}, fmt.Errorf("update to wallet balance: %w", err)
 	}
 
+	// The legs reference the transfer, which is inserted once both exist
+	TransferID := uuid.New()
+
 	transactionNewFrom, err := transaction_serv.transactionRepo.CreateTransaction(ctx, tx, model.Transactions{
 		WalletID:        FromWalletID,
 		CategoryID:      FromCategoryID,
-		Amount:          transaction.Amount + transaction.AdminFee,
+		Amount:          transaction.Amount,
+		Currency:        fromCurrency,
 		TransactionDate: transaction.Date,
 		Description:     "fund transfer to " + toWallet.GetName() + "(Cash Out)",
+		TransferID:      &TransferID,
 	})
 	if err != nil {
 		return dto.FundTransferResponse{}, fmt.Errorf("create from transaction: insert to db: %w", err)
 	}
 
-	transactionNewTo, err := transaction_serv.transactionRepo.CreateTransaction(ctx, tx, model.Transactions{
+	cashIn := model.Transactions{
 		WalletID:        ToWalletID,
 		CategoryID:      ToCategoryID,
-		Amount:          transaction.Amount,
 		TransactionDate: transaction.Date,
 		Description:     "fund transfer from " + fromWallet.GetName() + "(Cash In)",
-	})
+		TransferID:      &TransferID,
+	}
+	conversion.apply(&cashIn)
+
+	transactionNewTo, err := transaction_serv.transactionRepo.CreateTransaction(ctx, tx, cashIn)
 	if err != nil {
 		return dto.FundTransferResponse{}, fmt.Errorf("create to transaction: insert to db: %w", err)
 	}
 
-	transactionNewFromPayload, err := json.Marshal(helper.ConvertToResponseType(transactionNewFrom).(dto.TransactionsResponse))
-	if err != nil {
-		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: marshal from transaction response: %w", err)
+	transfer := model.Transfers{
+		Base:                 model.Base{ID: TransferID},
+		FromWalletID:         FromWalletID,
+		ToWalletID:           ToWalletID,
+		CashOutTransactionID: transactionNewFrom.ID,
+		CashInTransactionID:  transactionNewTo.ID,
+		Amount:               transaction.Amount,
+		ToAmount:             conversion.Amount,
+		ExchangeRate:         conversion.rate(),
+		AdminFee:             transaction.AdminFee,
+		TransferDate:         transaction.Date,
+		Description:          transaction.Description,
 	}
+	legs := []model.Transactions{transactionNewFrom, transactionNewTo}
+
+	if transaction.AdminFee.IsPositive() {
+		transactionFee, err := transaction_serv.transactionRepo.CreateTransaction(ctx, tx, model.Transactions{
+			WalletID:        FromWalletID,
+			CategoryID:      feeCategory.ID,
+			Amount:          transaction.AdminFee,
+			Currency:        fromCurrency,
+			TransactionDate: transaction.Date,
+			Description:     "admin fee for fund transfer to " + toWallet.GetName(),
+			TransferID:      &TransferID,
+			Category:        feeCategory,
+		})
+		if err != nil {
+			return dto.FundTransferResponse{}, fmt.Errorf("create fee transaction: insert to db: %w", err)
+		}
+		transactionFee.Category = feeCategory
 
-	transactionNewToPayload, err := json.Marshal(helper.ConvertToResponseType(transactionNewTo).(dto.TransactionsResponse))
-	if err != nil {
-		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: marshal to transaction response: %w", err)
+		// ? Bank fees count against the budgets of their category
+		if err := transaction_serv.budgets.TransactionChanged(ctx, tx, nil, &transactionFee); err != nil {
+			return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: evaluate budgets: %w", err)
+		}
+
+		transfer.FeeTransactionID = &transactionFee.ID
+		legs = append(legs, transactionFee)
 	}
 
-	if err := transaction_serv.outboxRepository.Create(ctx, tx, &model.OutboxMessage{
-		AggregateID: transactionNewFrom.ID.String(),
-		EventType:   data.OUTBOX_EVENT_TRANSACTION_CREATED,
-		Payload:     transactionNewFromPayload,
-		Published:   false,
-		MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
-	}); err != nil {
-		return dto.FundTransferResponse{}, err
+	if _, err := transaction_serv.transactionRepo.CreateTransfer(ctx, tx, transfer); err != nil {
+		return dto.FundTransferResponse{}, fmt.Errorf("create transfer: insert to db: %w", err)
 	}
 
-	if err := transaction_serv.outboxRepository.Create(ctx, tx, &model.OutboxMessage{
-		AggregateID: transactionNewTo.ID.String(),
-		EventType:   data.OUTBOX_EVENT_TRANSACTION_CREATED,
-		Payload:     transactionNewToPayload,
-		Published:   false,
-		MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
-	}); err != nil {
-		return dto.FundTransferResponse{}, err
+	if err := transaction_serv.history.RecordCreated(ctx, tx, legs...); err != nil {
+		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: %w", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: commit: %w", err)
+	if err := emitTransactions(ctx, transaction_serv.outboxRepository, tx, data.OUTBOX_EVENT_TRANSACTION_CREATED, legs...); err != nil {
+		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: %w", err)
 	}
 
 	response := dto.FundTransferResponse{
+		TransferID:           TransferID.String(),
 		CashOutTransactionID: transactionNewFrom.ID.String(),
 		CashInTransactionID:  transactionNewTo.ID.String(),
 		FromWalletID:         transaction.FromWalletID,
 		ToWalletID:           transaction.ToWalletID,
 		Amount:               transaction.Amount,
+		ToAmount:             conversion.Amount,
+		ExchangeRate:         conversion.rate(),
 		Date:                 transaction.Date,
 		Description:          transaction.Description,
 	}
 
+	if transfer.FeeTransactionID != nil {
+		response.FeeTransactionID = transfer.FeeTransactionID.String()
+	}
+
+	if err := transaction_serv.saveIdempotent(ctx, tx, data.IDEMPOTENCY_OPERATION_FUND_TRANSFER, idempotencyKey, requestHash, response); err != nil {
+		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: %w", err)
+	}
+
+	if err := transaction_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.FundTransferResponse{}, fmt.Errorf("fund transfer: commit: %w", err)
+	}
+	committed = true
+
 	return response, nil
 }
 
@@ -424,6 +689,15 @@ func (transaction_serv *transactionsService) UploadAttachment(ctx context.Contex
 }
 
 func (transaction_serv *transactionsService) UpdateTransaction(ctx context.Context, id string, transaction dto.TransactionsRequest) (dto.TransactionsResponse, error) {
+	// ! Compensate wallet-service updates unless the local transaction commits
+	saga := transaction_serv.saga.NewSaga(data.SAGA_TYPE_TRANSACTION_UPDATE)
+	committed := false
+	defer func() {
+		if !committed {
+			transaction_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
 	// ! Begin a new transaction
 	tx, err := transaction_serv.txManager.Begin(ctx)
 	if err != nil {
@@ -438,11 +712,19 @@ func (transaction_serv *transactionsService) UpdateTransaction(ctx context.Conte
 	if err != nil {
 		return dto.TransactionsResponse{}, fmt.Errorf("transaction not found [id=%s]: %w", id, err)
 	}
+	transactionBefore := transactionExist
+	categoryAfter := transactionExist.Category
+
+	// ? Whether the amount counts in the balance of the wallet it is in now
+	bookedBefore, err := transaction_serv.ledger.isBooked(ctx, tx, transactionExist)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: %w", id, err)
+	}
 
 	// ? If category ID is different, update category
 	if transaction.CategoryID != transactionExist.CategoryID.String() {
 		// * Check if category exist
-		_, err = transaction_serv.categoryRepo.GetCategoryByID(ctx, tx, transaction.CategoryID)
+		categoryAfter, err = transaction_serv.categoryRepo.GetCategoryByID(ctx, tx, transaction.CategoryID)
 		if err != nil {
 			return dto.TransactionsResponse{}, fmt.Errorf("category not found [id=%s]: %w", transaction.CategoryID, err)
 		}
@@ -455,58 +737,137 @@ func (transaction_serv *transactionsService) UpdateTransaction(ctx context.Conte
 		transactionExist.CategoryID = CategoryID
 	}
 
-	// ? If wallet ID is different, update wallet balance
+	// ? Book an amount entered in another currency in the wallet's, at the rate of its date
+	conversionDate := transaction.Date
+	if conversionDate.IsZero() {
+		conversionDate = transactionExist.TransactionDate
+	}
+	conversion, err := transaction_serv.currencies.forWallet(ctx, tx, transaction.WalletID, transaction.Currency, transaction.Amount, conversionDate)
+	if err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: %w", id, err)
+	}
 	if transaction.WalletID != transactionExist.WalletID.String() {
-		// *  Check if wallet exist
-		oldWallet, err := transaction_serv.walletClient.GetWalletByID(ctx, transactionExist.WalletID.String())
+		oldCurrency, err := transaction_serv.currencies.walletCurrency(ctx, tx, transactionExist.WalletID.String())
 		if err != nil {
-			return dto.TransactionsResponse{}, fmt.Errorf("wallet not found [id=%s]: %w", transactionExist.WalletID.String(), err)
+			return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: %w", id, err)
 		}
-
-		// *  Update wallet balance
-		switch transactionExist.Category.Type {
-		case "expense":
-			oldWallet.Balance += transactionExist.Amount
-		case "income":
-			oldWallet.Balance -= transactionExist.Amount
-		default:
-			return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction type [type=%s]", transactionExist.Category.Type)
+		if oldCurrency != conversion.Currency {
+			return dto.TransactionsResponse{}, fmt.Errorf("invalid wallet: cannot move a transaction from %s to a %s wallet [id=%s]", oldCurrency, conversion.Currency, id)
 		}
+	}
 
-		if _, err = transaction_serv.walletClient.UpdateWallet(ctx, oldWallet); err != nil {
-			return dto.TransactionsResponse{}, fmt.Errorf("update old wallet balance: %w", err)
+	// ? Check new tags before touching any balance
+	var tags []model.Tags
+	if transaction.TagIDs != nil {
+		if tags, err = resolveTags(ctx, tx, transaction_serv.tagRepo, interceptor.UserIDFromContext(ctx), transaction.TagIDs); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: %w", id, err)
 		}
+	}
 
-		// *  Check if new wallet exist
-		newWallet, err := transaction_serv.walletClient.GetWalletByID(ctx, transaction.WalletID)
-		if err != nil {
-			return dto.TransactionsResponse{}, fmt.Errorf("new wallet not found [id=%s]: %w", transaction.WalletID, err)
+	// ? Check split lines before touching any balance: replace them, or make sure
+	// the current ones still fit the new amount and category
+	splits := transactionExist.Splits
+	replaceSplits := transaction.Splits != nil
+	if transaction.Splits != nil {
+		// * New lines are entered in the same currency as the amount
+		if splits, err = transaction_serv.resolveSplits(ctx, tx, transaction.Splits, categoryAfter, transaction.Amount); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: %w", id, err)
 		}
-
-		// *  Update wallet balance
-		switch transactionExist.Category.Type {
-		case "expense":
-			newWallet.Balance -= transaction.Amount
-		case "income":
-			newWallet.Balance += transaction.Amount
-		default:
-			return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction type [type=%s]", transactionExist.Category.Type)
+		if conversion.converted() && len(splits) > 0 {
+			if splits, err = rescaleSplits(splits, conversion.Amount); err != nil {
+				return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: %w", id, err)
+			}
 		}
-
-		if _, err = transaction_serv.walletClient.UpdateWallet(ctx, newWallet); err != nil {
-			return dto.TransactionsResponse{}, fmt.Errorf("update new wallet balance: %w", err)
+	}
+	transaction.Amount = conversion.Amount
+	if err := checkEditable(transactionBefore, transaction.WalletID, transaction.Amount, categoryAfter); err != nil {
+		return dto.TransactionsResponse{}, err
+	}
+	if transaction.Splits == nil {
+		if transaction.RescaleSplits && len(splits) > 0 {
+			switch {
+			case transactionExist.CategoryID != transactionBefore.CategoryID:
+				// * The whole amount now belongs to the new category
+				splits, replaceSplits = nil, true
+			case transaction.Amount != transactionExist.Amount:
+				if splits, err = rescaleSplits(splits, transaction.Amount); err != nil {
+					return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: %w", id, err)
+				}
+				replaceSplits = true
+			}
+		}
+		if err := validateSplits(splits, categoryAfter.Type, transaction.Amount); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: %w", id, err)
 		}
+	}
 
+	// ? If wallet ID is different, update wallet balance
+	bookedAfter := bookedBefore
+	if transaction.WalletID != transactionExist.WalletID.String() {
 		// *  Parse ID from JSON to valid UUID
 		WalletID, err := helper.ParseUUID(transaction.WalletID)
 		if err != nil {
 			return dto.TransactionsResponse{}, fmt.Errorf("invalid wallet id [id=%s]: %w", transaction.WalletID, err)
 		}
+
+		// *  A pending transaction may count in one wallet and not in the other
+		moved := transactionExist
+		moved.WalletID = WalletID
+		if bookedAfter, err = transaction_serv.ledger.isBooked(ctx, tx, moved); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: %w", id, err)
+		}
+
+		if bookedBefore {
+			// *  Check if wallet exist
+			oldWallet, err := transaction_serv.walletClient.GetWalletByID(ctx, transactionExist.WalletID.String())
+			if err != nil {
+				return dto.TransactionsResponse{}, fmt.Errorf("wallet not found [id=%s]: %w", transactionExist.WalletID.String(), err)
+			}
+
+			// *  Update wallet balance
+			var delta money.Amount
+			switch transactionExist.Category.Type {
+			case "expense":
+				delta = transactionExist.Amount
+			case "income":
+				delta = transactionExist.Amount.Neg()
+			default:
+				return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction type [type=%s]", transactionExist.Category.Type)
+			}
+
+			if err = transaction_serv.saga.UpdateWalletBalance(ctx, saga, oldWallet, delta); err != nil {
+				return dto.TransactionsResponse{}, fmt.Errorf("update old wallet balance: %w", err)
+			}
+		}
+
+		if bookedAfter {
+			// *  Check if new wallet exist
+			newWallet, err := transaction_serv.walletClient.GetWalletByID(ctx, transaction.WalletID)
+			if err != nil {
+				return dto.TransactionsResponse{}, fmt.Errorf("new wallet not found [id=%s]: %w", transaction.WalletID, err)
+			}
+
+			// *  Update wallet balance
+			var delta money.Amount
+			switch transactionExist.Category.Type {
+			case "expense":
+				delta = transaction.Amount.Neg()
+			case "income":
+				delta = transaction.Amount
+			default:
+				return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction type [type=%s]", transactionExist.Category.Type)
+			}
+
+			if err = transaction_serv.saga.UpdateWalletBalance(ctx, saga, newWallet, delta); err != nil {
+				return dto.TransactionsResponse{}, fmt.Errorf("update new wallet balance: %w", err)
+			}
+		}
+
 		transactionExist.WalletID = WalletID
 	}
 
 	// ? Update transaction fields
-	if transaction.Amount != transactionExist.Amount {
+	if transaction.Amount != transactionExist.Amount && bookedAfter {
 		// *  Update wallet balance
 		oldWallet, err := transaction_serv.walletClient.GetWalletByID(ctx, transactionExist.WalletID.String())
 		if err != nil {
@@ -514,24 +875,29 @@ func (transaction_serv *transactionsService) UpdateTransaction(ctx context.Conte
 		}
 
 		// *  Update wallet balance
+		var delta money.Amount
 		switch transactionExist.Category.Type {
 		case "expense":
-			oldWallet.Balance += transactionExist.Amount
-			oldWallet.Balance -= transaction.Amount
+			delta = transactionExist.Amount.Sub(transaction.Amount)
 		case "income":
-			oldWallet.Balance -= transactionExist.Amount
-			oldWallet.Balance += transaction.Amount
+			delta = transaction.Amount.Sub(transactionExist.Amount)
 		default:
 			return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction type [type=%s]", transactionExist.Category.Type)
 		}
 
-		if _, err = transaction_serv.walletClient.UpdateWallet(ctx, oldWallet); err != nil {
+		if err = transaction_serv.saga.UpdateWalletBalance(ctx, saga, oldWallet, delta); err != nil {
 			return dto.TransactionsResponse{}, fmt.Errorf("update wallet balance: %w", err)
 		}
+	}
 
-		// *  Update transaction amount
-		transactionExist.Amount = transaction.Amount
+	// *  Update transaction amount
+	transactionExist.Amount = transaction.Amount
+
+	// ? Keep the entered amount of a converted transaction until the amount is re-entered
+	if conversion.converted() || transaction.Amount != transactionBefore.Amount {
+		conversion.apply(&transactionExist)
 	}
+	transactionExist.Currency = conversion.Currency
 
 	// ? Update transaction date
 	if !transaction.Date.IsZero() && !utils.SameDate(transaction.Date, transactionExist.TransactionDate) {
@@ -549,6 +915,33 @@ func (transaction_serv *transactionsService) UpdateTransaction(ctx context.Conte
 		return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: update in db: %w", id, err)
 	}
 
+	if replaceSplits {
+		for i := range splits {
+			splits[i].TransactionID = transactionUpdated.ID
+		}
+		if transactionUpdated.Splits, err = transaction_serv.transactionRepo.ReplaceTransactionSplits(ctx, tx, transactionUpdated.ID.String(), splits); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: replace splits: %w", id, err)
+		}
+	}
+
+	if transaction.TagIDs != nil {
+		if err := transaction_serv.tagRepo.ReplaceTransactionTags(ctx, tx, transactionUpdated.ID, tagIDsOf(tags)); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: replace tags: %w", id, err)
+		}
+		transactionUpdated.Tags = tags
+	}
+
+	// ? Emit budget events if the change pushes spending across a budget threshold
+	transactionAfter := transactionUpdated
+	transactionAfter.Category = categoryAfter
+	if err := transaction_serv.budgets.TransactionChanged(ctx, tx, &transactionBefore, &transactionAfter); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: evaluate budgets: %w", id, err)
+	}
+
+	if err := transaction_serv.history.RecordUpdated(ctx, tx, transactionBefore, transactionAfter); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction [id=%s]: %w", id, err)
+	}
+
 	// ? If attachments exist, update attachments
 	if len(transaction.Attachments) > 0 {
 		for _, attachment := range transaction.Attachments {
@@ -612,15 +1005,29 @@ func (transaction_serv *transactionsService) UpdateTransaction(ctx context.Conte
 		return dto.TransactionsResponse{}, err
 	}
 
+	if err := transaction_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("update transaction: %w", err)
+	}
+
 	// ! Commit transaction if all operations are successful
 	if err = tx.Commit(); err != nil {
 		return dto.TransactionsResponse{}, fmt.Errorf("update transaction: commit: %w", err)
 	}
+	committed = true
 
 	return transactionResponse, nil
 }
 
 func (transaction_serv *transactionsService) DeleteTransaction(ctx context.Context, id string) (dto.TransactionsResponse, error) {
+	// Compensate wallet-service updates unless the local transaction commits
+	saga := transaction_serv.saga.NewSaga(data.SAGA_TYPE_TRANSACTION_DELETE)
+	committed := false
+	defer func() {
+		if !committed {
+			transaction_serv.saga.Compensate(ctx, saga, sagaReasonNotCommitted)
+		}
+	}()
+
 	tx, err := transaction_serv.txManager.Begin(ctx)
 	if err != nil {
 		return dto.TransactionsResponse{}, fmt.Errorf("delete transaction: begin transaction: %w", err)
@@ -633,32 +1040,36 @@ func (transaction_serv *transactionsService) DeleteTransaction(ctx context.Conte
 	if err != nil {
 		return dto.TransactionsResponse{}, fmt.Errorf("transaction not found [id=%s]: %w", id, err)
 	}
+	if transactionExist.Status == model.StatusReconciled {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction status: a reconciled transaction cannot be deleted [id=%s]", id)
+	}
+	if transactionExist.TransferID != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction: a transfer leg cannot be deleted alone, delete the transfer instead [id=%s, transfer_id=%s]", id, transactionExist.TransferID)
+	}
+	if transactionExist.DebtID != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction: a debt transaction cannot be deleted alone, delete the debt instead [id=%s, debt_id=%s]", id, transactionExist.DebtID)
+	}
+	if transactionExist.Scheduled {
+		return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction: a scheduled transaction is cancelled, not deleted [id=%s]", id)
+	}
 
-	// Get wallet to update balance
-	wallet, err := transaction_serv.walletClient.GetWalletByID(ctx, transactionExist.WalletID.String())
+	// Reverse the balance change of the transaction, if it made one
+	effect, err := transaction_serv.ledger.bookedEffect(ctx, tx, transactionExist)
 	if err != nil {
-		return dto.TransactionsResponse{}, fmt.Errorf("wallet not found [id=%s]: %w", transactionExist.WalletID.String(), err)
+		return dto.TransactionsResponse{}, err
 	}
 
-	// Update wallet balance
-	if transactionExist.Category.Type == "expense" {
-		wallet.Balance += transactionExist.Amount
-	} else if transactionExist.Category.Type == "income" {
-		wallet.Balance -= transactionExist.Amount
-	} else {
-		if transactionExist.Category.Name == "Cash Out" {
-			wallet.Balance += transactionExist.Amount
-		} else if transactionExist.Category.Name == "Cash In" {
-			wallet.Balance -= transactionExist.Amount
-		} else {
-			return dto.TransactionsResponse{}, fmt.Errorf("invalid transaction type [type=%s]", transactionExist.Category.Type)
+	if !effect.IsZero() {
+		// Get wallet to update balance
+		wallet, err := transaction_serv.walletClient.GetWalletByID(ctx, transactionExist.WalletID.String())
+		if err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("wallet not found [id=%s]: %w", transactionExist.WalletID.String(), err)
 		}
-	}
 
-	// Update wallet balance
-	_, err = transaction_serv.walletClient.UpdateWallet(ctx, wallet)
-	if err != nil {
-		return dto.TransactionsResponse{}, fmt.Errorf("update wallet balance: %w", err)
+		// Update wallet balance
+		if err := transaction_serv.saga.UpdateWalletBalance(ctx, saga, wallet, effect.Neg()); err != nil {
+			return dto.TransactionsResponse{}, fmt.Errorf("update wallet balance: %w", err)
+		}
 	}
 
 	// Delete transaction
@@ -667,6 +1078,10 @@ func (transaction_serv *transactionsService) DeleteTransaction(ctx context.Conte
 		return dto.TransactionsResponse{}, fmt.Errorf("delete transaction [id=%s]: delete from db: %w", id, err)
 	}
 
+	if err := transaction_serv.history.RecordDeleted(ctx, tx, transactionExist); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("delete transaction [id=%s]: %w", id, err)
+	}
+
 	transactionResponse := helper.ConvertToResponseType(transactionDeleted).(dto.TransactionsResponse)
 
 	payload, err := json.Marshal(transactionResponse)
@@ -686,10 +1101,92 @@ func (transaction_serv *transactionsService) DeleteTransaction(ctx context.Conte
 		return dto.TransactionsResponse{}, err
 	}
 
+	if err := transaction_serv.saga.Complete(ctx, tx, saga); err != nil {
+		return dto.TransactionsResponse{}, fmt.Errorf("delete transaction: %w", err)
+	}
+
 	// Commit transaksi jika semua sukses
 	if err := tx.Commit(); err != nil {
 		return dto.TransactionsResponse{}, fmt.Errorf("delete transaction: commit: %w", err)
 	}
+	committed = true
 
 	return transactionResponse, nil
 }
+
+// balanceEffect returns the change a transaction made to its wallet balance
+// when it was booked: negative for money going out.
+func balanceEffect(transaction model.Transactions) (money.Amount, error) {
+	switch {
+	case transaction.Category.Type == "expense", transaction.Category.Name == "Cash Out":
+		return transaction.Amount.Neg(), nil
+	case transaction.Category.Type == "income", transaction.Category.Name == "Cash In":
+		return transaction.Amount, nil
+	}
+	return money.Zero, fmt.Errorf("invalid transaction type [type=%s]", transaction.Category.Type)
+}
+
+// replayIdempotent loads the response the caller stored for key into out.
+// It reports false when no key was supplied or the caller has not used the
+// key yet, and fails with ErrIdempotencyKeyReused when the key was used for
+// a different request.
+func (transaction_serv *transactionsService) replayIdempotent(ctx context.Context, operation, key, requestHash string, out any) (bool, error) {
+	if key == "" {
+		return false, nil
+	}
+
+	record, err := transaction_serv.idempotencyRepo.GetByKey(ctx, nil, interceptor.UserIDFromContext(ctx), operation, key)
+	if err != nil {
+		return false, fmt.Errorf("get idempotency key [key=%s]: %w", key, err)
+	}
+	if record == nil {
+		return false, nil
+	}
+	// Keys stored before request hashes were recorded have none to compare
+	if record.RequestHash != "" && record.RequestHash != requestHash {
+		return false, fmt.Errorf("%w [key=%s]", ErrIdempotencyKeyReused, key)
+	}
+
+	if err := json.Unmarshal(record.Response, out); err != nil {
+		return false, fmt.Errorf("unmarshal idempotent response [key=%s]: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// saveIdempotent stores the response for the caller's key inside tx, so it is
+// only kept when the request itself commits. The unique (user, operation,
+// key) index makes a concurrent duplicate fail here and roll back.
+func (transaction_serv *transactionsService) saveIdempotent(ctx context.Context, tx repository.Transaction, operation, key, requestHash string, response any) error {
+	if key == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("marshal idempotent response [key=%s]: %w", key, err)
+	}
+
+	if err := transaction_serv.idempotencyRepo.Create(ctx, tx, &model.IdempotencyKey{
+		UserID:      interceptor.UserIDFromContext(ctx),
+		Key:         key,
+		Operation:   operation,
+		RequestHash: requestHash,
+		Response:    payload,
+	}); err != nil {
+		return fmt.Errorf("save idempotency key [key=%s]: %w", key, err)
+	}
+
+	return nil
+}
+
+// idempotencyRequestHash fingerprints a request as received, before the
+// service fills in any defaults.
+func idempotencyRequestHash(request any) string {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	splitFoodID     = uuid.MustParse("55555555-0000-0000-0000-000000000001")
+	splitCleaningID = uuid.MustParse("55555555-0000-0000-0000-000000000002")
+	splitPetID      = uuid.MustParse("55555555-0000-0000-0000-000000000003")
+	splitSalaryID   = uuid.MustParse("55555555-0000-0000-0000-000000000004")
+)
+
+func sampleSplitCategory(id uuid.UUID, name string, categoryType model.CategoryType) model.Categories {
+	return model.Categories{Base: model.Base{ID: id}, Name: name, Type: categoryType}
+}
+
+// sampleGroceryRequest is one 150,000 receipt covering food, cleaning supplies and pet care.
+func sampleGroceryRequest() dto.TransactionsRequest {
+	return dto.TransactionsRequest{
+		WalletID:    walletTestID.String(),
+		CategoryID:  catTestID.String(),
+		Amount:      money.New(150000),
+		Date:        txnFixTime,
+		Description: "Belanja bulanan",
+		Splits: []dto.TransactionSplitsRequest{
+			{CategoryID: splitFoodID.String(), Amount: money.MustParse("90000.1"), Note: "Sayur dan buah"},
+			{CategoryID: splitCleaningID.String(), Amount: money.MustParse("35000.2")},
+			{CategoryID: splitPetID.String(), Amount: money.MustParse("24999.7"), Note: "Makanan kucing"},
+		},
+	}
+}
+
+func (d *transactionTestDeps) expectSplitCategories() {
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, splitFoodID.String()).Return(sampleSplitCategory(splitFoodID, "Makanan", model.Expense), nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, splitCleaningID.String()).Return(sampleSplitCategory(splitCleaningID, "Kebersihan", model.Expense), nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, splitPetID.String()).Return(sampleSplitCategory(splitPetID, "Hewan Peliharaan", model.Expense), nil)
+}
+
+func sampleSplitTransactionModel() model.Transactions {
+	txn := sampleTransactionModel()
+	txn.Amount = money.New(150000)
+	txn.Splits = []model.TransactionSplits{
+		{Base: model.Base{ID: uuid.New()}, TransactionID: txnTestID, CategoryID: splitFoodID, Amount: money.New(100000), Category: sampleSplitCategory(splitFoodID, "Makanan", model.Expense)},
+		{Base: model.Base{ID: uuid.New()}, TransactionID: txnTestID, CategoryID: splitPetID, Amount: money.New(50000), Position: 1, Category: sampleSplitCategory(splitPetID, "Hewan Peliharaan", model.Expense)},
+	}
+	return txn
+}
+
+// =====================================================================
+// CreateTransaction (split)
+// =====================================================================
+
+func TestCreateTransaction_SplitSuccess(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	createdTxn := sampleTransactionModel()
+	createdTxn.Amount = money.New(150000)
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.expectSplitCategories()
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 200000), nil)
+	// The balance moves once, by the full receipt amount
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-150000), mock.Anything).Return(sampleWalletProto(walletTestID, 50000), nil).Once()
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).Return(createdTxn, nil)
+	d.transactionRepo.On("ReplaceTransactionSplits", mock.Anything, d.tx, txnTestID.String(), mock.MatchedBy(func(splits []model.TransactionSplits) bool {
+		return len(splits) == 3 && splits[0].TransactionID == txnTestID && splits[2].Position == 2 && splits[2].Note == "Makanan kucing"
+	})).Return([]model.TransactionSplits{
+		{CategoryID: splitFoodID, Amount: money.MustParse("90000.1"), Category: sampleSplitCategory(splitFoodID, "Makanan", model.Expense)},
+		{CategoryID: splitCleaningID, Amount: money.MustParse("35000.2"), Category: sampleSplitCategory(splitCleaningID, "Kebersihan", model.Expense)},
+		{CategoryID: splitPetID, Amount: money.MustParse("24999.7"), Category: sampleSplitCategory(splitPetID, "Hewan Peliharaan", model.Expense)},
+	}, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CreateTransaction(context.Background(), sampleGroceryRequest())
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Splits, 3)
+	assert.Equal(t, "Kebersihan", result.Splits[1].CategoryName)
+	assert.Equal(t, money.MustParse("24999.7"), result.Splits[2].Amount)
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_SplitDefaultsCategoryToFirstLine(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	req := sampleGroceryRequest()
+	req.CategoryID = ""
+	req.Amount = money.New(100000) // does not match the lines, so nothing is written
+
+	d.expectSplitCategories()
+
+	_, err := svc.CreateTransaction(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid split: lines sum to 150000.00 but amount is 100000.00")
+	d.categoryRepo.AssertCalled(t, "GetCategoryByID", mock.Anything, mock.Anything, splitFoodID.String())
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_SplitCategoryTypeMismatch(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	req := sampleGroceryRequest()
+	req.Splits[1].CategoryID = splitSalaryID.String()
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, splitFoodID.String()).Return(sampleSplitCategory(splitFoodID, "Makanan", model.Expense), nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, splitSalaryID.String()).Return(sampleSplitCategory(splitSalaryID, "Gaji", model.Income), nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, splitPetID.String()).Return(sampleSplitCategory(splitPetID, "Hewan Peliharaan", model.Expense), nil)
+
+	_, err := svc.CreateTransaction(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid split category type [line=2, type=income]")
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_SplitSingleLineRejected(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	req := sampleGroceryRequest()
+	req.Amount = money.MustParse("90000.1")
+	req.Splits = req.Splits[:1]
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, splitFoodID.String()).Return(sampleSplitCategory(splitFoodID, "Makanan", model.Expense), nil)
+
+	_, err := svc.CreateTransaction(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "at least 2 lines")
+	d.assertAll(t)
+}
+
+// =====================================================================
+// UpdateTransaction (split)
+// =====================================================================
+
+func TestUpdateTransaction_SplitAmountChangeWithoutLinesRejected(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	existing := sampleSplitTransactionModel()
+
+	req := dto.TransactionsRequest{
+		WalletID:   walletTestID.String(),
+		CategoryID: catTestID.String(),
+		Amount:     money.New(175000),
+	}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(existing, nil)
+	// rejected before the wallet balance is touched
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.UpdateTransaction(context.Background(), txnTestID.String(), req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid split: lines sum to 150000.00 but amount is 175000.00")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance")
+	d.assertAll(t)
+}
+
+func TestUpdateTransaction_SplitLinesReplaced(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	existing := sampleSplitTransactionModel()
+	req := sampleGroceryRequest()
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(existing, nil)
+	d.expectSplitCategories()
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.Anything).Return(existing, nil)
+	d.transactionRepo.On("ReplaceTransactionSplits", mock.Anything, d.tx, txnTestID.String(), mock.MatchedBy(func(splits []model.TransactionSplits) bool {
+		return len(splits) == 3
+	})).Return([]model.TransactionSplits{
+		{CategoryID: splitFoodID, Amount: money.MustParse("90000.1"), Category: sampleSplitCategory(splitFoodID, "Makanan", model.Expense)},
+		{CategoryID: splitCleaningID, Amount: money.MustParse("35000.2"), Category: sampleSplitCategory(splitCleaningID, "Kebersihan", model.Expense)},
+		{CategoryID: splitPetID, Amount: money.MustParse("24999.7"), Category: sampleSplitCategory(splitPetID, "Hewan Peliharaan", model.Expense)},
+	}, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.UpdateTransaction(context.Background(), txnTestID.String(), req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Splits, 3)
+	d.assertAll(t)
+}
+
+func TestUpdateTransaction_SplitLinesCleared(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	existing := sampleSplitTransactionModel()
+
+	req := dto.TransactionsRequest{
+		WalletID:   walletTestID.String(),
+		CategoryID: catTestID.String(),
+		Amount:     money.New(150000),
+		Splits:     []dto.TransactionSplitsRequest{},
+	}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(existing, nil)
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.Anything).Return(existing, nil)
+	d.transactionRepo.On("ReplaceTransactionSplits", mock.Anything, d.tx, txnTestID.String(), []model.TransactionSplits(nil)).Return([]model.TransactionSplits(nil), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.UpdateTransaction(context.Background(), txnTestID.String(), req)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Splits)
+	d.assertAll(t)
+}
+
+func TestUpdateTransaction_SplitLinesRescaled(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	existing := sampleSplitTransactionModel()
+	req := dto.TransactionsRequest{
+		WalletID:      walletTestID.String(),
+		CategoryID:    catTestID.String(),
+		Amount:        money.New(200000),
+		RescaleSplits: true,
+	}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(existing, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 500000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).Return(sampleWalletProto(walletTestID, 450000), nil).Once()
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.Anything).Return(existing, nil)
+	// 100,000 / 50,000 keep their 2:1 ratio of the new 200,000
+	d.transactionRepo.On("ReplaceTransactionSplits", mock.Anything, d.tx, txnTestID.String(), mock.MatchedBy(func(splits []model.TransactionSplits) bool {
+		return len(splits) == 2 && splits[0].Amount == money.MustParse("133333.33") && splits[1].Amount == money.MustParse("66666.67") &&
+			splits[0].CategoryID == splitFoodID && splits[1].Position == 1 && splits[0].ID == uuid.Nil
+	})).Return(existing.Splits, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.UpdateTransaction(context.Background(), txnTestID.String(), req)
+
+	assert.NoError(t, err)
+	d.assertAll(t)
+}
+
+func TestUpdateTransaction_SplitLinesDroppedOnCategoryChange(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	existing := sampleSplitTransactionModel()
+	req := dto.TransactionsRequest{
+		WalletID:      walletTestID.String(),
+		CategoryID:    splitPetID.String(),
+		Amount:        money.New(150000),
+		RescaleSplits: true,
+	}
+
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(existing, nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, splitPetID.String()).Return(sampleSplitCategory(splitPetID, "Hewan Peliharaan", model.Expense), nil)
+	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.Anything).Return(existing, nil)
+	d.transactionRepo.On("ReplaceTransactionSplits", mock.Anything, d.tx, txnTestID.String(), []model.TransactionSplits(nil)).Return([]model.TransactionSplits(nil), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.UpdateTransaction(context.Background(), txnTestID.String(), req)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Splits)
+	d.assertAll(t)
+}
+
+// =====================================================================
+// Budget contribution of split lines
+// =====================================================================
+
+func TestBudgetContribution_CountsMatchingSplitLines(t *testing.T) {
+	txn := sampleSplitTransactionModel()
+	budget := model.Budgets{CategoryID: splitPetID}
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, money.New(50000), budgetContribution(budget, &txn, from, from.AddDate(0, 1, 0)))
+	assert.ElementsMatch(t, []string{splitFoodID.String(), splitPetID.String()}, budgetCategoryIDs(&txn))
+}
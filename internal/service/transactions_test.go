@@ -10,6 +10,7 @@ import (
 	"refina-transaction/internal/service/mocks"
 	"refina-transaction/internal/types/dto"
 	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
 
 	wpb "github.com/MuhammadMiftaa/Refina-Protobuf/wallet"
 	"github.com/google/uuid"
@@ -27,20 +28,41 @@ type transactionTestDeps struct {
 	categoryRepo   *mocks.MockCategoriesRepository
 	attachmentRepo *mocks.MockAttachmentsRepository
 	outboxRepo     *mocks.MockOutboxRepository
+	sagaRepo       *mocks.MockSagaLogRepository
+	idempotencyRepo *mocks.MockIdempotencyRepository
+	budgetRepo     *mocks.MockBudgetsRepository
+	currencyRepo   *mocks.MockCurrenciesRepository
+	tagRepo        *mocks.MockTagsRepository
+	historyRepo    *mocks.MockTransactionHistoryRepository
+	reconciliationRepo *mocks.MockReconciliationsRepository
 	walletClient   *mocks.MockWalletClient
 	tx             *mocks.MockTransaction
 }
 
 func newTransactionTestDeps() *transactionTestDeps {
-	return &transactionTestDeps{
+	d := &transactionTestDeps{
 		txManager:      new(mocks.MockTxManager),
 		transactionRepo: new(mocks.MockTransactionsRepository),
 		categoryRepo:   new(mocks.MockCategoriesRepository),
 		attachmentRepo: new(mocks.MockAttachmentsRepository),
 		outboxRepo:     new(mocks.MockOutboxRepository),
+		sagaRepo:       new(mocks.MockSagaLogRepository),
+		idempotencyRepo: new(mocks.MockIdempotencyRepository),
+		budgetRepo:     new(mocks.MockBudgetsRepository),
+		currencyRepo:   new(mocks.MockCurrenciesRepository),
+		tagRepo:        new(mocks.MockTagsRepository),
+		historyRepo:    new(mocks.MockTransactionHistoryRepository),
+		reconciliationRepo: new(mocks.MockReconciliationsRepository),
 		walletClient:   new(mocks.MockWalletClient),
 		tx:             new(mocks.MockTransaction),
 	}
+	// No budgets by default; budget events are covered in budgets_test.go
+	d.budgetRepo.On("GetBudgetsByCategoryIDs", mock.Anything, mock.Anything, mock.Anything).Return([]model.Budgets{}, nil).Maybe()
+	// Every wallet in the default currency; conversions are covered in currencies_test.go
+	d.currencyRepo.On("GetWalletCurrency", mock.Anything, mock.Anything, mock.Anything).Return("", nil).Maybe()
+	// History entries are covered in transactionHistory_test.go
+	d.historyRepo.On("CreateHistory", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return d
 }
 
 func (d *transactionTestDeps) service() TransactionsService {
@@ -53,6 +75,13 @@ func (d *transactionTestDeps) service() TransactionsService {
 		d.categoryRepo,
 		d.attachmentRepo,
 		d.outboxRepo,
+		d.sagaRepo,
+		d.idempotencyRepo,
+		d.budgetRepo,
+		d.currencyRepo,
+		d.tagRepo,
+		d.historyRepo,
+		d.reconciliationRepo,
 		nil, // minio — nil is acceptable for non-upload tests
 	)
 }
@@ -64,10 +93,31 @@ func (d *transactionTestDeps) assertAll(t *testing.T) {
 	d.categoryRepo.AssertExpectations(t)
 	d.attachmentRepo.AssertExpectations(t)
 	d.outboxRepo.AssertExpectations(t)
+	d.sagaRepo.AssertExpectations(t)
+	d.idempotencyRepo.AssertExpectations(t)
+	d.budgetRepo.AssertExpectations(t)
+	d.currencyRepo.AssertExpectations(t)
+	d.tagRepo.AssertExpectations(t)
+	d.historyRepo.AssertExpectations(t)
+	d.reconciliationRepo.AssertExpectations(t)
 	d.walletClient.AssertExpectations(t)
 	d.tx.AssertExpectations(t)
 }
 
+// expectSagaLog stubs saga log persistence for flows that reach wallet-service.
+// status is the terminal saga status the flow is expected to record.
+func (d *transactionTestDeps) expectSagaLog(status model.SagaStatus) {
+	d.sagaRepo.On("CreateSaga", mock.Anything, nil, mock.Anything).Return(nil).Once()
+	d.sagaRepo.On("CreateStep", mock.Anything, nil, mock.Anything).Return(nil)
+	d.sagaRepo.On("UpdateStepStatus", mock.Anything, nil, mock.Anything, mock.Anything).Return(nil)
+	if status == model.SagaCompleted {
+		d.sagaRepo.On("CompleteSaga", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+		return
+	}
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, mock.Anything, mock.Anything).Return(true, nil).Once()
+	d.sagaRepo.On("UpdateSagaStatus", mock.Anything, mock.Anything, mock.Anything, status, mock.Anything).Return(nil).Once()
+}
+
 // ─────────────────────────────────────────────
 // Fixed UUIDs & Timestamps
 // ─────────────────────────────────────────────
@@ -123,7 +173,7 @@ func sampleTransactionModel() model.Transactions {
 		Base:            model.Base{ID: txnTestID, CreatedAt: txnFixTime, UpdatedAt: txnFixTime},
 		WalletID:        walletTestID,
 		CategoryID:      catTestID,
-		Amount:          50000,
+		Amount:          money.New(50000),
 		TransactionDate: txnFixTime,
 		Description:     "Makan siang",
 		Category:        sampleExpenseCategory(),
@@ -143,7 +193,7 @@ func sampleTransactionRequest() dto.TransactionsRequest {
 	return dto.TransactionsRequest{
 		WalletID:    walletTestID.String(),
 		CategoryID:  catTestID.String(),
-		Amount:      50000,
+		Amount:      money.New(50000),
 		Date:        txnFixTime,
 		Description: "Makan siang",
 		Attachments: []dto.UpdateAttachmentsRequest{},
@@ -167,7 +217,7 @@ func TestGetAllTransactions_Success(t *testing.T) {
 	assert.Len(t, result, 1)
 	assert.Equal(t, txnTestID.String(), result[0].ID)
 	assert.Equal(t, walletTestID.String(), result[0].WalletID)
-	assert.Equal(t, float64(50000), result[0].Amount)
+	assert.Equal(t, money.New(50000), result[0].Amount)
 	d.assertAll(t)
 }
 
@@ -7,6 +7,7 @@ import (
 
 	"refina-transaction/internal/types/dto"
 	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -20,10 +21,11 @@ import (
 func TestUpdateTransaction_SuccessAmountChange(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
 
 	existing := sampleTransactionModel() // amount=50000, expense
 	updated := existing
-	updated.Amount = 75000
+	updated.Amount = money.New(75000)
 
 	wallet := sampleWalletProto(walletTestID, 150000)
 	updatedWallet := sampleWalletProto(walletTestID, 125000)
@@ -31,7 +33,7 @@ func TestUpdateTransaction_SuccessAmountChange(t *testing.T) {
 	req := dto.TransactionsRequest{
 		WalletID:    walletTestID.String(),
 		CategoryID:  catTestID.String(),
-		Amount:      75000,
+		Amount:      money.New(75000),
 		Date:        txnFixTime,
 		Description: "Makan siang updated",
 		Attachments: []dto.UpdateAttachmentsRequest{},
@@ -39,9 +41,9 @@ func TestUpdateTransaction_SuccessAmountChange(t *testing.T) {
 
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(existing, nil)
-	// amount changed — fetch wallet and update balance
+	// amount changed — adjust wallet by the difference only
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(updatedWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-25000), mock.Anything).Return(updatedWallet, nil)
 	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.Anything).Return(updated, nil)
 	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
 	d.tx.On("Commit").Return(nil)
@@ -65,7 +67,7 @@ func TestUpdateTransaction_SuccessSameAmountAndWallet(t *testing.T) {
 	req := dto.TransactionsRequest{
 		WalletID:    walletTestID.String(),
 		CategoryID:  catTestID.String(),
-		Amount:      50000, // same amount
+		Amount:      money.New(50000), // same amount
 		Date:        txnFixTime,
 		Description: "Updated description",
 		Attachments: []dto.UpdateAttachmentsRequest{},
@@ -90,6 +92,7 @@ func TestUpdateTransaction_SuccessSameAmountAndWallet(t *testing.T) {
 func TestUpdateTransaction_SuccessWalletChange(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
 
 	newWalletID := uuid.MustParse("77777777-7777-7777-7777-777777777777")
 	existing := sampleTransactionModel() // walletTestID, expense, 50000
@@ -104,7 +107,7 @@ func TestUpdateTransaction_SuccessWalletChange(t *testing.T) {
 	req := dto.TransactionsRequest{
 		WalletID:    newWalletID.String(),
 		CategoryID:  catTestID.String(),
-		Amount:      50000,
+		Amount:      money.New(50000),
 		Date:        txnFixTime,
 		Description: "Moved to new wallet",
 		Attachments: []dto.UpdateAttachmentsRequest{},
@@ -114,13 +117,9 @@ func TestUpdateTransaction_SuccessWalletChange(t *testing.T) {
 	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(existing, nil)
 	// wallet changed — restore old wallet, deduct new wallet
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(oldWallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.MatchedBy(func(w interface{ GetId() string }) bool {
-		return w.GetId() == walletTestID.String()
-	})).Return(updatedOldWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(50000), mock.Anything).Return(updatedOldWallet, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, newWalletID.String()).Return(newWallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.MatchedBy(func(w interface{ GetId() string }) bool {
-		return w.GetId() == newWalletID.String()
-	})).Return(updatedNewWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, newWalletID.String(), money.New(-50000), mock.Anything).Return(updatedNewWallet, nil)
 	d.transactionRepo.On("UpdateTransaction", mock.Anything, d.tx, mock.Anything).Return(updated, nil)
 	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
 	d.tx.On("Commit").Return(nil)
@@ -146,7 +145,7 @@ func TestUpdateTransaction_SuccessCategoryChange(t *testing.T) {
 	req := dto.TransactionsRequest{
 		WalletID:    walletTestID.String(),
 		CategoryID:  newCatID.String(),
-		Amount:      50000,
+		Amount:      money.New(50000),
 		Date:        txnFixTime,
 		Description: existing.Description,
 		Attachments: []dto.UpdateAttachmentsRequest{},
@@ -193,7 +192,7 @@ func TestUpdateTransaction_InvalidNewCategoryID(t *testing.T) {
 	req := dto.TransactionsRequest{
 		WalletID:    walletTestID.String(),
 		CategoryID:  "not-a-uuid",
-		Amount:      50000,
+		Amount:      money.New(50000),
 		Date:        txnFixTime,
 		Attachments: []dto.UpdateAttachmentsRequest{},
 	}
@@ -232,7 +231,7 @@ func TestUpdateTransaction_WithAttachmentDelete(t *testing.T) {
 	req := dto.TransactionsRequest{
 		WalletID:    walletTestID.String(),
 		CategoryID:  catTestID.String(),
-		Amount:      50000,
+		Amount:      money.New(50000),
 		Date:        txnFixTime,
 		Description: existing.Description,
 		Attachments: []dto.UpdateAttachmentsRequest{
@@ -266,7 +265,7 @@ func TestUpdateTransaction_WithAttachmentDeleteNotFound(t *testing.T) {
 	req := dto.TransactionsRequest{
 		WalletID:    walletTestID.String(),
 		CategoryID:  catTestID.String(),
-		Amount:      50000,
+		Amount:      money.New(50000),
 		Date:        txnFixTime,
 		Description: existing.Description,
 		Attachments: []dto.UpdateAttachmentsRequest{
@@ -299,7 +298,7 @@ func TestUpdateTransaction_WithAttachmentInvalidStatus(t *testing.T) {
 	req := dto.TransactionsRequest{
 		WalletID:    walletTestID.String(),
 		CategoryID:  catTestID.String(),
-		Amount:      50000,
+		Amount:      money.New(50000),
 		Date:        txnFixTime,
 		Description: existing.Description,
 		Attachments: []dto.UpdateAttachmentsRequest{
@@ -330,7 +329,7 @@ func TestUpdateTransaction_OutboxError(t *testing.T) {
 	req := dto.TransactionsRequest{
 		WalletID:    walletTestID.String(),
 		CategoryID:  catTestID.String(),
-		Amount:      50000,
+		Amount:      money.New(50000),
 		Date:        txnFixTime,
 		Description: "No change",
 		Attachments: []dto.UpdateAttachmentsRequest{},
@@ -359,7 +358,7 @@ func TestUpdateTransaction_CommitError(t *testing.T) {
 	req := dto.TransactionsRequest{
 		WalletID:    walletTestID.String(),
 		CategoryID:  catTestID.String(),
-		Amount:      50000,
+		Amount:      money.New(50000),
 		Date:        txnFixTime,
 		Description: "No change",
 		Attachments: []dto.UpdateAttachmentsRequest{},
@@ -2,11 +2,16 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
+	"refina-transaction/interface/grpc/interceptor"
 	"refina-transaction/internal/types/dto"
 	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -20,6 +25,7 @@ import (
 func TestCreateTransaction_SuccessExpense(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
 
 	req := sampleTransactionRequest() // expense, amount=50000
 	cat := sampleExpenseCategory()
@@ -30,7 +36,7 @@ func TestCreateTransaction_SuccessExpense(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(cat, nil)
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(updatedWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).Return(updatedWallet, nil)
 	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).Return(createdTxn, nil)
 	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
 	d.tx.On("Commit").Return(nil)
@@ -41,28 +47,29 @@ func TestCreateTransaction_SuccessExpense(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, txnTestID.String(), result.ID)
 	assert.Equal(t, walletTestID.String(), result.WalletID)
-	assert.Equal(t, float64(50000), result.Amount)
+	assert.Equal(t, money.New(50000), result.Amount)
 	d.assertAll(t)
 }
 
 func TestCreateTransaction_SuccessIncome(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
 
 	req := sampleTransactionRequest()
-	req.Amount = 5000000
+	req.Amount = money.New(5000000)
 
 	cat := sampleIncomeCategory()
 	wallet := sampleWalletProto(walletTestID, 100000)
 	updatedWallet := sampleWalletProto(walletTestID, 5100000)
 	createdTxn := sampleTransactionModel()
-	createdTxn.Amount = 5000000
+	createdTxn.Amount = money.New(5000000)
 	createdTxn.Category = cat
 
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(cat, nil)
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(updatedWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(updatedWallet, nil)
 	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).Return(createdTxn, nil)
 	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
 	d.tx.On("Commit").Return(nil)
@@ -98,7 +105,7 @@ func TestCreateTransaction_IsWalletNotCreated_SkipsWalletCheck(t *testing.T) {
 	assert.Equal(t, txnTestID.String(), result.ID)
 	// wallet client should NOT be called
 	d.walletClient.AssertNotCalled(t, "GetWalletByID")
-	d.walletClient.AssertNotCalled(t, "UpdateWallet")
+	d.walletClient.AssertNotCalled(t, "AdjustBalance")
 	d.assertAll(t)
 }
 
@@ -225,6 +232,7 @@ func TestCreateTransaction_InvalidCategoryType(t *testing.T) {
 func TestCreateTransaction_UpdateWalletError(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompensated)
 
 	req := sampleTransactionRequest()
 	cat := sampleExpenseCategory()
@@ -233,7 +241,9 @@ func TestCreateTransaction_UpdateWalletError(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(cat, nil)
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(nil, errors.New("grpc error"))
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("grpc error")).Once()
+	// compensation replays the pending step by its key, then reverts it
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(wallet, nil).Times(2)
 	d.tx.On("Rollback").Return(nil)
 
 	result, err := svc.CreateTransaction(context.Background(), req)
@@ -265,6 +275,7 @@ func TestCreateTransaction_BeginTxError(t *testing.T) {
 func TestCreateTransaction_InsertDBError(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompensated)
 
 	req := sampleTransactionRequest()
 	cat := sampleExpenseCategory()
@@ -274,7 +285,7 @@ func TestCreateTransaction_InsertDBError(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(cat, nil)
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(updatedWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(updatedWallet, nil)
 	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).
 		Return(model.Transactions{}, errors.New("db insert error"))
 	d.tx.On("Rollback").Return(nil)
@@ -284,12 +295,15 @@ func TestCreateTransaction_InsertDBError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "insert to db")
 	assert.Empty(t, result.ID)
+	// wallet balance is reverted once the insert fails
+	d.walletClient.AssertNumberOfCalls(t, "AdjustBalance", 2)
 	d.assertAll(t)
 }
 
 func TestCreateTransaction_OutboxCreateError(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompensated)
 
 	req := sampleTransactionRequest()
 	cat := sampleExpenseCategory()
@@ -300,7 +314,7 @@ func TestCreateTransaction_OutboxCreateError(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(cat, nil)
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(updatedWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(updatedWallet, nil)
 	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).Return(createdTxn, nil)
 	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(errors.New("outbox error"))
 	d.tx.On("Rollback").Return(nil)
@@ -315,6 +329,9 @@ func TestCreateTransaction_OutboxCreateError(t *testing.T) {
 func TestCreateTransaction_CommitError(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompensated)
+	// completion is written inside the rolled-back tx, so wallet updates are still reverted
+	d.sagaRepo.On("CompleteSaga", mock.Anything, d.tx, mock.Anything).Return(true, nil).Once()
 
 	req := sampleTransactionRequest()
 	cat := sampleExpenseCategory()
@@ -325,7 +342,7 @@ func TestCreateTransaction_CommitError(t *testing.T) {
 	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(cat, nil)
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(updatedWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(updatedWallet, nil)
 	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).Return(createdTxn, nil)
 	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
 	d.tx.On("Commit").Return(errors.New("commit error"))
@@ -339,6 +356,170 @@ func TestCreateTransaction_CommitError(t *testing.T) {
 	d.assertAll(t)
 }
 
+func TestCreateTransaction_SagaClaimedBeforeCommit(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	// The request ran past SAGA_RESUME_AFTER and the resumer claimed its saga
+	// and reverted the debit, so the ledger row must not be committed
+	d.sagaRepo.On("CreateSaga", mock.Anything, nil, mock.Anything).Return(nil).Once()
+	d.sagaRepo.On("CreateStep", mock.Anything, nil, mock.Anything).Return(nil)
+	d.sagaRepo.On("UpdateStepStatus", mock.Anything, nil, mock.Anything, mock.Anything).Return(nil)
+	d.sagaRepo.On("CompleteSaga", mock.Anything, d.tx, mock.Anything).Return(false, nil).Once()
+	d.sagaRepo.On("ClaimSaga", mock.Anything, nil, mock.Anything, mock.Anything).Return(false, nil).Once()
+
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(sampleExpenseCategory(), nil)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(sampleWalletProto(walletTestID, 200000), nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).
+		Return(sampleWalletProto(walletTestID, 150000), nil).Once()
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).Return(sampleTransactionModel(), nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CreateTransaction(context.Background(), sampleTransactionRequest())
+
+	assert.ErrorIs(t, err, ErrSagaClaimed)
+	assert.Empty(t, result.ID)
+	d.tx.AssertNotCalled(t, "Commit")
+	// the claimant reverts the debit; the request does not revert it a second time
+	d.walletClient.AssertNumberOfCalls(t, "AdjustBalance", 1)
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_IdempotentReplay(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	original := dto.TransactionsResponse{ID: txnTestID.String(), WalletID: walletTestID.String(), Amount: money.New(50000)}
+	payload, _ := json.Marshal(original)
+	ctx := helper.WithIdempotencyKey(context.Background(), "req-1")
+
+	d.idempotencyRepo.On("GetByKey", mock.Anything, nil, "", data.IDEMPOTENCY_OPERATION_TRANSACTION_CREATE, "req-1").
+		Return(&model.IdempotencyKey{Key: "req-1", Response: payload}, nil)
+
+	result, err := svc.CreateTransaction(ctx, sampleTransactionRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, original, result)
+	// nothing is created or debited again
+	d.txManager.AssertNotCalled(t, "Begin", mock.Anything)
+	d.walletClient.AssertNotCalled(t, "AdjustBalance")
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_IdempotentFirstRequestStoresResponse(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
+
+	req := sampleTransactionRequest()
+	cat := sampleExpenseCategory()
+	wallet := sampleWalletProto(walletTestID, 200000)
+	createdTxn := sampleTransactionModel()
+	ctx := helper.WithIdempotencyKey(context.Background(), "req-1")
+
+	d.idempotencyRepo.On("GetByKey", mock.Anything, nil, "", data.IDEMPOTENCY_OPERATION_TRANSACTION_CREATE, "req-1").Return(nil, nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, mock.Anything, catTestID.String()).Return(cat, nil)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-50000), mock.Anything).Return(wallet, nil)
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).Return(createdTxn, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
+	d.idempotencyRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(r *model.IdempotencyKey) bool {
+		var stored dto.TransactionsResponse
+		return r.Key == "req-1" &&
+			r.RequestHash == idempotencyRequestHash(sampleTransactionRequest()) &&
+			r.Operation == data.IDEMPOTENCY_OPERATION_TRANSACTION_CREATE &&
+			json.Unmarshal(r.Response, &stored) == nil &&
+			stored.ID == txnTestID.String()
+	})).Return(nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CreateTransaction(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, txnTestID.String(), result.ID)
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_IdempotencyLookupError(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	ctx := helper.WithIdempotencyKey(context.Background(), "req-1")
+	d.idempotencyRepo.On("GetByKey", mock.Anything, nil, "", data.IDEMPOTENCY_OPERATION_TRANSACTION_CREATE, "req-1").
+		Return(nil, errors.New("db error"))
+
+	result, err := svc.CreateTransaction(ctx, sampleTransactionRequest())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "get idempotency key")
+	assert.Empty(t, result.ID)
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_IdempotencyKeyScopedToUser(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	original := dto.TransactionsResponse{ID: txnTestID.String(), WalletID: walletTestID.String(), Amount: money.New(50000)}
+	payload, _ := json.Marshal(original)
+	ctx := interceptor.WithUserMetadata(context.Background(), interceptor.UserMetadata{UserID: "user-1"})
+	ctx = helper.WithIdempotencyKey(ctx, "req-1")
+
+	// the lookup only sees keys stored by the calling user
+	d.idempotencyRepo.On("GetByKey", mock.Anything, nil, "user-1", data.IDEMPOTENCY_OPERATION_TRANSACTION_CREATE, "req-1").
+		Return(&model.IdempotencyKey{UserID: "user-1", Key: "req-1", Response: payload}, nil)
+
+	result, err := svc.CreateTransaction(ctx, sampleTransactionRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, original, result)
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_IdempotencyKeyReusedWithDifferentBody(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	original := sampleTransactionRequest()
+	payload, _ := json.Marshal(dto.TransactionsResponse{ID: txnTestID.String()})
+	ctx := helper.WithIdempotencyKey(context.Background(), "req-1")
+
+	d.idempotencyRepo.On("GetByKey", mock.Anything, nil, "", data.IDEMPOTENCY_OPERATION_TRANSACTION_CREATE, "req-1").
+		Return(&model.IdempotencyKey{Key: "req-1", RequestHash: idempotencyRequestHash(original), Response: payload}, nil)
+
+	changed := sampleTransactionRequest()
+	changed.Amount = money.New(75000)
+	result, err := svc.CreateTransaction(ctx, changed)
+
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+	assert.Empty(t, result.ID)
+	d.txManager.AssertNotCalled(t, "Begin", mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCreateTransaction_IdempotencyKeySameBodyReplays(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	req := sampleTransactionRequest()
+	original := dto.TransactionsResponse{ID: txnTestID.String(), Amount: money.New(50000)}
+	payload, _ := json.Marshal(original)
+	ctx := helper.WithIdempotencyKey(context.Background(), "req-1")
+
+	d.idempotencyRepo.On("GetByKey", mock.Anything, nil, "", data.IDEMPOTENCY_OPERATION_TRANSACTION_CREATE, "req-1").
+		Return(&model.IdempotencyKey{Key: "req-1", RequestHash: idempotencyRequestHash(req), Response: payload}, nil)
+
+	result, err := svc.CreateTransaction(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, original, result)
+	d.assertAll(t)
+}
+
 // =====================================================================
 // FundTransfer
 // =====================================================================
@@ -346,14 +527,15 @@ func TestCreateTransaction_CommitError(t *testing.T) {
 func TestFundTransfer_Success(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
 
 	req := dto.FundTransferRequest{
 		CashInCategoryID:  cashInCatID.String(),
 		CashOutCategoryID: cashOutCatID.String(),
 		FromWalletID:      walletTestID.String(),
 		ToWalletID:        wallet2ID.String(),
-		Amount:            100000,
-		AdminFee:          2000,
+		Amount:            money.New(100000),
+		AdminFee:          money.New(2000),
 		Date:              txnFixTime,
 		Description:       "Transfer dana",
 	}
@@ -365,36 +547,54 @@ func TestFundTransfer_Success(t *testing.T) {
 
 	cashOutTxn := model.Transactions{
 		Base:     model.Base{ID: uuid.MustParse("55555555-5555-5555-5555-555555555555")},
-		WalletID: walletTestID, CategoryID: cashOutCatID, Amount: 102000,
+		WalletID: walletTestID, CategoryID: cashOutCatID, Amount: money.New(100000),
 	}
 	cashInTxn := model.Transactions{
 		Base:     model.Base{ID: uuid.MustParse("66666666-6666-6666-6666-666666666666")},
-		WalletID: wallet2ID, CategoryID: cashInCatID, Amount: 100000,
+		WalletID: wallet2ID, CategoryID: cashInCatID, Amount: money.New(100000),
+	}
+	feeTxn := model.Transactions{
+		Base:     model.Base{ID: uuid.MustParse("88888888-8888-8888-8888-888888888888")},
+		WalletID: walletTestID, CategoryID: sampleAdminFeeCategory().ID, Amount: money.New(2000),
 	}
 
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(fromWallet, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, wallet2ID.String()).Return(toWallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(fromWallet, nil).Once()
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(toWallet, nil).Once()
+	d.walletClient.On("AdjustBalance", mock.Anything, walletTestID.String(), money.New(-102000), mock.Anything).Return(fromWallet, nil).Once()
+	d.walletClient.On("AdjustBalance", mock.Anything, wallet2ID.String(), money.New(100000), mock.Anything).Return(toWallet, nil).Once()
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, data.CATEGORY_ID_ADMIN_FEE).Return(sampleAdminFeeCategory(), nil)
+	// The cash-out leg equals the cash-in leg; the fee is an expense of its own
 	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(t model.Transactions) bool {
-		return t.WalletID == walletTestID // cash out
+		return t.CategoryID == cashOutCatID && t.Amount == money.New(100000) && t.TransferID != nil
 	})).Return(cashOutTxn, nil)
 	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(t model.Transactions) bool {
-		return t.WalletID == wallet2ID // cash in
+		return t.CategoryID == cashInCatID && t.Amount == money.New(100000) && t.TransferID != nil
 	})).Return(cashInTxn, nil)
-	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil).Times(2)
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(t model.Transactions) bool {
+		return t.CategoryID == feeTxn.CategoryID && t.WalletID == walletTestID && t.Amount == money.New(2000) && t.TransferID != nil
+	})).Return(feeTxn, nil)
+	d.transactionRepo.On("CreateTransfer", mock.Anything, d.tx, mock.MatchedBy(func(tr model.Transfers) bool {
+		return tr.CashOutTransactionID == cashOutTxn.ID && tr.CashInTransactionID == cashInTxn.ID &&
+			tr.FeeTransactionID != nil && *tr.FeeTransactionID == feeTxn.ID &&
+			tr.Amount == money.New(100000) && tr.AdminFee == money.New(2000)
+	})).Return(model.Transfers{}, nil)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_CREATED
+	})).Return(nil).Times(3)
 	d.tx.On("Commit").Return(nil)
 	d.tx.On("Rollback").Return(nil)
 
 	result, err := svc.FundTransfer(context.Background(), req)
 
 	assert.NoError(t, err)
+	assert.NotEmpty(t, result.TransferID)
+	assert.Equal(t, feeTxn.ID.String(), result.FeeTransactionID)
 	assert.NotEmpty(t, result.CashOutTransactionID)
 	assert.NotEmpty(t, result.CashInTransactionID)
 	assert.Equal(t, walletTestID.String(), result.FromWalletID)
 	assert.Equal(t, wallet2ID.String(), result.ToWalletID)
-	assert.Equal(t, float64(100000), result.Amount)
+	assert.Equal(t, money.New(100000), result.Amount)
 	d.assertAll(t)
 }
 
@@ -405,7 +605,7 @@ func TestFundTransfer_BeginTxError(t *testing.T) {
 	req := dto.FundTransferRequest{
 		FromWalletID: walletTestID.String(),
 		ToWalletID:   wallet2ID.String(),
-		Amount:       100000,
+		Amount:       money.New(100000),
 	}
 	d.txManager.On("Begin", mock.Anything).Return(nil, errors.New("begin error"))
 
@@ -424,7 +624,7 @@ func TestFundTransfer_FromWalletNotFound(t *testing.T) {
 	req := dto.FundTransferRequest{
 		FromWalletID: walletTestID.String(),
 		ToWalletID:   wallet2ID.String(),
-		Amount:       100000,
+		Amount:       money.New(100000),
 	}
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).
@@ -446,7 +646,7 @@ func TestFundTransfer_ToWalletNotFound(t *testing.T) {
 	req := dto.FundTransferRequest{
 		FromWalletID: walletTestID.String(),
 		ToWalletID:   wallet2ID.String(),
-		Amount:       100000,
+		Amount:       money.New(100000),
 	}
 	fromWallet := sampleWalletProto(walletTestID, 500000)
 
@@ -471,8 +671,8 @@ func TestFundTransfer_InsufficientBalance(t *testing.T) {
 	req := dto.FundTransferRequest{
 		FromWalletID: walletTestID.String(),
 		ToWalletID:   wallet2ID.String(),
-		Amount:       900000,
-		AdminFee:     2000,
+		Amount:       money.New(900000),
+		AdminFee:     money.New(2000),
 	}
 	fromWallet := sampleWalletProto(walletTestID, 100000) // less than amount+fee
 	toWallet := sampleWalletProto(wallet2ID, 50000)
@@ -498,7 +698,7 @@ func TestFundTransfer_SameWallet(t *testing.T) {
 	req := dto.FundTransferRequest{
 		FromWalletID: walletTestID.String(),
 		ToWalletID:   walletTestID.String(), // same wallet
-		Amount:       100000,
+		Amount:       money.New(100000),
 	}
 	wallet := sampleWalletProto(walletTestID, 500000)
 
@@ -523,7 +723,7 @@ func TestFundTransfer_InvalidFromCategoryID(t *testing.T) {
 		CashInCategoryID:  cashInCatID.String(),
 		FromWalletID:      walletTestID.String(),
 		ToWalletID:        wallet2ID.String(),
-		Amount:            100000,
+		Amount:            money.New(100000),
 	}
 	fromWallet := sampleWalletProto(walletTestID, 500000)
 	toWallet := sampleWalletProto(wallet2ID, 50000)
@@ -532,8 +732,8 @@ func TestFundTransfer_InvalidFromCategoryID(t *testing.T) {
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(fromWallet, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, wallet2ID.String()).Return(toWallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(fromWallet, nil).Maybe()
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(toWallet, nil).Maybe()
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fromWallet, nil).Maybe()
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(toWallet, nil).Maybe()
 	d.tx.On("Rollback").Return(nil)
 
 	result, err := svc.FundTransfer(context.Background(), req)
@@ -544,6 +744,37 @@ func TestFundTransfer_InvalidFromCategoryID(t *testing.T) {
 	d.assertAll(t)
 }
 
+func TestFundTransfer_IdempotentReplay(t *testing.T) {
+	d := newTransactionTestDeps()
+	svc := d.service()
+
+	original := dto.FundTransferResponse{
+		CashOutTransactionID: "55555555-5555-5555-5555-555555555555",
+		CashInTransactionID:  "66666666-6666-6666-6666-666666666666",
+		FromWalletID:         walletTestID.String(),
+		ToWalletID:           wallet2ID.String(),
+		Amount:               money.New(100000),
+		Date:                 txnFixTime,
+	}
+	payload, _ := json.Marshal(original)
+	ctx := helper.WithIdempotencyKey(context.Background(), "transfer-1")
+
+	d.idempotencyRepo.On("GetByKey", mock.Anything, nil, "", data.IDEMPOTENCY_OPERATION_FUND_TRANSFER, "transfer-1").
+		Return(&model.IdempotencyKey{Key: "transfer-1", Response: payload}, nil)
+
+	result, err := svc.FundTransfer(ctx, dto.FundTransferRequest{
+		FromWalletID: walletTestID.String(),
+		ToWalletID:   wallet2ID.String(),
+		Amount:       money.New(100000),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, original.CashOutTransactionID, result.CashOutTransactionID)
+	assert.Equal(t, original.CashInTransactionID, result.CashInTransactionID)
+	d.txManager.AssertNotCalled(t, "Begin", mock.Anything)
+	d.assertAll(t)
+}
+
 // =====================================================================
 // DeleteTransaction
 // =====================================================================
@@ -551,6 +782,7 @@ func TestFundTransfer_InvalidFromCategoryID(t *testing.T) {
 func TestDeleteTransaction_SuccessExpense(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
 
 	txn := sampleTransactionModel() // expense type
 	wallet := sampleWalletProto(walletTestID, 50000)
@@ -559,7 +791,7 @@ func TestDeleteTransaction_SuccessExpense(t *testing.T) {
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(txn, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(updatedWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(updatedWallet, nil)
 	d.transactionRepo.On("DeleteTransaction", mock.Anything, d.tx, txn).Return(txn, nil)
 	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
 	d.tx.On("Commit").Return(nil)
@@ -575,6 +807,7 @@ func TestDeleteTransaction_SuccessExpense(t *testing.T) {
 func TestDeleteTransaction_SuccessIncome(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
 
 	txn := sampleTransactionModel()
 	txn.Category = sampleIncomeCategory()
@@ -584,7 +817,7 @@ func TestDeleteTransaction_SuccessIncome(t *testing.T) {
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(txn, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(updatedWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(updatedWallet, nil)
 	d.transactionRepo.On("DeleteTransaction", mock.Anything, d.tx, txn).Return(txn, nil)
 	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
 	d.tx.On("Commit").Return(nil)
@@ -600,6 +833,7 @@ func TestDeleteTransaction_SuccessIncome(t *testing.T) {
 func TestDeleteTransaction_SuccessFundTransferCashOut(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompleted)
 
 	txn := sampleTransactionModel()
 	txn.Category = sampleFundTransferCashOut()
@@ -609,7 +843,7 @@ func TestDeleteTransaction_SuccessFundTransferCashOut(t *testing.T) {
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(txn, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(updatedWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(updatedWallet, nil)
 	d.transactionRepo.On("DeleteTransaction", mock.Anything, d.tx, txn).Return(txn, nil)
 	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
 	d.tx.On("Commit").Return(nil)
@@ -661,6 +895,7 @@ func TestDeleteTransaction_WalletNotFound(t *testing.T) {
 func TestDeleteTransaction_DeleteDBError(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompensated)
 
 	txn := sampleTransactionModel()
 	wallet := sampleWalletProto(walletTestID, 50000)
@@ -669,7 +904,7 @@ func TestDeleteTransaction_DeleteDBError(t *testing.T) {
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(txn, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(updatedWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(updatedWallet, nil)
 	d.transactionRepo.On("DeleteTransaction", mock.Anything, d.tx, txn).
 		Return(model.Transactions{}, errors.New("db delete error"))
 	d.tx.On("Rollback").Return(nil)
@@ -685,6 +920,9 @@ func TestDeleteTransaction_DeleteDBError(t *testing.T) {
 func TestDeleteTransaction_CommitError(t *testing.T) {
 	d := newTransactionTestDeps()
 	svc := d.service()
+	d.expectSagaLog(model.SagaCompensated)
+	// completion is written inside the rolled-back tx, so wallet updates are still reverted
+	d.sagaRepo.On("CompleteSaga", mock.Anything, d.tx, mock.Anything).Return(true, nil).Once()
 
 	txn := sampleTransactionModel()
 	wallet := sampleWalletProto(walletTestID, 50000)
@@ -693,7 +931,7 @@ func TestDeleteTransaction_CommitError(t *testing.T) {
 	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
 	d.transactionRepo.On("GetTransactionByID", mock.Anything, d.tx, txnTestID.String()).Return(txn, nil)
 	d.walletClient.On("GetWalletByID", mock.Anything, walletTestID.String()).Return(wallet, nil)
-	d.walletClient.On("UpdateWallet", mock.Anything, mock.Anything).Return(updatedWallet, nil)
+	d.walletClient.On("AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(updatedWallet, nil)
 	d.transactionRepo.On("DeleteTransaction", mock.Anything, d.tx, txn).Return(txn, nil)
 	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.Anything).Return(nil)
 	d.tx.On("Commit").Return(errors.New("commit error"))
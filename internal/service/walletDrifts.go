@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"refina-transaction/config/log"
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/dto"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	helper "refina-transaction/internal/utils"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+)
+
+// WalletDriftsService compares the ledger kept here with the balances kept by
+// wallet-service. A check racing a transaction write may see one side before
+// the other; such a drift is resolved by the next check.
+type WalletDriftsService interface {
+	GetWalletDrifts(ctx context.Context, walletIDs []string) ([]dto.WalletDriftsResponse, error)
+	// CheckWallets checks each wallet and, with correct, books an adjustment
+	// transaction for every difference found.
+	CheckWallets(ctx context.Context, walletIDs []string, correct bool) ([]dto.WalletDriftsResponse, error)
+	// CheckAllWallets checks every wallet with transactions and returns the
+	// number of wallets found drifting.
+	CheckAllWallets(ctx context.Context) (int, error)
+}
+
+type walletDriftsService struct {
+	txManager        repository.TxManager
+	driftRepo        repository.WalletDriftsRepository
+	transactionRepo  repository.TransactionsRepository
+	walletClient     client.WalletClient
+	categoryRepo     repository.CategoriesRepository
+	outboxRepository repository.OutboxRepository
+	currencies       *currencyConverter
+	history          *historyRecorder
+	now              func() time.Time
+}
+
+func NewWalletDriftsService(txManager repository.TxManager, driftRepo repository.WalletDriftsRepository, transactionRepo repository.TransactionsRepository, walletClient client.WalletClient, categoryRepo repository.CategoriesRepository, outboxRepository repository.OutboxRepository, currencyRepo repository.CurrenciesRepository, historyRepo repository.TransactionHistoryRepository) WalletDriftsService {
+	return &walletDriftsService{
+		txManager:        txManager,
+		driftRepo:        driftRepo,
+		transactionRepo:  transactionRepo,
+		walletClient:     walletClient,
+		categoryRepo:     categoryRepo,
+		outboxRepository: outboxRepository,
+		currencies:       newCurrencyConverter(currencyRepo),
+		history:          newHistoryRecorder(historyRepo),
+		now:              time.Now,
+	}
+}
+
+func (drift_serv *walletDriftsService) GetWalletDrifts(ctx context.Context, walletIDs []string) ([]dto.WalletDriftsResponse, error) {
+	drifts, err := drift_serv.driftRepo.GetDriftsByWalletIDs(ctx, nil, walletIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get wallet drifts: %w", err)
+	}
+
+	responses := make([]dto.WalletDriftsResponse, 0, len(drifts))
+	for _, drift := range drifts {
+		responses = append(responses, walletDriftResponse(drift))
+	}
+	return responses, nil
+}
+
+func (drift_serv *walletDriftsService) CheckWallets(ctx context.Context, walletIDs []string, correct bool) ([]dto.WalletDriftsResponse, error) {
+	responses := make([]dto.WalletDriftsResponse, 0, len(walletIDs))
+	for _, walletID := range walletIDs {
+		response, err := drift_serv.checkWallet(ctx, walletID, correct)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (drift_serv *walletDriftsService) CheckAllWallets(ctx context.Context) (int, error) {
+	walletIDs, err := drift_serv.driftRepo.GetLedgerWalletIDs(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("check wallet drifts: %w", err)
+	}
+
+	// One failing wallet does not stop the others from being checked
+	drifting := 0
+	for _, walletID := range walletIDs {
+		if ctx.Err() != nil {
+			return drifting, ctx.Err()
+		}
+
+		response, err := drift_serv.checkWallet(ctx, walletID, false)
+		if err != nil {
+			log.Error(data.LogWalletDriftCheckFailed, map[string]any{"service": data.WalletDriftService, "wallet_id": walletID, "error": err.Error()})
+			continue
+		}
+		if response.Status == string(model.WalletDriftOpen) {
+			drifting++
+		}
+	}
+	return drifting, nil
+}
+
+// checkWallet records a difference between the two balances of a wallet,
+// emitting data.OUTBOX_EVENT_WALLET_DRIFT_DETECTED when it is new or has
+// changed since the last check, and resolves the open drift of a wallet
+// found in sync again.
+func (drift_serv *walletDriftsService) checkWallet(ctx context.Context, walletID string, correct bool) (dto.WalletDriftsResponse, error) {
+	wallet, err := drift_serv.walletClient.GetWalletByID(ctx, walletID)
+	if err != nil {
+		return dto.WalletDriftsResponse{}, fmt.Errorf("wallet not found [id=%s]: %w", walletID, err)
+	}
+	walletBalance := client.WalletBalance(wallet)
+
+	// The ledger counts every booked transaction, the initial deposit included
+	ledgerBalance, err := drift_serv.transactionRepo.GetWalletNetChangeSince(ctx, nil, walletID, time.Time{})
+	if err != nil {
+		return dto.WalletDriftsResponse{}, fmt.Errorf("check wallet drift [wallet_id=%s]: %w", walletID, err)
+	}
+
+	now := drift_serv.now()
+	difference := walletBalance.Sub(ledgerBalance)
+
+	tx, err := drift_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.WalletDriftsResponse{}, fmt.Errorf("check wallet drift: begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	open, err := drift_serv.driftRepo.GetOpenDrift(ctx, tx, walletID)
+	if err != nil {
+		return dto.WalletDriftsResponse{}, fmt.Errorf("check wallet drift [wallet_id=%s]: %w", walletID, err)
+	}
+
+	if difference.IsZero() && open == nil {
+		return dto.WalletDriftsResponse{
+			WalletID:      walletID,
+			LedgerBalance: ledgerBalance,
+			WalletBalance: walletBalance,
+			Status:        string(model.WalletDriftInSync),
+			CheckedAt:     now,
+		}, nil
+	}
+
+	WalletID, err := helper.ParseUUID(walletID)
+	if err != nil {
+		return dto.WalletDriftsResponse{}, fmt.Errorf("invalid wallet id [id=%s]: %w", walletID, err)
+	}
+
+	drift := model.WalletDrifts{WalletID: WalletID, Status: model.WalletDriftOpen}
+	if open != nil {
+		drift = *open
+	}
+	correcting := correct && !difference.IsZero()
+	// A drift already reported with the same difference is not reported again
+	detected := !difference.IsZero() && (open == nil || open.Difference != difference || correcting)
+
+	drift.LedgerBalance = ledgerBalance
+	drift.WalletBalance = walletBalance
+	drift.Difference = difference
+	drift.CheckedAt = now
+	if difference.IsZero() {
+		drift.Status = model.WalletDriftResolved
+		drift.ResolvedAt = &now
+	}
+
+	// ! The drift is saved before the adjustment is booked, in the same
+	// transaction, so a correction never lands in the ledger unrecorded
+	if open == nil {
+		drift, err = drift_serv.driftRepo.CreateDrift(ctx, tx, drift)
+	} else {
+		drift, err = drift_serv.driftRepo.UpdateDrift(ctx, tx, drift)
+	}
+	if err != nil {
+		return dto.WalletDriftsResponse{}, fmt.Errorf("check wallet drift [wallet_id=%s]: save drift: %w", walletID, err)
+	}
+
+	// ? Book the difference in the ledger only; the wallet balance stays as it is
+	if correcting {
+		adjustment, err := drift_serv.bookAdjustment(ctx, tx, WalletID, difference, now)
+		if err != nil {
+			return dto.WalletDriftsResponse{}, fmt.Errorf("check wallet drift [wallet_id=%s]: %w", walletID, err)
+		}
+
+		drift.Status = model.WalletDriftCorrected
+		drift.AdjustmentTransactionID = &adjustment.ID
+		drift.ResolvedAt = &now
+		drift, err = drift_serv.driftRepo.UpdateDrift(ctx, tx, drift)
+		if err != nil {
+			return dto.WalletDriftsResponse{}, fmt.Errorf("check wallet drift [wallet_id=%s]: link adjustment: %w", walletID, err)
+		}
+	}
+
+	response := walletDriftResponse(drift)
+
+	if detected {
+		payload, err := json.Marshal(response)
+		if err != nil {
+			return dto.WalletDriftsResponse{}, fmt.Errorf("check wallet drift: marshal wallet drift response: %w", err)
+		}
+
+		if err := drift_serv.outboxRepository.Create(ctx, tx, &model.OutboxMessage{
+			AggregateID: walletID,
+			EventType:   data.OUTBOX_EVENT_WALLET_DRIFT_DETECTED,
+			Payload:     payload,
+			Published:   false,
+			MaxRetries:  data.OUTBOX_PUBLISH_MAX_RETRIES,
+		}); err != nil {
+			return dto.WalletDriftsResponse{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.WalletDriftsResponse{}, fmt.Errorf("check wallet drift: commit: %w", err)
+	}
+
+	if detected {
+		log.Warn(data.LogWalletDriftDetected, map[string]any{
+			"service":        data.WalletDriftService,
+			"wallet_id":      walletID,
+			"ledger_balance": ledgerBalance.String(),
+			"wallet_balance": walletBalance.String(),
+			"difference":     difference.String(),
+		})
+	}
+
+	return response, nil
+}
+
+// bookAdjustment records difference as an income or expense of the wallet
+// without moving its balance in wallet-service.
+func (drift_serv *walletDriftsService) bookAdjustment(ctx context.Context, tx repository.Transaction, walletID uuid.UUID, difference money.Amount, date time.Time) (model.Transactions, error) {
+	categoryID := data.CATEGORY_ID_BALANCE_ADJUSTMENT_IN
+	if difference.IsNegative() {
+		categoryID = data.CATEGORY_ID_BALANCE_ADJUSTMENT_OUT
+	}
+
+	category, err := drift_serv.categoryRepo.GetCategoryByID(ctx, tx, categoryID)
+	if err != nil {
+		return model.Transactions{}, fmt.Errorf("category not found [id=%s]: %w", categoryID, err)
+	}
+
+	currency, err := drift_serv.currencies.walletCurrency(ctx, tx, walletID.String())
+	if err != nil {
+		return model.Transactions{}, err
+	}
+
+	adjustment, err := drift_serv.transactionRepo.CreateTransaction(ctx, tx, model.Transactions{
+		WalletID:        walletID,
+		CategoryID:      category.ID,
+		Amount:          difference.Abs(),
+		Currency:        currency,
+		TransactionDate: date,
+		Description:     "Penyesuaian saldo",
+		Status:          model.StatusCleared,
+	})
+	if err != nil {
+		return model.Transactions{}, fmt.Errorf("book adjustment transaction: insert transaction to db: %w", err)
+	}
+	adjustment.Category = category
+
+	if err := drift_serv.history.RecordCreated(ctx, tx, adjustment); err != nil {
+		return model.Transactions{}, fmt.Errorf("book adjustment transaction: %w", err)
+	}
+
+	if err := emitTransactions(ctx, drift_serv.outboxRepository, tx, data.OUTBOX_EVENT_TRANSACTION_CREATED, adjustment); err != nil {
+		return model.Transactions{}, err
+	}
+
+	return adjustment, nil
+}
+
+func walletDriftResponse(drift model.WalletDrifts) dto.WalletDriftsResponse {
+	response := dto.WalletDriftsResponse{
+		ID:            drift.ID.String(),
+		WalletID:      drift.WalletID.String(),
+		LedgerBalance: drift.LedgerBalance,
+		WalletBalance: drift.WalletBalance,
+		Difference:    drift.Difference,
+		Status:        string(drift.Status),
+		CheckedAt:     drift.CheckedAt,
+		ResolvedAt:    drift.ResolvedAt,
+	}
+	if drift.AdjustmentTransactionID != nil {
+		response.AdjustmentTransactionID = drift.AdjustmentTransactionID.String()
+	}
+	return response
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Checker
+// ──────────────────────────────────────────────────────────────────────────────
+
+// WalletDriftChecker compares every wallet on data.WALLET_DRIFT_CHECK_INTERVAL.
+// It only records and reports drifts; corrections are booked on request.
+type WalletDriftChecker struct {
+	driftService WalletDriftsService
+	interval     time.Duration
+}
+
+func NewWalletDriftChecker(driftService WalletDriftsService) *WalletDriftChecker {
+	return &WalletDriftChecker{
+		driftService: driftService,
+		interval:     data.WALLET_DRIFT_CHECK_INTERVAL,
+	}
+}
+
+// Start checks right away and then on every tick until ctx is cancelled.
+func (c *WalletDriftChecker) Start(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.driftService.CheckAllWallets(ctx); err != nil && ctx.Err() == nil {
+			log.Error(data.LogWalletDriftCheckFailed, map[string]any{"service": data.WalletDriftService, "error": err.Error()})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"refina-transaction/internal/service/mocks"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+	"refina-transaction/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────
+// Test Dependency Container
+// ─────────────────────────────────────────────
+
+type walletDriftTestDeps struct {
+	txManager       *mocks.MockTxManager
+	driftRepo       *mocks.MockWalletDriftsRepository
+	transactionRepo *mocks.MockTransactionsRepository
+	walletClient    *mocks.MockWalletClient
+	categoryRepo    *mocks.MockCategoriesRepository
+	outboxRepo      *mocks.MockOutboxRepository
+	currencyRepo    *mocks.MockCurrenciesRepository
+	historyRepo     *mocks.MockTransactionHistoryRepository
+	tx              *mocks.MockTransaction
+}
+
+func newWalletDriftTestDeps() *walletDriftTestDeps {
+	d := &walletDriftTestDeps{
+		txManager:       new(mocks.MockTxManager),
+		driftRepo:       new(mocks.MockWalletDriftsRepository),
+		transactionRepo: new(mocks.MockTransactionsRepository),
+		walletClient:    new(mocks.MockWalletClient),
+		categoryRepo:    new(mocks.MockCategoriesRepository),
+		outboxRepo:      new(mocks.MockOutboxRepository),
+		currencyRepo:    new(mocks.MockCurrenciesRepository),
+		historyRepo:     new(mocks.MockTransactionHistoryRepository),
+		tx:              new(mocks.MockTransaction),
+	}
+	d.currencyRepo.On("GetWalletCurrency", mock.Anything, mock.Anything, mock.Anything).Return("", nil).Maybe()
+	d.historyRepo.On("CreateHistory", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return d
+}
+
+func (d *walletDriftTestDeps) service() WalletDriftsService {
+	svc := NewWalletDriftsService(d.txManager, d.driftRepo, d.transactionRepo, d.walletClient, d.categoryRepo, d.outboxRepo, d.currencyRepo, d.historyRepo).(*walletDriftsService)
+	svc.now = func() time.Time { return txnFixTime }
+	return svc
+}
+
+func (d *walletDriftTestDeps) assertAll(t *testing.T) {
+	t.Helper()
+	d.txManager.AssertExpectations(t)
+	d.driftRepo.AssertExpectations(t)
+	d.transactionRepo.AssertExpectations(t)
+	d.walletClient.AssertExpectations(t)
+	d.categoryRepo.AssertExpectations(t)
+	d.outboxRepo.AssertExpectations(t)
+	d.tx.AssertExpectations(t)
+}
+
+// expectBalances stubs the wallet-service balance and the ledger sum of walletID.
+func (d *walletDriftTestDeps) expectBalances(walletID uuid.UUID, walletBalance float64, ledgerBalance int64) {
+	d.walletClient.On("GetWalletByID", mock.Anything, walletID.String()).Return(sampleWalletProto(walletID, walletBalance), nil)
+	d.transactionRepo.On("GetWalletNetChangeSince", mock.Anything, nil, walletID.String(), time.Time{}).Return(money.New(ledgerBalance), nil)
+}
+
+var walletDriftTestID = uuid.MustParse("88888888-8888-8888-8888-888888888888")
+
+func sampleOpenDrift(difference int64) *model.WalletDrifts {
+	return &model.WalletDrifts{
+		Base:          model.Base{ID: walletDriftTestID},
+		WalletID:      walletTestID,
+		LedgerBalance: money.New(100000),
+		WalletBalance: money.New(100000 + difference),
+		Difference:    money.New(difference),
+		Status:        model.WalletDriftOpen,
+		CheckedAt:     txnFixTime.Add(-time.Hour),
+	}
+}
+
+func expectDriftEvent(outboxRepo *mocks.MockOutboxRepository, tx any) {
+	outboxRepo.On("Create", mock.Anything, tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_WALLET_DRIFT_DETECTED && msg.AggregateID == walletTestID.String()
+	})).Return(nil).Once()
+}
+
+// =====================================================================
+// CheckWallets
+// =====================================================================
+
+func TestCheckWallets_RecordsNewDriftAndEmitsEvent(t *testing.T) {
+	d := newWalletDriftTestDeps()
+	svc := d.service()
+
+	d.expectBalances(walletTestID, 120000, 100000)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.driftRepo.On("GetOpenDrift", mock.Anything, d.tx, walletTestID.String()).Return(nil, nil)
+	d.driftRepo.On("CreateDrift", mock.Anything, d.tx, mock.MatchedBy(func(drift model.WalletDrifts) bool {
+		return drift.Difference == money.New(20000) && drift.Status == model.WalletDriftOpen && drift.CheckedAt.Equal(txnFixTime)
+	})).Return(*sampleOpenDrift(20000), nil)
+	expectDriftEvent(d.outboxRepo, d.tx)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CheckWallets(context.Background(), []string{walletTestID.String()}, false)
+
+	assert.NoError(t, err)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, walletDriftTestID.String(), result[0].ID)
+		assert.Equal(t, money.New(20000), result[0].Difference)
+		assert.Equal(t, string(model.WalletDriftOpen), result[0].Status)
+	}
+	d.assertAll(t)
+}
+
+func TestCheckWallets_SameDifferenceIsNotReportedAgain(t *testing.T) {
+	d := newWalletDriftTestDeps()
+	svc := d.service()
+
+	d.expectBalances(walletTestID, 120000, 100000)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.driftRepo.On("GetOpenDrift", mock.Anything, d.tx, walletTestID.String()).Return(sampleOpenDrift(20000), nil)
+	d.driftRepo.On("UpdateDrift", mock.Anything, d.tx, mock.MatchedBy(func(drift model.WalletDrifts) bool {
+		return drift.ID == walletDriftTestID && drift.Status == model.WalletDriftOpen && drift.CheckedAt.Equal(txnFixTime)
+	})).Return(*sampleOpenDrift(20000), nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.CheckWallets(context.Background(), []string{walletTestID.String()}, false)
+
+	assert.NoError(t, err)
+	d.outboxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCheckWallets_InSyncResolvesOpenDrift(t *testing.T) {
+	d := newWalletDriftTestDeps()
+	svc := d.service()
+
+	d.expectBalances(walletTestID, 100000, 100000)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.driftRepo.On("GetOpenDrift", mock.Anything, d.tx, walletTestID.String()).Return(sampleOpenDrift(20000), nil)
+	d.driftRepo.On("UpdateDrift", mock.Anything, d.tx, mock.MatchedBy(func(drift model.WalletDrifts) bool {
+		return drift.Status == model.WalletDriftResolved && drift.Difference.IsZero() && drift.ResolvedAt != nil
+	})).Return(func() model.WalletDrifts {
+		resolved := *sampleOpenDrift(0)
+		resolved.Status = model.WalletDriftResolved
+		return resolved
+	}(), nil)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CheckWallets(context.Background(), []string{walletTestID.String()}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(model.WalletDriftResolved), result[0].Status)
+	d.outboxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCheckWallets_InSyncWithoutDriftWritesNothing(t *testing.T) {
+	d := newWalletDriftTestDeps()
+	svc := d.service()
+
+	d.expectBalances(walletTestID, 100000, 100000)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.driftRepo.On("GetOpenDrift", mock.Anything, d.tx, walletTestID.String()).Return(nil, nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CheckWallets(context.Background(), []string{walletTestID.String()}, false)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result[0].ID)
+	assert.Equal(t, string(model.WalletDriftInSync), result[0].Status)
+	d.driftRepo.AssertNotCalled(t, "CreateDrift", mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func sampleAdjustmentOutCategory() model.Categories {
+	return model.Categories{
+		Base: model.Base{ID: uuid.MustParse(data.CATEGORY_ID_BALANCE_ADJUSTMENT_OUT)},
+		Name: "Penyesuaian Saldo",
+		Type: model.Expense,
+	}
+}
+
+func TestCheckWallets_CorrectBooksAdjustmentInLedgerOnly(t *testing.T) {
+	d := newWalletDriftTestDeps()
+	svc := d.service()
+
+	adjustmentID := uuid.New()
+
+	// The wallet holds 15000 less than the ledger says
+	d.expectBalances(walletTestID, 85000, 100000)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.driftRepo.On("GetOpenDrift", mock.Anything, d.tx, walletTestID.String()).Return(sampleOpenDrift(-15000), nil)
+	// The drift is saved before the adjustment is booked, then linked to it
+	saved := d.driftRepo.On("UpdateDrift", mock.Anything, d.tx, mock.MatchedBy(func(drift model.WalletDrifts) bool {
+		return drift.Status == model.WalletDriftOpen && drift.AdjustmentTransactionID == nil
+	})).Return(*sampleOpenDrift(-15000), nil).Once()
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, data.CATEGORY_ID_BALANCE_ADJUSTMENT_OUT).Return(sampleAdjustmentOutCategory(), nil)
+	booked := d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.MatchedBy(func(txn model.Transactions) bool {
+		return txn.WalletID == walletTestID && txn.CategoryID.String() == data.CATEGORY_ID_BALANCE_ADJUSTMENT_OUT &&
+			txn.Amount == money.New(15000) && txn.TransactionDate.Equal(txnFixTime) && txn.Currency == data.DEFAULT_CURRENCY
+	})).Return(model.Transactions{Base: model.Base{ID: adjustmentID}, WalletID: walletTestID, Amount: money.New(15000)}, nil).NotBefore(saved)
+	d.driftRepo.On("UpdateDrift", mock.Anything, d.tx, mock.MatchedBy(func(drift model.WalletDrifts) bool {
+		return drift.Status == model.WalletDriftCorrected && drift.AdjustmentTransactionID != nil && *drift.AdjustmentTransactionID == adjustmentID && drift.ResolvedAt != nil
+	})).Return(func() model.WalletDrifts {
+		corrected := *sampleOpenDrift(-15000)
+		corrected.Status = model.WalletDriftCorrected
+		corrected.AdjustmentTransactionID = &adjustmentID
+		return corrected
+	}(), nil).Once().NotBefore(booked)
+	d.outboxRepo.On("Create", mock.Anything, d.tx, mock.MatchedBy(func(msg *model.OutboxMessage) bool {
+		return msg.EventType == data.OUTBOX_EVENT_TRANSACTION_CREATED && msg.AggregateID == adjustmentID.String()
+	})).Return(nil).Once()
+	expectDriftEvent(d.outboxRepo, d.tx)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	result, err := svc.CheckWallets(context.Background(), []string{walletTestID.String()}, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, adjustmentID.String(), result[0].AdjustmentTransactionID)
+	d.walletClient.AssertNotCalled(t, "AdjustBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.assertAll(t)
+}
+
+func TestCheckWallets_CorrectSaveDriftErrorBooksNothing(t *testing.T) {
+	d := newWalletDriftTestDeps()
+	svc := d.service()
+
+	d.expectBalances(walletTestID, 85000, 100000)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.driftRepo.On("GetOpenDrift", mock.Anything, d.tx, walletTestID.String()).Return(nil, nil)
+	d.driftRepo.On("CreateDrift", mock.Anything, d.tx, mock.Anything).Return(model.WalletDrifts{}, errors.New("duplicate key value violates unique constraint"))
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.CheckWallets(context.Background(), []string{walletTestID.String()}, true)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "save drift")
+	d.transactionRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything, mock.Anything)
+	d.outboxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	d.tx.AssertNotCalled(t, "Commit")
+	d.assertAll(t)
+}
+
+func TestCheckWallets_CorrectBookingErrorKeepsNoDriftOrAdjustment(t *testing.T) {
+	d := newWalletDriftTestDeps()
+	svc := d.service()
+
+	d.expectBalances(walletTestID, 85000, 100000)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.driftRepo.On("GetOpenDrift", mock.Anything, d.tx, walletTestID.String()).Return(nil, nil)
+	d.driftRepo.On("CreateDrift", mock.Anything, d.tx, mock.Anything).Return(*sampleOpenDrift(-15000), nil)
+	d.categoryRepo.On("GetCategoryByID", mock.Anything, d.tx, data.CATEGORY_ID_BALANCE_ADJUSTMENT_OUT).Return(sampleAdjustmentOutCategory(), nil)
+	d.transactionRepo.On("CreateTransaction", mock.Anything, d.tx, mock.Anything).Return(model.Transactions{}, errors.New("insert failed"))
+	d.tx.On("Rollback").Return(nil)
+
+	_, err := svc.CheckWallets(context.Background(), []string{walletTestID.String()}, true)
+
+	// Both rows roll back together, so the drift is found again on the next check
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "book adjustment transaction")
+	d.driftRepo.AssertNotCalled(t, "UpdateDrift", mock.Anything, mock.Anything, mock.Anything)
+	d.tx.AssertNotCalled(t, "Commit")
+	d.assertAll(t)
+}
+
+// =====================================================================
+// CheckAllWallets
+// =====================================================================
+
+func TestCheckAllWallets_SkipsFailingWallet(t *testing.T) {
+	d := newWalletDriftTestDeps()
+	svc := d.service()
+
+	d.driftRepo.On("GetLedgerWalletIDs", mock.Anything, nil).Return([]string{wallet2ID.String(), walletTestID.String()}, nil)
+	d.walletClient.On("GetWalletByID", mock.Anything, wallet2ID.String()).Return(nil, errors.New("wallet not found"))
+	d.expectBalances(walletTestID, 120000, 100000)
+	d.txManager.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.driftRepo.On("GetOpenDrift", mock.Anything, d.tx, walletTestID.String()).Return(nil, nil)
+	d.driftRepo.On("CreateDrift", mock.Anything, d.tx, mock.Anything).Return(*sampleOpenDrift(20000), nil)
+	expectDriftEvent(d.outboxRepo, d.tx)
+	d.tx.On("Commit").Return(nil)
+	d.tx.On("Rollback").Return(nil)
+
+	drifting, err := svc.CheckAllWallets(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, drifting)
+	d.assertAll(t)
+}
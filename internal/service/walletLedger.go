@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"refina-transaction/interface/grpc/client"
+	"refina-transaction/internal/repository"
+	"refina-transaction/internal/types/model"
+	"refina-transaction/internal/types/money"
+
+	wpb "github.com/MuhammadMiftaa/Refina-Protobuf/wallet"
+	"github.com/google/uuid"
+)
+
+// walletLedger books the amounts of transactions on the balances kept by
+// wallet-service, recording every update in the saga of the caller.
+type walletLedger struct {
+	walletClient       client.WalletClient
+	saga               *SagaOrchestrator
+	reconciliationRepo repository.ReconciliationsRepository
+}
+
+func newWalletLedger(walletClient client.WalletClient, saga *SagaOrchestrator, reconciliationRepo repository.ReconciliationsRepository) *walletLedger {
+	return &walletLedger{walletClient: walletClient, saga: saga, reconciliationRepo: reconciliationRepo}
+}
+
+// isBooked reports whether the amount of a transaction counts in the balance
+// of its wallet: void and scheduled ones never do, and pending ones only
+// while the wallet books transactions on entry.
+func (ledger *walletLedger) isBooked(ctx context.Context, tx repository.Transaction, transaction model.Transactions) (bool, error) {
+	if transaction.Scheduled {
+		return false, nil
+	}
+
+	switch transaction.Status {
+	case model.StatusVoid:
+		return false, nil
+	case model.StatusPending:
+		stage, err := ledger.reconciliationRepo.GetBalanceStage(ctx, tx, transaction.WalletID.String())
+		if err != nil {
+			return false, fmt.Errorf("get balance stage [wallet_id=%s]: %w", transaction.WalletID, err)
+		}
+		return stage != model.BalanceStageCleared, nil
+	}
+	return true, nil
+}
+
+// bookedEffect is the balanceEffect of a transaction when it is booked, and
+// zero when it is not.
+func (ledger *walletLedger) bookedEffect(ctx context.Context, tx repository.Transaction, transaction model.Transactions) (money.Amount, error) {
+	booked, err := ledger.isBooked(ctx, tx, transaction)
+	if err != nil || !booked {
+		return money.Zero, err
+	}
+	return balanceEffect(transaction)
+}
+
+// moveBalances reverses the booked effect of the transactions in before
+// and books the ones in after, with one balance update per wallet. wallets
+// holds wallets already fetched; the others are fetched here.
+func (ledger *walletLedger) moveBalances(ctx context.Context, tx repository.Transaction, saga *model.SagaLog, before, after []model.Transactions, wallets map[uuid.UUID]*wpb.Wallet) error {
+	deltas := make(map[uuid.UUID]money.Amount)
+	var walletIDs []uuid.UUID
+	addDelta := func(walletID uuid.UUID, delta money.Amount) {
+		if _, ok := deltas[walletID]; !ok {
+			walletIDs = append(walletIDs, walletID)
+		}
+		deltas[walletID] = deltas[walletID].Add(delta)
+	}
+
+	for _, transaction := range before {
+		effect, err := ledger.bookedEffect(ctx, tx, transaction)
+		if err != nil {
+			return err
+		}
+		addDelta(transaction.WalletID, effect.Neg())
+	}
+	for _, transaction := range after {
+		effect, err := ledger.bookedEffect(ctx, tx, transaction)
+		if err != nil {
+			return err
+		}
+		addDelta(transaction.WalletID, effect)
+	}
+
+	// Check every wallet before updating any of them
+	for _, walletID := range walletIDs {
+		if deltas[walletID].IsZero() {
+			continue
+		}
+		wallet, ok := wallets[walletID]
+		if !ok {
+			var err error
+			if wallet, err = ledger.walletClient.GetWalletByID(ctx, walletID.String()); err != nil {
+				return fmt.Errorf("wallet not found [id=%s]: %w", walletID, err)
+			}
+		}
+		if client.WalletBalance(wallet).Add(deltas[walletID]).IsNegative() {
+			return fmt.Errorf("insufficient wallet balance [wallet_id=%s]", walletID)
+		}
+		if wallets == nil {
+			wallets = make(map[uuid.UUID]*wpb.Wallet)
+		}
+		wallets[walletID] = wallet
+	}
+
+	for _, walletID := range walletIDs {
+		if deltas[walletID].IsZero() {
+			continue
+		}
+		if err := ledger.saga.UpdateWalletBalance(ctx, saga, wallets[walletID], deltas[walletID]); err != nil {
+			return fmt.Errorf("update wallet balance [wallet_id=%s]: %w", walletID, err)
+		}
+	}
+	return nil
+}
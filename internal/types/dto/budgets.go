@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+)
+
+type BudgetsResponse struct {
+	ID string `json:"id"`
+
+	CategoryID   string `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	// IsGroup is true when the budget covers a parent category and all of its children
+	IsGroup bool `json:"is_group"`
+
+	Period   string       `json:"period"`
+	Amount   money.Amount `json:"amount"`
+	Rollover bool         `json:"rollover"`
+
+	PeriodStart time.Time    `json:"period_start"`
+	PeriodEnd   time.Time    `json:"period_end"`
+	Limit       money.Amount `json:"limit"`
+	Spent       money.Amount `json:"spent"`
+	Remaining   money.Amount `json:"remaining"`
+	Percentage  float64      `json:"percentage"`
+}
+
+type BudgetsRequest struct {
+	CategoryID string       `json:"category_id"`
+	Period     string       `json:"period"`
+	Amount     money.Amount `json:"amount"`
+	Rollover   bool         `json:"rollover"`
+}
+
+// BudgetEvent is the outbox payload of budget.threshold_reached and budget.exceeded.
+type BudgetEvent struct {
+	BudgetID      string       `json:"budget_id"`
+	UserID        string       `json:"user_id"`
+	CategoryID    string       `json:"category_id"`
+	Period        string       `json:"period"`
+	PeriodStart   time.Time    `json:"period_start"`
+	PeriodEnd     time.Time    `json:"period_end"`
+	Limit         money.Amount `json:"limit"`
+	Spent         money.Amount `json:"spent"`
+	Percentage    float64      `json:"percentage"`
+	TransactionID string       `json:"transaction_id"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+type FXRatesResponse struct {
+	ID            string    `json:"id"`
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          float64   `json:"rate"`
+	EffectiveDate time.Time `json:"effective_date"`
+	Source        string    `json:"source"`
+}
+
+// FXRatesRequest says one BaseCurrency is worth Rate QuoteCurrency from EffectiveDate.
+type FXRatesRequest struct {
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          float64   `json:"rate"`
+	EffectiveDate time.Time `json:"effective_date"`
+}
+
+// FXRatesImportRequest carries a CSV file with a date,base,quote,rate header.
+type FXRatesImportRequest struct {
+	Content string `json:"content"`
+}
+
+type WalletCurrencyResponse struct {
+	WalletID string `json:"wallet_id"`
+	Currency string `json:"currency"`
+}
+
+type WalletCurrencyRequest struct {
+	Currency string `json:"currency"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+)
+
+type DebtsResponse struct {
+	ID           string `json:"id"`
+	WalletID     string `json:"wallet_id"`
+	Counterparty string `json:"counterparty"`
+	// Direction is payable (hutang) or receivable (piutang)
+	Direction   string       `json:"direction"`
+	Principal   money.Amount `json:"principal"`
+	Currency    string       `json:"currency"`
+	Repaid      money.Amount `json:"repaid"`
+	Outstanding money.Amount `json:"outstanding"`
+	Date        time.Time    `json:"date"`
+	DueDate     *time.Time   `json:"due_date"`
+	Description string       `json:"description"`
+	// Status is open or settled; Overdue is set on an open debt past its due date
+	Status    string     `json:"status"`
+	Overdue   bool       `json:"overdue"`
+	SettledAt *time.Time `json:"settled_at"`
+
+	// PrincipalTransaction is nil for a debt recorded without moving money
+	PrincipalTransaction *TransactionsResponse  `json:"principal_transaction,omitempty"`
+	Repayments           []TransactionsResponse `json:"repayments"`
+}
+
+// DebtsRequest records money lent (receivable) or borrowed (payable). The
+// principal leaves or enters WalletID on Date unless RecordOnly is set, for a
+// debt whose money moved before it was tracked.
+type DebtsRequest struct {
+	WalletID     string       `json:"wallet_id"`
+	Counterparty string       `json:"counterparty"`
+	Direction    string       `json:"direction"`
+	Principal    money.Amount `json:"principal"`
+	Date         time.Time    `json:"date"` // defaults to now
+	DueDate      *time.Time   `json:"due_date"`
+	Description  string       `json:"description"`
+	RecordOnly   bool         `json:"record_only"`
+}
+
+// UpdateDebtRequest changes the details of a debt; its amounts change
+// through repayments only.
+type UpdateDebtRequest struct {
+	Counterparty string     `json:"counterparty"`
+	DueDate      *time.Time `json:"due_date"`
+	Description  string     `json:"description"`
+}
+
+// DebtRepaymentRequest books part or all of the outstanding balance of a
+// debt, paid from or into WalletID, the debt's wallet when empty.
+type DebtRepaymentRequest struct {
+	WalletID    string       `json:"wallet_id"`
+	Amount      money.Amount `json:"amount"`
+	Date        time.Time    `json:"date"` // defaults to now
+	Description string       `json:"description"`
+}
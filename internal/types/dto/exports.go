@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+)
+
+type StatementResponse struct {
+	WalletID       string       `json:"wallet_id"`
+	Month          string       `json:"month"`
+	OpeningBalance money.Amount `json:"opening_balance"`
+	TotalIncome    money.Amount `json:"total_income"`
+	TotalExpense   money.Amount `json:"total_expense"`
+	ClosingBalance money.Amount `json:"closing_balance"`
+	Entries        int          `json:"entries"`
+	FileName       string       `json:"file_name"`
+	URL            string       `json:"url,omitempty"`
+	ExpiresAt      time.Time    `json:"expires_at,omitempty"`
+}
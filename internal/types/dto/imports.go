@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+)
+
+// ImportCSVMapping names the CSV header columns holding each field.
+type ImportCSVMapping struct {
+	Date        string `json:"date"`
+	Amount      string `json:"amount"`
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	// Type is optional; without it the amount sign decides (negative = expense)
+	Type       string `json:"type"`
+	DateFormat string `json:"date_format"`
+	Delimiter  string `json:"delimiter"`
+	// DecimalSeparator is "." or ","; detected from each amount when empty
+	DecimalSeparator string `json:"decimal_separator"`
+}
+
+type ImportsRequest struct {
+	WalletID string `json:"wallet_id"`
+	Format   string `json:"format"`
+	FileName string `json:"file_name"`
+	Content  string `json:"content"`
+
+	CSV ImportCSVMapping `json:"csv"`
+	// DateFormat overrides the QIF date layout (Go reference time, default 01/02/2006)
+	DateFormat string `json:"date_format"`
+	// DecimalSeparator sets the QIF decimal mark ("." or ","); detected when empty
+	DecimalSeparator string `json:"decimal_separator"`
+
+	// CategoryMap maps a category label from the file to a category ID
+	CategoryMap              map[string]string `json:"category_map"`
+	DefaultIncomeCategoryID  string            `json:"default_income_category_id"`
+	DefaultExpenseCategoryID string            `json:"default_expense_category_id"`
+}
+
+type ImportRowResponse struct {
+	Line         int          `json:"line"`
+	Date         time.Time    `json:"date"`
+	Amount       money.Amount `json:"amount"`
+	Type         string       `json:"type"`
+	Description  string       `json:"description"`
+	Label        string       `json:"label"`
+	CategoryID   string       `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	Error        string       `json:"error,omitempty"`
+}
+
+type ImportsResponse struct {
+	ID           string              `json:"id"`
+	WalletID     string              `json:"wallet_id"`
+	Format       string              `json:"format"`
+	FileName     string              `json:"file_name"`
+	Status       string              `json:"status"`
+	TotalRows    int                 `json:"total_rows"`
+	ValidRows    int                 `json:"valid_rows"`
+	InvalidRows  int                 `json:"invalid_rows"`
+	BalanceDelta money.Amount        `json:"balance_delta"`
+	Rows         []ImportRowResponse `json:"rows"`
+	CreatedAt    time.Time           `json:"created_at"`
+	CommittedAt  *time.Time          `json:"committed_at"`
+	UndoneAt     *time.Time          `json:"undone_at"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+)
+
+type ReconciliationsRequest struct {
+	WalletID         string       `json:"wallet_id"`
+	StatementDate    time.Time    `json:"statement_date"`
+	StatementBalance money.Amount `json:"statement_balance"`
+}
+
+type CompleteReconciliationRequest struct {
+	// TransactionIDs are the cleared transactions that appear on the statement
+	TransactionIDs []string `json:"transaction_ids"`
+}
+
+type ReconciliationsResponse struct {
+	ID               string       `json:"id"`
+	WalletID         string       `json:"wallet_id"`
+	StatementDate    time.Time    `json:"statement_date"`
+	StatementBalance money.Amount `json:"statement_balance"`
+	// OpeningBalance sums the transactions reconciled before this session
+	OpeningBalance money.Amount `json:"opening_balance"`
+	// ClearedBalance is OpeningBalance plus the transactions counted so far:
+	// every candidate while open, the selection when completing
+	ClearedBalance   money.Amount `json:"cleared_balance"`
+	Difference       money.Amount `json:"difference"`
+	Status           string       `json:"status"`
+	TransactionCount int          `json:"transaction_count"`
+	CompletedAt      *time.Time   `json:"completed_at,omitempty"`
+	CreatedAt        time.Time    `json:"created_at"`
+	// Transactions lists the cleared transactions an open session may reconcile
+	Transactions []TransactionsResponse `json:"transactions,omitempty"`
+}
+
+type BalanceStageRequest struct {
+	// BalanceStage is "pending" to book transactions on entry or "cleared"
+	// to leave pending ones out of the balance until they clear
+	BalanceStage string `json:"balance_stage"`
+}
+
+type BalanceStageResponse struct {
+	WalletID     string `json:"wallet_id"`
+	BalanceStage string `json:"balance_stage"`
+}
+
+type UpdateTransactionStatusRequest struct {
+	Status string `json:"status"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+)
+
+type RecurringTransactionsResponse struct {
+	ID string `json:"id"`
+
+	WalletID     string `json:"wallet_id"`
+	CategoryID   string `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	CategoryType string `json:"category_type"`
+
+	Amount      money.Amount `json:"amount"`
+	Description string       `json:"description"`
+
+	Frequency   string     `json:"frequency"`
+	Interval    int        `json:"interval"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	Count       int        `json:"count"`
+	Occurrences int        `json:"occurrences"`
+	NextRunAt   time.Time  `json:"next_run_at"`
+	LastRunAt   *time.Time `json:"last_run_at"`
+	Status      string     `json:"status"`
+
+	FailureCount int        `json:"failure_count"`
+	LastError    string     `json:"last_error,omitempty"`
+	RetryAt      *time.Time `json:"retry_at"`
+}
+
+type RecurringTransactionsRequest struct {
+	WalletID    string       `json:"wallet_id"`
+	CategoryID  string       `json:"category_id"`
+	Amount      money.Amount `json:"amount"`
+	Description string       `json:"description"`
+
+	// Frequency is one of daily, weekly, monthly or yearly, repeated every Interval units
+	Frequency string     `json:"frequency"`
+	Interval  int        `json:"interval"`
+	StartDate time.Time  `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+	// Count limits the number of occurrences, 0 means unlimited
+	Count int `json:"count"`
+}
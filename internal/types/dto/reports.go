@@ -0,0 +1,32 @@
+package dto
+
+import "refina-transaction/internal/types/money"
+
+type SummaryTotals struct {
+	Total   money.Amount `json:"total"`
+	Count   int64        `json:"count"`
+	Average money.Amount `json:"average"`
+}
+
+type SummaryBucket struct {
+	Key     string        `json:"key"`
+	Label   string        `json:"label"`
+	Income  SummaryTotals `json:"income"`
+	Expense SummaryTotals `json:"expense"`
+	Net     money.Amount  `json:"net"`
+}
+
+type TransactionSummaryResponse struct {
+	GroupBy string `json:"group_by"`
+	// Currency is the base currency totals were converted to; empty when
+	// amounts were summed in the currencies of their wallets
+	Currency string          `json:"currency,omitempty"`
+	Buckets  []SummaryBucket `json:"buckets"`
+	Income   SummaryTotals   `json:"income"`
+	Expense  SummaryTotals   `json:"expense"`
+	Net      money.Amount    `json:"net"`
+	// FXGainLoss is what transfers between wallets of different currencies
+	// were worth on arrival over departure, in Currency at the rates of their
+	// day; left out when totals were not converted
+	FXGainLoss *money.Amount `json:"fx_gain_loss,omitempty"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+type TagsResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type TagsRequest struct {
+	Name string `json:"name"`
+}
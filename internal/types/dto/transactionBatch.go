@@ -0,0 +1,35 @@
+package dto
+
+// BatchTransactionsRequest applies several changes in one go: either every
+// item is applied or none is.
+type BatchTransactionsRequest struct {
+	Items []BatchTransactionItem `json:"items"`
+}
+
+type BatchTransactionItem struct {
+	// Action is "create", "update" or "delete"
+	Action string `json:"action"`
+	// ID of the transaction to update or delete
+	ID string `json:"id"`
+	// Transaction is the body of a create or update, as for the single
+	// endpoints. Attachments are uploaded separately.
+	Transaction TransactionsRequest `json:"transaction"`
+}
+
+// BatchTransactionsResponse reports the outcome of every item, in request
+// order. Applied is false when an item failed and nothing was changed.
+type BatchTransactionsResponse struct {
+	Applied bool                     `json:"applied"`
+	Results []BatchTransactionResult `json:"results"`
+}
+
+type BatchTransactionResult struct {
+	Index  int    `json:"index"`
+	Action string `json:"action"`
+	ID     string `json:"id,omitempty"`
+	// Status is "applied", "failed", or "not_applied" for a valid item of a
+	// batch that failed
+	Status      string                `json:"status"`
+	Error       string                `json:"error,omitempty"`
+	Transaction *TransactionsResponse `json:"transaction,omitempty"`
+}
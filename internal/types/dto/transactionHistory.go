@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TransactionHistoryResponse is one entry of the audit trail of a transaction.
+// Before and After are the transaction as a TransactionsResponse, null when
+// it did not exist before or no longer exists after the change.
+type TransactionHistoryResponse struct {
+	ID            string          `json:"id"`
+	TransactionID string          `json:"transaction_id"`
+	Action        string          `json:"action"`
+	ActorID       string          `json:"actor_id"`
+	Source        string          `json:"source"`
+	RequestID     string          `json:"request_id"`
+	Before        json.RawMessage `json:"before"`
+	After         json.RawMessage `json:"after"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+)
 
 type TransactionsResponse struct {
 	ID string `json:"id"`
@@ -10,11 +14,50 @@ type TransactionsResponse struct {
 	CategoryName string `json:"category_name"`
 	CategoryType string `json:"category_type"`
 
-	Amount          float64   `json:"amount"`
-	TransactionDate time.Time `json:"transaction_date"`
-	Description     string    `json:"description"`
+	Amount          money.Amount `json:"amount"`
+	Currency        string       `json:"currency"`
+	TransactionDate time.Time    `json:"transaction_date"`
+	Description     string       `json:"description"`
+
+	OriginalAmount   *money.Amount `json:"original_amount,omitempty"`
+	OriginalCurrency *string       `json:"original_currency,omitempty"`
+	FxRate           *float64      `json:"fx_rate,omitempty"`
+
+	// Status is pending, cleared, reconciled or void
+	Status           string  `json:"status"`
+	ReconciliationID *string `json:"reconciliation_id,omitempty"`
+	// TransferID is set on both legs of a fund transfer
+	TransferID *string `json:"transfer_id,omitempty"`
+	// DebtID is set on the principal and the repayments of a debt
+	DebtID *string `json:"debt_id,omitempty"`
+	// Scheduled is set until a future-dated transaction posts on its date
+	Scheduled bool `json:"scheduled"`
+	// Set on a scheduled transaction whose date has come but which failed to post
+	PostFailureCount int        `json:"post_failure_count,omitempty"`
+	PostError        string     `json:"post_error,omitempty"`
+	PostRetryAt      *time.Time `json:"post_retry_at,omitempty"`
+
+	Attachments []AttachmentsResponse       `json:"attachments"`
+	Splits      []TransactionSplitsResponse `json:"splits,omitempty"`
+	Tags        []TagsResponse              `json:"tags"`
+
+	// Set on transactions in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type TransactionSplitsResponse struct {
+	ID           string       `json:"id"`
+	CategoryID   string       `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	CategoryType string       `json:"category_type"`
+	Amount       money.Amount `json:"amount"`
+	Note         string       `json:"note"`
+}
 
-	Attachments []AttachmentsResponse `json:"attachments"`
+type TransactionSplitsRequest struct {
+	CategoryID string       `json:"category_id"`
+	Amount     money.Amount `json:"amount"`
+	Note       string       `json:"note"`
 }
 
 type UpdateAttachmentsRequest struct {
@@ -25,32 +68,62 @@ type UpdateAttachmentsRequest struct {
 type TransactionsRequest struct {
 	WalletID    string                     `json:"wallet_id"`
 	CategoryID  string                     `json:"category_id"`
-	Amount      float64                    `json:"amount"`
+	Amount      money.Amount               `json:"amount"`
 	Date        time.Time                  `json:"date"`
 	Description string                     `json:"description"`
 	Attachments []UpdateAttachmentsRequest `json:"attachments"`
+	// Currency of Amount, defaulting to the wallet's. Other currencies are
+	// converted at the rate of Date and the original amount is kept.
+	Currency string `json:"currency"`
+	// Splits spreads Amount over several categories. On update, nil keeps the
+	// current lines and an empty list removes them.
+	Splits []TransactionSplitsRequest `json:"splits"`
+	// TagIDs are tags of the caller to label the transaction with. On update,
+	// nil keeps the current tags and an empty list removes them.
+	TagIDs []string `json:"tag_ids"`
+	// Status a new transaction starts in, pending or cleared (the default).
+	// It is ignored on update; see UpdateTransactionStatusRequest.
+	Status string `json:"status"`
+	// Scheduled enters a transaction dated in the future without touching
+	// the wallet balance; it posts when Date arrives. Ignored on update.
+	Scheduled bool `json:"scheduled"`
+	// RescaleSplits is set by callers that cannot send split lines (gRPC): when
+	// Splits is nil, the current lines are scaled to a new Amount, or dropped
+	// when the category changes, instead of rejecting the update.
+	RescaleSplits bool `json:"-"`
 
 	// Indicates if the wallet was created during the transaction use event
 	IsWalletNotCreated bool
 }
 
 type FundTransferResponse struct {
-	CashInTransactionID  string    `json:"cash_in_transaction_id"`
-	CashOutTransactionID string    `json:"cash_out_transaction_id"`
-	FromWalletID         string    `json:"from_wallet_id"`
-	ToWalletID           string    `json:"to_wallet_id"`
-	Amount               float64   `json:"amount"`
-	Date                 time.Time `json:"date"`
-	Description          string    `json:"description"`
+	TransferID           string       `json:"transfer_id"`
+	CashInTransactionID  string       `json:"cash_in_transaction_id"`
+	CashOutTransactionID string       `json:"cash_out_transaction_id"`
+	FeeTransactionID     string       `json:"fee_transaction_id,omitempty"` // expense booking the admin fee, if any
+	FromWalletID         string       `json:"from_wallet_id"`
+	ToWalletID           string       `json:"to_wallet_id"`
+	Amount               money.Amount `json:"amount"`
+	ToAmount             money.Amount `json:"to_amount"`
+	ExchangeRate         float64      `json:"exchange_rate"`
+	Date                 time.Time    `json:"date"`
+	Description          string       `json:"description"`
 }
 
+// FundTransferRequest moves Amount out of the source wallet. Between wallets
+// of different currencies, ToAmount is what the destination wallet receives
+// and ExchangeRate what one unit of the source currency buys; either one is
+// enough, and both must agree when given together.
 type FundTransferRequest struct {
-	CashInCategoryID  string    `json:"cash_in_category_id"`
-	CashOutCategoryID string    `json:"cash_out_category_id"`
-	FromWalletID      string    `json:"from_wallet_id"`
-	ToWalletID        string    `json:"to_wallet_id"`
-	Amount            float64   `json:"amount"`
-	AdminFee          float64   `json:"admin_fee"`
-	Date              time.Time `json:"date"`
-	Description       string    `json:"description"`
+	CashInCategoryID   string       `json:"cash_in_category_id"`
+	CashOutCategoryID  string       `json:"cash_out_category_id"`
+	FromWalletID       string       `json:"from_wallet_id"`
+	ToWalletID         string       `json:"to_wallet_id"`
+	Amount             money.Amount `json:"amount"`
+	ToAmount           money.Amount `json:"to_amount"`
+	ExchangeRate       float64      `json:"exchange_rate"`
+	AdminFee           money.Amount `json:"admin_fee"`
+	AdminFeeCategoryID string       `json:"admin_fee_category_id"` // expense category of the fee, "Biaya Admin" by default
+	Date               time.Time    `json:"date"`
+	Description        string       `json:"description"`
 }
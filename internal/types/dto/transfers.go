@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+)
+
+// UpdateTransferRequest replaces a transfer; both legs keep their categories.
+type UpdateTransferRequest struct {
+	FromWalletID       string       `json:"from_wallet_id"`
+	ToWalletID         string       `json:"to_wallet_id"`
+	Amount             money.Amount `json:"amount"`
+	ToAmount           money.Amount `json:"to_amount"`     // as in FundTransferRequest
+	ExchangeRate       float64      `json:"exchange_rate"` // as in FundTransferRequest
+	AdminFee           money.Amount `json:"admin_fee"`
+	AdminFeeCategoryID string       `json:"admin_fee_category_id"` // empty keeps the fee's category, "Biaya Admin" for a new fee
+	Date               time.Time    `json:"date"`
+	Description        string       `json:"description"`
+}
+
+type TransfersResponse struct {
+	ID           string       `json:"id"`
+	FromWalletID string       `json:"from_wallet_id"`
+	ToWalletID   string       `json:"to_wallet_id"`
+	Amount       money.Amount `json:"amount"`
+	ToAmount     money.Amount `json:"to_amount"`
+	ExchangeRate float64      `json:"exchange_rate"`
+	AdminFee     money.Amount `json:"admin_fee"`
+	Date         time.Time    `json:"date"`
+	Description  string       `json:"description"`
+
+	CashOut TransactionsResponse `json:"cash_out"`
+	CashIn  TransactionsResponse `json:"cash_in"`
+	// Fee is the expense booking AdminFee, nil without a fee
+	Fee *TransactionsResponse `json:"fee,omitempty"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+)
+
+type CheckWalletDriftsRequest struct {
+	// WalletIDs defaults to every wallet of the caller
+	WalletIDs []string `json:"wallet_ids"`
+	// Correct books an adjustment transaction for every difference found,
+	// bringing the ledger in line with the wallet balance
+	Correct bool `json:"correct"`
+}
+
+type WalletDriftsResponse struct {
+	// ID is empty for a check that found the wallet in sync
+	ID       string `json:"id,omitempty"`
+	WalletID string `json:"wallet_id"`
+	// LedgerBalance sums the booked transactions of the wallet, initial deposit included
+	LedgerBalance money.Amount `json:"ledger_balance"`
+	WalletBalance money.Amount `json:"wallet_balance"`
+	// Difference is WalletBalance minus LedgerBalance
+	Difference              money.Amount `json:"difference"`
+	Status                  string       `json:"status"`
+	AdjustmentTransactionID string       `json:"adjustment_transaction_id,omitempty"`
+	CheckedAt               time.Time    `json:"checked_at"`
+	ResolvedAt              *time.Time   `json:"resolved_at,omitempty"`
+}
@@ -1,10 +1,12 @@
 package dto
 
+import "refina-transaction/internal/types/money"
+
 type WalletsResponse struct {
-	ID           string  `json:"id"`
-	UserID       string  `json:"user_id"`
-	WalletTypeID string  `json:"wallet_type_id"`
-	Name         string  `json:"name"`
-	Number       string  `json:"number"`
-	Balance      float64 `json:"balance"`
+	ID           string       `json:"id"`
+	UserID       string       `json:"user_id"`
+	WalletTypeID string       `json:"wallet_type_id"`
+	Name         string       `json:"name"`
+	Number       string       `json:"number"`
+	Balance      money.Amount `json:"balance"`
 }
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+)
+
+type BudgetPeriod string
+
+const (
+	BudgetWeekly  BudgetPeriod = "weekly"
+	BudgetMonthly BudgetPeriod = "monthly"
+	BudgetYearly  BudgetPeriod = "yearly"
+)
+
+type Budgets struct {
+	Base
+	UserID     string       `gorm:"type:varchar(255);not null"`
+	CategoryID uuid.UUID    `gorm:"type:uuid;not null"`
+	Period     BudgetPeriod `gorm:"type:varchar(20);not null;default:monthly"`
+	Amount     money.Amount `gorm:"type:decimal(18,2);not null"`
+	Rollover   bool         `gorm:"not null;default:false"`
+
+	Category Categories `gorm:"foreignKey:CategoryID;references:ID"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FXRateSource string
+
+const (
+	FXRateManual FXRateSource = "manual"
+	FXRateImport FXRateSource = "import"
+)
+
+// FXRates says one BaseCurrency is worth Rate QuoteCurrency from EffectiveDate
+// until the next rate of the same pair.
+type FXRates struct {
+	Base
+	BaseCurrency  string       `gorm:"type:varchar(3);not null"`
+	QuoteCurrency string       `gorm:"type:varchar(3);not null"`
+	Rate          float64      `gorm:"type:decimal(24,10);not null"`
+	EffectiveDate time.Time    `gorm:"type:date;not null"`
+	Source        FXRateSource `gorm:"type:varchar(50);not null;default:manual"`
+}
+
+type WalletCurrencies struct {
+	WalletID  uuid.UUID `gorm:"type:uuid;primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Currency  string `gorm:"type:varchar(3);not null"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+)
+
+type DebtDirection string
+
+const (
+	// DebtPayable is money the user owes the counterparty (hutang)
+	DebtPayable DebtDirection = "payable"
+	// DebtReceivable is money the counterparty owes the user (piutang)
+	DebtReceivable DebtDirection = "receivable"
+)
+
+type DebtStatus string
+
+const (
+	DebtOpen    DebtStatus = "open"
+	DebtSettled DebtStatus = "settled"
+)
+
+// Debts tracks money lent to or borrowed from a counterparty. The principal
+// and every repayment are transactions linked through their DebtID, which
+// are changed and deleted through the debt.
+type Debts struct {
+	Base
+	WalletID     uuid.UUID     `gorm:"type:uuid;not null"`
+	Counterparty string        `gorm:"type:varchar(100);not null"`
+	Direction    DebtDirection `gorm:"type:varchar(20);not null"`
+	Principal    money.Amount  `gorm:"type:decimal(18,2);not null"`
+	Currency     string        `gorm:"type:varchar(3);not null;default:IDR"`
+	DebtDate     time.Time     `gorm:"type:timestamp;not null"`
+	DueDate      *time.Time    `gorm:"type:timestamp"`
+	Description  string        `gorm:"type:text"`
+	Status       DebtStatus    `gorm:"type:varchar(20);not null;default:open"`
+	SettledAt    *time.Time
+	// PrincipalTransactionID is nil for a debt recorded without moving money
+	PrincipalTransactionID *uuid.UUID `gorm:"type:uuid"`
+
+	Transactions []Transactions `gorm:"foreignKey:DebtID;references:ID"`
+}
@@ -0,0 +1,10 @@
+package model
+
+type IdempotencyKey struct {
+	Base
+	UserID      string `gorm:"type:varchar(255);not null;default:'';uniqueIndex:idx_idempotency_keys_user_operation_key"`
+	Key         string `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_user_operation_key"`
+	Operation   string `gorm:"type:varchar(100);not null;uniqueIndex:idx_idempotency_keys_user_operation_key"`
+	RequestHash string `gorm:"type:varchar(64);not null;default:''"`
+	Response    []byte `gorm:"type:jsonb;not null"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+)
+
+type ImportFormat string
+
+const (
+	ImportCSV ImportFormat = "csv"
+	ImportOFX ImportFormat = "ofx"
+	ImportQIF ImportFormat = "qif"
+)
+
+type ImportStatus string
+
+const (
+	ImportPreviewed ImportStatus = "previewed"
+	ImportCommitted ImportStatus = "committed"
+	ImportUndone    ImportStatus = "undone"
+)
+
+type Imports struct {
+	Base
+	UserID       string       `gorm:"type:varchar(255);not null"`
+	WalletID     uuid.UUID    `gorm:"type:uuid;not null"`
+	Format       ImportFormat `gorm:"type:varchar(10);not null"`
+	FileName     string       `gorm:"type:varchar(255)"`
+	Status       ImportStatus `gorm:"type:varchar(20);not null;default:previewed"`
+	TotalRows    int          `gorm:"not null;default:0"`
+	ValidRows    int          `gorm:"not null;default:0"`
+	BalanceDelta money.Amount `gorm:"type:decimal(18,2);not null;default:0"`
+	Rows         []byte       `gorm:"type:jsonb;not null"`
+	CommittedAt  *time.Time
+	UndoneAt     *time.Time
+}
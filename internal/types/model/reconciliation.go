@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+)
+
+// BalanceStage is the status from which a transaction counts in the balance
+// of its wallet.
+type BalanceStage string
+
+const (
+	// BalanceStagePending books a transaction as soon as it is entered
+	BalanceStagePending BalanceStage = "pending"
+	// BalanceStageCleared leaves pending transactions out until they clear
+	BalanceStageCleared BalanceStage = "cleared"
+)
+
+// WalletSettings holds per-wallet options; wallets without a row use the defaults.
+type WalletSettings struct {
+	WalletID     uuid.UUID `gorm:"type:uuid;primary_key"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	BalanceStage BalanceStage `gorm:"type:varchar(20);not null;default:pending"`
+}
+
+type ReconciliationStatus string
+
+const (
+	ReconciliationOpen      ReconciliationStatus = "open"
+	ReconciliationCompleted ReconciliationStatus = "completed"
+	ReconciliationCancelled ReconciliationStatus = "cancelled"
+)
+
+// Reconciliations is a session matching the cleared transactions of a
+// wallet against the ending balance of a bank statement.
+type Reconciliations struct {
+	Base
+	UserID           string               `gorm:"type:varchar(255);not null"`
+	WalletID         uuid.UUID            `gorm:"type:uuid;not null"`
+	StatementDate    time.Time            `gorm:"type:date;not null"`
+	StatementBalance money.Amount         `gorm:"type:decimal(18,2);not null"`
+	OpeningBalance   money.Amount         `gorm:"type:decimal(18,2);not null;default:0"`
+	Status           ReconciliationStatus `gorm:"type:varchar(20);not null;default:open"`
+	TransactionCount int                  `gorm:"not null;default:0"`
+	CompletedAt      *time.Time
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+)
+
+type RecurringFrequency string
+
+const (
+	RecurringDaily   RecurringFrequency = "daily"
+	RecurringWeekly  RecurringFrequency = "weekly"
+	RecurringMonthly RecurringFrequency = "monthly"
+	RecurringYearly  RecurringFrequency = "yearly"
+)
+
+type RecurringStatus string
+
+const (
+	RecurringActive    RecurringStatus = "active"
+	RecurringPaused    RecurringStatus = "paused"
+	RecurringCompleted RecurringStatus = "completed"
+)
+
+type RecurringTransactions struct {
+	Base
+	WalletID    uuid.UUID          `gorm:"type:uuid;not null"`
+	CategoryID  uuid.UUID          `gorm:"type:uuid;not null"`
+	Amount      money.Amount       `gorm:"type:decimal(18,2);not null"`
+	Description string             `gorm:"type:text"`
+	Frequency   RecurringFrequency `gorm:"type:varchar(20);not null"`
+	Interval    int                `gorm:"not null;default:1"`
+	StartDate   time.Time          `gorm:"type:timestamp;not null"`
+	EndDate     *time.Time         `gorm:"type:timestamp"`
+	Count       int                `gorm:"not null;default:0"`
+	Occurrences int                `gorm:"not null;default:0"`
+	NextRunAt   time.Time          `gorm:"type:timestamp;not null"`
+	LastRunAt   *time.Time         `gorm:"type:timestamp"`
+	Status      RecurringStatus    `gorm:"type:varchar(20);not null;default:active"`
+
+	FailureCount int        `gorm:"not null;default:0"`
+	LastError    string     `gorm:"type:text"`
+	RetryAt      *time.Time `gorm:"type:timestamp"`
+
+	Category Categories `gorm:"foreignKey:CategoryID;references:ID"`
+}
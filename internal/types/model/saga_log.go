@@ -0,0 +1,46 @@
+package model
+
+import (
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+)
+
+type SagaStatus string
+
+const (
+	SagaStarted      SagaStatus = "started"
+	SagaCompleted    SagaStatus = "completed"
+	SagaCompensating SagaStatus = "compensating"
+	SagaCompensated  SagaStatus = "compensated"
+	SagaFailed       SagaStatus = "failed"
+)
+
+type SagaStepStatus string
+
+const (
+	SagaStepPending     SagaStepStatus = "pending"
+	SagaStepApplied     SagaStepStatus = "applied"
+	SagaStepFailed      SagaStepStatus = "failed"
+	SagaStepCompensated SagaStepStatus = "compensated"
+)
+
+type SagaLog struct {
+	Base
+	Type   string     `gorm:"type:varchar(100);not null"`
+	Status SagaStatus `gorm:"type:varchar(50);not null;default:started"`
+	Error  string     `gorm:"type:text"`
+
+	Steps []SagaLogStep `gorm:"foreignKey:SagaID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+type SagaLogStep struct {
+	Base
+	SagaID          uuid.UUID      `gorm:"type:uuid;not null"`
+	Sequence        int            `gorm:"not null"`
+	WalletID        uuid.UUID      `gorm:"type:uuid;not null"`
+	Delta           money.Amount   `gorm:"type:decimal(18,2);not null"`
+	PreviousBalance money.Amount   `gorm:"type:decimal(18,2);not null"`
+	NewBalance      money.Amount   `gorm:"type:decimal(18,2);not null"`
+	Status          SagaStepStatus `gorm:"type:varchar(50);not null;default:pending"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "github.com/google/uuid"
+
+// Tags are free-form labels of a user. Unlike categories a transaction can
+// carry any number of them.
+type Tags struct {
+	Base
+	UserID string `gorm:"type:varchar(255);not null"`
+	Name   string `gorm:"type:varchar(50);not null"`
+}
+
+// TransactionTags links a transaction to one of its tags.
+type TransactionTags struct {
+	TransactionID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	TagID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HistoryAction is the kind of change a TransactionHistory row records.
+type HistoryAction string
+
+const (
+	HistoryCreated  HistoryAction = "created"
+	HistoryUpdated  HistoryAction = "updated"
+	HistoryDeleted  HistoryAction = "deleted"
+	HistoryRestored HistoryAction = "restored"
+)
+
+// TransactionHistory is one entry of the append-only audit trail of a
+// transaction. Before and After hold the transaction as a JSON response.
+type TransactionHistory struct {
+	ID            uuid.UUID     `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	CreatedAt     time.Time     `gorm:"not null"`
+	TransactionID uuid.UUID     `gorm:"type:uuid;not null"`
+	WalletID      uuid.UUID     `gorm:"type:uuid;not null"`
+	Action        HistoryAction `gorm:"type:varchar(20);not null"`
+	ActorID       string        `gorm:"type:varchar(255)"`
+	Source        string        `gorm:"type:varchar(20);not null"`
+	RequestID     string        `gorm:"type:varchar(255)"`
+	Before        []byte        `gorm:"type:jsonb"`
+	After         []byte        `gorm:"type:jsonb"`
+}
+
+func (TransactionHistory) TableName() string {
+	return "transaction_history"
+}
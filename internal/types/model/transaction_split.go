@@ -0,0 +1,20 @@
+package model
+
+import (
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+)
+
+// TransactionSplits is one category line of a split transaction. The lines
+// of a transaction share its category type and sum to its amount.
+type TransactionSplits struct {
+	Base
+	TransactionID uuid.UUID    `gorm:"type:uuid;not null"`
+	CategoryID    uuid.UUID    `gorm:"type:uuid;not null"`
+	Amount        money.Amount `gorm:"type:decimal(18,2);not null"`
+	Note          string       `gorm:"type:text"`
+	Position      int          `gorm:"not null;default:0"`
+
+	Category Categories `gorm:"foreignKey:CategoryID;references:ID"`
+}
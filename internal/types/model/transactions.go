@@ -3,17 +3,72 @@ package model
 import (
 	"time"
 
+	"refina-transaction/internal/types/money"
+
 	"github.com/google/uuid"
 )
 
+// TransactionStatus is where a transaction is in its lifecycle:
+// pending → cleared → reconciled, with pending and cleared ones voidable.
+type TransactionStatus string
+
+const (
+	StatusPending    TransactionStatus = "pending"
+	StatusCleared    TransactionStatus = "cleared"
+	StatusReconciled TransactionStatus = "reconciled"
+	StatusVoid       TransactionStatus = "void"
+)
+
+// transactionTransitions lists the statuses each status may change to by
+// hand; reconciled is only reached through a reconciliation.
+var transactionTransitions = map[TransactionStatus][]TransactionStatus{
+	StatusPending: {StatusCleared, StatusVoid},
+	StatusCleared: {StatusVoid},
+}
+
+// CanTransition reports whether a transaction may be moved from s to next.
+func (s TransactionStatus) CanTransition(next TransactionStatus) bool {
+	for _, allowed := range transactionTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type Transactions struct {
 	Base
-	WalletID        uuid.UUID `gorm:"type:uuid;not null"`
-	CategoryID      uuid.UUID `gorm:"type:uuid;not null"`
-	Amount          float64   `gorm:"type:decimal(18,2);not null"`
-	TransactionDate time.Time `gorm:"type:timestamp;not null"`
-	Description     string    `gorm:"type:text"`
-
-	Category    Categories    `gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
-	Attachments []Attachments `gorm:"foreignKey:TransactionID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	WalletID        uuid.UUID    `gorm:"type:uuid;not null"`
+	CategoryID      uuid.UUID    `gorm:"type:uuid;not null"`
+	Amount          money.Amount `gorm:"type:decimal(18,2);not null"`
+	Currency        string       `gorm:"type:varchar(3);not null;default:IDR"`
+	TransactionDate time.Time    `gorm:"type:timestamp;not null"`
+	Description     string       `gorm:"type:text"`
+	ImportID        *uuid.UUID   `gorm:"type:uuid"`
+
+	Status           TransactionStatus `gorm:"type:varchar(20);not null;default:cleared"`
+	ReconciliationID *uuid.UUID        `gorm:"type:uuid"`
+
+	// Set on a future-dated transaction until it is posted on its date
+	Scheduled bool `gorm:"not null;default:false"`
+	// Failed attempts at posting a due scheduled transaction, cleared once it posts
+	PostFailureCount int        `gorm:"not null;default:0"`
+	PostError        string     `gorm:"type:text"`
+	PostRetryAt      *time.Time `gorm:"type:timestamp"`
+
+	// Set on both legs of a fund transfer
+	TransferID *uuid.UUID `gorm:"type:uuid"`
+
+	// Set on the principal and the repayments of a debt
+	DebtID *uuid.UUID `gorm:"type:uuid"`
+
+	// Set when the amount was entered in another currency than the wallet's
+	OriginalAmount   *money.Amount `gorm:"type:decimal(18,2)"`
+	OriginalCurrency *string       `gorm:"type:varchar(3)"`
+	FxRate           *float64      `gorm:"type:decimal(24,10)"`
+
+	Category    Categories          `gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Attachments []Attachments       `gorm:"foreignKey:TransactionID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Splits      []TransactionSplits `gorm:"foreignKey:TransactionID;references:ID"`
+	Tags        []Tags              `gorm:"many2many:transaction_tags;joinForeignKey:TransactionID;joinReferences:TagID"`
 }
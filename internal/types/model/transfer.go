@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+)
+
+// Transfers links the cash-out and cash-in legs of a fund transfer, and the
+// expense booking its admin fee, which are changed and deleted together.
+type Transfers struct {
+	Base
+	FromWalletID         uuid.UUID `gorm:"type:uuid;not null"`
+	ToWalletID           uuid.UUID `gorm:"type:uuid;not null"`
+	CashOutTransactionID uuid.UUID `gorm:"type:uuid;not null"`
+	CashInTransactionID  uuid.UUID `gorm:"type:uuid;not null"`
+	// FeeTransactionID is the expense booking AdminFee, nil without a fee
+	FeeTransactionID *uuid.UUID `gorm:"type:uuid"`
+	// Amount leaves the source wallet in its currency; ToAmount is credited
+	// to the destination wallet in its own, one ExchangeRate per unit.
+	Amount       money.Amount `gorm:"type:decimal(18,2);not null"`
+	ToAmount     money.Amount `gorm:"type:decimal(18,2);not null"`
+	ExchangeRate float64      `gorm:"type:decimal(24,10);not null;default:1"`
+	// AdminFee is paid by the source wallet on top of Amount
+	AdminFee     money.Amount `gorm:"type:decimal(18,2);not null;default:0"`
+	TransferDate time.Time    `gorm:"type:timestamp;not null"`
+	Description  string       `gorm:"type:text"`
+
+	CashOut Transactions  `gorm:"foreignKey:CashOutTransactionID;references:ID"`
+	CashIn  Transactions  `gorm:"foreignKey:CashInTransactionID;references:ID"`
+	Fee     *Transactions `gorm:"foreignKey:FeeTransactionID;references:ID"`
+}
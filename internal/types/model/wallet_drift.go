@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"refina-transaction/internal/types/money"
+
+	"github.com/google/uuid"
+)
+
+type WalletDriftStatus string
+
+const (
+	// WalletDriftOpen is a difference still found by the last check
+	WalletDriftOpen WalletDriftStatus = "open"
+	// WalletDriftCorrected was closed by booking an adjustment transaction
+	WalletDriftCorrected WalletDriftStatus = "corrected"
+	// WalletDriftResolved disappeared without an adjustment
+	WalletDriftResolved WalletDriftStatus = "resolved"
+	// WalletDriftInSync reports a check that found no difference; it is never stored
+	WalletDriftInSync WalletDriftStatus = "in_sync"
+)
+
+// WalletDrifts records a difference between the sum of a wallet's
+// transactions and its balance in wallet-service.
+type WalletDrifts struct {
+	Base
+	WalletID                uuid.UUID         `gorm:"type:uuid;not null"`
+	LedgerBalance           money.Amount      `gorm:"type:decimal(18,2);not null"`
+	WalletBalance           money.Amount      `gorm:"type:decimal(18,2);not null"`
+	Difference              money.Amount      `gorm:"type:decimal(18,2);not null"`
+	Status                  WalletDriftStatus `gorm:"type:varchar(20);not null;default:open"`
+	AdjustmentTransactionID *uuid.UUID        `gorm:"type:uuid"`
+	CheckedAt               time.Time         `gorm:"not null"`
+	ResolvedAt              *time.Time
+}
@@ -0,0 +1,313 @@
+// Package money holds Amount, the exact type of every monetary value. Amounts
+// are whole cents, matching the decimal(18,2) columns they are stored in.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxCents is the largest value a decimal(18,2) column holds.
+const maxCents = 999_999_999_999_999_999
+
+// numberPattern accepts plain decimal numbers with an optional short exponent,
+// as written in JSON. big.Rat alone would also take fractions and base
+// prefixes, and a long exponent would make it allocate without bound.
+var numberPattern = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)([eE][-+]?\d{1,2})?$`)
+
+var errTooManyDecimals = errors.New("more than 2 decimal places")
+
+// ErrOutOfRange is returned for an amount a decimal(18,2) column cannot hold.
+var ErrOutOfRange = errors.New("out of range")
+
+// Amount is a signed amount of money in cents. The zero value is zero.
+// Amounts are compared with == and combined with the methods below, never
+// through float64.
+type Amount struct {
+	cents int64
+}
+
+// Zero is the zero amount.
+var Zero Amount
+
+// New returns an amount of whole units, New(1500) being 1500.00.
+func New(units int64) Amount {
+	return Amount{cents: units * 100}
+}
+
+// FromCents returns an amount of cents.
+func FromCents(cents int64) Amount {
+	return Amount{cents: cents}
+}
+
+// FromFloat converts a float64 amount, as carried by the protobuf messages,
+// rounding half away from zero to the cent. NaN and infinities are zero.
+func FromFloat(f float64) Amount {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Zero
+	}
+	// The shortest representation keeps 1.005 from rounding as 1.00499...
+	amount, err := parse(strconv.FormatFloat(f, 'f', -1, 64), true)
+	if err != nil {
+		return Zero
+	}
+	return amount
+}
+
+// Parse reads a decimal string such as "-1500.25" exactly. More than two
+// decimal places is an error rather than a silent rounding.
+func Parse(s string) (Amount, error) {
+	return parse(s, false)
+}
+
+// MustParse is Parse for constants; it panics on invalid input.
+func MustParse(s string) Amount {
+	amount, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+func parse(s string, round bool) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if !numberPattern.MatchString(s) {
+		return Zero, fmt.Errorf("invalid amount [amount=%s]", s)
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Zero, fmt.Errorf("invalid amount [amount=%s]", s)
+	}
+	r.Mul(r, big.NewRat(100, 1))
+
+	cents := r.Num()
+	if !r.IsInt() {
+		if !round {
+			return Zero, fmt.Errorf("invalid amount [amount=%s]: %w", s, errTooManyDecimals)
+		}
+		cents = roundRat(r)
+	}
+	if !cents.IsInt64() || !inRange(cents.Int64()) {
+		return Zero, fmt.Errorf("invalid amount [amount=%s]: %w", s, ErrOutOfRange)
+	}
+
+	return Amount{cents: cents.Int64()}, nil
+}
+
+// roundRat rounds r half away from zero.
+func roundRat(r *big.Rat) *big.Int {
+	num := new(big.Int).Abs(r.Num())
+	quo, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return quo
+}
+
+// Cents returns the amount in cents.
+func (a Amount) Cents() int64 {
+	return a.cents
+}
+
+// Float64 converts the amount for the float64 protobuf fields. It is exact
+// for any amount a decimal(18,2) column holds below 2^53 cents.
+func (a Amount) Float64() float64 {
+	return float64(a.cents) / 100
+}
+
+// String formats the amount with exactly two decimals, like "-1500.25".
+func (a Amount) String() string {
+	sign := ""
+	cents := a.cents
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Add and Sub cannot overflow for amounts in range, but their result may
+// leave it; Value rejects such an amount rather than storing it.
+func (a Amount) Add(b Amount) Amount {
+	return Amount{cents: a.cents + b.cents}
+}
+
+func (a Amount) Sub(b Amount) Amount {
+	return Amount{cents: a.cents - b.cents}
+}
+
+func (a Amount) Neg() Amount {
+	return Amount{cents: -a.cents}
+}
+
+func (a Amount) Abs() Amount {
+	if a.cents < 0 {
+		return a.Neg()
+	}
+	return a
+}
+
+// Cmp returns -1, 0 or +1 as a is less than, equal to or greater than b.
+func (a Amount) Cmp(b Amount) int {
+	switch {
+	case a.cents < b.cents:
+		return -1
+	case a.cents > b.cents:
+		return 1
+	}
+	return 0
+}
+
+func (a Amount) LessThan(b Amount) bool {
+	return a.cents < b.cents
+}
+
+func (a Amount) GreaterThan(b Amount) bool {
+	return a.cents > b.cents
+}
+
+func (a Amount) IsZero() bool {
+	return a.cents == 0
+}
+
+func (a Amount) IsNegative() bool {
+	return a.cents < 0
+}
+
+func (a Amount) IsPositive() bool {
+	return a.cents > 0
+}
+
+// MulRate converts the amount at an exchange rate, rounding half away from
+// zero to the cent. The rate is taken at its shortest decimal form, so a
+// stored 16000.33 multiplies as 16000.33 and not as its binary neighbour. It
+// fails with ErrOutOfRange when the result does not fit a decimal(18,2).
+func (a Amount) MulRate(rate float64) (Amount, error) {
+	if math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return Zero, fmt.Errorf("invalid rate [rate=%g]", rate)
+	}
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'g', -1, 64))
+	if !ok {
+		return Zero, fmt.Errorf("invalid rate [rate=%g]", rate)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(a.cents))
+	return fromRat(r)
+}
+
+// MulRatio scales the amount by num/den, rounding half away from zero. It is
+// how a total is spread over lines in proportion to their share. It fails
+// with ErrOutOfRange when the result does not fit a decimal(18,2).
+func (a Amount) MulRatio(num, den Amount) (Amount, error) {
+	if den.cents == 0 {
+		return Zero, nil
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(a.cents), big.NewInt(den.cents))
+	r.Mul(r, new(big.Rat).SetInt64(num.cents))
+	return fromRat(r)
+}
+
+// Div divides the amount by n, rounding half away from zero; zero when n is 0.
+// The result is never larger than the amount, so it stays in range.
+func (a Amount) Div(n int64) Amount {
+	if n == 0 {
+		return Zero
+	}
+	return Amount{cents: roundRat(big.NewRat(a.cents, n)).Int64()}
+}
+
+// fromRat rounds a number of cents to an amount, rejecting one out of range
+// instead of letting it wrap around.
+func fromRat(r *big.Rat) (Amount, error) {
+	cents := roundRat(r)
+	if !cents.IsInt64() || !inRange(cents.Int64()) {
+		return Zero, ErrOutOfRange
+	}
+	return Amount{cents: cents.Int64()}, nil
+}
+
+func inRange(cents int64) bool {
+	return cents <= maxCents && cents >= -maxCents
+}
+
+// Ratio returns a/b as a float64, for percentages; zero when b is zero.
+func (a Amount) Ratio(b Amount) float64 {
+	if b.cents == 0 {
+		return 0
+	}
+	return float64(a.cents) / float64(b.cents)
+}
+
+// MarshalJSON writes the amount as a JSON number without trailing zeros, the
+// same text a float64 amount used to produce.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	s := a.String()
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	return []byte(s), nil
+}
+
+// UnmarshalJSON reads a JSON number or a quoted decimal string exactly.
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	amount, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// Scan reads a numeric column. Aggregates such as AVG carry more than two
+// decimals and are rounded to the cent.
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = Zero
+		return nil
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	case int64:
+		*a = New(v)
+		return nil
+	case float64:
+		*a = FromFloat(v)
+		return nil
+	}
+	return fmt.Errorf("scan amount: unsupported type %T", src)
+}
+
+func (a *Amount) scanString(s string) error {
+	amount, err := parse(s, true)
+	if err != nil {
+		return fmt.Errorf("scan amount: %w", err)
+	}
+	*a = amount
+	return nil
+}
+
+// Value stores the amount as its exact decimal text.
+func (a Amount) Value() (driver.Value, error) {
+	if !inRange(a.cents) {
+		return nil, fmt.Errorf("amount %s: %w", a, ErrOutOfRange)
+	}
+	return a.String(), nil
+}
@@ -0,0 +1,210 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in    string
+		cents int64
+		err   bool
+	}{
+		{in: "1500", cents: 150000},
+		{in: "-1500.25", cents: -150025},
+		{in: "0.1", cents: 10},
+		{in: ".5", cents: 50},
+		{in: "1.5e3", cents: 150000},
+		{in: " 42.00 ", cents: 4200},
+		{in: "1.005", err: true},
+		{in: "1/3", err: true},
+		{in: "0x10", err: true},
+		{in: "NaN", err: true},
+		{in: "1e999", err: true},
+		{in: "10000000000000000", err: true},
+		{in: "", err: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			amount, err := Parse(tc.in)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.cents, amount.Cents())
+		})
+	}
+}
+
+func TestFromFloat_RoundsToTheCent(t *testing.T) {
+	assert.Equal(t, FromCents(30), FromFloat(0.1+0.2))
+	assert.Equal(t, FromCents(101), FromFloat(1.005))
+	assert.Equal(t, FromCents(-101), FromFloat(-1.005))
+	assert.Equal(t, New(50000), FromFloat(50000))
+	assert.Equal(t, Zero, FromFloat(1/zero()))
+}
+
+func zero() float64 { return 0 }
+
+func TestArithmeticIsExact(t *testing.T) {
+	total := Zero
+	for i := 0; i < 10; i++ {
+		total = total.Add(MustParse("0.10"))
+	}
+
+	assert.Equal(t, New(1), total)
+	assert.Equal(t, MustParse("0.70"), New(1).Sub(MustParse("0.3")))
+	assert.Equal(t, -1, MustParse("-5").Cmp(Zero))
+	assert.Equal(t, New(5), MustParse("-5").Abs())
+}
+
+// must unwraps an arithmetic result that is expected to succeed.
+func must(amount Amount, err error) Amount {
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+func TestMulRate(t *testing.T) {
+	assert.Equal(t, MustParse("168003.47"), must(MustParse("10.50").MulRate(16000.33)))
+	assert.Equal(t, MustParse("160000"), must(New(10).MulRate(1/0.0000625)))
+	assert.Equal(t, MustParse("-0.01"), must(MustParse("-0.01").MulRate(1)))
+}
+
+func TestMulRate_RangeEdges(t *testing.T) {
+	largest := FromCents(maxCents)
+
+	cases := []struct {
+		name   string
+		amount Amount
+		rate   float64
+		cents  int64
+		err    bool
+	}{
+		{name: "largest amount at rate 1", amount: largest, rate: 1, cents: maxCents},
+		{name: "smallest amount at rate 1", amount: largest.Neg(), rate: 1, cents: -maxCents},
+		{name: "half of the largest amount doubled", amount: FromCents(maxCents / 2), rate: 2, cents: maxCents - 1},
+		{name: "largest amount times two", amount: largest, rate: 2, err: true},
+		{name: "smallest amount times two", amount: largest.Neg(), rate: 2, err: true},
+		{name: "beyond int64", amount: largest, rate: 1e6, err: true},
+		{name: "huge rate", amount: New(1), rate: 1e300, err: true},
+		{name: "infinite rate", amount: New(1), rate: math.Inf(1), err: true},
+		{name: "NaN rate", amount: New(1), rate: math.NaN(), err: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			amount, err := tc.amount.MulRate(tc.rate)
+			if tc.err {
+				assert.Error(t, err)
+				assert.Equal(t, Zero, amount)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.cents, amount.Cents())
+		})
+	}
+
+	_, err := FromCents(maxCents).MulRate(1.5)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestMulRatioAndDiv(t *testing.T) {
+	assert.Equal(t, MustParse("33.33"), must(New(100).MulRatio(New(1), New(3))))
+	assert.Equal(t, MustParse("66.67"), must(New(100).MulRatio(New(2), New(3))))
+	assert.Equal(t, Zero, must(New(100).MulRatio(New(2), Zero)))
+	assert.Equal(t, MustParse("3.33"), New(10).Div(3))
+	assert.Equal(t, Zero, New(10).Div(0))
+	assert.InDelta(t, 0.25, New(1).Ratio(New(4)), 1e-9)
+}
+
+func TestMulRatio_RangeEdges(t *testing.T) {
+	largest := FromCents(maxCents)
+
+	assert.Equal(t, largest, must(largest.MulRatio(New(3), New(3))))
+	assert.Equal(t, largest.Neg(), must(largest.MulRatio(New(-1), New(1))))
+
+	_, err := largest.MulRatio(FromCents(2), FromCents(1))
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = largest.MulRatio(largest, FromCents(1))
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestDiv_RangeEdges(t *testing.T) {
+	largest := FromCents(maxCents)
+
+	assert.Equal(t, largest, largest.Div(1))
+	assert.Equal(t, largest.Neg(), largest.Div(-1))
+	assert.Equal(t, FromCents(500_000_000_000_000_000), largest.Div(2))
+}
+
+func TestAddAndSub_RangeEdges(t *testing.T) {
+	largest := FromCents(maxCents)
+
+	// Amounts in range never overflow int64, and the result is exact
+	assert.Equal(t, int64(2*maxCents), largest.Add(largest).Cents())
+	assert.Equal(t, int64(-2*maxCents), largest.Neg().Sub(largest).Cents())
+	assert.Equal(t, Zero, largest.Sub(largest))
+
+	// but an amount out of range is not stored
+	_, err := largest.Add(FromCents(1)).Value()
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = largest.Neg().Sub(FromCents(1)).Value()
+	assert.ErrorIs(t, err, ErrOutOfRange)
+
+	value, err := largest.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "9999999999999999.99", value)
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "1500.00", New(1500).String())
+	assert.Equal(t, "-0.05", FromCents(-5).String())
+}
+
+func TestJSON(t *testing.T) {
+	var body struct {
+		Amount   Amount  `json:"amount"`
+		Fee      Amount  `json:"fee"`
+		Original *Amount `json:"original"`
+	}
+
+	err := json.Unmarshal([]byte(`{"amount": 10.5, "fee": "2500", "original": null}`), &body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, MustParse("10.5"), body.Amount)
+	assert.Equal(t, New(2500), body.Fee)
+	assert.Nil(t, body.Original)
+
+	out, err := json.Marshal(body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount": 10.5, "fee": 2500, "original": null}`, string(out))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": 0.125}`), &body))
+}
+
+func TestScanAndValue(t *testing.T) {
+	var amount Amount
+
+	assert.NoError(t, amount.Scan([]byte("1234.5600000000000000")))
+	assert.Equal(t, MustParse("1234.56"), amount)
+
+	assert.NoError(t, amount.Scan("33.3333333333333333"))
+	assert.Equal(t, MustParse("33.33"), amount)
+
+	assert.NoError(t, amount.Scan(int64(7)))
+	assert.Equal(t, New(7), amount)
+
+	assert.Error(t, amount.Scan(true))
+
+	value, err := MustParse("-12.5").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "-12.50", value)
+}
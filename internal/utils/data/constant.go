@@ -7,13 +7,94 @@ var (
 	STAGING_MODE     = "staging"
 	PRODUCTION_MODE  = "production"
 
-	OUTBOX_PUBLISH_EXCHANGE          = "refina_microservice"
-	OUTBOX_PUBLISH_INTERVAL          = 5 * time.Second
-	OUTBOX_PUBLISH_BATCH             = 100
-	OUTBOX_PUBLISH_MAX_RETRIES       = 5
-	OUTBOX_EVENT_TRANSACTION_CREATED = "transaction.created"
-	OUTBOX_EVENT_TRANSACTION_UPDATED = "transaction.updated"
-	OUTBOX_EVENT_TRANSACTION_DELETED = "transaction.deleted"
+	OUTBOX_PUBLISH_EXCHANGE               = "refina_microservice"
+	OUTBOX_PUBLISH_INTERVAL               = 5 * time.Second
+	OUTBOX_PUBLISH_BATCH                  = 100
+	OUTBOX_PUBLISH_MAX_RETRIES            = 5
+	OUTBOX_EVENT_TRANSACTION_CREATED      = "transaction.created"
+	OUTBOX_EVENT_TRANSACTION_UPDATED      = "transaction.updated"
+	OUTBOX_EVENT_TRANSACTION_DELETED      = "transaction.deleted"
+	OUTBOX_EVENT_TRANSACTION_RESTORED     = "transaction.restored"
+	OUTBOX_EVENT_BUDGET_THRESHOLD_REACHED = "budget.threshold_reached"
+	OUTBOX_EVENT_BUDGET_EXCEEDED          = "budget.exceeded"
+	OUTBOX_EVENT_WALLET_DRIFT_DETECTED    = "wallet.drift_detected"
+	OUTBOX_EVENT_DEBT_SETTLED             = "debt.settled"
+
+	// BUDGET_THRESHOLD_WARNING is the share of a budget limit that triggers budget.threshold_reached
+	BUDGET_THRESHOLD_WARNING = 0.8
+
+	SAGA_RESUME_INTERVAL           = time.Minute
+	SAGA_RESUME_AFTER              = 5 * time.Minute
+	SAGA_RESUME_BATCH              = 100
+	SAGA_TYPE_TRANSACTION_CREATE   = "transaction.create"
+	SAGA_TYPE_TRANSACTION_UPDATE   = "transaction.update"
+	SAGA_TYPE_TRANSACTION_DELETE   = "transaction.delete"
+	SAGA_TYPE_TRANSACTION_RESTORE  = "transaction.restore"
+	SAGA_TYPE_TRANSACTION_TRANSFER = "transaction.fund_transfer"
+	SAGA_TYPE_TRANSACTION_BATCH    = "transaction.batch"
+	SAGA_TYPE_TRANSACTION_STATUS   = "transaction.status"
+	SAGA_TYPE_TRANSACTION_POST     = "transaction.post"
+	SAGA_TYPE_TRANSFER_UPDATE      = "transfer.update"
+	SAGA_TYPE_TRANSFER_DELETE      = "transfer.delete"
+	SAGA_TYPE_DEBT_CREATE          = "debt.create"
+	SAGA_TYPE_DEBT_REPAY           = "debt.repay"
+	SAGA_TYPE_DEBT_DELETE          = "debt.delete"
+	SAGA_TYPE_IMPORT_COMMIT        = "import.commit"
+	SAGA_TYPE_IMPORT_UNDO          = "import.undo"
+
+	IDEMPOTENCY_KEY_HEADER                   = "Idempotency-Key"
+	IDEMPOTENCY_OPERATION_TRANSACTION_CREATE = "transaction.create"
+	IDEMPOTENCY_OPERATION_FUND_TRANSFER      = "transaction.fund_transfer"
+	IDEMPOTENCY_OPERATION_TRANSACTION_BATCH  = "transaction.batch"
+
+	RECURRING_SCHEDULER_INTERVAL = time.Minute
+	RECURRING_SCHEDULER_BATCH    = 100
+	// RECURRING_CATCHUP_LIMIT caps the occurrences one schedule materialises per tick after downtime
+	RECURRING_CATCHUP_LIMIT = 31
+	// RECURRING_MAX_FAILURES pauses a schedule whose next occurrence failed this many times in a row
+	RECURRING_MAX_FAILURES = 5
+	// RECURRING_RETRY_BACKOFF doubles after every failed attempt, up to RECURRING_RETRY_BACKOFF_MAX
+	RECURRING_RETRY_BACKOFF     = 5 * time.Minute
+	RECURRING_RETRY_BACKOFF_MAX = 6 * time.Hour
+
+	// TRASH_RETENTION is how long a deleted transaction stays restorable before it is purged
+	TRASH_RETENTION      = 30 * 24 * time.Hour
+	TRASH_PURGE_INTERVAL = time.Hour
+
+	// SCHEDULED_POST_INTERVAL is how often scheduled transactions whose date has come are posted
+	SCHEDULED_POST_INTERVAL = time.Minute
+	SCHEDULED_POST_BATCH    = 100
+	// SCHEDULED_POST_RETRY_BACKOFF doubles after every failed post, up to SCHEDULED_POST_RETRY_BACKOFF_MAX
+	SCHEDULED_POST_RETRY_BACKOFF     = 5 * time.Minute
+	SCHEDULED_POST_RETRY_BACKOFF_MAX = 6 * time.Hour
+
+	// WALLET_DRIFT_CHECK_INTERVAL is how often every wallet's ledger sum is compared with wallet-service
+	WALLET_DRIFT_CHECK_INTERVAL = 6 * time.Hour
+
+	// DEFAULT_CURRENCY is the currency of wallets that have none set
+	DEFAULT_CURRENCY = "IDR"
+
+	// TAG_NAME_MAX_LENGTH matches the varchar(50) tags.name column
+	TAG_NAME_MAX_LENGTH = 50
+
+	// TRANSACTION_BATCH_MAX_ITEMS caps the items of one batch request, which run in a single DB transaction
+	TRANSACTION_BATCH_MAX_ITEMS = 500
+	BATCH_ACTION_CREATE         = "create"
+	BATCH_ACTION_UPDATE         = "update"
+	BATCH_ACTION_DELETE         = "delete"
+	BATCH_ITEM_APPLIED          = "applied"
+	BATCH_ITEM_FAILED           = "failed"
+	BATCH_ITEM_NOT_APPLIED      = "not_applied"
+
+	IMPORT_MAX_ROWS     = 5000
+	IMPORT_COMMIT_BATCH = 500
+
+	EXPORT_BATCH_SIZE       = 500
+	STATEMENT_MAX_FILE_SIZE = 10 * 1024 * 1024 // 10MB
+	STATEMENT_URL_EXPIRY    = 15 * time.Minute
+
+	WALLET_ADJUST_MAX_RETRIES   = 3
+	WALLET_ADJUST_RETRY_BACKOFF = 100 * time.Millisecond
 
 	EVENT_INVESTMENT_QUEUE = "refina-investments"
 	// Investment event routing keys (consumed from investment-service)
@@ -24,6 +105,13 @@ var (
 	CATEGORY_ID_FUND_TRANSFER          = "00000000-0000-0000-0000-000000000010"
 	CATEGORY_ID_FUND_TRANSFER_CASH_IN  = "00000000-0000-0000-0000-000000000011"
 	CATEGORY_ID_FUND_TRANSFER_CASH_OUT = "00000000-0000-0000-0000-000000000012"
+	CATEGORY_ID_BALANCE_ADJUSTMENT_IN  = "00000000-0000-0000-0000-000000000020"
+	CATEGORY_ID_BALANCE_ADJUSTMENT_OUT = "00000000-0000-0000-0000-000000000021"
+	CATEGORY_ID_ADMIN_FEE              = "00000000-0000-0000-0000-000000000022"
+	CATEGORY_ID_DEBT_BORROWED          = "00000000-0000-0000-0000-000000000030"
+	CATEGORY_ID_DEBT_REPAID            = "00000000-0000-0000-0000-000000000031"
+	CATEGORY_ID_RECEIVABLE_LENT        = "00000000-0000-0000-0000-000000000032"
+	CATEGORY_ID_RECEIVABLE_COLLECTED   = "00000000-0000-0000-0000-000000000033"
 	CATEGORY_ID_INVESTMENT_BUY         = "66239d17-3320-4c98-9b8c-fb8d84827085"
 	CATEGORY_ID_INVESTMENT_SELL        = "635fdfd1-31f4-472c-8d52-e59a66c31351"
 
@@ -31,4 +119,18 @@ var (
 	REQUEST_ID_HEADER = "X-Request-ID"
 	// REQUEST_ID_LOCAL_KEY is the key used to store the request ID in Gin's context locals.
 	REQUEST_ID_LOCAL_KEY = "request_id"
+	// REQUEST_ID_METADATA_KEY carries the request ID in gRPC metadata.
+	REQUEST_ID_METADATA_KEY = "x-request-id"
+	// USER_DATA_LOCAL_KEY is the key used to store the authenticated dto.UserData in Gin's context locals.
+	USER_DATA_LOCAL_KEY = "user_data"
+
+	// REQUEST_SOURCE_* name the entry point of a change in the transaction history
+	REQUEST_SOURCE_HTTP      = "http"
+	REQUEST_SOURCE_GRPC      = "grpc"
+	REQUEST_SOURCE_CONSUMER  = "consumer"
+	REQUEST_SOURCE_SCHEDULER = "scheduler"
+
+	// USER_ROLE_ADMIN is the role, carried in the JWT "role" claim or the
+	// x-user-role metadata, allowed to edit the shared category tree.
+	USER_ROLE_ADMIN = "admin"
 )
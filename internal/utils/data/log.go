@@ -14,6 +14,17 @@ const (
 	TransactionService        = "transaction"
 	CategoryService           = "category"
 	InvestmentConsumerService = "investment_consumer"
+	SagaService               = "saga"
+	RecurringService          = "recurring"
+	BudgetService             = "budget"
+	ReportService             = "report"
+	ImportService             = "import"
+	ExportService             = "export"
+	CurrencyService           = "currency"
+	TagService                = "tag"
+	ReconciliationService     = "reconciliation"
+	WalletDriftService        = "wallet_drift"
+	DebtService               = "debt"
 )
 
 // Message field logging constants
@@ -36,6 +47,14 @@ const (
 	LogOutboxMessagePublished          = "outbox_message_published"
 	LogOutboxCleanupFailed             = "outbox_cleanup_failed"
 
+	// --- saga orchestrator ---
+	LogSagaCompensated        = "saga_compensated"
+	LogSagaCompensationFailed = "saga_compensation_failed"
+	LogSagaStatusUpdateFailed = "saga_status_update_failed"
+	LogSagaStepUpdateFailed   = "saga_step_update_failed"
+	LogSagaResumeFailed       = "saga_resume_failed"
+	LogSagaResumeCompleted    = "saga_resume_completed"
+
 	// --- gRPC client ---
 	LogGRPCClientSetupFailed  = "grpc_client_setup_failed"
 	LogGRPCClientSetupSuccess = "grpc_client_setup_success"
@@ -59,6 +78,34 @@ const (
 	LogShutdownCompletedWithErrors = "shutdown_completed_with_errors"
 	LogShutdownCompleted           = "shutdown_completed"
 
+	// --- recurring scheduler ---
+	LogRecurringSchedulerStarted   = "recurring_scheduler_started"
+	LogRecurringProcessFailed      = "recurring_process_failed"
+	LogRecurringOccurrenceFailed   = "recurring_occurrence_failed"
+	LogRecurringOccurrencesCreated = "recurring_occurrences_created"
+	LogRecurringAutoPaused         = "recurring_auto_paused"
+
+	// --- trash purger ---
+	LogTrashPurgerStarted = "trash_purger_started"
+	LogTrashPurgeFailed   = "trash_purge_failed"
+	LogTrashPurged        = "trash_purged"
+
+	// --- scheduled transaction poster ---
+	LogScheduledPosterStarted       = "scheduled_poster_started"
+	LogScheduledPostFailed          = "scheduled_post_failed"
+	LogScheduledPostFailureNotSaved = "scheduled_post_failure_not_saved"
+	LogScheduledTransactionsPosted  = "scheduled_transactions_posted"
+
+	// --- wallet drift checker ---
+	LogWalletDriftCheckerStarted = "wallet_drift_checker_started"
+	LogWalletDriftCheckFailed    = "wallet_drift_check_failed"
+	LogWalletDriftDetected       = "wallet_drift_detected"
+
+	// --- authorization ---
+	LogAuthorizationDenied       = "authorization_denied"
+	LogAuthTokenRejected         = "auth_token_rejected"
+	LogAuthMiddlewareSetupFailed = "auth_middleware_setup_failed"
+
 	// --- gRPC server handlers (transaction) ---
 	LogGetTransactionsFailed         = "get_transactions_failed"
 	LogGetUserTransactionsFailed     = "get_user_transactions_failed"
@@ -101,6 +148,101 @@ const (
 	LogUpdateTransactionBadRequest       = "update_transaction_bad_request"
 	LogUpdateTransactionFailed           = "update_transaction_failed"
 	LogDeleteTransactionHTTPFailed       = "delete_transaction_failed"
+	LogGetDeletedTransactionsFailed      = "get_deleted_transactions_failed"
+	LogRestoreTransactionFailed          = "restore_transaction_failed"
+	LogGetTransactionHistoryFailed       = "get_transaction_history_failed"
+	LogBatchTransactionsBadRequest       = "batch_transactions_bad_request"
+	LogBatchTransactionsFailed           = "batch_transactions_failed"
+	LogUpdateTransactionStatusBadRequest = "update_transaction_status_bad_request"
+	LogUpdateTransactionStatusFailed     = "update_transaction_status_failed"
+	LogGetTransfersFailed                = "get_transfers_failed"
+	LogGetTransferByIDFailed             = "get_transfer_by_id_failed"
+	LogUpdateTransferBadRequest          = "update_transfer_bad_request"
+	LogUpdateTransferFailed              = "update_transfer_failed"
+	LogDeleteTransferFailed              = "delete_transfer_failed"
+	LogGetScheduledTransactionsFailed    = "get_scheduled_transactions_failed"
+	LogCancelScheduledTransactionFailed  = "cancel_scheduled_transaction_failed"
+	LogGetDebtsFailed                    = "get_debts_failed"
+	LogGetDebtByIDFailed                 = "get_debt_by_id_failed"
+	LogCreateDebtBadRequest              = "create_debt_bad_request"
+	LogCreateDebtFailed                  = "create_debt_failed"
+	LogUpdateDebtBadRequest              = "update_debt_bad_request"
+	LogUpdateDebtFailed                  = "update_debt_failed"
+	LogDeleteDebtFailed                  = "delete_debt_failed"
+	LogRepayDebtBadRequest               = "repay_debt_bad_request"
+	LogRepayDebtFailed                   = "repay_debt_failed"
+
+	// --- http handler (recurring transaction) ---
+	LogGetRecurringTransactionsFailed       = "get_recurring_transactions_failed"
+	LogGetRecurringTransactionByIDFailed    = "get_recurring_transaction_by_id_failed"
+	LogCreateRecurringTransactionBadRequest = "create_recurring_transaction_bad_request"
+	LogCreateRecurringTransactionFailed     = "create_recurring_transaction_failed"
+	LogUpdateRecurringTransactionBadRequest = "update_recurring_transaction_bad_request"
+	LogUpdateRecurringTransactionFailed     = "update_recurring_transaction_failed"
+	LogDeleteRecurringTransactionFailed     = "delete_recurring_transaction_failed"
+	LogChangeRecurringScheduleFailed        = "change_recurring_schedule_failed"
+
+	// --- http handler (budget) ---
+	LogGetBudgetsFailed        = "get_budgets_failed"
+	LogGetBudgetByIDFailed     = "get_budget_by_id_failed"
+	LogCreateBudgetBadRequest  = "create_budget_bad_request"
+	LogCreateBudgetFailed      = "create_budget_failed"
+	LogUpdateBudgetBadRequest  = "update_budget_bad_request"
+	LogUpdateBudgetFailed      = "update_budget_failed"
+	LogDeleteBudgetFailed      = "delete_budget_failed"
+	LogBudgetEvaluationSkipped = "budget_evaluation_skipped"
+
+	// --- http handler (tag) ---
+	LogGetTagsFailed       = "get_tags_failed"
+	LogGetTagByIDFailed    = "get_tag_by_id_failed"
+	LogCreateTagBadRequest = "create_tag_bad_request"
+	LogCreateTagFailed     = "create_tag_failed"
+	LogUpdateTagBadRequest = "update_tag_bad_request"
+	LogUpdateTagFailed     = "update_tag_failed"
+	LogDeleteTagFailed     = "delete_tag_failed"
+
+	// --- http handler (report) ---
+	LogGetTransactionSummaryFailed = "get_transaction_summary_failed"
+
+	// --- http handler (reconciliation) ---
+	LogGetReconciliationsFailed         = "get_reconciliations_failed"
+	LogGetReconciliationByIDFailed      = "get_reconciliation_by_id_failed"
+	LogStartReconciliationBadRequest    = "start_reconciliation_bad_request"
+	LogStartReconciliationFailed        = "start_reconciliation_failed"
+	LogCompleteReconciliationBadRequest = "complete_reconciliation_bad_request"
+	LogCompleteReconciliationFailed     = "complete_reconciliation_failed"
+	LogCancelReconciliationFailed       = "cancel_reconciliation_failed"
+	LogGetBalanceStageFailed            = "get_balance_stage_failed"
+	LogSetBalanceStageBadRequest        = "set_balance_stage_bad_request"
+	LogSetBalanceStageFailed            = "set_balance_stage_failed"
+
+	// --- http handler (wallet drift) ---
+	LogGetWalletDriftsFailed      = "get_wallet_drifts_failed"
+	LogCheckWalletDriftBadRequest = "check_wallet_drift_bad_request"
+	LogCheckWalletDriftFailed     = "check_wallet_drift_failed"
+
+	// --- http handler (import) ---
+	LogGetImportsFailed        = "get_imports_failed"
+	LogGetImportByIDFailed     = "get_import_by_id_failed"
+	LogPreviewImportBadRequest = "preview_import_bad_request"
+	LogPreviewImportFailed     = "preview_import_failed"
+	LogCommitImportFailed      = "commit_import_failed"
+	LogUndoImportFailed        = "undo_import_failed"
+
+	// --- http handler (export) ---
+	LogExportTransactionsFailed    = "export_transactions_failed"
+	LogGetMonthlyStatementFailed   = "get_monthly_statement_failed"
+	LogStoreMonthlyStatementFailed = "store_monthly_statement_failed"
+
+	// --- http handler (currency) ---
+	LogGetFXRatesFailed            = "get_fx_rates_failed"
+	LogUpsertFXRatesBadRequest     = "upsert_fx_rates_bad_request"
+	LogUpsertFXRatesFailed         = "upsert_fx_rates_failed"
+	LogImportFXRatesBadRequest     = "import_fx_rates_bad_request"
+	LogImportFXRatesFailed         = "import_fx_rates_failed"
+	LogGetWalletCurrencyFailed     = "get_wallet_currency_failed"
+	LogSetWalletCurrencyBadRequest = "set_wallet_currency_bad_request"
+	LogSetWalletCurrencyFailed     = "set_wallet_currency_failed"
 
 	// --- http handler (category) ---
 	LogGetAllCategoriesFailed    = "get_all_categories_failed"
@@ -7,6 +7,7 @@ import (
 	"refina-transaction/internal/types/model"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 func ConvertToResponseType(data any) any {
@@ -30,21 +31,141 @@ func ConvertToResponseType(data any) any {
 		return responses
 	case model.Transactions:
 		return dto.TransactionsResponse{
-			ID:              v.ID.String(),
-			WalletID:        v.WalletID.String(),
-			CategoryID:      v.CategoryID.String(),
-			CategoryName:    v.Category.Name,
-			CategoryType:    string(v.Category.Type),
-			Amount:          v.Amount,
-			TransactionDate: v.TransactionDate,
-			Description:     v.Description,
-			Attachments:     ConvertToResponseType(v.Attachments).([]dto.AttachmentsResponse),
+			ID:               v.ID.String(),
+			WalletID:         v.WalletID.String(),
+			CategoryID:       v.CategoryID.String(),
+			CategoryName:     v.Category.Name,
+			CategoryType:     string(v.Category.Type),
+			Amount:           v.Amount,
+			Currency:         v.Currency,
+			TransactionDate:  v.TransactionDate,
+			Description:      v.Description,
+			OriginalAmount:   v.OriginalAmount,
+			OriginalCurrency: v.OriginalCurrency,
+			FxRate:           v.FxRate,
+			Status:           string(v.Status),
+			ReconciliationID: uuidString(v.ReconciliationID),
+			TransferID:       uuidString(v.TransferID),
+			DebtID:           uuidString(v.DebtID),
+			Scheduled:        v.Scheduled,
+			PostFailureCount: v.PostFailureCount,
+			PostError:        v.PostError,
+			PostRetryAt:      v.PostRetryAt,
+			Attachments:      ConvertToResponseType(v.Attachments).([]dto.AttachmentsResponse),
+			Splits:           ConvertToResponseType(v.Splits).([]dto.TransactionSplitsResponse),
+			Tags:             ConvertToResponseType(v.Tags).([]dto.TagsResponse),
+			DeletedAt:        deletedAt(v.DeletedAt),
+		}
+	case model.Transfers:
+		response := dto.TransfersResponse{
+			ID:           v.ID.String(),
+			FromWalletID: v.FromWalletID.String(),
+			ToWalletID:   v.ToWalletID.String(),
+			Amount:       v.Amount,
+			ToAmount:     v.ToAmount,
+			ExchangeRate: v.ExchangeRate,
+			AdminFee:     v.AdminFee,
+			Date:         v.TransferDate,
+			Description:  v.Description,
+			CashOut:      ConvertToResponseType(v.CashOut).(dto.TransactionsResponse),
+			CashIn:       ConvertToResponseType(v.CashIn).(dto.TransactionsResponse),
+		}
+		if v.Fee != nil {
+			fee := ConvertToResponseType(*v.Fee).(dto.TransactionsResponse)
+			response.Fee = &fee
+		}
+		return response
+	case model.TransactionSplits:
+		return dto.TransactionSplitsResponse{
+			ID:           v.ID.String(),
+			CategoryID:   v.CategoryID.String(),
+			CategoryName: v.Category.Name,
+			CategoryType: string(v.Category.Type),
+			Amount:       v.Amount,
+			Note:         v.Note,
+		}
+	case []model.TransactionSplits:
+		if len(v) == 0 {
+			return []dto.TransactionSplitsResponse(nil)
+		}
+		responses := make([]dto.TransactionSplitsResponse, len(v))
+		for i, split := range v {
+			responses[i] = ConvertToResponseType(split).(dto.TransactionSplitsResponse)
+		}
+		return responses
+	case model.Tags:
+		return dto.TagsResponse{
+			ID:   v.ID.String(),
+			Name: v.Name,
+		}
+	case []model.Tags:
+		responses := make([]dto.TagsResponse, len(v))
+		for i, tag := range v {
+			responses[i] = ConvertToResponseType(tag).(dto.TagsResponse)
+		}
+		return responses
+	case model.TransactionHistory:
+		return dto.TransactionHistoryResponse{
+			ID:            v.ID.String(),
+			TransactionID: v.TransactionID.String(),
+			Action:        string(v.Action),
+			ActorID:       v.ActorID,
+			Source:        v.Source,
+			RequestID:     v.RequestID,
+			Before:        v.Before,
+			After:         v.After,
+			CreatedAt:     v.CreatedAt,
+		}
+	case []model.TransactionHistory:
+		responses := make([]dto.TransactionHistoryResponse, len(v))
+		for i, entry := range v {
+			responses[i] = ConvertToResponseType(entry).(dto.TransactionHistoryResponse)
+		}
+		return responses
+	case model.RecurringTransactions:
+		return dto.RecurringTransactionsResponse{
+			ID:           v.ID.String(),
+			WalletID:     v.WalletID.String(),
+			CategoryID:   v.CategoryID.String(),
+			CategoryName: v.Category.Name,
+			CategoryType: string(v.Category.Type),
+			Amount:       v.Amount,
+			Description:  v.Description,
+			Frequency:    string(v.Frequency),
+			Interval:     v.Interval,
+			StartDate:    v.StartDate,
+			EndDate:      v.EndDate,
+			Count:        v.Count,
+			Occurrences:  v.Occurrences,
+			NextRunAt:    v.NextRunAt,
+			LastRunAt:    v.LastRunAt,
+			Status:       string(v.Status),
+			FailureCount: v.FailureCount,
+			LastError:    v.LastError,
+			RetryAt:      v.RetryAt,
 		}
 	default:
 		return nil
 	}
 }
 
+// deletedAt returns the deletion time of a soft-deleted row, or nil.
+func deletedAt(d gorm.DeletedAt) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	t := d.Time
+	return &t
+}
+
+func uuidString(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
 func ParseUUID(id string) (uuid.UUID, error) {
 	parsedID, err := uuid.Parse(id)
 	if err != nil {
@@ -61,4 +182,4 @@ func SameDate(t1, t2 time.Time) bool {
 	y1, m1, d1 := t1.Date()
 	y2, m2, d2 := t2.Date()
 	return y1 == y2 && m1 == m2 && d1 == d2
-}
\ No newline at end of file
+}
@@ -0,0 +1,20 @@
+package utils
+
+import "context"
+
+type idempotencyKeyCtxKey struct{}
+
+// WithIdempotencyKey stores the caller supplied idempotency key so services can
+// deduplicate retried create requests regardless of the transport.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the key set by WithIdempotencyKey, if any.
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(idempotencyKeyCtxKey{}).(string)
+	return v
+}
@@ -0,0 +1,31 @@
+package utils
+
+import "context"
+
+type (
+	requestSourceCtxKey struct{}
+	requestIDCtxKey     struct{}
+)
+
+// WithRequestSource stores the entry point a request came through, one of
+// the data.REQUEST_SOURCE_* values, and its request ID, so that the audit
+// trail can tell where a change came from.
+func WithRequestSource(ctx context.Context, source, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestSourceCtxKey{}, source)
+	if requestID != "" {
+		ctx = context.WithValue(ctx, requestIDCtxKey{}, requestID)
+	}
+	return ctx
+}
+
+// RequestSourceFromContext returns the source set by WithRequestSource, if any.
+func RequestSourceFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(requestSourceCtxKey{}).(string)
+	return v
+}
+
+// RequestIDFromContext returns the request ID set by WithRequestSource, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return v
+}